@@ -161,6 +161,9 @@ generate the CA cert
 {{- define "generate-ca-certs" }}
     {{- $ca := genCA "kdoctor.io" (.Values.tls.server.auto.caExpiration | int) -}}
     {{- $_ := set . "ca" $ca -}}
+    {{- /* the client ca only signs the certificate of the controller, and its key is not stored, so the agents could not sign a client certificate */ -}}
+    {{- $clientCa := genCA "kdoctor-client.io" (.Values.tls.server.auto.caExpiration | int) -}}
+    {{- $_ := set . "clientCa" $clientCa -}}
 {{- end }}
 
 {{- define "project.kdoctorAgent.serviceIpv4Name" -}}
@@ -106,6 +106,7 @@ func GenServerCert(logger *zap.Logger) error {
 	// generate self-signed certificates
 	if e := utils.NewServerCertKeyForLocalNode(alternateDNS, alternateIP, types.AgentConfig.TlsCaCertPath, types.AgentConfig.TlsCaKeyPath, TlsCertPath, TlsKeyPath, CaCertPath); e != nil {
 		logger.Sugar().Errorf("failed to generate certiface, error=%v", e)
+		return e
 	}
 	return err
 }
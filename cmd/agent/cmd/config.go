@@ -81,6 +81,8 @@ func init() {
 	globalFlag.BoolVarP(&types.AgentConfig.AppDnsUpstream, "dns-upstream", "D", true, "core dns upstream")
 	globalFlag.StringVarP(&types.AgentConfig.TlsCaCertPath, "tls-ca-cert", "R", "/etc/tls/ca.crt", "ca file path")
 	globalFlag.StringVarP(&types.AgentConfig.TlsCaKeyPath, "tls-ca-key", "Y", "/etc/tls/ca.key", "ca key file path")
+	globalFlag.StringVar(&types.AgentConfig.TlsClientIdentity, "tls-client-identity", "", "the identity in the client certificate of the controller, which calls the grpc server, and it is required")
+	globalFlag.StringVar(&types.AgentConfig.TlsClientCaPath, "tls-client-ca", "/etc/tls/client-ca.crt", "the ca file path which signs the client certificate of the controller")
 	if e := viper.BindPFlags(globalFlag); e != nil {
 		logger.Sugar().Fatalf("failed to BindPFlags, reason=%v", e)
 	}
@@ -102,6 +104,8 @@ func init() {
 		logger.Info("task-name = " + types.AgentConfig.TaskName)
 		logger.Info("service-ipv4-name = " + types.AgentConfig.ServiceV4Name)
 		logger.Info("service-ipv6-name = " + types.AgentConfig.ServiceV6Name)
+		logger.Info("tls-client-identity = " + types.AgentConfig.TlsClientIdentity)
+		logger.Info("tls-client-ca = " + types.AgentConfig.TlsClientCaPath)
 	}
 	cobra.OnInitialize(printFlag)
 
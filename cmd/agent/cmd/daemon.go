@@ -13,9 +13,12 @@ import (
 
 	"github.com/kdoctor-io/kdoctor/pkg/agentDnsServer"
 	"github.com/kdoctor-io/kdoctor/pkg/agentHttpServer"
+	"github.com/kdoctor-io/kdoctor/pkg/agentTcpServer"
+	"github.com/kdoctor-io/kdoctor/pkg/agentUdpServer"
 	"github.com/kdoctor-io/kdoctor/pkg/debug"
 	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
 
@@ -34,7 +37,7 @@ func SetupUtility() {
 func DaemonMain() {
 	rootLogger.Sugar().Infof("config: %+v", types.AgentConfig)
 
-	// TODO: udp server, tcp server, websocket server
+	// TODO: websocket server
 	if types.AgentConfig.AppMode {
 		// app mode, just used to debug
 		rootLogger.Info("run in app mode")
@@ -77,11 +80,15 @@ func DaemonMain() {
 			agentHttpServer.SetupAppHttpServer(rootLogger, TlsCertPath, TlsKeyPath)
 			agentDnsServer.SetupAppDnsServer(rootLogger, TlsCertPath, TlsKeyPath)
 		}
+		agentTcpServer.SetupAppTcpServer(rootLogger)
+		agentUdpServer.SetupAppUdpServer(rootLogger)
 	} else {
 		rootLogger.Info("run in agent mode")
 
 		SetupUtility()
 		RunMetricsServer(types.AgentConfig.PodName)
+		tracing.RunTracing(types.AgentConfig.EnableTrace, types.AgentConfig.TraceOtlpEndpoint, types.AgentConfig.TraceOtlpInsecure,
+			types.AgentConfig.TraceSamplePercent, "kdoctor-agent", types.AgentConfig.PodName, rootLogger.Named("tracing"))
 
 		s := pluginManager.InitPluginManager(rootLogger.Named("agentController"))
 		s.RunAgentController()
@@ -90,6 +97,8 @@ func DaemonMain() {
 			rootLogger.Sugar().Fatalf("Generating a certificate fails,err=%v", err)
 		}
 		agentHttpServer.SetupAppHttpServer(rootLogger, TlsCertPath, TlsKeyPath)
+		agentTcpServer.SetupAppTcpServer(rootLogger)
+		agentUdpServer.SetupAppUdpServer(rootLogger)
 		initGrpcServer()
 
 	}
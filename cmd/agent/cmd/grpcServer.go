@@ -4,7 +4,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+
 	"github.com/kdoctor-io/kdoctor/pkg/grpcManager"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
@@ -13,7 +15,21 @@ func initGrpcServer() {
 	// ---- grpc server
 	rootLogger.Info("start grpc server")
 
-	t := grpcManager.NewGrpcServer(rootLogger, TlsCertPath, TlsKeyPath)
+	// only the controller calls the grpc server, so its identity and its ca are required
+	if len(types.AgentConfig.TlsClientIdentity) == 0 || len(types.AgentConfig.TlsClientCaPath) == 0 {
+		rootLogger.Sugar().Fatalf("--tls-client-identity and --tls-client-ca are required for the grpc server")
+	}
+
+	// the server certificate is signed by the mounted ca again when the ca is rotated,
+	// and the client is verified with the client ca, whose key is never mounted to the agent
+	files := grpcManager.TlsFiles{CertPath: TlsCertPath, KeyPath: TlsKeyPath, CaPath: types.AgentConfig.TlsCaCertPath, ClientCaPath: types.AgentConfig.TlsClientCaPath}
+	certReloader, e := grpcManager.NewCertReloader(rootLogger.Named("grpcTls"), files, func() error { return GenServerCert(rootLogger) })
+	if e != nil {
+		rootLogger.Sugar().Fatalf("failed to load the tls of grpc server, error=%v", e)
+	}
+	go certReloader.Run(context.Background(), grpcManager.DefaultCertReloadInterval)
+
+	t := grpcManager.NewGrpcServer(rootLogger, certReloader, types.AgentConfig.TlsClientIdentity)
 	listenAddr := fmt.Sprintf(":%d", types.AgentConfig.AgentGrpcListenPort)
 	t.Run(listenAddr)
 }
@@ -82,6 +82,9 @@ func init() {
 	globalFlag.StringVarP(&types.ControllerConfig.TlsCaCertPath, "tls-ca-cert", "R", "", "ca file path")
 	globalFlag.StringVarP(&types.ControllerConfig.TlsServerCertPath, "tls-server-cert", "T", "", "server cert file path")
 	globalFlag.StringVarP(&types.ControllerConfig.TlsServerKeyPath, "tls-server-key", "Y", "", "server key file path")
+	globalFlag.StringVar(&types.ControllerConfig.TlsClientCertPath, "tls-client-cert", "", "client cert file path of the grpc to the agents")
+	globalFlag.StringVar(&types.ControllerConfig.TlsClientKeyPath, "tls-client-key", "", "client key file path of the grpc to the agents")
+	globalFlag.StringVar(&types.ControllerConfig.TlsAgentCaPath, "tls-agent-ca", "", "ca file path which signs the certificates of the agents")
 	if e := viper.BindPFlags(globalFlag); e != nil {
 		logger.Sugar().Fatalf("failed to BindPFlags, reason=%v", e)
 	}
@@ -95,6 +98,9 @@ func init() {
 		logger.Info("tls-ca-cert = " + types.ControllerConfig.TlsCaCertPath)
 		logger.Info("tls-server-cert = " + types.ControllerConfig.TlsServerCertPath)
 		logger.Info("tls-server-key = " + types.ControllerConfig.TlsServerKeyPath)
+		logger.Info("tls-client-cert = " + types.ControllerConfig.TlsClientCertPath)
+		logger.Info("tls-client-key = " + types.ControllerConfig.TlsClientKeyPath)
+		logger.Info("tls-agent-ca = " + types.ControllerConfig.TlsAgentCaPath)
 
 		// load configmap
 		if len(types.ControllerConfig.ConfigMapPath) > 0 {
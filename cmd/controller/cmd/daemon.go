@@ -15,6 +15,7 @@ import (
 	"github.com/kdoctor-io/kdoctor/pkg/apiserver"
 	"github.com/kdoctor-io/kdoctor/pkg/debug"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
 
@@ -42,6 +43,8 @@ func DaemonMain() {
 	// ------
 
 	RunMetricsServer(types.ControllerConfig.PodName)
+	tracing.RunTracing(types.ControllerConfig.EnableTrace, types.ControllerConfig.TraceOtlpEndpoint, types.ControllerConfig.TraceOtlpInsecure,
+		types.ControllerConfig.TraceSamplePercent, "kdoctor-controller", types.ControllerConfig.PodName, rootLogger.Named("tracing"))
 	MetricGaugeEndpoint.Add(context.Background(), 100)
 	MetricGaugeEndpoint.Add(context.Background(), -10)
 	MetricGaugeEndpoint.Add(context.Background(), 5)
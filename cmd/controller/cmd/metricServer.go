@@ -18,11 +18,11 @@ var (
 	MetricHistogramDuration syncfloat64.Histogram
 )
 
-var metricMapping = []pkgmetric.MetricMappingType{
+var metricMapping = append([]pkgmetric.MetricMappingType{
 	{P: &MetricCounterRequest, Name: "request_counts", Description: "the request counter"},
 	{P: &MetricGaugeEndpoint, Name: "endpoint_number", Description: "the endpoint number"},
 	{P: &MetricHistogramDuration, Name: "request_duration_seconds", Description: "the request duration histogram"},
-}
+}, pkgmetric.ControllerTaskMetricMapping...)
 
 // var globalMeter metric.Meter
 func RunMetricsServer(meterName string) {
@@ -0,0 +1,85 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+)
+
+// the exit codes of kdoctorctl
+const (
+	exitTaskFailed = 1
+	exitError      = 2
+)
+
+// taskFailedError makes kdoctorctl exit with exitTaskFailed
+type taskFailedError struct {
+	msg string
+}
+
+func (e *taskFailedError) Error() string {
+	return e.msg
+}
+
+var BinName = filepath.Base(os.Args[0])
+
+var globalFlags struct {
+	kubeconfig string
+	context    string
+	namespace  string
+	timeout    time.Duration
+}
+
+// rootCmd represents the base command.
+var rootCmd = &cobra.Command{
+	Use:   BinName,
+	Short: "create, watch and summarize the kdoctor tasks",
+	Long: `kdoctorctl creates the kdoctor tasks, waits for them, and summarizes their status and reports.
+The command run exits with 1 when any round of the task fails, and all commands exit with 2 on the other errors.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+	flags.StringVar(&globalFlags.kubeconfig, "kubeconfig", "", "path to the kubeconfig file, or else $KUBECONFIG and ~/.kube/config are used")
+	flags.StringVar(&globalFlags.context, "context", "", "the kubeconfig context to use")
+	flags.StringVarP(&globalFlags.namespace, "namespace", "n", "kdoctor", "the namespace where kdoctor is installed")
+	flags.DurationVar(&globalFlags.timeout, "request-timeout", 30*time.Second, "the timeout of each request to the api server")
+}
+
+// newClient creates the client from the kubeconfig
+func newClient() (*kdoctorctl.Client, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = globalFlags.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: globalFlags.context}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig, error: %v", err)
+	}
+	config.Timeout = globalFlags.timeout
+	return kdoctorctl.NewClient(config, globalFlags.namespace)
+}
+
+// Execute adds all child commands to the root command sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var failed *taskFailedError
+		if errors.As(err, &failed) {
+			os.Exit(exitTaskFailed)
+		}
+		os.Exit(exitError)
+	}
+}
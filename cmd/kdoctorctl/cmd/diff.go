@@ -0,0 +1,54 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff KIND NAME FROM_ROUND TO_ROUND",
+	Short: "compare the results of the nodes and the metrics of the targets between two rounds",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, err := kdoctorctl.NormalizeKind(args[0])
+		if err != nil {
+			return err
+		}
+		rounds := [2]int64{}
+		for i, v := range args[2:] {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("the round %q must be a positive integer", v)
+			}
+			rounds[i] = n
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		from, err := c.GetReport(cmd.Context(), kind, args[1], rounds[0])
+		if err != nil {
+			return err
+		}
+		to, err := c.GetReport(cmd.Context(), kind, args[1], rounds[1])
+		if err != nil {
+			return err
+		}
+		diff := kdoctorctl.DiffRounds(rounds[0], rounds[1], kdoctorctl.RoundReports(from, rounds[0]), kdoctorctl.RoundReports(to, rounds[1]))
+		kdoctorctl.PrintDiff(os.Stdout, diff)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
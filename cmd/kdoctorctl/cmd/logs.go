@@ -0,0 +1,56 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+)
+
+var logsFlags struct {
+	round  int64
+	follow bool
+}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs KIND NAME",
+	Short: "stream the round output of the agents running a task",
+	Long: `Stream the logs of the rounds and the round reports from the agents running a task, and each line is prefixed with the node.
+The agents shared by the tasks print the reports of all tasks of the kind.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, err := kdoctorctl.NormalizeKind(args[0])
+		if err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		status, err := c.GetTaskStatus(cmd.Context(), kind, args[1])
+		if err != nil {
+			return fmt.Errorf("failed to get %v %v, error: %v", kind, args[1], err)
+		}
+		pods, err := c.AgentPods(cmd.Context(), status)
+		if err != nil {
+			return fmt.Errorf("failed to get the agents of %v %v, error: %v", kind, args[1], err)
+		}
+		if len(pods) == 0 {
+			return fmt.Errorf("no agent of %v %v in the namespace %v", kind, args[1], c.Namespace)
+		}
+
+		filter := kdoctorctl.LogFilter{Kind: kind, TaskName: args[1], Round: logsFlags.round}
+		return c.StreamLogs(cmd.Context(), pods, logsFlags.follow, filter, os.Stdout)
+	},
+}
+
+func init() {
+	logsCmd.Flags().Int64Var(&logsFlags.round, "round", 0, "only show the output of the round")
+	logsCmd.Flags().BoolVarP(&logsFlags.follow, "follow", "f", false, "keep streaming the logs")
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+)
+
+var reportFlags struct {
+	round  int64
+	output string
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report KIND NAME",
+	Short: "show the agent reports of the latest round or the selected round, in a table of each node",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, err := kdoctorctl.NormalizeKind(args[0])
+		if err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		report, err := c.GetReport(cmd.Context(), kind, args[1], reportFlags.round)
+		if err != nil {
+			return err
+		}
+
+		switch reportFlags.output {
+		case outputJSON:
+			return printJSON(report)
+		case outputTable:
+			kdoctorctl.PrintReport(os.Stdout, report, kdoctorctl.RoundReports(report, reportFlags.round))
+			return nil
+		}
+		return fmt.Errorf("unknown output format %q", reportFlags.output)
+	},
+}
+
+func init() {
+	reportCmd.Flags().Int64Var(&reportFlags.round, "round", 0, "the round to show, or else the latest round")
+	reportCmd.Flags().StringVarP(&reportFlags.output, "output", "o", outputTable, "the output format, table or json")
+	rootCmd.AddCommand(reportCmd)
+}
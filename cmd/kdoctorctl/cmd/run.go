@@ -0,0 +1,107 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+)
+
+var runFlags struct {
+	task         kdoctorctl.TaskOptions
+	wait         bool
+	waitTimeout  time.Duration
+	pollInterval time.Duration
+	cleanup      bool
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run KIND NAME",
+	Short: "create a NetReach, AppHttpHealthy or Netdns task and wait for it to finish",
+	Long: `Create a NetReach, AppHttpHealthy or Netdns task from the flags, and wait for all rounds to finish.
+It exits with 1 when any round fails, so it could be the gate of a pipeline:
+
+  kdoctorctl run netreach upgrade-check --rounds 3 --schedule "0 1" --success-rate 1
+  kdoctorctl run apphttphealthy api --host http://api.default.svc --qps 20 --duration 10
+  kdoctorctl run netdns coredns --domain kubernetes.default.svc.cluster.local`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, err := kdoctorctl.NormalizeKind(args[0])
+		if err != nil {
+			return err
+		}
+		runFlags.task.Name = args[1]
+		task, err := kdoctorctl.NewTask(kind, &runFlags.task)
+		if err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		if err := c.CreateTask(cmd.Context(), task); err != nil {
+			return fmt.Errorf("failed to create %v %v, error: %v", kind, runFlags.task.Name, err)
+		}
+		fmt.Fprintf(os.Stdout, "%v %v created\n", kind, runFlags.task.Name)
+		if !runFlags.wait {
+			return nil
+		}
+		if runFlags.cleanup {
+			defer func() {
+				if err := c.DeleteTask(context.Background(), kind, runFlags.task.Name); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to delete %v %v, error: %v\n", kind, runFlags.task.Name, err)
+				}
+			}()
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), runFlags.waitTimeout)
+		defer cancel()
+		status, err := c.WaitForTask(ctx, kind, runFlags.task.Name, runFlags.pollInterval, func(record crd.StatusHistoryRecord) {
+			kdoctorctl.PrintRound(os.Stdout, record)
+		})
+		if err != nil {
+			return err
+		}
+
+		result := kdoctorctl.ResultOf(status)
+		if result.Failed() {
+			return &taskFailedError{msg: fmt.Sprintf("%v %v failed in rounds %v", kind, runFlags.task.Name, result.FailedRounds)}
+		}
+		fmt.Fprintf(os.Stdout, "%v %v succeeded in %d rounds\n", kind, runFlags.task.Name, result.DoneRound)
+		return nil
+	},
+}
+
+func init() {
+	flags := runCmd.Flags()
+	o := &runFlags.task
+	flags.Int64Var(&o.Rounds, "rounds", 1, "the number of the rounds")
+	flags.StringVar(&o.Schedule, "schedule", "", `the schedule of the rounds, "M N" to start after M minutes and run every N minutes, or a crontab`)
+	flags.Int64Var(&o.RoundTimeoutMinute, "round-timeout", 60, "the timeout of each round in minutes")
+	flags.IntVar(&o.QPS, "qps", 0, "the qps of the requests of each agent, or else the default of the task")
+	flags.IntVar(&o.DurationInSecond, "duration", 0, "the duration of the requests in seconds, or else the default of the task")
+	flags.IntVar(&o.PerRequestTimeoutInMS, "request-timeout-ms", 0, "the timeout of each request in milliseconds, or else the default of the task")
+	flags.Float64Var(&o.SuccessRate, "success-rate", 0, "the minimum success rate from 0 to 1, or else the default of the task")
+	flags.Int64Var(&o.MeanAccessDelayInMs, "mean-delay-ms", 0, "the maximum mean delay in milliseconds, or else the default of the task")
+	flags.StringVar(&o.Host, "host", "", "the url of the target, required for AppHttpHealthy")
+	flags.StringVar(&o.Method, "method", "GET", "the http method, for AppHttpHealthy")
+	flags.StringVar(&o.Server, "server", "", "the address of the dns server, or else the dns service of the cluster, for Netdns")
+	flags.StringVar(&o.Domain, "domain", "kubernetes.default.svc.cluster.local", "the domain to resolve, for Netdns")
+	flags.StringVar(&o.Protocol, "protocol", "", "the protocol of the dns requests, udp, tcp, tcp-tls, https or quic, for Netdns")
+
+	flags.BoolVar(&runFlags.wait, "wait", true, "wait for the task to finish")
+	flags.DurationVar(&runFlags.waitTimeout, "wait-timeout", time.Hour, "the timeout to wait for the task to finish")
+	flags.DurationVar(&runFlags.pollInterval, "poll-interval", 5*time.Second, "the interval to poll the status of the task")
+	flags.BoolVar(&runFlags.cleanup, "cleanup", false, "delete the task after it finishes")
+
+	rootCmd.AddCommand(runCmd)
+}
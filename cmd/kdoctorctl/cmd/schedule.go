@@ -0,0 +1,47 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+)
+
+// newPatchCmd returns the command which patches the task
+func newPatchCmd(use, short, done string, patch func() []byte) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " KIND NAME",
+		Short: short,
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, err := kdoctorctl.NormalizeKind(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			if err := c.PatchTask(cmd.Context(), kind, args[1], patch()); err != nil {
+				return fmt.Errorf("failed to %v %v %v, error: %v", use, kind, args[1], err)
+			}
+			fmt.Fprintf(os.Stdout, "%v %v %v\n", kind, args[1], done)
+			return nil
+		},
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(newPatchCmd("suspend", "stop starting the scheduled rounds of a task, the running round still finishes", "suspended",
+		func() []byte { return kdoctorctl.SuspendPatch(true) }))
+	rootCmd.AddCommand(newPatchCmd("resume", "start the scheduled rounds of a suspended task again", "resumed",
+		func() []byte { return kdoctorctl.SuspendPatch(false) }))
+	rootCmd.AddCommand(newPatchCmd("trigger", "add a round to a task which starts right now", "triggered",
+		func() []byte { return kdoctorctl.RunNowPatch(time.Now().UTC().Format(time.RFC3339Nano)) }))
+}
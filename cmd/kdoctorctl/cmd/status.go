@@ -0,0 +1,67 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+)
+
+var statusFlags struct {
+	output string
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status KIND NAME",
+	Short: "show the rounds and the conditions of a task",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, err := kdoctorctl.NormalizeKind(args[0])
+		if err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		status, err := c.GetTaskStatus(cmd.Context(), kind, args[1])
+		if err != nil {
+			return fmt.Errorf("failed to get %v %v, error: %v", kind, args[1], err)
+		}
+
+		switch statusFlags.output {
+		case outputJSON:
+			return printJSON(status)
+		case outputTable:
+			kdoctorctl.PrintStatus(os.Stdout, kind, args[1], status)
+			return nil
+		}
+		return fmt.Errorf("unknown output format %q", statusFlags.output)
+	},
+}
+
+// the output formats of the commands
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stdout, string(data))
+	return nil
+}
+
+func init() {
+	statusCmd.Flags().StringVarP(&statusFlags.output, "output", "o", outputTable, "the output format, table or json")
+	rootCmd.AddCommand(statusCmd)
+}
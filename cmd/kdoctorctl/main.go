@@ -0,0 +1,12 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/kdoctor-io/kdoctor/cmd/kdoctorctl/cmd"
+)
+
+func main() {
+	cmd.Execute()
+}
@@ -8,13 +8,17 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
 	"go.uber.org/zap"
 
 	"github.com/kdoctor-io/kdoctor/pkg/agentHttpServer"
 	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDns"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
 
@@ -97,6 +101,36 @@ func SetupAppDnsServer(rootLogger *zap.Logger, tlsCert, tlsKey string) {
 				logger.Sugar().Errorf("dns tcp-tls server , err: %v ", err)
 			}
 		}()
+
+		mux := http.NewServeMux()
+		mux.Handle(loadDns.DefaultDohPath, NewDohHandler(handler))
+		httpsServer := &http.Server{
+			Addr:              fmt.Sprintf(":%d", types.AgentConfig.AppDnsHttpsPort),
+			TLSConfig:         tlsConfig.Clone(),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			logger.Sugar().Infof("dns https server listien %s", httpsServer.Addr)
+			if err := httpsServer.ListenAndServeTLS("", ""); err != nil {
+				logger.Sugar().Errorf("dns https server , err: %v ", err)
+			}
+		}()
+
+		quicTlsConfig := tlsConfig.Clone()
+		quicTlsConfig.NextProtos = []string{loadDns.DoqALPN}
+		quicAddr := fmt.Sprintf(":%d", types.AgentConfig.AppDnsQuicPort)
+		go func() {
+			logger.Sugar().Infof("dns quic server listien %s", quicAddr)
+			listener, err := quic.ListenAddr(quicAddr, quicTlsConfig, &quic.Config{})
+			if err != nil {
+				logger.Sugar().Errorf("dns quic server , err: %v ", err)
+				return
+			}
+			if err := ServeDoq(logger, listener, handler); err != nil {
+				logger.Sugar().Errorf("dns quic server , err: %v ", err)
+			}
+		}()
 	}
 
 	udpServer := &dns.Server{
@@ -0,0 +1,96 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package agentDnsServer
+
+import (
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/miekg/dns"
+
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDns"
+)
+
+// msgWriter implements the dns.ResponseWriter for the transports which are not served by the dns package,
+// it keeps the reply for the caller to send
+type msgWriter struct {
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	reply      *dns.Msg
+}
+
+var _ dns.ResponseWriter = &msgWriter{}
+
+func (w *msgWriter) LocalAddr() net.Addr  { return w.localAddr }
+func (w *msgWriter) RemoteAddr() net.Addr { return w.remoteAddr }
+func (w *msgWriter) WriteMsg(m *dns.Msg) error {
+	w.reply = m
+	return nil
+}
+func (w *msgWriter) Write(b []byte) (int, error) {
+	m := new(dns.Msg)
+	if err := m.Unpack(b); err != nil {
+		return 0, err
+	}
+	w.reply = m
+	return len(b), nil
+}
+func (w *msgWriter) Close() error        { return nil }
+func (w *msgWriter) TsigStatus() error   { return nil }
+func (w *msgWriter) TsigTimersOnly(bool) {}
+func (w *msgWriter) Hijack()             {}
+
+// NewDohHandler serves the dns message over https as RFC 8484, with both GET and POST method
+func NewDohHandler(handler dns.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var buf []byte
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			buf, err = base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		case http.MethodPost:
+			if r.Header.Get("Content-Type") != loadDns.DohMediaType {
+				http.Error(rw, "unsupported content type", http.StatusUnsupportedMediaType)
+				return
+			}
+			buf, err = io.ReadAll(r.Body)
+		default:
+			http.Error(rw, "unsupported method", http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil || len(buf) == 0 {
+			http.Error(rw, "invalid dns message", http.StatusBadRequest)
+			return
+		}
+
+		req := new(dns.Msg)
+		if err := req.Unpack(buf); err != nil || len(req.Question) == 0 {
+			http.Error(rw, "invalid dns message", http.StatusBadRequest)
+			return
+		}
+
+		w := &msgWriter{}
+		if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+			w.localAddr = addr
+		}
+		if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+			w.remoteAddr = addr
+		}
+		handler.ServeDNS(w, req)
+		if w.reply == nil {
+			http.Error(rw, "no dns reply", http.StatusInternalServerError)
+			return
+		}
+
+		out, err := w.reply.Pack()
+		if err != nil {
+			http.Error(rw, "failed to pack dns reply", http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", loadDns.DohMediaType)
+		_, _ = rw.Write(out)
+	})
+}
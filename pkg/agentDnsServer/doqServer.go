@@ -0,0 +1,70 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package agentDnsServer
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+
+	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+	"go.uber.org/zap"
+)
+
+// ServeDoq serves the dns message over quic as RFC 9250, each query comes in a bidirectional stream.
+// It returns when the listener is closed
+func ServeDoq(logger *zap.Logger, listener *quic.Listener, handler dns.Handler) error {
+	for {
+		conn, err := listener.Accept(context.Background())
+		if err != nil {
+			return err
+		}
+		go serveDoqConn(logger, conn, handler)
+	}
+}
+
+func serveDoqConn(logger *zap.Logger, conn quic.Connection, handler dns.Handler) {
+	for {
+		stream, err := conn.AcceptStream(context.Background())
+		if err != nil {
+			logger.Sugar().Debugf("quic connection from %v closed, %v", conn.RemoteAddr(), err)
+			return
+		}
+		go serveDoqStream(logger, conn, stream, handler)
+	}
+}
+
+func serveDoqStream(logger *zap.Logger, conn quic.Connection, stream quic.Stream, handler dns.Handler) {
+	defer stream.Close()
+
+	length := make([]byte, 2)
+	if _, err := io.ReadFull(stream, length); err != nil {
+		stream.CancelRead(0)
+		return
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(length))
+	if _, err := io.ReadFull(stream, buf); err != nil {
+		stream.CancelRead(0)
+		return
+	}
+	req := new(dns.Msg)
+	if err := req.Unpack(buf); err != nil || len(req.Question) == 0 {
+		logger.Sugar().Debugf("invalid dns message from %v", conn.RemoteAddr())
+		stream.CancelRead(0)
+		return
+	}
+
+	w := &msgWriter{localAddr: conn.LocalAddr(), remoteAddr: conn.RemoteAddr()}
+	handler.ServeDNS(w, req)
+	if w.reply == nil {
+		return
+	}
+	out, err := w.reply.Pack()
+	if err != nil {
+		logger.Sugar().Errorf("failed to pack dns reply, %v", err)
+		return
+	}
+	_, _ = stream.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(out))), out...))
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package agentTcpServer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// the longest time that a client could hold a connection
+const maxConnectionDuration = 10 * time.Minute
+
+// ackSize is the size of the acknowledgement sent back to the client,
+// which carries the received data size in big endian
+const ackSize = 8
+
+func SetupAppTcpServer(rootLogger *zap.Logger) {
+	logger := rootLogger.Named("app tcp server")
+
+	if types.AgentConfig.AppTcpPort == 0 {
+		logger.Sugar().Warn("app tcp server is disabled")
+		return
+	}
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", types.AgentConfig.AppTcpPort))
+	if err != nil {
+		logger.Sugar().Fatalf("failed to listen on tcp port %v, reason=%v", types.AgentConfig.AppTcpPort, err)
+	}
+	logger.Sugar().Infof("setup agent app tcp server at port %v", types.AgentConfig.AppTcpPort)
+
+	go func() {
+		e := Serve(logger, l)
+		s := "app tcp server break"
+		if e != nil {
+			s += fmt.Sprintf(" reason=%v", e)
+		}
+		logger.Fatal(s)
+	}()
+}
+
+// Serve works as a sink for the tcp client. For each connection, it discards all received data
+// until the client half-closes the connection, then replies the received data size
+func Serve(logger *zap.Logger, l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				logger.Sugar().Warnf("failed to accept connection, error=%v", err)
+				continue
+			}
+			return err
+		}
+		go handleConn(logger, conn)
+	}
+}
+
+func handleConn(logger *zap.Logger, conn net.Conn) {
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(maxConnectionDuration)); err != nil {
+		logger.Sugar().Debugf("failed to set deadline for connection from %v, error=%v", conn.RemoteAddr(), err)
+		return
+	}
+
+	n, err := io.Copy(io.Discard, conn)
+	if err != nil {
+		logger.Sugar().Debugf("failed to receive data from %v, error=%v", conn.RemoteAddr(), err)
+		return
+	}
+
+	ack := make([]byte, ackSize)
+	binary.BigEndian.PutUint64(ack, uint64(n))
+	if _, err := conn.Write(ack); err != nil {
+		logger.Sugar().Debugf("failed to reply %v, error=%v", conn.RemoteAddr(), err)
+		return
+	}
+	logger.Sugar().Debugf("received %d bytes from %v", n, conn.RemoteAddr())
+}
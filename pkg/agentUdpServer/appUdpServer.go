@@ -0,0 +1,61 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package agentUdpServer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// the maximum size of a udp packet
+const maxPacketSize = 65535
+
+func SetupAppUdpServer(rootLogger *zap.Logger) {
+	logger := rootLogger.Named("app udp server")
+
+	if types.AgentConfig.AppUdpPort == 0 {
+		logger.Sugar().Warn("app udp server is disabled")
+		return
+	}
+
+	pc, err := net.ListenPacket("udp", fmt.Sprintf(":%d", types.AgentConfig.AppUdpPort))
+	if err != nil {
+		logger.Sugar().Fatalf("failed to listen on udp port %v, reason=%v", types.AgentConfig.AppUdpPort, err)
+	}
+	logger.Sugar().Infof("setup agent app udp server at port %v", types.AgentConfig.AppUdpPort)
+
+	go func() {
+		e := Serve(logger, pc)
+		s := "app udp server break"
+		if e != nil {
+			s += fmt.Sprintf(" reason=%v", e)
+		}
+		logger.Fatal(s)
+	}()
+}
+
+// Serve works as an echo server for the udp client, it sends back each received packet as it is,
+// so the client could measure the loss, reordering and jitter of the packets
+func Serve(logger *zap.Logger, pc net.PacketConn) error {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				logger.Sugar().Warnf("failed to read packet, error=%v", err)
+				continue
+			}
+			return err
+		}
+		if _, err := pc.WriteTo(buf[:n], addr); err != nil {
+			logger.Sugar().Debugf("failed to echo %d bytes to %v, error=%v", n, addr, err)
+		}
+	}
+}
@@ -141,6 +141,8 @@ func (c completedConfig) New() (*kdoctorServer, error) {
 
 	v1beta1storage := map[string]rest.Storage{}
 	v1beta1storage["kdoctorreports"] = registry.RESTInPeace(kdoctorreport.NewREST(clientSet, Scheme, c.GenericConfig.RESTOptionsGetter))
+	v1beta1storage["kdoctorreports/rounds"] = kdoctorreport.NewRoundsREST()
+	v1beta1storage["kdoctorreports/render"] = kdoctorreport.NewRenderREST()
 	apiGroupInfo.VersionedResourcesStorageMap["v1beta1"] = v1beta1storage
 
 	err = s.GenericAPIServer.InstallAPIGroup(&apiGroupInfo)
@@ -5,6 +5,7 @@ package printers
 
 import (
 	"context"
+	"fmt"
 
 	metatable "k8s.io/apimachinery/pkg/api/meta/table"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,13 +16,26 @@ import (
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
 )
 
+const (
+	roundResultSucceed = "succeed"
+	roundResultFail    = "fail"
+	// none is printed for the column without data
+	none = "<none>"
+)
+
 var (
+	// the columns with priority 1 are printed for the wide output only
 	tableColumnDefinitions = []metav1.TableColumnDefinition{
 		{Name: "Name", Type: "string", Format: "name", Description: swaggerMetadataDescriptions["name"]},
-		{Name: "RoundNumber", Type: "int", Description: ""},
-		{Name: "RoundResult", Type: "string", Description: ""},
-		{Name: "NodeName", Type: "string", Description: ""},
-		{Name: "Started At", Type: "date", Description: swaggerMetadataDescriptions["startTimestamp"]},
+		{Name: "Kind", Type: "string", Description: "the kind of the task"},
+		{Name: "Status", Type: "string", Description: "whether the task is finished"},
+		{Name: "Rounds", Type: "string", Description: "the finished and the total round number"},
+		{Name: "Latest Result", Type: "string", Description: "the result of the latest round with the agent reports"},
+		{Name: "Failed Nodes", Type: "integer", Description: "the number of the agents which fail in the latest round"},
+		{Name: "Worst P99", Type: "string", Description: "the worst 99th percentile latency of all targets in the latest round"},
+		{Name: "Age", Type: "string", Description: swaggerMetadataDescriptions["creationTimestamp"]},
+		{Name: "Success Rate", Type: "string", Priority: 1, Description: "the rate of the succeeded requests of all targets in the latest round"},
+		{Name: "Last Failure", Type: "string", Priority: 1, Description: "the failure reason of an agent in the latest round"},
 	}
 )
 
@@ -38,18 +52,153 @@ func NewTableGenerator(defaultQualifiedResource schema.GroupResource) *TableGene
 var swaggerMetadataDescriptions = metav1.ObjectMeta{}.SwaggerDoc()
 
 func (t TableGenerator) ConvertToTable(ctx context.Context, obj runtime.Object, tableOptions runtime.Object) (*metav1.Table, error) {
-	table := &metav1.Table{
-		ColumnDefinitions: tableColumnDefinitions,
+	table := &metav1.Table{}
+	if opt, ok := tableOptions.(*metav1.TableOptions); !ok || !opt.NoHeaders {
+		table.ColumnDefinitions = tableColumnDefinitions
 	}
 
 	var err error
 	table.Rows, err = metatable.MetaToTableRow(obj, func(obj runtime.Object, m metav1.Object, name, age string) ([]interface{}, error) {
-		pluginReport := obj.(*v1beta1.KdoctorReport)
+		kdoctorReport, ok := obj.(*v1beta1.KdoctorReport)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object %T of %v", obj, t.defaultQualifiedResource)
+		}
+		s := summarize(kdoctorReport)
 		return []interface{}{
 			name,
-			pluginReport.Status.ToTalRoundNumber,
-			// pluginReport.Spec.StartTimeStamp.Time.UTC().Format(time.RFC3339),
+			kdoctorReport.Task.TaskType,
+			kdoctorReport.Status.Status,
+			fmt.Sprintf("%d/%d", kdoctorReport.Status.FinishedRoundNumber, kdoctorReport.Status.ToTalRoundNumber),
+			s.result,
+			s.failedNodes,
+			s.worstP99,
+			age,
+			s.successRate,
+			s.lastFailure,
 		}, nil
 	})
-	return table, err
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := obj.(metav1.ListInterface); ok {
+		table.ResourceVersion = m.GetResourceVersion()
+		table.Continue = m.GetContinue()
+		table.RemainingItemCount = m.GetRemainingItemCount()
+	}
+	return table, nil
+}
+
+type reportSummary struct {
+	result      string
+	failedNodes int64
+	worstP99    string
+	successRate string
+	lastFailure string
+}
+
+// targetStats is the requests of a target in the agent report
+type targetStats struct {
+	requests  int64
+	successes int64
+	p99       float32
+}
+
+// reportTargets returns the requests of all targets in the agent report
+func reportTargets(report *v1beta1.Report) []targetStats {
+	result := []targetStats{}
+	switch {
+	case report.TaskNetReach != nil:
+		for _, d := range report.TaskNetReach.Detail {
+			result = append(result, targetStats{d.Metrics.RequestCounts, d.Metrics.SuccessCounts, d.Metrics.Latencies.P99})
+		}
+	case report.TaskAppHttpHealthy != nil:
+		for _, d := range report.TaskAppHttpHealthy.Detail {
+			result = append(result, targetStats{d.Metrics.RequestCounts, d.Metrics.SuccessCounts, d.Metrics.Latencies.P99})
+		}
+	case report.TaskNetDNS != nil:
+		for _, d := range report.TaskNetDNS.Detail {
+			result = append(result, targetStats{d.Metrics.RequestCounts, d.Metrics.SuccessCounts, d.Metrics.Latencies.P99})
+		}
+	case report.TaskNetTcp != nil:
+		for _, d := range report.TaskNetTcp.Detail {
+			result = append(result, targetStats{d.Metrics.RequestCounts, d.Metrics.SuccessCounts, d.Metrics.ConnectLatencies.P99})
+		}
+	case report.TaskNetUdp != nil:
+		for _, d := range report.TaskNetUdp.Detail {
+			result = append(result, targetStats{d.Metrics.SendCounts, d.Metrics.ReceivedCounts, d.Metrics.Latencies.P99})
+		}
+	case report.TaskNetDelay != nil:
+		for _, d := range report.TaskNetDelay.Detail {
+			result = append(result, targetStats{d.Metrics.RequestCounts, d.Metrics.SuccessCounts, d.P99})
+		}
+	}
+	return result
+}
+
+// failureReason returns the failure reason of the agent report, or of its task
+func failureReason(report *v1beta1.Report) string {
+	if report.FailedReason != nil && len(*report.FailedReason) != 0 {
+		return *report.FailedReason
+	}
+	var reason *string
+	switch {
+	case report.TaskNetReach != nil:
+		reason = report.TaskNetReach.FailureReason
+	case report.TaskAppHttpHealthy != nil:
+		reason = report.TaskAppHttpHealthy.FailureReason
+	case report.TaskNetDNS != nil:
+		reason = report.TaskNetDNS.FailureReason
+	case report.TaskNetTcp != nil:
+		reason = report.TaskNetTcp.FailureReason
+	case report.TaskNetUdp != nil:
+		reason = report.TaskNetUdp.FailureReason
+	case report.TaskNetDelay != nil:
+		reason = report.TaskNetDelay.FailureReason
+	}
+	if reason != nil {
+		return *reason
+	}
+	return ""
+}
+
+// summarize the agent reports of the latest round, or of the rounds selected by the query
+func summarize(kdoctorReport *v1beta1.KdoctorReport) reportSummary {
+	s := reportSummary{result: none, worstP99: none, successRate: none, lastFailure: none}
+	reports := kdoctorReport.Report.LatestRoundReport
+	if reports == nil {
+		reports = kdoctorReport.Report.RoundReports
+	}
+	if reports == nil || len(*reports) == 0 {
+		return s
+	}
+
+	s.result = roundResultSucceed
+	var requests, successes int64
+	var worstP99 float32
+	for i := range *reports {
+		report := &(*reports)[i]
+		if report.RoundResult != roundResultSucceed {
+			s.result = roundResultFail
+			s.failedNodes++
+			if reason := failureReason(report); len(reason) != 0 {
+				s.lastFailure = reason
+			}
+		}
+		for _, target := range reportTargets(report) {
+			requests += target.requests
+			successes += target.successes
+			if target.p99 > worstP99 {
+				worstP99 = target.p99
+			}
+		}
+	}
+	// the latency is 0 when the latency metric is disabled
+	if worstP99 > 0 {
+		s.worstP99 = fmt.Sprintf("%.2fms", worstP99)
+	}
+	if requests > 0 {
+		s.successRate = fmt.Sprintf("%.2f%%", float64(successes)*100/float64(requests))
+	}
+	return s
 }
@@ -0,0 +1,85 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package printers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kdoctor-io/kdoctor/pkg/apiserver/printers"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+)
+
+var _ = Describe("test table generator", Label("printers"), func() {
+	generator := printers.NewTableGenerator(v1beta1.Resource("kdoctorreports"))
+
+	netReachReport := func(node, result string, requests, successes int64, p99 float32) v1beta1.Report {
+		detail := v1beta1.NetReachTaskDetail{}
+		detail.Metrics.RequestCounts = requests
+		detail.Metrics.SuccessCounts = successes
+		detail.Metrics.Latencies.P99 = p99
+		return v1beta1.Report{
+			NodeName:     node,
+			RoundResult:  result,
+			TaskNetReach: &v1beta1.NetReachTask{Detail: []v1beta1.NetReachTaskDetail{detail}},
+		}
+	}
+
+	It("convert the kdoctorreport", func() {
+		reason := "timeout"
+		failed := netReachReport("worker2", "fail", 100, 50, 80.5)
+		failed.FailedReason = &reason
+		reports := []v1beta1.Report{netReachReport("worker1", "succeed", 100, 100, 10), failed}
+
+		report := &v1beta1.KdoctorReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "netreach-task"},
+			Status:     v1beta1.Status{ToTalRoundNumber: 48, FinishedRoundNumber: 37, Status: "NotFinished"},
+			Task:       v1beta1.TaskInfo{TaskName: "task", TaskType: v1beta1.NetReachTaskName},
+			Report:     v1beta1.Reports{LatestRoundReport: &reports},
+		}
+		table, err := generator.ConvertToTable(context.Background(), report, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(table.ColumnDefinitions).To(HaveLen(10))
+		Expect(table.Rows).To(HaveLen(1))
+		cells := table.Rows[0].Cells
+		Expect(cells[:7]).To(Equal([]interface{}{"netreach-task", v1beta1.NetReachTaskName, "NotFinished", "37/48", "fail", int64(1), "80.50ms"}))
+		Expect(cells[8:]).To(Equal([]interface{}{"75.00%", "timeout"}))
+
+		table, err = generator.ConvertToTable(context.Background(), report, &metav1.TableOptions{NoHeaders: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(table.ColumnDefinitions).To(BeEmpty())
+	})
+
+	It("show none for the worst p99 without the latency metric", func() {
+		reports := []v1beta1.Report{netReachReport("worker1", "succeed", 100, 100, 0)}
+		report := &v1beta1.KdoctorReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "netreach-task"},
+			Task:       v1beta1.TaskInfo{TaskName: "task", TaskType: v1beta1.NetReachTaskName},
+			Report:     v1beta1.Reports{LatestRoundReport: &reports},
+		}
+		table, err := generator.ConvertToTable(context.Background(), report, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(table.Rows[0].Cells[6]).To(Equal("<none>"))
+		Expect(table.Rows[0].Cells[8]).To(Equal("100.00%"))
+	})
+
+	It("convert the list without reports", func() {
+		list := &v1beta1.KdoctorReportList{
+			ListMeta: metav1.ListMeta{ResourceVersion: "10"},
+			Items: []v1beta1.KdoctorReport{
+				{ObjectMeta: metav1.ObjectMeta{Name: "netdns-a"}, Task: v1beta1.TaskInfo{TaskType: v1beta1.NetDNSTaskName}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "nettcp-b"}, Task: v1beta1.TaskInfo{TaskType: v1beta1.NetTcpTaskName}},
+			},
+		}
+		table, err := generator.ConvertToTable(context.Background(), list, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(table.ResourceVersion).To(Equal("10"))
+		Expect(table.Rows).To(HaveLen(2))
+		Expect(table.Rows[1].Cells[4]).To(Equal("<none>"))
+		Expect(table.Rows[1].Cells[5]).To(Equal(int64(0)))
+	})
+})
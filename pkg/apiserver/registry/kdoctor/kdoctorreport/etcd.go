@@ -7,10 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"os"
-	"path"
-	"sort"
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -25,14 +22,15 @@ import (
 	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
 	"k8s.io/klog/v2"
 
+	"github.com/kdoctor-io/kdoctor/pkg/apiserver/printers"
 	"github.com/kdoctor-io/kdoctor/pkg/apiserver/registry"
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/client/clientset/versioned"
+	"github.com/kdoctor-io/kdoctor/pkg/reportManager"
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
 )
 
-const dir = "/report"
-
 func NewREST(clientSet *versioned.Clientset, scheme *runtime.Scheme, optsGetter generic.RESTOptionsGetter) (*registry.REST, error) {
 	strategy := NewStrategy(scheme)
 
@@ -65,7 +63,7 @@ func NewREST(clientSet *versioned.Clientset, scheme *runtime.Scheme, optsGetter
 		Storage:     dryRunnableStorage,
 		DestroyFunc: destroyFunc,
 
-		// TableConvertor: printers.NewTableGenerator(v1beta1.Resource("kdoctorreports")),
+		TableConvertor: printers.NewTableGenerator(v1beta1.Resource("kdoctorreports")),
 	}
 
 	return &registry.REST{Store: store}, nil
@@ -101,176 +99,99 @@ func (p kdoctorReportStorage) Delete(ctx context.Context, key string, out runtim
 	return fmt.Errorf("delete API not implement")
 }
 
+// Watch sends the event of the kdoctorreport when the reportManager collects the reports of a new round,
+// or when the status of the task changes
 func (p kdoctorReportStorage) Watch(ctx context.Context, key string, opts storage.ListOptions) (watch.Interface, error) {
-	return nil, fmt.Errorf("watch API not implement")
+	klog.Infof("Watch called with key: %v on resource %v\n", key, p.resourceName)
+	var revision uint64
+	if len(opts.ResourceVersion) != 0 {
+		rv, err := p.Versioner().ParseResourceVersion(opts.ResourceVersion)
+		if nil != err {
+			return nil, err
+		}
+		revision = rv
+	}
+
+	// subscribe before the initial events, so that no update is missed
+	updates, unsubscribe := reportManager.SubscribeReportUpdate()
+	watchCtx, cancel := context.WithCancel(ctx)
+	w := &reportWatcher{
+		storage: p,
+		ctx:     watchCtx,
+		cancel:  cancel,
+		key:     key,
+		opts:    opts,
+		result:  make(chan watch.Event, watchResultBuffer),
+		updates: updates,
+		sent:    map[string]*v1beta1.KdoctorReport{},
+	}
+	go w.run(revision, unsubscribe)
 
+	return w, nil
 }
 
 func (p kdoctorReportStorage) Get(ctx context.Context, key string, opts storage.GetOptions, objPtr runtime.Object) error {
 	klog.Infof("Get called with key: %v on resource %v\n", key, p.resourceName)
 	kdoctorReport := objPtr.(*v1beta1.KdoctorReport)
-	var taskStatus *crd.TaskStatus
-	var taskType string
-	var creationTimestamp metav1.Time
 	_, taskKindName, err := NamespaceAndNameFromKey(key, false)
 	if nil != err {
 		return err
 	}
-
-	name := taskKindName[strings.Index(taskKindName, "-")+1:]
-
-	// TODO (Icarus9913): we need options to specify which CRD that we are looking for.
-	netdns, err := p.clientSet.KdoctorV1beta1().Netdnses().Get(ctx, name, metav1.GetOptions{})
+	q, err := reportQueryFrom(ctx)
 	if nil != err {
-		if errors.IsNotFound(err) {
-			klog.Infof("no NetDNS %s found", name)
-		} else {
-			return fmt.Errorf("failed to get NetDNS %s, error: %w", name, err)
-		}
-	} else {
-		fmt.Printf("succeed to get NetDNS %s\n", name)
-		taskStatus = netdns.Status.DeepCopy()
-		creationTimestamp = netdns.CreationTimestamp
-		taskType = v1beta1.NetDNSTaskName
-		kdoctorReport.Task.Spec.NetDNSTaskSpec = &netdns.Spec
+		return err
 	}
 
-	netReach, err := p.clientSet.KdoctorV1beta1().NetReaches().Get(ctx, name, metav1.GetOptions{})
+	taskType, name, err := taskFromName(taskKindName)
 	if nil != err {
-		if errors.IsNotFound(err) {
-			klog.Infof("no NetReachHealthy %s found", name)
-		} else {
-			return fmt.Errorf("failed to get NetReachHealthy %s, error: %w", name, err)
-		}
-	} else {
-		fmt.Printf("succeed to get NetReachHealthy %s\n", name)
-		taskStatus = netReach.Status.DeepCopy()
-		creationTimestamp = netReach.CreationTimestamp
-		taskType = v1beta1.NetReachTaskName
-		kdoctorReport.Task.Spec.NetReachTaskSpec = &netReach.Spec
+		return err
 	}
-
-	appHttpHealthy, err := p.clientSet.KdoctorV1beta1().AppHttpHealthies().Get(ctx, name, metav1.GetOptions{})
+	task, err := p.getReportTask(ctx, taskType, name)
 	if nil != err {
 		if errors.IsNotFound(err) {
-			klog.Infof("no HttpAppHealthy %s found", name)
-		} else {
-			return fmt.Errorf("failed to get HttpAppHealthy %s, error: %w", name, err)
+			return errors.NewNotFound(v1beta1.Resource("kdoctorreports"), taskKindName)
 		}
-	} else {
-		fmt.Printf("succeed to get HttpAppHealthy %s\n", name)
-		taskStatus = appHttpHealthy.Status.DeepCopy()
-		creationTimestamp = appHttpHealthy.CreationTimestamp
-		taskType = v1beta1.AppHttpHealthyTaskName
-		kdoctorReport.Task.Spec.AppHttpHealthyTaskSpec = &appHttpHealthy.Spec
+		return fmt.Errorf("failed to get %s %s, error: %w", taskType, name, err)
 	}
+	klog.V(4).Infof("succeed to get %s %s", taskType, name)
 
-	if taskStatus == nil {
-		return fmt.Errorf("no crd instance %s found", name)
-	}
-	var status string
-	if taskStatus.Finish {
-		status = "Finished"
-	} else {
-		status = "NotFinished"
-	}
-
-	var toTalRoundNumber, finishedRoundNumber int64
-	if taskStatus.ExpectedRound != nil {
-		toTalRoundNumber = *taskStatus.ExpectedRound
-	}
-	if taskStatus.DoneRound != nil {
-		finishedRoundNumber = *taskStatus.DoneRound
+	var finishedRoundNumber int64
+	if task.status.DoneRound != nil {
+		finishedRoundNumber = *task.status.DoneRound
 	}
-
-	readDir, err := os.ReadDir(dir)
+	report, err := p.newKdoctorReport(taskType, task, finishedRoundNumber)
 	if nil != err {
-		return fmt.Errorf("failed to read directory %s, error: %w", dir, err)
-	}
-	var fileNameList []string
-	for _, item := range readDir {
-		if item.IsDir() {
-			continue
-		}
-
-		if strings.Contains(item.Name(), name) && !strings.Contains(item.Name(), summary) {
-			fileNameList = append(fileNameList, item.Name())
-		}
-	}
-
-	getReports, latestRoundNumber, err := p.getLatestRoundReports(name, fileNameList)
-	if nil != err {
-		return fmt.Errorf("failed to get latest round reports: %w", err)
+		return err
 	}
-	if getReports == nil {
+	if report.Report.LatestRoundReport == nil {
 		return fmt.Errorf("no '%s' reports found", name)
 	}
-
-	kdoctorReport.CreationTimestamp = creationTimestamp
-	kdoctorReport.Report = v1beta1.Reports{LatestRoundReport: getReports}
-	kdoctorReport.Status = v1beta1.Status{
-		ToTalRoundNumber:    toTalRoundNumber,
-		FinishedRoundNumber: finishedRoundNumber,
-		Status:              status,
-		RoundNumber:         latestRoundNumber,
-	}
-	kdoctorReport.Task.TaskName = name
-	kdoctorReport.Task.TaskType = taskType
-	kdoctorReport.Name = strings.ToLower(taskType) + "-" + name
-	kdoctorReport.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   v1beta1.GroupName,
-		Version: v1beta1.V1betaVersion,
-		Kind:    v1beta1.KindKdoctorReport,
-	})
-
-	return nil
+	*kdoctorReport = *report
+	return p.applyReportQuery(q, kdoctorReport)
 }
 
 const summary = "summary"
 
 func (p kdoctorReportStorage) GetList(ctx context.Context, key string, opts storage.ListOptions, listObj runtime.Object) error {
-	readDir, err := os.ReadDir(dir)
-	if nil != err {
-		return fmt.Errorf("failed to read directory %s, error: %w", dir, err)
-	}
-	var fileNameList []string
-	for _, item := range readDir {
-		if item.IsDir() {
-			continue
-		}
-		fileNameList = append(fileNameList, item.Name())
-	}
-
 	kdoctorReportList := listObj.(*v1beta1.KdoctorReportList)
 	var resList []runtime.Object
-
-	{
-		netDNSKdoctorReports, err := p.getNetDNSKdoctorReports(ctx, fileNameList)
-		if nil != err {
-			return err
-		}
-		for i := range netDNSKdoctorReports {
-			resList = append(resList, netDNSKdoctorReports[i].DeepCopy())
-		}
-	}
-
-	{
-		httpAppHealthyReports, err := p.getHttpAppHealthyReports(ctx, fileNameList)
-		if nil != err {
-			return err
-		}
-		for i := range httpAppHealthyReports {
-			resList = append(resList, httpAppHealthyReports[i].DeepCopy())
-		}
+	q, err := reportQueryFrom(ctx)
+	if nil != err {
+		return err
 	}
+	// the revision is taken before the reports are read, so that the watch from it misses no update
+	revision := reportManager.CurrentRevision()
 
-	{
-		netReachHealthyReports, err := p.getNetReachHealthyReports(ctx, fileNameList)
+	for _, taskType := range taskTypes {
+		reports, err := p.getKindReports(ctx, taskType)
 		if nil != err {
 			return err
 		}
-		for i := range netReachHealthyReports {
-			resList = append(resList, netReachHealthyReports[i].DeepCopy())
+		for i := range reports {
+			if err := p.applyReportQuery(q, reports[i]); nil != err {
+				return err
+			}
+			resList = append(resList, reports[i])
 		}
 	}
 
@@ -278,6 +199,7 @@ func (p kdoctorReportStorage) GetList(ctx context.Context, key string, opts stor
 	if nil != err {
 		return err
 	}
+	kdoctorReportList.ResourceVersion = strconv.FormatUint(revision, 10)
 
 	kdoctorReportList.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
 		Group:   v1beta1.GroupName,
@@ -296,273 +218,316 @@ func (p kdoctorReportStorage) Count(key string) (int64, error) {
 	return 0, fmt.Errorf("Count not supported for key: %s", key)
 }
 
-func (p kdoctorReportStorage) getLatestRoundReports(key string, fileNameList []string) (*[]v1beta1.Report, int64, error) {
-	var reports []v1beta1.Report
-	for _, netDNSFileName := range fileNameList {
-		split := strings.Split(netDNSFileName, "_")
-		if len(split) != 5 {
-			klog.Infof("unrecognized file %s", netDNSFileName)
-			continue
+// getLatestRoundReports returns the agent reports of the latest round which has them, and the round number
+func (p kdoctorReportStorage) getLatestRoundReports(kindName, taskName string) (*[]v1beta1.Report, int64, error) {
+	store := reportStore.GetReportStore()
+	if store == nil {
+		return nil, -1, fmt.Errorf("report store is not ready")
+	}
+	latestRoundNumber, err := store.LatestRound(kindName, taskName)
+	if nil != err {
+		return nil, -1, err
+	}
+
+	// the summary of the controller may come before the agent reports of the round
+	for roundNumber := latestRoundNumber; roundNumber > 0; roundNumber-- {
+		records, err := store.Query(reportStore.Query{KindName: kindName, TaskName: taskName, StartRound: roundNumber, EndRound: roundNumber})
+		if nil != err {
+			return nil, -1, err
 		}
 
-		if key == split[1] {
-			file, err := os.Open(path.Join(dir, netDNSFileName))
-			if nil != err {
-				return nil, -1, err
-			}
-			readAll, err := io.ReadAll(file)
-			if nil != err {
-				return nil, -1, err
+		var reports []v1beta1.Report
+		for _, record := range records {
+			if record.NodeName == summary || isAggregateReport(kindName, record.NodeName) {
+				continue
 			}
-
 			report := v1beta1.Report{}
-			err = json.Unmarshal(readAll, &report)
+			err = json.Unmarshal(record.Data, &report)
 			if nil != err {
 				return nil, -1, err
 			}
 			reports = append(reports, report)
 		}
+		if len(reports) != 0 {
+			return &reports, int64(roundNumber), nil
+		}
 	}
 
-	var latestRoundNumber int64
-	result := func() *[]v1beta1.Report {
-		var latestReports []v1beta1.Report
+	return nil, 0, nil
+}
 
-		for _, tmpReport := range reports {
-			if tmpReport.RoundNumber > latestRoundNumber {
-				latestRoundNumber = tmpReport.RoundNumber
-				latestReports = []v1beta1.Report{tmpReport}
-			} else if tmpReport.RoundNumber == latestRoundNumber {
-				latestReports = append(latestReports, tmpReport)
-			} else {
-				continue
-			}
-		}
-		if len(latestReports) == 0 {
-			return nil
-		}
+// reportTask is the task of a kdoctorreport, with the accessors to the parts which differ between the kinds of tasks
+type reportTask struct {
+	metav1.ObjectMeta
+	status  *crd.TaskStatus
+	setSpec func(spec *v1beta1.TaskSpec)
+}
 
-		return &latestReports
-	}()
+func netReachTask(t *crd.NetReach) *reportTask {
+	return &reportTask{ObjectMeta: t.ObjectMeta, status: &t.Status, setSpec: func(spec *v1beta1.TaskSpec) { spec.NetReachTaskSpec = &t.Spec }}
+}
 
-	return result, latestRoundNumber, nil
+func appHttpHealthyTask(t *crd.AppHttpHealthy) *reportTask {
+	return &reportTask{ObjectMeta: t.ObjectMeta, status: &t.Status, setSpec: func(spec *v1beta1.TaskSpec) { spec.AppHttpHealthyTaskSpec = &t.Spec }}
 }
 
-func (p kdoctorReportStorage) getNetDNSKdoctorReports(ctx context.Context, fileNameList []string) ([]*v1beta1.KdoctorReport, error) {
-	var resList []*v1beta1.KdoctorReport
+func netDNSTask(t *crd.Netdns) *reportTask {
+	return &reportTask{ObjectMeta: t.ObjectMeta, status: &t.Status, setSpec: func(spec *v1beta1.TaskSpec) { spec.NetDNSTaskSpec = &t.Spec }}
+}
 
-	netDNSList, err := p.clientSet.KdoctorV1beta1().Netdnses().List(ctx, metav1.ListOptions{})
-	if nil != err {
-		return nil, err
-	}
+func netTcpTask(t *crd.NetTcp) *reportTask {
+	return &reportTask{ObjectMeta: t.ObjectMeta, status: &t.Status, setSpec: func(spec *v1beta1.TaskSpec) { spec.NetTcpTaskSpec = &t.Spec }}
+}
 
-	netDNSFileNameList := func() []string {
-		var arr []string
-		for _, fileName := range fileNameList {
-			if strings.HasPrefix(fileName, v1beta1.NetDNSTaskName) {
-				if strings.Contains(fileName, summary) {
-					continue
-				}
-				arr = append(arr, fileName)
-			}
+func netUdpTask(t *crd.NetUdp) *reportTask {
+	return &reportTask{ObjectMeta: t.ObjectMeta, status: &t.Status, setSpec: func(spec *v1beta1.TaskSpec) { spec.NetUdpTaskSpec = &t.Spec }}
+}
+
+func netDelayTask(t *crd.NetDelay) *reportTask {
+	return &reportTask{ObjectMeta: t.ObjectMeta, status: &t.Status, setSpec: func(spec *v1beta1.TaskSpec) { spec.NetDelayTaskSpec = &t.Spec }}
+}
+
+// getReportTask gets the task of the kind only
+func (p kdoctorReportStorage) getReportTask(ctx context.Context, taskType, name string) (*reportTask, error) {
+	c := p.clientSet.KdoctorV1beta1()
+	switch taskType {
+	case v1beta1.NetReachTaskName:
+		t, err := c.NetReaches().Get(ctx, name, metav1.GetOptions{})
+		if nil != err {
+			return nil, err
 		}
-		sort.Strings(arr)
-		return arr
-	}()
-
-	for _, netDNS := range netDNSList.Items {
-		tmpNetDNS := netDNS.DeepCopy()
-		if tmpNetDNS.Status.DoneRound == nil || tmpNetDNS.Status.ExpectedRound == nil {
-			klog.Infof("NetDNS %s has no expectedRound or no done round", tmpNetDNS.Name)
-			continue
+		return netReachTask(t), nil
+	case v1beta1.AppHttpHealthyTaskName:
+		t, err := c.AppHttpHealthies().Get(ctx, name, metav1.GetOptions{})
+		if nil != err {
+			return nil, err
 		}
-
-		result, latestRoundNumber, err := p.getLatestRoundReports(tmpNetDNS.Name, netDNSFileNameList)
+		return appHttpHealthyTask(t), nil
+	case v1beta1.NetDNSTaskName:
+		t, err := c.Netdnses().Get(ctx, name, metav1.GetOptions{})
 		if nil != err {
 			return nil, err
 		}
-
-		// TODO (Icarus9913): redesign this
-		var taskStatus string
-		if tmpNetDNS.Status.Finish {
-			taskStatus = "Finished"
-		} else {
-			taskStatus = "NotFinished"
+		return netDNSTask(t), nil
+	case v1beta1.NetTcpTaskName:
+		t, err := c.NetTcps().Get(ctx, name, metav1.GetOptions{})
+		if nil != err {
+			return nil, err
 		}
-
-		var finishedRoundNumber int64
-		if len(tmpNetDNS.Status.History) != 0 {
-			finishedRoundNumber = int64(tmpNetDNS.Status.History[0].RoundNumber)
+		return netTcpTask(t), nil
+	case v1beta1.NetUdpTaskName:
+		t, err := c.NetUdps().Get(ctx, name, metav1.GetOptions{})
+		if nil != err {
+			return nil, err
 		}
-
-		kdoctorReportStatus := v1beta1.Status{
-			ToTalRoundNumber:    *tmpNetDNS.Status.ExpectedRound,
-			FinishedRoundNumber: finishedRoundNumber,
-			Status:              taskStatus,
-			RoundNumber:         latestRoundNumber,
+		return netUdpTask(t), nil
+	case v1beta1.NetDelayTaskName:
+		t, err := c.NetDelays().Get(ctx, name, metav1.GetOptions{})
+		if nil != err {
+			return nil, err
 		}
-
-		kdoctorReport := &v1beta1.KdoctorReport{}
-		kdoctorReport.Name = strings.ToLower(v1beta1.NetDNSTaskName) + "-" + tmpNetDNS.Name
-		kdoctorReport.CreationTimestamp = tmpNetDNS.CreationTimestamp
-		kdoctorReport.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
-			Group:   v1beta1.GroupName,
-			Version: v1beta1.V1betaVersion,
-			Kind:    v1beta1.KindKdoctorReport,
-		})
-		kdoctorReport.Status = kdoctorReportStatus
-		kdoctorReport.Task.Spec.NetDNSTaskSpec = &netDNS.Spec
-		kdoctorReport.Task.TaskName = tmpNetDNS.Name
-		kdoctorReport.Task.TaskType = v1beta1.NetDNSTaskName
-		kdoctorReport.Report = v1beta1.Reports{LatestRoundReport: result}
-		resList = append(resList, kdoctorReport)
+		return netDelayTask(t), nil
 	}
-
-	return resList, nil
+	return nil, fmt.Errorf("unknown task type %s", taskType)
 }
 
-func (p kdoctorReportStorage) getHttpAppHealthyReports(ctx context.Context, fileNameList []string) ([]*v1beta1.KdoctorReport, error) {
-	var resList []*v1beta1.KdoctorReport
-
-	httpAppHealthyList, err := p.clientSet.KdoctorV1beta1().AppHttpHealthies().List(ctx, metav1.ListOptions{})
-	if nil != err {
-		return nil, err
-	}
-
-	httpAppHealthyNameList := func() []string {
-		var arr []string
-		for _, fileName := range fileNameList {
-			if strings.Contains(fileName, v1beta1.AppHttpHealthyTaskName) {
-				if strings.Contains(fileName, summary) {
-					continue
-				}
-				arr = append(arr, fileName)
-			}
+// listReportTasks lists the tasks of the kind
+func (p kdoctorReportStorage) listReportTasks(ctx context.Context, taskType string) ([]*reportTask, error) {
+	c := p.clientSet.KdoctorV1beta1()
+	var tasks []*reportTask
+	switch taskType {
+	case v1beta1.NetReachTaskName:
+		list, err := c.NetReaches().List(ctx, metav1.ListOptions{})
+		if nil != err {
+			return nil, err
 		}
-		sort.Strings(arr)
-		return arr
-	}()
-
-	for _, appHttpHealthy := range httpAppHealthyList.Items {
-		tmpHttpAppHealthy := appHttpHealthy.DeepCopy()
-		if appHttpHealthy.Status.DoneRound == nil || appHttpHealthy.Status.ExpectedRound == nil {
-			klog.Infof("HttpAppHealthy %s has no expectedRound or no done round", tmpHttpAppHealthy.Name)
-			continue
+		for i := range list.Items {
+			tasks = append(tasks, netReachTask(&list.Items[i]))
 		}
-
-		result, latestRoundNumber, err := p.getLatestRoundReports(tmpHttpAppHealthy.Name, httpAppHealthyNameList)
+	case v1beta1.AppHttpHealthyTaskName:
+		list, err := c.AppHttpHealthies().List(ctx, metav1.ListOptions{})
 		if nil != err {
 			return nil, err
 		}
-
-		// TODO (Icarus9913): redesign this
-		var taskStatus string
-		if tmpHttpAppHealthy.Status.Finish {
-			taskStatus = "Finished"
-		} else {
-			taskStatus = "NotFinished"
+		for i := range list.Items {
+			tasks = append(tasks, appHttpHealthyTask(&list.Items[i]))
 		}
+	case v1beta1.NetDNSTaskName:
+		list, err := c.Netdnses().List(ctx, metav1.ListOptions{})
+		if nil != err {
+			return nil, err
+		}
+		for i := range list.Items {
+			tasks = append(tasks, netDNSTask(&list.Items[i]))
+		}
+	case v1beta1.NetTcpTaskName:
+		list, err := c.NetTcps().List(ctx, metav1.ListOptions{})
+		if nil != err {
+			return nil, err
+		}
+		for i := range list.Items {
+			tasks = append(tasks, netTcpTask(&list.Items[i]))
+		}
+	case v1beta1.NetUdpTaskName:
+		list, err := c.NetUdps().List(ctx, metav1.ListOptions{})
+		if nil != err {
+			return nil, err
+		}
+		for i := range list.Items {
+			tasks = append(tasks, netUdpTask(&list.Items[i]))
+		}
+	case v1beta1.NetDelayTaskName:
+		list, err := c.NetDelays().List(ctx, metav1.ListOptions{})
+		if nil != err {
+			return nil, err
+		}
+		for i := range list.Items {
+			tasks = append(tasks, netDelayTask(&list.Items[i]))
+		}
+	default:
+		return nil, fmt.Errorf("unknown task type %s", taskType)
+	}
+	return tasks, nil
+}
+
+// getKindReports returns the kdoctorreports of all tasks of the kind which have started
+func (p kdoctorReportStorage) getKindReports(ctx context.Context, taskType string) ([]*v1beta1.KdoctorReport, error) {
+	tasks, err := p.listReportTasks(ctx, taskType)
+	if nil != err {
+		return nil, err
+	}
 
+	var resList []*v1beta1.KdoctorReport
+	for _, task := range tasks {
+		if task.status.DoneRound == nil || task.status.ExpectedRound == nil {
+			klog.Infof("%s %s has no expectedRound or no done round", taskType, task.Name)
+			continue
+		}
 		var finishedRoundNumber int64
-		if len(tmpHttpAppHealthy.Status.History) != 0 {
-			finishedRoundNumber = int64(tmpHttpAppHealthy.Status.History[0].RoundNumber)
+		if len(task.status.History) != 0 {
+			finishedRoundNumber = int64(task.status.History[0].RoundNumber)
 		}
-
-		kdoctorReportStatus := v1beta1.Status{
-			ToTalRoundNumber:    *tmpHttpAppHealthy.Status.ExpectedRound,
-			FinishedRoundNumber: finishedRoundNumber,
-			Status:              taskStatus,
-			RoundNumber:         latestRoundNumber,
+		kdoctorReport, err := p.newKdoctorReport(taskType, task, finishedRoundNumber)
+		if nil != err {
+			return nil, err
 		}
-
-		kdoctorReport := &v1beta1.KdoctorReport{}
-		kdoctorReport.Name = strings.ToLower(v1beta1.AppHttpHealthyTaskName) + "-" + tmpHttpAppHealthy.Name
-		kdoctorReport.CreationTimestamp = tmpHttpAppHealthy.CreationTimestamp
-		kdoctorReport.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
-			Group:   v1beta1.GroupName,
-			Version: v1beta1.V1betaVersion,
-			Kind:    v1beta1.KindKdoctorReport,
-		})
-		kdoctorReport.Status = kdoctorReportStatus
-		kdoctorReport.Report = v1beta1.Reports{LatestRoundReport: result}
-		kdoctorReport.Task.Spec.AppHttpHealthyTaskSpec = &appHttpHealthy.Spec
-		kdoctorReport.Task.TaskName = tmpHttpAppHealthy.Name
-		kdoctorReport.Task.TaskType = v1beta1.AppHttpHealthyTaskName
 		resList = append(resList, kdoctorReport)
 	}
 
 	return resList, nil
 }
 
-func (p kdoctorReportStorage) getNetReachHealthyReports(ctx context.Context, fileNameList []string) ([]*v1beta1.KdoctorReport, error) {
-	var resList []*v1beta1.KdoctorReport
-
-	netReachHealthyList, err := p.clientSet.KdoctorV1beta1().NetReaches().List(ctx, metav1.ListOptions{})
+// newKdoctorReport builds the kdoctorreport of the task from the reports of its latest round
+func (p kdoctorReportStorage) newKdoctorReport(taskType string, task *reportTask, finishedRoundNumber int64) (*v1beta1.KdoctorReport, error) {
+	result, latestRoundNumber, err := p.getLatestRoundReports(taskType, task.Name)
 	if nil != err {
-		return nil, err
+		return nil, fmt.Errorf("failed to get latest round reports: %w", err)
 	}
 
-	netReachHealthyFileNameList := func() []string {
-		var arr []string
-		for _, fileName := range fileNameList {
-			if strings.HasPrefix(fileName, v1beta1.NetReachTaskName) {
-				if strings.Contains(fileName, summary) {
-					continue
-				}
-				arr = append(arr, fileName)
-			}
-		}
-		sort.Strings(arr)
-		return arr
-	}()
-
-	for _, netReach := range netReachHealthyList.Items {
-		tmpNetReachHealthy := netReach.DeepCopy()
-		if tmpNetReachHealthy.Status.DoneRound == nil || tmpNetReachHealthy.Status.ExpectedRound == nil {
-			klog.Infof("NetReachHealthy %s has no expectedRound or no done round", tmpNetReachHealthy.Name)
-			continue
-		}
+	status := "NotFinished"
+	if task.status.Finish {
+		status = "Finished"
+	}
+	var toTalRoundNumber int64
+	if task.status.ExpectedRound != nil {
+		toTalRoundNumber = *task.status.ExpectedRound
+	}
 
-		result, latestRoundNumber, err := p.getLatestRoundReports(tmpNetReachHealthy.Name, netReachHealthyFileNameList)
+	kdoctorReport := &v1beta1.KdoctorReport{}
+	kdoctorReport.Name = strings.ToLower(taskType) + "-" + task.Name
+	kdoctorReport.CreationTimestamp = task.CreationTimestamp
+	kdoctorReport.ResourceVersion = strconv.FormatUint(reportManager.TaskRevision(taskType, task.Name), 10)
+	kdoctorReport.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   v1beta1.GroupName,
+		Version: v1beta1.V1betaVersion,
+		Kind:    v1beta1.KindKdoctorReport,
+	})
+	kdoctorReport.Status = v1beta1.Status{
+		ToTalRoundNumber:    toTalRoundNumber,
+		FinishedRoundNumber: finishedRoundNumber,
+		Status:              status,
+		RoundNumber:         latestRoundNumber,
+	}
+	task.setSpec(&kdoctorReport.Task.Spec)
+	kdoctorReport.Task.TaskName = task.Name
+	kdoctorReport.Task.TaskType = taskType
+	kdoctorReport.Report = v1beta1.Reports{LatestRoundReport: result}
+	switch taskType {
+	case v1beta1.NetDelayTaskName:
+		kdoctorReport.Report.NetDelayMatrix, err = p.getNetDelayMatrix(task.Name, latestRoundNumber)
 		if nil != err {
-			return nil, err
+			return nil, fmt.Errorf("failed to get the matrix of latest round: %w", err)
 		}
-
-		// TODO (Icarus9913): redesign this
-		var taskStatus string
-		if tmpNetReachHealthy.Status.Finish {
-			taskStatus = "Finished"
-		} else {
-			taskStatus = "NotFinished"
+	case v1beta1.NetDNSTaskName:
+		kdoctorReport.Report.NetDnsDetect, err = p.getNetDnsDetectSummary(task.Name, latestRoundNumber)
+		if nil != err {
+			return nil, fmt.Errorf("failed to get the detect summary of latest round: %w", err)
 		}
+	}
+	kdoctorReport.Report.BaselineComparison, err = p.getBaselineComparison(taskType, task.Name, latestRoundNumber)
+	if nil != err {
+		return nil, fmt.Errorf("failed to get the baseline comparison of latest round: %w", err)
+	}
 
-		var finishedRoundNumber int64
-		if len(tmpNetReachHealthy.Status.History) != 0 {
-			finishedRoundNumber = int64(tmpNetReachHealthy.Status.History[0].RoundNumber)
-		}
+	return kdoctorReport, nil
+}
 
-		kdoctorReportStatus := v1beta1.Status{
-			ToTalRoundNumber:    *tmpNetReachHealthy.Status.ExpectedRound,
-			FinishedRoundNumber: finishedRoundNumber,
-			Status:              taskStatus,
-			RoundNumber:         latestRoundNumber,
-		}
+// isAggregateReport checks whether the report is aggregated by the controller from the reports of all agents,
+// such as the node-to-node matrix of NetDelay, the detect summary of Netdns and the baseline comparison of any task
+func isAggregateReport(kindName, nodeName string) bool {
+	return (kindName == v1beta1.NetDelayTaskName && nodeName == v1beta1.NetDelayMatrixNodeName) ||
+		(kindName == v1beta1.NetDNSTaskName && nodeName == v1beta1.NetDnsDetectNodeName) ||
+		nodeName == v1beta1.BaselineNodeName
+}
 
-		kdoctorReport := &v1beta1.KdoctorReport{}
-		kdoctorReport.Name = strings.ToLower(v1beta1.NetReachTaskName) + "-" + tmpNetReachHealthy.Name
-		kdoctorReport.CreationTimestamp = tmpNetReachHealthy.CreationTimestamp
-		kdoctorReport.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
-			Group:   v1beta1.GroupName,
-			Version: v1beta1.V1betaVersion,
-			Kind:    v1beta1.KindKdoctorReport,
-		})
-		kdoctorReport.Status = kdoctorReportStatus
-		kdoctorReport.Report = v1beta1.Reports{LatestRoundReport: result}
-		kdoctorReport.Task.Spec.NetReachTaskSpec = &netReach.Spec
-		kdoctorReport.Task.TaskName = tmpNetReachHealthy.Name
-		kdoctorReport.Task.TaskType = v1beta1.NetReachTaskName
-		resList = append(resList, kdoctorReport)
+// readAggregateReport reads the aggregated report of the round into out, and returns false if it is not found
+func (p kdoctorReportStorage) readAggregateReport(kindName, taskName string, roundNumber int64, nodeName string, out interface{}) (bool, error) {
+	store := reportStore.GetReportStore()
+	if store == nil {
+		return false, fmt.Errorf("report store is not ready")
+	}
+	records, err := store.Query(reportStore.Query{
+		KindName:   kindName,
+		TaskName:   taskName,
+		StartRound: int(roundNumber),
+		EndRound:   int(roundNumber),
+		NodeName:   nodeName,
+		Limit:      1,
+	})
+	if nil != err || len(records) == 0 {
+		return false, err
 	}
 
-	return resList, nil
+	err = json.Unmarshal(records[0].Data, out)
+	if nil != err {
+		return false, err
+	}
+	return true, nil
+}
+
+func (p kdoctorReportStorage) getNetDelayMatrix(key string, roundNumber int64) (*v1beta1.NetDelayMatrix, error) {
+	matrix := v1beta1.NetDelayMatrix{}
+	found, err := p.readAggregateReport(v1beta1.NetDelayTaskName, key, roundNumber, v1beta1.NetDelayMatrixNodeName, &matrix)
+	if nil != err || !found {
+		return nil, err
+	}
+	return &matrix, nil
+}
+
+func (p kdoctorReportStorage) getNetDnsDetectSummary(key string, roundNumber int64) (*v1beta1.NetDnsDetectSummary, error) {
+	summary := v1beta1.NetDnsDetectSummary{}
+	found, err := p.readAggregateReport(v1beta1.NetDNSTaskName, key, roundNumber, v1beta1.NetDnsDetectNodeName, &summary)
+	if nil != err || !found {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (p kdoctorReportStorage) getBaselineComparison(kindName, key string, roundNumber int64) (*v1beta1.BaselineComparison, error) {
+	comparison := v1beta1.BaselineComparison{}
+	found, err := p.readAggregateReport(kindName, key, roundNumber, v1beta1.BaselineNodeName, &comparison)
+	if nil != err || !found {
+		return nil, err
+	}
+	return &comparison, nil
 }
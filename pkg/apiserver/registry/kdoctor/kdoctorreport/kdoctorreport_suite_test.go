@@ -0,0 +1,19 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+package kdoctorreport
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestKdoctorReport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "kdoctorreport Suite")
+}
+
+var _ = BeforeSuite(func() {
+	// nothing to do
+})
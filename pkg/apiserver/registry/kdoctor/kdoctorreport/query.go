@@ -0,0 +1,185 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorreport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kdoctor-io/kdoctor/pkg/apiserver/request"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
+)
+
+// the query parameters to select the reports
+const (
+	QueryRound      = "round"
+	QueryStartRound = "startRound"
+	QueryEndRound   = "endRound"
+	QueryNode       = "node"
+	QueryFailedOnly = "failedOnly"
+)
+
+type reportQuery struct {
+	// the inclusive range of the rounds, and 0 means not set
+	startRound int64
+	endRound   int64
+	nodeName   string
+	failedOnly bool
+}
+
+func parseRoundNumber(name, value string) (int64, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if nil != err || n <= 0 {
+		return 0, errors.NewBadRequest(fmt.Sprintf("query parameter %s=%q must be a positive integer", name, value))
+	}
+	return n, nil
+}
+
+// reportQueryFrom parses the query parameters of the request
+func reportQueryFrom(ctx context.Context) (*reportQuery, error) {
+	q := &reportQuery{}
+	values := request.RequestQueryFrom(ctx)
+	if values == nil {
+		return q, nil
+	}
+
+	var err error
+	if v := values.Get(QueryRound); len(v) != 0 {
+		if values.Has(QueryStartRound) || values.Has(QueryEndRound) {
+			return nil, errors.NewBadRequest(fmt.Sprintf("query parameter %s conflicts with %s and %s", QueryRound, QueryStartRound, QueryEndRound))
+		}
+		if q.startRound, err = parseRoundNumber(QueryRound, v); nil != err {
+			return nil, err
+		}
+		q.endRound = q.startRound
+	}
+	if v := values.Get(QueryStartRound); len(v) != 0 {
+		if q.startRound, err = parseRoundNumber(QueryStartRound, v); nil != err {
+			return nil, err
+		}
+	}
+	if v := values.Get(QueryEndRound); len(v) != 0 {
+		if q.endRound, err = parseRoundNumber(QueryEndRound, v); nil != err {
+			return nil, err
+		}
+	}
+	if q.startRound > 0 && q.endRound > 0 && q.startRound > q.endRound {
+		return nil, errors.NewBadRequest(fmt.Sprintf("query parameter %s=%d is bigger than %s=%d", QueryStartRound, q.startRound, QueryEndRound, q.endRound))
+	}
+
+	q.nodeName = values.Get(QueryNode)
+	if v := values.Get(QueryFailedOnly); len(v) != 0 {
+		if q.failedOnly, err = strconv.ParseBool(v); nil != err {
+			return nil, errors.NewBadRequest(fmt.Sprintf("query parameter %s=%q must be a boolean", QueryFailedOnly, v))
+		}
+	}
+	return q, nil
+}
+
+// selectRounds returns whether the rounds are selected, or else the latest round is used
+func (q *reportQuery) selectRounds() bool {
+	return q.startRound > 0 || q.endRound > 0
+}
+
+func (q *reportQuery) matchRound(roundNumber int64) bool {
+	return (q.startRound == 0 || roundNumber >= q.startRound) && (q.endRound == 0 || roundNumber <= q.endRound)
+}
+
+func (q *reportQuery) matchReport(report *v1beta1.Report) bool {
+	if len(q.nodeName) != 0 && report.NodeName != q.nodeName {
+		return false
+	}
+	if q.failedOnly && report.RoundResult == string(plugintypes.RoundResultSucceed) {
+		return false
+	}
+	return true
+}
+
+func (q *reportQuery) filterReports(reports *[]v1beta1.Report) *[]v1beta1.Report {
+	if reports == nil || (len(q.nodeName) == 0 && !q.failedOnly) {
+		return reports
+	}
+	result := []v1beta1.Report{}
+	for i := range *reports {
+		if q.matchReport(&(*reports)[i]) {
+			result = append(result, (*reports)[i])
+		}
+	}
+	return &result
+}
+
+// getRoundReports returns the agent reports of the selected rounds, and the last round which has reports
+func (p kdoctorReportStorage) getRoundReports(kindName, taskName string, q *reportQuery) (*[]v1beta1.Report, int64, error) {
+	store := reportStore.GetReportStore()
+	if store == nil {
+		return nil, -1, fmt.Errorf("report store is not ready")
+	}
+	records, err := store.Query(reportStore.Query{
+		KindName:   kindName,
+		TaskName:   taskName,
+		StartRound: int(q.startRound),
+		EndRound:   int(q.endRound),
+		NodeName:   q.nodeName,
+	})
+	if nil != err {
+		return nil, -1, err
+	}
+
+	reports := []v1beta1.Report{}
+	var lastRoundNumber int64
+	for _, record := range records {
+		if record.NodeName == summary || isAggregateReport(kindName, record.NodeName) {
+			continue
+		}
+		report := v1beta1.Report{}
+		if err := json.Unmarshal(record.Data, &report); nil != err {
+			return nil, -1, err
+		}
+		lastRoundNumber = int64(record.RoundNumber)
+		if q.matchReport(&report) {
+			reports = append(reports, report)
+		}
+	}
+	return &reports, lastRoundNumber, nil
+}
+
+// applyReportQuery replaces the reports of the latest round with the ones selected by the query
+func (p kdoctorReportStorage) applyReportQuery(q *reportQuery, kdoctorReport *v1beta1.KdoctorReport) error {
+	if !q.selectRounds() {
+		kdoctorReport.Report.LatestRoundReport = q.filterReports(kdoctorReport.Report.LatestRoundReport)
+		return nil
+	}
+
+	reports, lastRoundNumber, err := p.getRoundReports(kdoctorReport.Task.TaskType, kdoctorReport.Task.TaskName, q)
+	if nil != err {
+		return fmt.Errorf("failed to get round reports: %w", err)
+	}
+	kdoctorReport.Report.LatestRoundReport = nil
+	kdoctorReport.Report.RoundReports = reports
+
+	// the aggregated report is of the last selected round
+	kdoctorReport.Report.NetDelayMatrix = nil
+	kdoctorReport.Report.NetDnsDetect = nil
+	kdoctorReport.Report.BaselineComparison = nil
+	if lastRoundNumber <= 0 {
+		return nil
+	}
+	switch kdoctorReport.Task.TaskType {
+	case v1beta1.NetDelayTaskName:
+		kdoctorReport.Report.NetDelayMatrix, err = p.getNetDelayMatrix(kdoctorReport.Task.TaskName, lastRoundNumber)
+	case v1beta1.NetDNSTaskName:
+		kdoctorReport.Report.NetDnsDetect, err = p.getNetDnsDetectSummary(kdoctorReport.Task.TaskName, lastRoundNumber)
+	}
+	if nil != err {
+		return err
+	}
+	kdoctorReport.Report.BaselineComparison, err = p.getBaselineComparison(kdoctorReport.Task.TaskType, kdoctorReport.Task.TaskName, lastRoundNumber)
+	return err
+}
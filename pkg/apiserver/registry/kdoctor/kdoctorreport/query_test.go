@@ -0,0 +1,135 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorreport
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kdoctor-io/kdoctor/pkg/apiserver/request"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
+)
+
+var _ = Describe("test report query", Label("kdoctorreport query"), func() {
+
+	parse := func(query string) (*reportQuery, error) {
+		values, err := url.ParseQuery(query)
+		Expect(err).NotTo(HaveOccurred())
+		return reportQueryFrom(request.WithRequestQuery(context.Background(), values))
+	}
+
+	It("parse the query parameters", func() {
+		q, err := reportQueryFrom(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(q.selectRounds()).To(BeFalse())
+
+		q, err = parse("round=37")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(q.startRound).To(BeEquivalentTo(37))
+		Expect(q.endRound).To(BeEquivalentTo(37))
+
+		q, err = parse("startRound=30&node=worker1&failedOnly=true")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(q.selectRounds()).To(BeTrue())
+		Expect(q.matchRound(48)).To(BeTrue())
+		Expect(q.matchRound(29)).To(BeFalse())
+		Expect(q.matchReport(&v1beta1.Report{NodeName: "worker1", RoundResult: "fail"})).To(BeTrue())
+		Expect(q.matchReport(&v1beta1.Report{NodeName: "worker1", RoundResult: "succeed"})).To(BeFalse())
+		Expect(q.matchReport(&v1beta1.Report{NodeName: "worker2", RoundResult: "fail"})).To(BeFalse())
+
+		for _, query := range []string{"round=0", "round=a", "round=1&endRound=2", "startRound=3&endRound=2", "failedOnly=yes"} {
+			_, err = parse(query)
+			Expect(errors.IsBadRequest(err)).To(BeTrue(), "query %s", query)
+		}
+	})
+
+	It("filter the reports", func() {
+		reports := &[]v1beta1.Report{
+			{NodeName: "worker1", RoundResult: "succeed"},
+			{NodeName: "worker2", RoundResult: "fail"},
+		}
+		q, err := parse("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(q.filterReports(reports)).To(Equal(reports))
+
+		q, err = parse("failedOnly=true")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*q.filterReports(reports)).To(ConsistOf(v1beta1.Report{NodeName: "worker2", RoundResult: "fail"}))
+	})
+
+	It("parse the name of the kdoctorreport", func() {
+		taskType, taskName, err := taskFromName("netdns-nightly-check")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taskType).To(Equal(v1beta1.NetDNSTaskName))
+		Expect(taskName).To(Equal("nightly-check"))
+
+		_, _, err = taskFromName("unknown-task")
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		_, _, err = taskFromName("netdns-")
+		Expect(errors.IsBadRequest(err)).To(BeTrue())
+	})
+
+	It("summarize the rounds", func() {
+		now := time.Now()
+		record := func(round int, node string, data interface{}) reportStore.Record {
+			b, err := json.Marshal(data)
+			Expect(err).NotTo(HaveOccurred())
+			return reportStore.Record{KindName: v1beta1.NetDNSTaskName, TaskName: "task", RoundNumber: round, NodeName: node, Data: b}
+		}
+		agent := func(node, result string) v1beta1.Report {
+			return v1beta1.Report{
+				NodeName:       node,
+				RoundResult:    result,
+				StartTimeStamp: metav1.NewTime(now),
+				EndTimeStamp:   metav1.NewTime(now.Add(time.Minute)),
+			}
+		}
+		records := []reportStore.Record{
+			record(1, "summary", plugintypes.PluginReport{RoundResult: plugintypes.RoundResultSucceed, StartTimeStamp: now, EndTimeStamp: now}),
+			record(1, "worker1", agent("worker1", "succeed")),
+			record(2, v1beta1.NetDnsDetectNodeName, v1beta1.NetDnsDetectSummary{}),
+			record(2, "summary", plugintypes.PluginReport{RoundResult: plugintypes.RoundResultFail, FailedReason: "timeout"}),
+			record(2, "worker1", agent("worker1", "succeed")),
+			record(2, "worker2", agent("worker2", "fail")),
+			record(3, "worker1", agent("worker1", "succeed")),
+		}
+
+		q, err := parse("")
+		Expect(err).NotTo(HaveOccurred())
+		rounds, err := summarizeRounds(v1beta1.NetDNSTaskName, records, q)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rounds).To(HaveLen(3))
+		Expect(rounds[0].RoundResult).To(Equal("succeed"))
+		Expect(rounds[0].AgentNumber).To(BeEquivalentTo(1))
+		Expect(rounds[1].RoundResult).To(Equal("fail"))
+		Expect(*rounds[1].FailedReason).To(Equal("timeout"))
+		Expect(rounds[1].AgentNumber).To(BeEquivalentTo(2))
+		Expect(rounds[1].FailedNodeList).To(Equal([]string{"worker2"}))
+		Expect(rounds[2].RoundResult).To(Equal(roundResultOngoing))
+		Expect(rounds[2].EndTimeStamp.Time.Equal(now.Add(time.Minute).Truncate(time.Second))).To(BeTrue())
+
+		q, err = parse("failedOnly=true")
+		Expect(err).NotTo(HaveOccurred())
+		rounds, err = summarizeRounds(v1beta1.NetDNSTaskName, records, q)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rounds).To(HaveLen(1))
+		Expect(rounds[0].RoundNumber).To(BeEquivalentTo(2))
+
+		q, err = parse("node=worker1")
+		Expect(err).NotTo(HaveOccurred())
+		rounds, err = summarizeRounds(v1beta1.NetDNSTaskName, records, q)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rounds[1].AgentNumber).To(BeEquivalentTo(1))
+		Expect(rounds[1].FailedAgentNumber).To(BeZero())
+	})
+})
@@ -0,0 +1,149 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kdoctor-io/kdoctor/pkg/apiserver/request"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/reportRender"
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
+)
+
+// QueryFormat selects the format of the rendered report, html or md
+const QueryFormat = "format"
+
+// the number of the latest rounds rendered when no round is selected
+const renderDefaultRounds = 20
+
+// RenderREST serves the subresource kdoctorreports/<name>/render, which renders the rounds of the task
+// as a human-readable HTML or Markdown document. It takes the same query parameters as the subresource rounds
+type RenderREST struct{}
+
+var _ rest.Storage = &RenderREST{}
+var _ rest.Connecter = &RenderREST{}
+
+func NewRenderREST() *RenderREST {
+	return &RenderREST{}
+}
+
+func (r *RenderREST) New() runtime.Object {
+	return &v1beta1.KdoctorReport{}
+}
+
+func (r *RenderREST) Destroy() {
+}
+
+func (r *RenderREST) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, false, ""
+}
+
+func (r *RenderREST) ConnectMethods() []string {
+	return []string{http.MethodGet}
+}
+
+func (r *RenderREST) Connect(ctx context.Context, name string, _ runtime.Object, _ rest.Responder) (http.Handler, error) {
+	format := reportRender.FormatHTML
+	if v := request.RequestQueryFrom(ctx).Get(QueryFormat); len(v) != 0 {
+		format = v
+	}
+	contentType, err := reportRender.ContentType(format)
+	if nil != err {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	taskType, taskName, err := taskFromName(name)
+	if nil != err {
+		return nil, err
+	}
+	q, err := reportQueryFrom(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	store := reportStore.GetReportStore()
+	if store == nil {
+		return nil, fmt.Errorf("report store is not ready")
+	}
+	if !q.selectRounds() {
+		latest, err := store.LatestRound(taskType, taskName)
+		if nil != err {
+			return nil, err
+		}
+		if latest > renderDefaultRounds {
+			q.startRound = int64(latest - renderDefaultRounds + 1)
+		}
+	}
+	records, err := store.Query(reportStore.Query{
+		KindName:   taskType,
+		TaskName:   taskName,
+		StartRound: int(q.startRound),
+		EndRound:   int(q.endRound),
+	})
+	if nil != err {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.NewNotFound(v1beta1.Resource("kdoctorreports"), name)
+	}
+
+	report, err := buildTaskReport(taskType, taskName, records, q)
+	if nil != err {
+		return nil, err
+	}
+	// render before writing the response, so a failure is still returned as an api error
+	buf := &bytes.Buffer{}
+	if err := reportRender.Render(buf, report, format); nil != err {
+		return nil, fmt.Errorf("failed to render the report of %s, error: %w", name, err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf.Bytes())
+	}), nil
+}
+
+// buildTaskReport builds the rounds to be rendered from the records in the order of the round number
+func buildTaskReport(taskType, taskName string, records []reportStore.Record, q *reportQuery) (*reportRender.TaskReport, error) {
+	summaries, err := summarizeRounds(taskType, records, q)
+	if nil != err {
+		return nil, err
+	}
+
+	reports := map[int64][]v1beta1.Report{}
+	for _, record := range records {
+		if record.NodeName == summary || isAggregateReport(taskType, record.NodeName) {
+			continue
+		}
+		if len(q.nodeName) != 0 && record.NodeName != q.nodeName {
+			continue
+		}
+		report := v1beta1.Report{}
+		if err := json.Unmarshal(record.Data, &report); nil != err {
+			return nil, fmt.Errorf("failed to parse the report of node %s round %d, error: %w", record.NodeName, record.RoundNumber, err)
+		}
+		reports[int64(record.RoundNumber)] = append(reports[int64(record.RoundNumber)], report)
+	}
+
+	result := &reportRender.TaskReport{
+		TaskType:      taskType,
+		TaskName:      taskName,
+		GeneratedTime: time.Now(),
+	}
+	for _, s := range summaries {
+		result.Rounds = append(result.Rounds, reportRender.NewRound(s, reports[s.RoundNumber]))
+	}
+	return result, nil
+}
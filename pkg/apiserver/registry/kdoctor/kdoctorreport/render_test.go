@@ -0,0 +1,67 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorreport
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kdoctor-io/kdoctor/pkg/apiserver/request"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
+)
+
+var _ = Describe("test report render", Label("kdoctorreport render"), func() {
+
+	record := func(round int, node string, data interface{}) reportStore.Record {
+		b, err := json.Marshal(data)
+		Expect(err).NotTo(HaveOccurred())
+		return reportStore.Record{KindName: v1beta1.NetReachTaskName, TaskName: "task", RoundNumber: round, NodeName: node, Data: b}
+	}
+	agent := func(node, result string) v1beta1.Report {
+		return v1beta1.Report{
+			NodeName:    node,
+			RoundResult: result,
+			TaskNetReach: &v1beta1.NetReachTask{Detail: []v1beta1.NetReachTaskDetail{{
+				TargetName: "clusterIP",
+				Metrics:    v1beta1.HttpMetrics{Errors: map[string]int{"connection refused": 3}},
+			}}},
+		}
+	}
+
+	It("build the rounds to render", func() {
+		records := []reportStore.Record{
+			record(1, "summary", plugintypes.PluginReport{RoundResult: plugintypes.RoundResultSucceed}),
+			record(1, "worker1", agent("worker1", "succeed")),
+			record(2, "summary", plugintypes.PluginReport{RoundResult: plugintypes.RoundResultFail}),
+			record(2, "worker1", agent("worker1", "succeed")),
+			record(2, "worker2", agent("worker2", "fail")),
+		}
+
+		report, err := buildTaskReport(v1beta1.NetReachTaskName, "task", records, &reportQuery{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Rounds).To(HaveLen(2))
+		Expect(report.Rounds[1].Nodes).To(HaveLen(2))
+		Expect(report.Rounds[1].FailedNodes()).To(Equal([]string{"worker2"}))
+		Expect(report.Errors()[0].Count).To(Equal(9))
+
+		report, err = buildTaskReport(v1beta1.NetReachTaskName, "task", records, &reportQuery{nodeName: "worker2", failedOnly: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Rounds).To(HaveLen(1))
+		Expect(report.Rounds[0].RoundNumber).To(BeEquivalentTo(2))
+		Expect(report.Rounds[0].Nodes).To(HaveLen(1))
+	})
+
+	It("reject the unknown format", func() {
+		ctx := request.WithRequestQuery(context.Background(), url.Values{QueryFormat: []string{"pdf"}})
+		_, err := NewRenderREST().Connect(ctx, "netreach-task", nil, nil)
+		Expect(errors.IsBadRequest(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,187 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorreport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
+)
+
+const roundResultOngoing = "ongoing"
+
+var taskTypes = []string{
+	v1beta1.NetReachTaskName,
+	v1beta1.AppHttpHealthyTaskName,
+	v1beta1.NetDNSTaskName,
+	v1beta1.NetTcpTaskName,
+	v1beta1.NetUdpTaskName,
+	v1beta1.NetDelayTaskName,
+}
+
+// RoundsREST serves the subresource kdoctorreports/<name>/rounds, which lists the summary of each round of the task.
+// The query parameters round, startRound and endRound select the rounds, node counts the report of the node only,
+// and failedOnly lists the failed rounds only
+type RoundsREST struct{}
+
+var _ rest.Storage = &RoundsREST{}
+var _ rest.Getter = &RoundsREST{}
+
+func NewRoundsREST() *RoundsREST {
+	return &RoundsREST{}
+}
+
+func (r *RoundsREST) New() runtime.Object {
+	return &v1beta1.KdoctorReportRounds{}
+}
+
+func (r *RoundsREST) Destroy() {
+}
+
+// taskFromName parses the name of the kdoctorreport, which consists of ${TaskKind}-${TaskName}
+func taskFromName(name string) (taskType, taskName string, err error) {
+	index := strings.Index(name, "-")
+	if index <= 0 || index == len(name)-1 {
+		return "", "", errors.NewBadRequest(fmt.Sprintf("invalid kdoctorreport name %q", name))
+	}
+	for _, t := range taskTypes {
+		if strings.ToLower(t) == name[:index] {
+			return t, name[index+1:], nil
+		}
+	}
+	return "", "", errors.NewNotFound(v1beta1.Resource("kdoctorreports"), name)
+}
+
+func (r *RoundsREST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	taskType, taskName, err := taskFromName(name)
+	if nil != err {
+		return nil, err
+	}
+	q, err := reportQueryFrom(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	store := reportStore.GetReportStore()
+	if store == nil {
+		return nil, fmt.Errorf("report store is not ready")
+	}
+	records, err := store.Query(reportStore.Query{
+		KindName:   taskType,
+		TaskName:   taskName,
+		StartRound: int(q.startRound),
+		EndRound:   int(q.endRound),
+	})
+	if nil != err {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.NewNotFound(v1beta1.Resource("kdoctorreports"), name)
+	}
+
+	rounds, err := summarizeRounds(taskType, records, q)
+	if nil != err {
+		return nil, err
+	}
+
+	result := &v1beta1.KdoctorReportRounds{
+		TaskName: taskName,
+		TaskType: taskType,
+		Rounds:   rounds,
+	}
+	result.Name = name
+	result.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   v1beta1.GroupName,
+		Version: v1beta1.V1betaVersion,
+		Kind:    v1beta1.KindKdoctorReportRounds,
+	})
+	return result, nil
+}
+
+// summarizeRounds builds the summary of each round from the records in the order of the round number
+func summarizeRounds(taskType string, records []reportStore.Record, q *reportQuery) ([]v1beta1.RoundSummary, error) {
+	rounds := []v1beta1.RoundSummary{}
+	var current *v1beta1.RoundSummary
+	// the controller summary of the current round is found
+	var summarized bool
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+		if !summarized {
+			current.RoundResult = roundResultOngoing
+			if current.FailedAgentNumber > 0 {
+				current.RoundResult = string(plugintypes.RoundResultFail)
+			}
+		}
+		if !q.failedOnly || current.RoundResult == string(plugintypes.RoundResultFail) {
+			rounds = append(rounds, *current)
+		}
+	}
+
+	for _, record := range records {
+		if current == nil || current.RoundNumber != int64(record.RoundNumber) {
+			finish()
+			current = &v1beta1.RoundSummary{RoundNumber: int64(record.RoundNumber)}
+			summarized = false
+		}
+
+		switch {
+		case record.NodeName == summary:
+			report := plugintypes.PluginReport{}
+			if err := json.Unmarshal(record.Data, &report); nil != err {
+				return nil, fmt.Errorf("failed to parse the summary of round %d, error: %w", record.RoundNumber, err)
+			}
+			summarized = true
+			current.RoundResult = string(report.RoundResult)
+			if len(report.FailedReason) != 0 {
+				current.FailedReason = &report.FailedReason
+			}
+			start := metav1.NewTime(report.StartTimeStamp)
+			end := metav1.NewTime(report.EndTimeStamp)
+			current.StartTimeStamp = &start
+			current.EndTimeStamp = &end
+
+		case isAggregateReport(taskType, record.NodeName):
+			continue
+
+		default:
+			if len(q.nodeName) != 0 && record.NodeName != q.nodeName {
+				continue
+			}
+			report := v1beta1.Report{}
+			if err := json.Unmarshal(record.Data, &report); nil != err {
+				return nil, fmt.Errorf("failed to parse the report of node %s round %d, error: %w", record.NodeName, record.RoundNumber, err)
+			}
+			current.AgentNumber++
+			if report.RoundResult != string(plugintypes.RoundResultSucceed) {
+				current.FailedAgentNumber++
+				current.FailedNodeList = append(current.FailedNodeList, report.NodeName)
+			}
+			if current.StartTimeStamp == nil {
+				start := report.StartTimeStamp
+				current.StartTimeStamp = &start
+			}
+			if !summarized && (current.EndTimeStamp == nil || current.EndTimeStamp.Before(&report.EndTimeStamp)) {
+				end := report.EndTimeStamp
+				current.EndTimeStamp = &end
+			}
+		}
+	}
+	finish()
+
+	return rounds, nil
+}
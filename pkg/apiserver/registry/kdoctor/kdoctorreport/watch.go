@@ -0,0 +1,223 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorreport
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/klog/v2"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/reportManager"
+)
+
+const watchResultBuffer = 100
+
+// reportWatcher rebuilds the kdoctorreport of the task notified by the reportManager,
+// and sends the event when the kdoctorreport changes
+type reportWatcher struct {
+	storage kdoctorReportStorage
+	ctx     context.Context
+	cancel  context.CancelFunc
+	key     string
+	opts    storage.ListOptions
+	result  chan watch.Event
+	updates <-chan reportManager.ReportUpdate
+	// the kdoctorreport sent to the client, by name
+	sent map[string]*v1beta1.KdoctorReport
+}
+
+var _ watch.Interface = &reportWatcher{}
+
+func (w *reportWatcher) Stop() {
+	w.cancel()
+}
+
+func (w *reportWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func reportName(kindName, taskName string) string {
+	return strings.ToLower(kindName) + "-" + taskName
+}
+
+func (w *reportWatcher) objectKey(name string) string {
+	if w.opts.Recursive {
+		return strings.TrimSuffix(w.key, "/") + "/" + name
+	}
+	return w.key
+}
+
+// matches returns whether the watcher cares about the kdoctorreport
+func (w *reportWatcher) matches(name string) bool {
+	if w.opts.Recursive {
+		return true
+	}
+	_, keyName, err := NamespaceAndNameFromKey(w.key, false)
+	return err == nil && keyName == name
+}
+
+func (w *reportWatcher) send(eventType watch.EventType, obj runtime.Object) bool {
+	select {
+	case w.result <- watch.Event{Type: eventType, Object: obj}:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+// snapshot returns the current kdoctorreports which the watcher cares about
+func (w *reportWatcher) snapshot() ([]*v1beta1.KdoctorReport, error) {
+	result := []*v1beta1.KdoctorReport{}
+	if !w.opts.Recursive {
+		obj := &v1beta1.KdoctorReport{}
+		if err := w.storage.Get(w.ctx, w.key, storage.GetOptions{}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				return result, nil
+			}
+			return nil, err
+		}
+		return append(result, obj), nil
+	}
+
+	list := &v1beta1.KdoctorReportList{}
+	if err := w.storage.GetList(w.ctx, w.key, storage.ListOptions{}, list); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		result = append(result, &list.Items[i])
+	}
+	return result, nil
+}
+
+// sendInitialEvents sends all the kdoctorreports as added when the watch starts without a resource version,
+// or else sends the ones changed after the resource version
+func (w *reportWatcher) sendInitialEvents(revision uint64) error {
+	objs, err := w.snapshot()
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if !w.opts.Predicate.Empty() {
+			if ok, _ := w.opts.Predicate.Matches(obj); !ok {
+				continue
+			}
+		}
+		w.sent[obj.Name] = obj
+		if revision == 0 {
+			if !w.send(watch.Added, obj) {
+				return nil
+			}
+			continue
+		}
+		if rv, _ := strconv.ParseUint(obj.ResourceVersion, 10, 64); rv > revision {
+			if !w.send(watch.Modified, obj) {
+				return nil
+			}
+		}
+	}
+	if revision == 0 {
+		return nil
+	}
+
+	// the tasks deleted after the resource version
+	for _, update := range reportManager.UpdatesSince(revision) {
+		name := reportName(update.KindName, update.TaskName)
+		if _, ok := w.sent[name]; ok || !w.matches(name) {
+			continue
+		}
+		obj := &v1beta1.KdoctorReport{}
+		obj.Name = name
+		obj.ResourceVersion = strconv.FormatUint(update.Revision, 10)
+		obj.Task.TaskName = update.TaskName
+		obj.Task.TaskType = update.KindName
+		if !w.send(watch.Deleted, obj) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// handleUpdate rebuilds the kdoctorreport of the task, and sends the event when it changes
+func (w *reportWatcher) handleUpdate(update reportManager.ReportUpdate) bool {
+	name := reportName(update.KindName, update.TaskName)
+	if !w.matches(name) {
+		return true
+	}
+	old, existed := w.sent[name]
+
+	obj := &v1beta1.KdoctorReport{}
+	err := w.storage.Get(w.ctx, w.objectKey(name), storage.GetOptions{}, obj)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			// the task may not have reports yet
+			klog.Infof("ignore the update of kdoctorreport %s, error: %v", name, err)
+			return true
+		}
+		if !existed {
+			return true
+		}
+		delete(w.sent, name)
+		deleted := old.DeepCopy()
+		deleted.ResourceVersion = strconv.FormatUint(update.Revision, 10)
+		return w.send(watch.Deleted, deleted)
+	}
+
+	if !w.opts.Predicate.Empty() {
+		if ok, _ := w.opts.Predicate.Matches(obj); !ok {
+			if !existed {
+				return true
+			}
+			delete(w.sent, name)
+			return w.send(watch.Deleted, obj)
+		}
+	}
+
+	if existed {
+		// the resource version is not compared, which changes with each notification
+		current := obj.DeepCopy()
+		current.ResourceVersion = old.ResourceVersion
+		if equality.Semantic.DeepEqual(current, old) {
+			return true
+		}
+	}
+	w.sent[name] = obj
+	if existed {
+		return w.send(watch.Modified, obj)
+	}
+	return w.send(watch.Added, obj)
+}
+
+func (w *reportWatcher) run(revision uint64, unsubscribe func()) {
+	defer close(w.result)
+	defer unsubscribe()
+
+	if err := w.sendInitialEvents(revision); err != nil {
+		klog.Errorf("failed to send initial events of kdoctorreport watch, error: %v", err)
+		w.send(watch.Error, &errors.NewInternalError(err).ErrStatus)
+		return
+	}
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case update, ok := <-w.updates:
+			if !ok {
+				// the watcher falls behind, and the client watches again
+				return
+			}
+			if !w.handleUpdate(update) {
+				return
+			}
+		}
+	}
+}
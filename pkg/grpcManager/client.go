@@ -5,7 +5,6 @@ package grpcManager
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"github.com/kdoctor-io/kdoctor/api/v1/agentGrpc"
 	"github.com/pkg/errors"
@@ -34,29 +33,22 @@ type GrpcClientManager interface {
 }
 
 type grpcClientManager struct {
-	logger *zap.Logger
-	opts   []grpc.DialOption
-	client *grpc.ClientConn
+	logger       *zap.Logger
+	opts         []grpc.DialOption
+	certReloader *CertReloader
+	client       *grpc.ClientConn
 }
 
-func NewGrpcClient(logger *zap.Logger, enableTls bool) GrpcClientManager {
+// NewGrpcClient dials with mTLS by the certificate and the CA of the certReloader, and no tls when it is nil
+func NewGrpcClient(logger *zap.Logger, certReloader *CertReloader) GrpcClientManager {
 	s := &grpcClientManager{
-		logger: logger.Named("GrpcClientManager"),
+		logger:       logger.Named("GrpcClientManager"),
+		certReloader: certReloader,
 	}
 
 	s.opts = append(s.opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(DefaultMaxRecvMsgSize), grpc.MaxCallSendMsgSize(DefaultMaxRecvMsgSize)))
 	s.opts = append(s.opts, grpc.WithBlock())
 
-	if enableTls {
-		tlsConfig := &tls.Config{
-			InsecureSkipVerify: true,
-		}
-		s.opts = append(s.opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
-	} else {
-		// no tls
-		s.opts = append(s.opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	}
-
 	return s
 }
 
@@ -93,8 +85,16 @@ func (s *grpcClientManager) clientDial(ctx context.Context, serverAddress []stri
 
 	opts = append(opts, s.opts...)
 
+	// the latest certificate and ca are used for each dial
+	if s.certReloader != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(s.certReloader.ClientTLSConfig())))
+	} else {
+		// no tls
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
 	if c, err := grpc.DialContext(ctx, addr, opts...); err != nil {
-		return errors.Errorf("grpc failed to dial")
+		return errors.Errorf("grpc failed to dial, error=%v", err)
 	} else {
 		s.client = c
 	}
@@ -0,0 +1,16 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcManager_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestGrpcManager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GrpcManager Suite")
+}
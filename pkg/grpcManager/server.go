@@ -5,7 +5,6 @@ package grpcManager
 
 import (
 	"context"
-	"crypto/tls"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -38,19 +37,17 @@ const (
 	DefaultMaxSendMsgSize = math.MaxInt32
 )
 
-func NewGrpcServer(logger *zap.Logger, tlsCertPath, tlskeyPath string) GrpcServerManager {
+// NewGrpcServer serves with mTLS, which requires the certificate of the client signed by the client CA of the certReloader,
+// and the clientIdentity in the certificate
+func NewGrpcServer(logger *zap.Logger, certReloader *CertReloader, clientIdentity string) GrpcServerManager {
 	m := &grpcServer{}
 	opts := []grpc.ServerOption{}
 
 	m.logger = logger.Named("grpcManager")
-	m.logger.Sugar().Infof("NewGrpcServer, tlsCertPath=%v, tlskeyPath=%v", tlsCertPath, tlskeyPath)
+	m.logger.Sugar().Infof("NewGrpcServer, clientIdentity=%v", clientIdentity)
 
 	// ----- tls
-	cert, err := tls.LoadX509KeyPair(tlsCertPath, tlskeyPath)
-	if err != nil {
-		m.logger.Sugar().Fatalf("failed to load tls: %s", err)
-	}
-	opts = append(opts, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+	opts = append(opts, grpc.Creds(credentials.NewTLS(certReloader.ServerTLSConfig(clientIdentity))))
 
 	// https://godoc.org/google.golang.org/grpc/keepalive#EnforcementPolicy
 	// Enforcement policy is a special setting on server side to protect server from malicious or misbehaving clients
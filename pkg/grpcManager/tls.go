@@ -0,0 +1,200 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcManager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/lock"
+)
+
+const (
+	DefaultCertReloadInterval = 10 * time.Second
+)
+
+// TlsFiles are the certificate, the key and the CA of one side of the mTLS
+type TlsFiles struct {
+	CertPath string
+	KeyPath  string
+	// the CA which signs the certificate of the peer
+	CaPath string
+	// the CA which signs the certificates of the clients, which is only required by the server,
+	// so the certificates signed by CaPath, which the agents are able to sign, are not trusted as the clients
+	ClientCaPath string
+}
+
+// CertReloader loads the certificate and the CA again when the files change, for example, the mounted secret is updated
+type CertReloader struct {
+	lock.RWMutex
+	logger *zap.Logger
+	files  TlsFiles
+	// renew regenerates the certificate when the CA changes, for example, the agent signs its own certificate with the CA
+	renew func() error
+
+	caDigest [sha256.Size]byte
+	digest   [sha256.Size]byte
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	// the CA of the clients, and it is nil when the ClientCaPath is empty
+	clientPool *x509.CertPool
+}
+
+// NewCertReloader loads the files for the first time, and the renew could be nil
+func NewCertReloader(logger *zap.Logger, files TlsFiles, renew func() error) (*CertReloader, error) {
+	r := &CertReloader{
+		logger: logger.Named("certReloader"),
+		files:  files,
+		renew:  renew,
+	}
+	if _, e := r.Reload(); e != nil {
+		return nil, e
+	}
+	return r, nil
+}
+
+// Reload loads the files when they change, and returns whether the certificate or the CA is updated.
+// The old certificate and CA are still used when the new ones are invalid
+func (r *CertReloader) Reload() (bool, error) {
+	caPem, e := os.ReadFile(r.files.CaPath)
+	if e != nil {
+		return false, fmt.Errorf("failed to read ca %v, error=%v", r.files.CaPath, e)
+	}
+	caDigest := sha256.Sum256(caPem)
+	if r.cert != nil && caDigest != r.caDigest && r.renew != nil {
+		r.logger.Sugar().Infof("ca %v changes, renew the certificate", r.files.CaPath)
+		if e := r.renew(); e != nil {
+			return false, fmt.Errorf("failed to renew the certificate with the new ca, error=%v", e)
+		}
+	}
+
+	certPem, e := os.ReadFile(r.files.CertPath)
+	if e != nil {
+		return false, fmt.Errorf("failed to read certificate %v, error=%v", r.files.CertPath, e)
+	}
+	keyPem, e := os.ReadFile(r.files.KeyPath)
+	if e != nil {
+		return false, fmt.Errorf("failed to read key %v, error=%v", r.files.KeyPath, e)
+	}
+	var clientCaPem []byte
+	if len(r.files.ClientCaPath) > 0 {
+		if clientCaPem, e = os.ReadFile(r.files.ClientCaPath); e != nil {
+			return false, fmt.Errorf("failed to read client ca %v, error=%v", r.files.ClientCaPath, e)
+		}
+	}
+	digest := sha256.Sum256(bytes.Join([][]byte{caPem, certPem, keyPem, clientCaPem}, nil))
+	if r.cert != nil && digest == r.digest {
+		return false, nil
+	}
+
+	cert, e := tls.X509KeyPair(certPem, keyPem)
+	if e != nil {
+		return false, fmt.Errorf("failed to load certificate %v and key %v, error=%v", r.files.CertPath, r.files.KeyPath, e)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPem) {
+		return false, fmt.Errorf("no certificate is found in ca %v", r.files.CaPath)
+	}
+	var clientPool *x509.CertPool
+	if len(r.files.ClientCaPath) > 0 {
+		clientPool = x509.NewCertPool()
+		if !clientPool.AppendCertsFromPEM(clientCaPem) {
+			return false, fmt.Errorf("no certificate is found in client ca %v", r.files.ClientCaPath)
+		}
+	}
+
+	r.Lock()
+	r.cert = &cert
+	r.pool = pool
+	r.clientPool = clientPool
+	r.caDigest = caDigest
+	r.digest = digest
+	r.Unlock()
+	r.logger.Sugar().Infof("loaded certificate %v and ca %v", r.files.CertPath, r.files.CaPath)
+	return true, nil
+}
+
+// Run reloads the files at the interval until the ctx is done
+func (r *CertReloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, e := r.Reload(); e != nil {
+				r.logger.Sugar().Errorf("failed to reload tls files, keep the old ones, error=%v", e)
+			}
+		}
+	}
+}
+
+func (r *CertReloader) current() (*tls.Certificate, *x509.CertPool, *x509.CertPool) {
+	r.RLock()
+	defer r.RUnlock()
+	return r.cert, r.pool, r.clientPool
+}
+
+// ServerTLSConfig requires the certificate of the client signed by the client CA, and the identity of the client.
+// Each connection uses the latest certificate and CA, and the connection is refused when the client CA is not set
+func (r *CertReloader) ServerTLSConfig(clientIdentity string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, _, clientPool := r.current()
+			if clientPool == nil {
+				return nil, fmt.Errorf("no client ca to verify the client")
+			}
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   []string{"h2"},
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    clientPool,
+				VerifyConnection: func(cs tls.ConnectionState) error {
+					if len(cs.PeerCertificates) == 0 {
+						return fmt.Errorf("no client certificate")
+					}
+					return VerifyPeerIdentity(cs.PeerCertificates[0], clientIdentity)
+				},
+			}, nil
+		},
+	}
+}
+
+// ClientTLSConfig presents the certificate, and verifies the server with the CA.
+// It is built for each dial, so the new connection uses the latest certificate and CA
+func (r *CertReloader) ClientTLSConfig() *tls.Config {
+	cert, pool, _ := r.current()
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*cert},
+		RootCAs:      pool,
+	}
+}
+
+// VerifyPeerIdentity checks the common name or the dns names of the certificate, and the empty identity matches no peer
+func VerifyPeerIdentity(cert *x509.Certificate, identity string) error {
+	if len(identity) == 0 {
+		return fmt.Errorf("no identity is expected for the peer %v", cert.Subject.CommonName)
+	}
+	if cert.Subject.CommonName == identity {
+		return nil
+	}
+	for _, name := range cert.DNSNames {
+		if name == identity {
+			return nil
+		}
+	}
+	return fmt.Errorf("the peer %v is not the expected identity %v", cert.Subject.CommonName, identity)
+}
@@ -0,0 +1,174 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package grpcManager_test
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/kdoctor-io/kdoctor/pkg/grpcManager"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/utils"
+)
+
+// writeCert signs the certificate of the common name by the ca, or signs itself as a ca when the ca is nil
+func writeCert(dir, name, commonName string, ca *x509.Certificate, caKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(cryptorand.Reader, 2048)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if ca == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+		tmpl.ExtKeyUsage = nil
+		ca, caKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(cryptorand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(os.WriteFile(path.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: keyutil.RSAPrivateKeyBlockType, Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	Expect(os.WriteFile(path.Join(dir, name+".key"), keyPem, 0600)).To(Succeed())
+	return cert, key
+}
+
+func filesOf(dir, name, ca string) grpcManager.TlsFiles {
+	return grpcManager.TlsFiles{CertPath: path.Join(dir, name+".crt"), KeyPath: path.Join(dir, name+".key"), CaPath: path.Join(dir, ca+".crt")}
+}
+
+var _ = Describe("test grpc mTLS", Label("tls"), func() {
+
+	It("call the server with the client certificate of the expected identity, and reload the rotated certificates", func() {
+		log := logger.NewStdoutLogger("debug", "grpc Test")
+		dir := GinkgoT().TempDir()
+
+		// the agent signs its server certificate with the ca
+		ca, caKey := writeCert(dir, "ca", "kdoctor.io", nil, nil)
+		renewServerCert := func() error {
+			serverCert, serverKey, _, e := utils.NewServerCertKey("127.0.0.1", nil, nil, path.Join(dir, "ca.crt"), path.Join(dir, "ca.key"))
+			if e != nil {
+				return e
+			}
+			if e := os.WriteFile(path.Join(dir, "server.crt"), serverCert, 0600); e != nil {
+				return e
+			}
+			return os.WriteFile(path.Join(dir, "server.key"), serverKey, 0600)
+		}
+		Expect(renewServerCert()).To(Succeed())
+		// the controller is signed by the client ca, whose key is not mounted to the agent
+		clientCa, clientCaKey := writeCert(dir, "client-ca", "kdoctor-client.io", nil, nil)
+		serverFiles := filesOf(dir, "server", "ca")
+		serverFiles.ClientCaPath = path.Join(dir, "client-ca.crt")
+		serverTls, err := grpcManager.NewCertReloader(log, serverFiles, renewServerCert)
+		Expect(err).NotTo(HaveOccurred())
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		address := l.Addr().String()
+		Expect(l.Close()).To(Succeed())
+		grpcManager.NewGrpcServer(log, serverTls, "kdoctor-controller").Run(address)
+
+		listFiles := func(certReloader *grpcManager.CertReloader) error {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			_, e := grpcManager.NewGrpcClient(log, certReloader).GetFileList(ctx, address, dir)
+			return e
+		}
+
+		writeCert(dir, "controller", "kdoctor-controller", clientCa, clientCaKey)
+		controllerTls, err := grpcManager.NewCertReloader(log, filesOf(dir, "controller", "ca"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listFiles(controllerTls)).To(Succeed())
+
+		// the client of another identity, another ca, or no tls is refused
+		writeCert(dir, "other", "someone", clientCa, clientCaKey)
+		otherTls, err := grpcManager.NewCertReloader(log, filesOf(dir, "other", "ca"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listFiles(otherTls)).NotTo(Succeed())
+
+		fakeCa, fakeCaKey := writeCert(dir, "fake-ca", "kdoctor.io", nil, nil)
+		writeCert(dir, "fake", "kdoctor-controller", fakeCa, fakeCaKey)
+		fakeTls, err := grpcManager.NewCertReloader(log, filesOf(dir, "fake", "ca"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listFiles(fakeTls)).NotTo(Succeed())
+		Expect(listFiles(nil)).NotTo(Succeed())
+
+		// the agent could sign a certificate with the ca of the agents, which is not trusted as the client
+		writeCert(dir, "agent", "kdoctor-controller", ca, caKey)
+		agentTls, err := grpcManager.NewCertReloader(log, filesOf(dir, "agent", "ca"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listFiles(agentTls)).NotTo(Succeed())
+
+		// rotate the ca, the server renews its certificate and the controller loads the new one
+		writeCert(dir, "ca", "kdoctor.io", nil, nil)
+		updated, err := serverTls.Reload()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(listFiles(controllerTls)).NotTo(Succeed())
+
+		updated, err = controllerTls.Reload()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(listFiles(controllerTls)).To(Succeed())
+
+		// nothing changes
+		updated, err = controllerTls.Reload()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+
+		// the invalid files are not loaded, and the old ones are kept
+		Expect(os.WriteFile(path.Join(dir, "controller.key"), []byte("invalid"), 0600)).To(Succeed())
+		_, err = controllerTls.Reload()
+		Expect(err).To(HaveOccurred())
+		Expect(listFiles(controllerTls)).To(Succeed())
+	})
+
+	It("refuse all clients when the client ca is not set", func() {
+		log := logger.NewStdoutLogger("debug", "grpc Test")
+		dir := GinkgoT().TempDir()
+		ca, caKey := writeCert(dir, "ca", "kdoctor.io", nil, nil)
+		writeCert(dir, "server", "127.0.0.1", ca, caKey)
+		serverTls, err := grpcManager.NewCertReloader(log, filesOf(dir, "server", "ca"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = serverTls.ServerTLSConfig("kdoctor-controller").GetConfigForClient(nil)
+		Expect(err).To(MatchError("no client ca to verify the client"))
+
+		serverFiles := filesOf(dir, "server", "ca")
+		serverFiles.ClientCaPath = path.Join(dir, "server.crt")
+		_, err = grpcManager.NewCertReloader(log, serverFiles, nil)
+		Expect(err).NotTo(HaveOccurred())
+		serverFiles.ClientCaPath = path.Join(dir, "server.key")
+		_, err = grpcManager.NewCertReloader(log, serverFiles, nil)
+		Expect(err).To(MatchError(ContainSubstring("no certificate is found in client ca")))
+	})
+
+	It("verify the identity of the peer", func() {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: "kdoctor.io"}, DNSNames: []string{"kdoctor-controller"}}
+		Expect(grpcManager.VerifyPeerIdentity(cert, "")).To(MatchError(ContainSubstring("no identity is expected")))
+		Expect(grpcManager.VerifyPeerIdentity(cert, "kdoctor.io")).To(Succeed())
+		Expect(grpcManager.VerifyPeerIdentity(cert, "kdoctor-controller")).To(Succeed())
+		Expect(grpcManager.VerifyPeerIdentity(cert, "kdoctor-agent")).To(MatchError(ContainSubstring("is not the expected identity")))
+	})
+})
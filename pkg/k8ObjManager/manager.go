@@ -23,6 +23,7 @@ type K8sObjManager interface {
 	GetNodeIP(ctx context.Context, nodeName string) (ipv4, ipv6 string, err error)
 
 	// daemonset
+	ListDaemonsetPod(ctx context.Context, daemonsetName, daemonsetNameSpace string) ([]corev1.Pod, error)
 	ListDaemonsetPodNodes(ctx context.Context, daemonsetName, daemonsetNameSpace string) ([]string, error)
 	GetDaemonset(ctx context.Context, name, namespace string) (*appsv1.DaemonSet, error)
 	ListDaemonsetPodIPs(ctx context.Context, daemonsetName, daemonsetNameSpace string) (PodIps, error)
@@ -199,6 +199,21 @@ func (mr *MockK8sObjManagerMockRecorder) GetServiceAccessUrl(ctx, name, namespac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccessUrl", reflect.TypeOf((*MockK8sObjManager)(nil).GetServiceAccessUrl), ctx, name, namespace, portName)
 }
 
+// ListDaemonsetPod mocks base method.
+func (m *MockK8sObjManager) ListDaemonsetPod(ctx context.Context, daemonsetName, daemonsetNameSpace string) ([]v10.Pod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDaemonsetPod", ctx, daemonsetName, daemonsetNameSpace)
+	ret0, _ := ret[0].([]v10.Pod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDaemonsetPod indicates an expected call of ListDaemonsetPod.
+func (mr *MockK8sObjManagerMockRecorder) ListDaemonsetPod(ctx, daemonsetName, daemonsetNameSpace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDaemonsetPod", reflect.TypeOf((*MockK8sObjManager)(nil).ListDaemonsetPod), ctx, daemonsetName, daemonsetNameSpace)
+}
+
 // ListDaemonsetPodIPs mocks base method.
 func (m *MockK8sObjManager) ListDaemonsetPodIPs(ctx context.Context, daemonsetName, daemonsetNameSpace string) (k8sObjManager.PodIps, error) {
 	m.ctrl.T.Helper()
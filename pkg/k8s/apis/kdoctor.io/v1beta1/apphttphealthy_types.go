@@ -24,6 +24,51 @@ type AppHttpHealthySpec struct {
 
 	// +kubebuilder:validation:Optional
 	SuccessCondition *NetSuccessCondition `json:"expect,omitempty"`
+
+	// detect mode ramps the qps step by step to discover the maximum sustainable qps of the target,
+	// and the qps and duration of the request are ignored
+	// +kubebuilder:validation:Optional
+	Detect *AppHttpDetect `json:"detect,omitempty"`
+	// +kubebuilder:validation:Optional
+	ReportSinks []ReportSink `json:"reportSinks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Baseline *Baseline `json:"baseline,omitempty"`
+}
+
+type AppHttpDetect struct {
+	// the qps of the first step
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	StartQPS int `json:"startQPS,omitempty"`
+
+	// the qps increased by each step
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	StepQPS int `json:"stepQPS,omitempty"`
+
+	// the qps of the last step
+	// +kubebuilder:validation:Minimum=1
+	MaxQPS int `json:"maxQPS"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	StepDurationInSecond int `json:"stepDurationInSecond,omitempty"`
+
+	// the ramp stops when the success rate of a step is lower than it
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	SuccessRate *float64 `json:"successRate,omitempty"`
+
+	// the ramp stops when the p99 latency of a step is bigger than it
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxP99InMs *int64 `json:"maxP99InMs,omitempty"`
 }
 
 type AppHttpHealthyTarget struct {
@@ -13,6 +13,10 @@ type SchedulePlan struct {
 	// +kubebuilder:validation:Optional
 	Schedule *string `json:"schedule,omitempty"`
 
+	// the IANA time zone of the crontab schedule, for example, Asia/Shanghai, and the empty is the time zone of the controller
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+
 	// +kubebuilder:default=60
 	// +kubebuilder:validation:Minimum=1
 	RoundTimeoutMinute int64 `json:"roundTimeoutMinute"`
@@ -20,6 +24,98 @@ type SchedulePlan struct {
 	// +kubebuilder:default=1
 	// +kubebuilder:validation:Minimum=-1
 	RoundNumber int64 `json:"roundNumber"`
+
+	// Allow runs the round along with the rounds of the other tasks, Forbid skips the round when the rounds of the other tasks are running,
+	// and Queue delays the round until the rounds of the other tasks finish. The round of Forbid or Queue runs alone
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Allow
+	// +kubebuilder:validation:Enum=Allow;Forbid;Queue
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+
+	// the priority class in the configmap of the controller, and the delayed round of higher priority starts first
+	// +kubebuilder:validation:Optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// the scheduled rounds do not start when it is true, and the running round still finishes
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	Suspend *bool `json:"suspend,omitempty"`
+}
+
+// AnnotationRunNow on the task adds a manual round which starts right now, when its value changes
+const AnnotationRunNow = "kdoctor.io/run-now"
+
+const (
+	ConcurrencyPolicyAllow  = "Allow"
+	ConcurrencyPolicyForbid = "Forbid"
+	ConcurrencyPolicyQueue  = "Queue"
+)
+
+// ReportSink receives the summary of each round by POST, after the controller collects the agent reports of the round
+type ReportSink struct {
+	// the http or https url
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// the secret in the namespace of the kdoctor-controller, whose data signs the request body with HMAC-SHA256
+	// +kubebuilder:validation:Optional
+	HmacSecret *SecretKeySelector `json:"hmacSecret,omitempty"`
+
+	// only push the summary of the failed round
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	FailedRoundOnly *bool `json:"failedRoundOnly,omitempty"`
+
+	// the retry times after the first push fails, and the summary is saved to the dead-letter directory when all fail
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	TimeoutInSecond *int32 `json:"timeoutInSecond,omitempty"`
+}
+
+type SecretKeySelector struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+}
+
+// Baseline compares each round with a pinned round or the previous rounds, and marks the round as regressed
+// when the metrics of any target get worse than the baseline by more than the percentages
+type Baseline struct {
+	// the pinned round as the baseline, which conflicts with window
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Round *int64 `json:"round,omitempty"`
+
+	// the number of the previous rounds as the baseline, whose metrics are averaged
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Window *int64 `json:"window,omitempty"`
+
+	// the maximum increase percentage of the P50 latency of a target
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	P50IncreasePercent *float64 `json:"p50IncreasePercent,omitempty"`
+
+	// the maximum increase percentage of the P99 latency of a target
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	P99IncreasePercent *float64 `json:"p99IncreasePercent,omitempty"`
+
+	// the maximum decrease percentage of the success rate of a target
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	SuccessRateDecreasePercent *float64 `json:"successRateDecreasePercent,omitempty"`
 }
 
 type TaskStatus struct {
@@ -45,6 +141,19 @@ type TaskStatus struct {
 
 	// +kubebuilder:validation:Optional
 	Resource *TaskResource `json:"resource,omitempty"`
+
+	// the value of the run-now annotation which has added the manual round
+	// +kubebuilder:validation:Optional
+	LastRunNow string `json:"lastRunNow,omitempty"`
+
+	// the start times of the next planned rounds, at most 5, and it is empty when the task is suspended or finished
+	// +kubebuilder:validation:Optional
+	NextRoundTimes []metav1.Time `json:"nextRoundTimes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type TaskResource struct {
@@ -71,16 +180,46 @@ const (
 	RuntimeDeleted  = "deleted"
 )
 
+// the conditions of the task
+const (
+	// the latest finished round succeeds
+	TaskConditionReady = "Ready"
+	// the latest finished round fails
+	TaskConditionDegraded = "Degraded"
+	// all rounds of the task are done
+	TaskConditionFinished = "Finished"
+)
+
+// the reasons of the task conditions
+const (
+	TaskReasonNoRoundFinished = "NoRoundFinished"
+	TaskReasonRoundSucceeded  = "RoundSucceeded"
+	TaskReasonRoundFailed     = "RoundFailed"
+	TaskReasonRoundsRemaining = "RoundsRemaining"
+	TaskReasonAllRoundsDone   = "AllRoundsDone"
+	TaskReasonRoundRegressed  = "RoundRegressed"
+)
+
+// the reasons of the events when the admission delays or skips the round
+const (
+	TaskReasonRoundDelayed = "RoundDelayed"
+	TaskReasonRoundSkipped = "RoundSkipped"
+)
+
+// the reason of the events when the run-now annotation adds the manual round
+const TaskReasonRunNow = "RunNow"
+
 const (
 	StatusHistoryRecordStatusSucceed    = "succeed"
 	StatusHistoryRecordStatusFail       = "fail"
 	StatusHistoryRecordStatusOngoing    = "ongoing"
 	StatusHistoryRecordStatusNotstarted = "notstarted"
+	StatusHistoryRecordStatusSkipped    = "skipped"
 )
 
 type StatusHistoryRecord struct {
 
-	// +kubebuilder:validation:Enum=succeed;fail;ongoing;notstarted
+	// +kubebuilder:validation:Enum=succeed;fail;ongoing;notstarted;skipped
 	Status string `json:"status"`
 
 	// +kubebuilder:validation:Optional
@@ -113,6 +252,27 @@ type StatusHistoryRecord struct {
 	SucceedAgentNodeList []string `json:"succeedAgentNodeList"`
 
 	NotReportAgentNodeList []string `json:"notReportAgentNodeList"`
+
+	// whether the round gets worse than the baseline, which is set after the reports of the round are collected
+	// +kubebuilder:validation:Optional
+	Regressed *bool `json:"regressed,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	RegressionReason string `json:"regressionReason,omitempty"`
+
+	// the start time in the schedule, which is set when the admission delays the round or the round runs manually
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type:=string
+	// +kubebuilder:validation:Format:=date-time
+	ScheduledTimeStamp *metav1.Time `json:"scheduledTimeStamp,omitempty"`
+
+	// why the admission delays or skips the round
+	// +kubebuilder:validation:Optional
+	AdmissionReason string `json:"admissionReason,omitempty"`
+
+	// the round is added by the run-now annotation, and the scheduled round after it starts at its scheduledTimeStamp
+	// +kubebuilder:validation:Optional
+	Manual bool `json:"manual,omitempty"`
 }
 
 type NetSuccessCondition struct {
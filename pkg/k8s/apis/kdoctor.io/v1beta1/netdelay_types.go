@@ -0,0 +1,118 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NetDelaySpec struct {
+	// for the nested field, you should add the kubebuilder default tag even if the nested field properties own the default value.
+
+	// only the DaemonSet runtime is supported, for every node should probe all the other nodes
+	// +kubebuilder:validation:Optional
+	AgentSpec *AgentSpec `json:"agentSpec,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Schedule *SchedulePlan `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Target *NetDelayTarget `json:"target,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Request *NetDelayRequest `json:"request,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	SuccessCondition *NetDelaySuccessCondition `json:"expect,omitempty"`
+	// +kubebuilder:validation:Optional
+	ReportSinks []ReportSink `json:"reportSinks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Baseline *Baseline `json:"baseline,omitempty"`
+}
+
+type NetDelayTarget struct {
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	IPv4 *bool `json:"ipv4,omitempty"`
+
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:Optional
+	IPv6 *bool `json:"ipv6,omitempty"`
+
+	// +kubebuilder:default=false
+	MultusInterface *bool `json:"multusInterface,omitempty"`
+}
+
+type NetDelayRequest struct {
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	DurationInSecond int `json:"durationInSecond,omitempty"`
+
+	// the number of tcp probes per second sent to each of the other agents
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	QPS int `json:"qps,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=1
+	PerRequestTimeoutInMS int `json:"perRequestTimeoutInMS,omitempty"`
+}
+
+type NetDelaySuccessCondition struct {
+
+	// the success rate of the probes between each pair of nodes
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	SuccessRate *float64 `json:"successRate,omitempty"`
+
+	// the maximum p50 round-trip time allowed for any pair of nodes
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxPairP50InMs *int64 `json:"maxPairP50InMs,omitempty"`
+
+	// the maximum p99 round-trip time allowed for any pair of nodes
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxPairP99InMs *int64 `json:"maxPairP99InMs,omitempty"`
+}
+
+// scope(Namespaced or Cluster)
+// +kubebuilder:resource:categories={kdoctor},path="netdelays",singular="netdelay",shortName={nd},scope="Cluster"
+// +kubebuilder:printcolumn:JSONPath=".status.finish",description="finish",name="finish",type=boolean
+// +kubebuilder:printcolumn:JSONPath=".status.expectedRound",description="expectedRound",name="expectedRound",type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.doneRound",description="doneRound",name="doneRound",type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.lastRoundStatus",description="lastRoundStatus",name="lastRoundStatus",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.schedule.schedule",description="schedule",name="schedule",type=string
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +genclient
+// +genclient:nonNamespaced
+
+type NetDelay struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   NetDelaySpec `json:"spec,omitempty"`
+	Status TaskStatus   `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type NetDelayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NetDelay `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetDelay{}, &NetDelayList{})
+}
@@ -27,6 +27,51 @@ type NetdnsSpec struct {
 
 	// +kubebuilder:validation:Optional
 	SuccessCondition *NetSuccessCondition `json:"expect,omitempty"`
+
+	// detect mode ramps the qps of all agents step by step to discover the maximum throughput of the dns server,
+	// and the qps and duration of the request are ignored
+	// +kubebuilder:validation:Optional
+	Detect *NetdnsDetect `json:"detect,omitempty"`
+	// +kubebuilder:validation:Optional
+	ReportSinks []ReportSink `json:"reportSinks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Baseline *Baseline `json:"baseline,omitempty"`
+}
+
+type NetdnsDetect struct {
+	// the qps of the first step for each agent
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	StartQPS int `json:"startQPS,omitempty"`
+
+	// the qps increased by each step for each agent
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	StepQPS int `json:"stepQPS,omitempty"`
+
+	// the qps of the last step for each agent
+	// +kubebuilder:validation:Minimum=1
+	MaxQPS int `json:"maxQPS"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	StepDurationInSecond int `json:"stepDurationInSecond,omitempty"`
+
+	// the ramp stops when the rate of the requests answered without SERVFAIL in a step is lower than it
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	SuccessRate *float64 `json:"successRate,omitempty"`
+
+	// the ramp stops when the p99 latency of a step is bigger than it
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxP99InMs *int64 `json:"maxP99InMs,omitempty"`
 }
 
 type NetDnsTarget struct {
@@ -38,6 +83,23 @@ type NetDnsTarget struct {
 	// +kubebuilder:default=false
 	// +kubebuilder:validation:Optional
 	EnableLatencyMetric bool `json:"enableLatencyMetric,omitempty"`
+
+	// set the DO bit of the requests and check the DNSSEC of the replies
+	// +kubebuilder:validation:Optional
+	Dnssec *NetDnsDnssec `json:"dnssec,omitempty"`
+}
+
+type NetDnsDnssec struct {
+	// the reply without the AD flag, which is set by the validating resolver, is a failure
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	CheckAD *bool `json:"checkAD,omitempty"`
+
+	// the DS or DNSKEY records of the trust anchors in the presentation format, for example,
+	// ". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D".
+	// The RRSIG chains of the replies are validated up to the trust anchors when it is not empty
+	// +kubebuilder:validation:Optional
+	TrustAnchors []string `json:"trustAnchors,omitempty"`
 }
 
 type NetDnsTargetUserSpec struct {
@@ -84,11 +146,41 @@ type NetdnsRequest struct {
 	// +kubebuilder:validation:Optional
 	Domain string `json:"domain"`
 
+	// the requests rotate through the queries when it is not empty, and the domain is ignored
+	// +kubebuilder:validation:Optional
+	Queries []NetdnsQuery `json:"queries,omitempty"`
+
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=udp
 	// +kubebuilder:validation:Type:=string
-	// +kubebuilder:validation:Enum=udp;tcp;tcp-tls
+	// +kubebuilder:validation:Enum=udp;tcp;tcp-tls;https;quic
 	Protocol *string `json:"protocol,omitempty"`
+
+	// the http method of the DNS-over-HTTPS request, only for the https protocol
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=POST
+	// +kubebuilder:validation:Enum=GET;POST
+	DohMethod *string `json:"dohMethod,omitempty"`
+
+	// the path of the DNS-over-HTTPS request, only for the https protocol, and it is /dns-query by default
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^/`
+	DohPath *string `json:"dohPath,omitempty"`
+}
+
+type NetdnsQuery struct {
+	// the domain to query, or the ip address for the PTR query
+	Domain string `json:"domain"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=A
+	// +kubebuilder:validation:Enum=A;AAAA;SRV;PTR;CNAME;TXT;MX;NS
+	Type string `json:"type,omitempty"`
+
+	// the expected rdata of the answers in the presentation format regardless of the order, for example,
+	// "0 100 53 coredns.kube-system.svc.cluster.local" for SRV. The reply with different answers is counted as a failure
+	// +kubebuilder:validation:Optional
+	ExpectedAnswers []string `json:"expectedAnswers,omitempty"`
 }
 
 // scope(Namespaced or Cluster)
@@ -24,6 +24,11 @@ type NetReachSpec struct {
 
 	// +kubebuilder:validation:Optional
 	SuccessCondition *NetSuccessCondition `json:"expect,omitempty"`
+	// +kubebuilder:validation:Optional
+	ReportSinks []ReportSink `json:"reportSinks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Baseline *Baseline `json:"baseline,omitempty"`
 }
 
 type NetReachTarget struct {
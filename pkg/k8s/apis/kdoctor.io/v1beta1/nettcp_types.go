@@ -0,0 +1,137 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NetTcpSpec struct {
+	// for the nested field, you should add the kubebuilder default tag even if the nested field properties own the default value.
+
+	// +kubebuilder:validation:Optional
+	AgentSpec *AgentSpec `json:"agentSpec,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Schedule *SchedulePlan `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Target *NetTcpTarget `json:"target,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Request *NetTcpRequest `json:"request,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	SuccessCondition *NetTcpSuccessCondition `json:"expect,omitempty"`
+	// +kubebuilder:validation:Optional
+	ReportSinks []ReportSink `json:"reportSinks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Baseline *Baseline `json:"baseline,omitempty"`
+}
+
+type NetTcpTarget struct {
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	IPv4 *bool `json:"ipv4,omitempty"`
+
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:Optional
+	IPv6 *bool `json:"ipv6,omitempty"`
+
+	// +kubebuilder:default=true
+	Endpoint *bool `json:"endpoint,omitempty"`
+
+	// +kubebuilder:default=false
+	MultusInterface *bool `json:"multusInterface,omitempty"`
+
+	// +kubebuilder:default=true
+	ClusterIP *bool `json:"clusterIP,omitempty"`
+
+	// +kubebuilder:default=true
+	NodePort *bool `json:"nodePort,omitempty"`
+
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:Optional
+	EnableLatencyMetric bool `json:"enableLatencyMetric,omitempty"`
+}
+
+type NetTcpRequest struct {
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=1
+	DurationInSecond int `json:"durationInSecond,omitempty"`
+
+	// the number of new tcp connections per second
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=5
+	// +kubebuilder:validation:Minimum=1
+	QPS int `json:"qps,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=1
+	PerRequestTimeoutInMS int `json:"perRequestTimeoutInMS,omitempty"`
+
+	// the data size sent through each connection for measuring the throughput, 0 for only connecting
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=65536
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=104857600
+	PayloadSizeInByte int `json:"payloadSizeInByte"`
+}
+
+type NetTcpSuccessCondition struct {
+
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	SuccessRate *float64 `json:"successRate,omitempty"`
+
+	// the mean delay of tcp connecting
+	// +kubebuilder:default=5000
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MeanAccessDelayInMs *int64 `json:"meanAccessDelayInMs,omitempty"`
+
+	// the minimum mean throughput of a single connection
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MinThroughputInKbps *int64 `json:"minThroughputInKbps,omitempty"`
+}
+
+// scope(Namespaced or Cluster)
+// +kubebuilder:resource:categories={kdoctor},path="nettcps",singular="nettcp",shortName={nt},scope="Cluster"
+// +kubebuilder:printcolumn:JSONPath=".status.finish",description="finish",name="finish",type=boolean
+// +kubebuilder:printcolumn:JSONPath=".status.expectedRound",description="expectedRound",name="expectedRound",type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.doneRound",description="doneRound",name="doneRound",type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.lastRoundStatus",description="lastRoundStatus",name="lastRoundStatus",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.schedule.schedule",description="schedule",name="schedule",type=string
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +genclient
+// +genclient:nonNamespaced
+
+type NetTcp struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   NetTcpSpec `json:"spec,omitempty"`
+	Status TaskStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type NetTcpList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NetTcp `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetTcp{}, &NetTcpList{})
+}
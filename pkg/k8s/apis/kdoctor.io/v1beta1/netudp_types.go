@@ -0,0 +1,148 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NetUdpSpec struct {
+	// for the nested field, you should add the kubebuilder default tag even if the nested field properties own the default value.
+
+	// +kubebuilder:validation:Optional
+	AgentSpec *AgentSpec `json:"agentSpec,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Schedule *SchedulePlan `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Target *NetUdpTarget `json:"target,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Request *NetUdpRequest `json:"request,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	SuccessCondition *NetUdpSuccessCondition `json:"expect,omitempty"`
+	// +kubebuilder:validation:Optional
+	ReportSinks []ReportSink `json:"reportSinks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Baseline *Baseline `json:"baseline,omitempty"`
+}
+
+type NetUdpTarget struct {
+	// +kubebuilder:default=true
+	// +kubebuilder:validation:Optional
+	IPv4 *bool `json:"ipv4,omitempty"`
+
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:Optional
+	IPv6 *bool `json:"ipv6,omitempty"`
+
+	// +kubebuilder:default=true
+	Endpoint *bool `json:"endpoint,omitempty"`
+
+	// +kubebuilder:default=false
+	MultusInterface *bool `json:"multusInterface,omitempty"`
+
+	// +kubebuilder:default=true
+	ClusterIP *bool `json:"clusterIP,omitempty"`
+
+	// +kubebuilder:default=true
+	NodePort *bool `json:"nodePort,omitempty"`
+
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:Optional
+	EnableLatencyMetric bool `json:"enableLatencyMetric,omitempty"`
+}
+
+type NetUdpRequest struct {
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=2
+	// +kubebuilder:validation:Minimum=1
+	DurationInSecond int `json:"durationInSecond,omitempty"`
+
+	// the number of udp packets sent per second
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	PacketRate int `json:"packetRate,omitempty"`
+
+	// the time to wait for the echo of a packet, or else the packet is considered as lost
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1000
+	// +kubebuilder:validation:Minimum=1
+	PerPacketTimeoutInMS int `json:"perPacketTimeoutInMS,omitempty"`
+
+	// the size of each udp packet, which should not be smaller than the size of the packet header
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=64
+	// +kubebuilder:validation:Minimum=20
+	// +kubebuilder:validation:Maximum=65507
+	PayloadSizeInByte int `json:"payloadSizeInByte,omitempty"`
+}
+
+type NetUdpSuccessCondition struct {
+
+	// the maximum percentage of the lost packets
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxLossPercentage *float64 `json:"maxLossPercentage,omitempty"`
+
+	// the maximum jitter of the round trip time, calculated as RFC 3550
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxJitterInMs *float64 `json:"maxJitterInMs,omitempty"`
+
+	// the maximum number of the packets received out of order
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxOutOfOrderCounts *int64 `json:"maxOutOfOrderCounts,omitempty"`
+
+	// the maximum number of the duplicate packets
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Optional
+	MaxDuplicateCounts *int64 `json:"maxDuplicateCounts,omitempty"`
+
+	// the mean round trip time of the packets
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MeanAccessDelayInMs *int64 `json:"meanAccessDelayInMs,omitempty"`
+}
+
+// scope(Namespaced or Cluster)
+// +kubebuilder:resource:categories={kdoctor},path="netudps",singular="netudp",shortName={nu},scope="Cluster"
+// +kubebuilder:printcolumn:JSONPath=".status.finish",description="finish",name="finish",type=boolean
+// +kubebuilder:printcolumn:JSONPath=".status.expectedRound",description="expectedRound",name="expectedRound",type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.doneRound",description="doneRound",name="doneRound",type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.lastRoundStatus",description="lastRoundStatus",name="lastRoundStatus",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.schedule.schedule",description="schedule",name="schedule",type=string
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +genclient
+// +genclient:nonNamespaced
+
+type NetUdp struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   NetUdpSpec `json:"spec,omitempty"`
+	Status TaskStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type NetUdpList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NetUdp `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetUdp{}, &NetUdpList{})
+}
@@ -14,7 +14,19 @@
 // +kubebuilder:rbac:groups=kdoctor.io,resources=netdnses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kdoctor.io,resources=netdnses/status,verbs=get;update;patch
 
-// +kubebuilder:rbac:groups="",resources=events,verbs=create;get;list;watch;update;delete
+// +kubebuilder:rbac:groups=kdoctor.io,resources=nettcps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kdoctor.io,resources=nettcps/status,verbs=get;update;patch
+
+// +kubebuilder:rbac:groups=kdoctor.io,resources=netudps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kdoctor.io,resources=netudps/status,verbs=get;update;patch
+
+// +kubebuilder:rbac:groups=kdoctor.io,resources=netdelays,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kdoctor.io,resources=netdelays/status,verbs=get;update;patch
+
+// +kubebuilder:rbac:groups=kdoctor.io,resources=tasksuites,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kdoctor.io,resources=tasksuites/status,verbs=get;update;patch
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=create;get;update
 // +kubebuilder:rbac:groups="apps",resources=statefulsets;deployments;replicasets;daemonsets,verbs=get;list;update;watch
 // +kubebuilder:rbac:groups="batch",resources=jobs;cronjobs,verbs=get;list;update;watch
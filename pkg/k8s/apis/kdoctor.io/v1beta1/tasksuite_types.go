@@ -0,0 +1,161 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type TaskSuiteSpec struct {
+	// each round of the suite runs one round of all steps in the order of the dependencies,
+	// and the roundTimeoutMinute is the round timeout of each step
+	// +kubebuilder:validation:Optional
+	Schedule *SchedulePlan `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	Steps []TaskSuiteStep `json:"steps"`
+}
+
+// TaskSuiteStep runs the task of one of the specs, whose schedule is set by the suite
+type TaskSuiteStep struct {
+	// the unique name of the step in the suite
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// the steps whose rounds finish before the step starts
+	// +kubebuilder:validation:Optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// the steps depending on the step still start when the step fails, otherwise they are skipped.
+	// The failed step fails the round of the suite either way
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	ContinueOnFailure bool `json:"continueOnFailure,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	NetReach *NetReachSpec `json:"netReach,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	AppHttpHealthy *AppHttpHealthySpec `json:"appHttpHealthy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Netdns *NetdnsSpec `json:"netdns,omitempty"`
+}
+
+type TaskSuiteStatus struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=-1
+	ExpectedRound *int64 `json:"expectedRound,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	DoneRound *int64 `json:"doneRound,omitempty"`
+
+	Finish bool `json:"finish"`
+
+	// +kubebuilder:validation:Optional
+	FinishTime *metav1.Time `json:"finishTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=succeed;fail
+	LastRoundStatus *string `json:"lastRoundStatus,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	History []TaskSuiteRoundRecord `json:"history,omitempty"`
+
+	// the start times of the next planned rounds, at most 5, and it is empty when the suite is suspended or finished
+	// +kubebuilder:validation:Optional
+	NextRoundTimes []metav1.Time `json:"nextRoundTimes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type TaskSuiteRoundRecord struct {
+	// +kubebuilder:validation:Enum=succeed;fail;ongoing;notstarted
+	Status string `json:"status"`
+
+	// +kubebuilder:validation:Optional
+	FailureReason string `json:"failureReason,omitempty"`
+
+	RoundNumber int `json:"roundNumber"`
+
+	// +kubebuilder:validation:Type:=string
+	// +kubebuilder:validation:Format:=date-time
+	StartTimeStamp metav1.Time `json:"startTimeStamp"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Type:=string
+	// +kubebuilder:validation:Format:=date-time
+	EndTimeStamp *metav1.Time `json:"endTimeStamp,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Duration *string `json:"duration,omitempty"`
+
+	Steps []TaskSuiteStepStatus `json:"steps"`
+}
+
+const (
+	TaskSuiteStepStatusPending = "pending"
+	TaskSuiteStepStatusRunning = "running"
+	TaskSuiteStepStatusSucceed = "succeed"
+	TaskSuiteStepStatusFail    = "fail"
+	TaskSuiteStepStatusSkipped = "skipped"
+)
+
+type TaskSuiteStepStatus struct {
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=NetReach;AppHttpHealthy;Netdns
+	Kind string `json:"kind"`
+
+	// the task created for the round of the suite
+	// +kubebuilder:validation:Optional
+	TaskName string `json:"taskName,omitempty"`
+
+	// +kubebuilder:validation:Enum=pending;running;succeed;fail;skipped
+	Status string `json:"status"`
+
+	// +kubebuilder:validation:Optional
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// the reason of the event when a step of the suite fails or is skipped
+const TaskSuiteReasonStepFailed = "StepFailed"
+
+// scope(Namespaced or Cluster)
+// +kubebuilder:resource:categories={kdoctor},path="tasksuites",singular="tasksuite",scope="Cluster"
+// +kubebuilder:printcolumn:JSONPath=".status.finish",description="finish",name="finish",type=boolean
+// +kubebuilder:printcolumn:JSONPath=".status.expectedRound",description="expectedRound",name="expectedRound",type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.doneRound",description="doneRound",name="doneRound",type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.lastRoundStatus",description="lastRoundStatus",name="lastRoundStatus",type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.schedule.schedule",description="schedule",name="schedule",type=string
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +genclient
+// +genclient:nonNamespaced
+
+type TaskSuite struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   TaskSuiteSpec   `json:"spec,omitempty"`
+	Status TaskSuiteStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type TaskSuiteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TaskSuite `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TaskSuite{}, &TaskSuiteList{})
+}
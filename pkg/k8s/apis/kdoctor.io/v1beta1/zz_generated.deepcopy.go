@@ -6,8 +6,8 @@
 package v1beta1
 
 import (
-	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -28,19 +28,19 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.TerminationGracePeriodMinutes != nil {
@@ -60,6 +60,31 @@ func (in *AgentSpec) DeepCopy() *AgentSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppHttpDetect) DeepCopyInto(out *AppHttpDetect) {
+	*out = *in
+	if in.SuccessRate != nil {
+		in, out := &in.SuccessRate, &out.SuccessRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxP99InMs != nil {
+		in, out := &in.MaxP99InMs, &out.MaxP99InMs
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppHttpDetect.
+func (in *AppHttpDetect) DeepCopy() *AppHttpDetect {
+	if in == nil {
+		return nil
+	}
+	out := new(AppHttpDetect)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppHttpHealthy) DeepCopyInto(out *AppHttpHealthy) {
 	*out = *in
@@ -147,6 +172,23 @@ func (in *AppHttpHealthySpec) DeepCopyInto(out *AppHttpHealthySpec) {
 		*out = new(NetSuccessCondition)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Detect != nil {
+		in, out := &in.Detect, &out.Detect
+		*out = new(AppHttpDetect)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportSinks != nil {
+		in, out := &in.ReportSinks, &out.ReportSinks
+		*out = make([]ReportSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(Baseline)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppHttpHealthySpec.
@@ -199,6 +241,257 @@ func (in *AppHttpHealthyTarget) DeepCopy() *AppHttpHealthyTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Baseline) DeepCopyInto(out *Baseline) {
+	*out = *in
+	if in.Round != nil {
+		in, out := &in.Round, &out.Round
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(int64)
+		**out = **in
+	}
+	if in.P50IncreasePercent != nil {
+		in, out := &in.P50IncreasePercent, &out.P50IncreasePercent
+		*out = new(float64)
+		**out = **in
+	}
+	if in.P99IncreasePercent != nil {
+		in, out := &in.P99IncreasePercent, &out.P99IncreasePercent
+		*out = new(float64)
+		**out = **in
+	}
+	if in.SuccessRateDecreasePercent != nil {
+		in, out := &in.SuccessRateDecreasePercent, &out.SuccessRateDecreasePercent
+		*out = new(float64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Baseline.
+func (in *Baseline) DeepCopy() *Baseline {
+	if in == nil {
+		return nil
+	}
+	out := new(Baseline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelay) DeepCopyInto(out *NetDelay) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelay.
+func (in *NetDelay) DeepCopy() *NetDelay {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetDelay) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelayList) DeepCopyInto(out *NetDelayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetDelay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelayList.
+func (in *NetDelayList) DeepCopy() *NetDelayList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetDelayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelayRequest) DeepCopyInto(out *NetDelayRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelayRequest.
+func (in *NetDelayRequest) DeepCopy() *NetDelayRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelayRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelaySpec) DeepCopyInto(out *NetDelaySpec) {
+	*out = *in
+	if in.AgentSpec != nil {
+		in, out := &in.AgentSpec, &out.AgentSpec
+		*out = new(AgentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(SchedulePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(NetDelayTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(NetDelayRequest)
+		**out = **in
+	}
+	if in.SuccessCondition != nil {
+		in, out := &in.SuccessCondition, &out.SuccessCondition
+		*out = new(NetDelaySuccessCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportSinks != nil {
+		in, out := &in.ReportSinks, &out.ReportSinks
+		*out = make([]ReportSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(Baseline)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelaySpec.
+func (in *NetDelaySpec) DeepCopy() *NetDelaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelaySuccessCondition) DeepCopyInto(out *NetDelaySuccessCondition) {
+	*out = *in
+	if in.SuccessRate != nil {
+		in, out := &in.SuccessRate, &out.SuccessRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxPairP50InMs != nil {
+		in, out := &in.MaxPairP50InMs, &out.MaxPairP50InMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxPairP99InMs != nil {
+		in, out := &in.MaxPairP99InMs, &out.MaxPairP99InMs
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelaySuccessCondition.
+func (in *NetDelaySuccessCondition) DeepCopy() *NetDelaySuccessCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelaySuccessCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelayTarget) DeepCopyInto(out *NetDelayTarget) {
+	*out = *in
+	if in.IPv4 != nil {
+		in, out := &in.IPv4, &out.IPv4
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MultusInterface != nil {
+		in, out := &in.MultusInterface, &out.MultusInterface
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelayTarget.
+func (in *NetDelayTarget) DeepCopy() *NetDelayTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelayTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDnsDnssec) DeepCopyInto(out *NetDnsDnssec) {
+	*out = *in
+	if in.CheckAD != nil {
+		in, out := &in.CheckAD, &out.CheckAD
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TrustAnchors != nil {
+		in, out := &in.TrustAnchors, &out.TrustAnchors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDnsDnssec.
+func (in *NetDnsDnssec) DeepCopy() *NetDnsDnssec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDnsDnssec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetDnsTarget) DeepCopyInto(out *NetDnsTarget) {
 	*out = *in
@@ -212,6 +505,11 @@ func (in *NetDnsTarget) DeepCopyInto(out *NetDnsTarget) {
 		*out = new(NetDnsTargetDnsSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Dnssec != nil {
+		in, out := &in.Dnssec, &out.Dnssec
+		*out = new(NetDnsDnssec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDnsTarget.
@@ -386,6 +684,18 @@ func (in *NetReachSpec) DeepCopyInto(out *NetReachSpec) {
 		*out = new(NetSuccessCondition)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReportSinks != nil {
+		in, out := &in.ReportSinks, &out.ReportSinks
+		*out = make([]ReportSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(Baseline)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetReachSpec.
@@ -484,7 +794,7 @@ func (in *NetSuccessCondition) DeepCopy() *NetSuccessCondition {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Netdns) DeepCopyInto(out *Netdns) {
+func (in *NetTcp) DeepCopyInto(out *NetTcp) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -492,18 +802,18 @@ func (in *Netdns) DeepCopyInto(out *Netdns) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Netdns.
-func (in *Netdns) DeepCopy() *Netdns {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetTcp.
+func (in *NetTcp) DeepCopy() *NetTcp {
 	if in == nil {
 		return nil
 	}
-	out := new(Netdns)
+	out := new(NetTcp)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Netdns) DeepCopyObject() runtime.Object {
+func (in *NetTcp) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -511,31 +821,31 @@ func (in *Netdns) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetdnsList) DeepCopyInto(out *NetdnsList) {
+func (in *NetTcpList) DeepCopyInto(out *NetTcpList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Netdns, len(*in))
+		*out = make([]NetTcp, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetdnsList.
-func (in *NetdnsList) DeepCopy() *NetdnsList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetTcpList.
+func (in *NetTcpList) DeepCopy() *NetTcpList {
 	if in == nil {
 		return nil
 	}
-	out := new(NetdnsList)
+	out := new(NetTcpList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NetdnsList) DeepCopyObject() runtime.Object {
+func (in *NetTcpList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -543,27 +853,22 @@ func (in *NetdnsList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetdnsRequest) DeepCopyInto(out *NetdnsRequest) {
+func (in *NetTcpRequest) DeepCopyInto(out *NetTcpRequest) {
 	*out = *in
-	if in.Protocol != nil {
-		in, out := &in.Protocol, &out.Protocol
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetdnsRequest.
-func (in *NetdnsRequest) DeepCopy() *NetdnsRequest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetTcpRequest.
+func (in *NetTcpRequest) DeepCopy() *NetTcpRequest {
 	if in == nil {
 		return nil
 	}
-	out := new(NetdnsRequest)
+	out := new(NetTcpRequest)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetdnsSpec) DeepCopyInto(out *NetdnsSpec) {
+func (in *NetTcpSpec) DeepCopyInto(out *NetTcpSpec) {
 	*out = *in
 	if in.AgentSpec != nil {
 		in, out := &in.AgentSpec, &out.AgentSpec
@@ -575,28 +880,524 @@ func (in *NetdnsSpec) DeepCopyInto(out *NetdnsSpec) {
 		*out = new(SchedulePlan)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.SourceAgentNodeSelector != nil {
-		in, out := &in.SourceAgentNodeSelector, &out.SourceAgentNodeSelector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
 	if in.Target != nil {
 		in, out := &in.Target, &out.Target
-		*out = new(NetDnsTarget)
+		*out = new(NetTcpTarget)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Request != nil {
 		in, out := &in.Request, &out.Request
-		*out = new(NetdnsRequest)
-		(*in).DeepCopyInto(*out)
+		*out = new(NetTcpRequest)
+		**out = **in
 	}
 	if in.SuccessCondition != nil {
 		in, out := &in.SuccessCondition, &out.SuccessCondition
-		*out = new(NetSuccessCondition)
+		*out = new(NetTcpSuccessCondition)
 		(*in).DeepCopyInto(*out)
 	}
-}
-
+	if in.ReportSinks != nil {
+		in, out := &in.ReportSinks, &out.ReportSinks
+		*out = make([]ReportSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(Baseline)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetTcpSpec.
+func (in *NetTcpSpec) DeepCopy() *NetTcpSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetTcpSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetTcpSuccessCondition) DeepCopyInto(out *NetTcpSuccessCondition) {
+	*out = *in
+	if in.SuccessRate != nil {
+		in, out := &in.SuccessRate, &out.SuccessRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MeanAccessDelayInMs != nil {
+		in, out := &in.MeanAccessDelayInMs, &out.MeanAccessDelayInMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinThroughputInKbps != nil {
+		in, out := &in.MinThroughputInKbps, &out.MinThroughputInKbps
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetTcpSuccessCondition.
+func (in *NetTcpSuccessCondition) DeepCopy() *NetTcpSuccessCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(NetTcpSuccessCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetTcpTarget) DeepCopyInto(out *NetTcpTarget) {
+	*out = *in
+	if in.IPv4 != nil {
+		in, out := &in.IPv4, &out.IPv4
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MultusInterface != nil {
+		in, out := &in.MultusInterface, &out.MultusInterface
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ClusterIP != nil {
+		in, out := &in.ClusterIP, &out.ClusterIP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetTcpTarget.
+func (in *NetTcpTarget) DeepCopy() *NetTcpTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NetTcpTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetUdp) DeepCopyInto(out *NetUdp) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetUdp.
+func (in *NetUdp) DeepCopy() *NetUdp {
+	if in == nil {
+		return nil
+	}
+	out := new(NetUdp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetUdp) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetUdpList) DeepCopyInto(out *NetUdpList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetUdp, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetUdpList.
+func (in *NetUdpList) DeepCopy() *NetUdpList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetUdpList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetUdpList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetUdpRequest) DeepCopyInto(out *NetUdpRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetUdpRequest.
+func (in *NetUdpRequest) DeepCopy() *NetUdpRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(NetUdpRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetUdpSpec) DeepCopyInto(out *NetUdpSpec) {
+	*out = *in
+	if in.AgentSpec != nil {
+		in, out := &in.AgentSpec, &out.AgentSpec
+		*out = new(AgentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(SchedulePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(NetUdpTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(NetUdpRequest)
+		**out = **in
+	}
+	if in.SuccessCondition != nil {
+		in, out := &in.SuccessCondition, &out.SuccessCondition
+		*out = new(NetUdpSuccessCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportSinks != nil {
+		in, out := &in.ReportSinks, &out.ReportSinks
+		*out = make([]ReportSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(Baseline)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetUdpSpec.
+func (in *NetUdpSpec) DeepCopy() *NetUdpSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetUdpSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetUdpSuccessCondition) DeepCopyInto(out *NetUdpSuccessCondition) {
+	*out = *in
+	if in.MaxLossPercentage != nil {
+		in, out := &in.MaxLossPercentage, &out.MaxLossPercentage
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxJitterInMs != nil {
+		in, out := &in.MaxJitterInMs, &out.MaxJitterInMs
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxOutOfOrderCounts != nil {
+		in, out := &in.MaxOutOfOrderCounts, &out.MaxOutOfOrderCounts
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxDuplicateCounts != nil {
+		in, out := &in.MaxDuplicateCounts, &out.MaxDuplicateCounts
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MeanAccessDelayInMs != nil {
+		in, out := &in.MeanAccessDelayInMs, &out.MeanAccessDelayInMs
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetUdpSuccessCondition.
+func (in *NetUdpSuccessCondition) DeepCopy() *NetUdpSuccessCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(NetUdpSuccessCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetUdpTarget) DeepCopyInto(out *NetUdpTarget) {
+	*out = *in
+	if in.IPv4 != nil {
+		in, out := &in.IPv4, &out.IPv4
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IPv6 != nil {
+		in, out := &in.IPv6, &out.IPv6
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MultusInterface != nil {
+		in, out := &in.MultusInterface, &out.MultusInterface
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ClusterIP != nil {
+		in, out := &in.ClusterIP, &out.ClusterIP
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodePort != nil {
+		in, out := &in.NodePort, &out.NodePort
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetUdpTarget.
+func (in *NetUdpTarget) DeepCopy() *NetUdpTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NetUdpTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Netdns) DeepCopyInto(out *Netdns) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Netdns.
+func (in *Netdns) DeepCopy() *Netdns {
+	if in == nil {
+		return nil
+	}
+	out := new(Netdns)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Netdns) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetdnsDetect) DeepCopyInto(out *NetdnsDetect) {
+	*out = *in
+	if in.SuccessRate != nil {
+		in, out := &in.SuccessRate, &out.SuccessRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxP99InMs != nil {
+		in, out := &in.MaxP99InMs, &out.MaxP99InMs
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetdnsDetect.
+func (in *NetdnsDetect) DeepCopy() *NetdnsDetect {
+	if in == nil {
+		return nil
+	}
+	out := new(NetdnsDetect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetdnsList) DeepCopyInto(out *NetdnsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Netdns, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetdnsList.
+func (in *NetdnsList) DeepCopy() *NetdnsList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetdnsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetdnsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetdnsQuery) DeepCopyInto(out *NetdnsQuery) {
+	*out = *in
+	if in.ExpectedAnswers != nil {
+		in, out := &in.ExpectedAnswers, &out.ExpectedAnswers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetdnsQuery.
+func (in *NetdnsQuery) DeepCopy() *NetdnsQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(NetdnsQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetdnsRequest) DeepCopyInto(out *NetdnsRequest) {
+	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]NetdnsQuery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.DohMethod != nil {
+		in, out := &in.DohMethod, &out.DohMethod
+		*out = new(string)
+		**out = **in
+	}
+	if in.DohPath != nil {
+		in, out := &in.DohPath, &out.DohPath
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetdnsRequest.
+func (in *NetdnsRequest) DeepCopy() *NetdnsRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(NetdnsRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetdnsSpec) DeepCopyInto(out *NetdnsSpec) {
+	*out = *in
+	if in.AgentSpec != nil {
+		in, out := &in.AgentSpec, &out.AgentSpec
+		*out = new(AgentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(SchedulePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SourceAgentNodeSelector != nil {
+		in, out := &in.SourceAgentNodeSelector, &out.SourceAgentNodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(NetDnsTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(NetdnsRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SuccessCondition != nil {
+		in, out := &in.SuccessCondition, &out.SuccessCondition
+		*out = new(NetSuccessCondition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Detect != nil {
+		in, out := &in.Detect, &out.Detect
+		*out = new(NetdnsDetect)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReportSinks != nil {
+		in, out := &in.ReportSinks, &out.ReportSinks
+		*out = make([]ReportSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(Baseline)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetdnsSpec.
 func (in *NetdnsSpec) DeepCopy() *NetdnsSpec {
 	if in == nil {
@@ -607,6 +1408,41 @@ func (in *NetdnsSpec) DeepCopy() *NetdnsSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportSink) DeepCopyInto(out *ReportSink) {
+	*out = *in
+	if in.HmacSecret != nil {
+		in, out := &in.HmacSecret, &out.HmacSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.FailedRoundOnly != nil {
+		in, out := &in.FailedRoundOnly, &out.FailedRoundOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutInSecond != nil {
+		in, out := &in.TimeoutInSecond, &out.TimeoutInSecond
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportSink.
+func (in *ReportSink) DeepCopy() *ReportSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchedulePlan) DeepCopyInto(out *SchedulePlan) {
 	*out = *in
@@ -615,6 +1451,11 @@ func (in *SchedulePlan) DeepCopyInto(out *SchedulePlan) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulePlan.
@@ -627,6 +1468,21 @@ func (in *SchedulePlan) DeepCopy() *SchedulePlan {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusHistoryRecord) DeepCopyInto(out *StatusHistoryRecord) {
 	*out = *in
@@ -661,6 +1517,15 @@ func (in *StatusHistoryRecord) DeepCopyInto(out *StatusHistoryRecord) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Regressed != nil {
+		in, out := &in.Regressed, &out.Regressed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScheduledTimeStamp != nil {
+		in, out := &in.ScheduledTimeStamp, &out.ScheduledTimeStamp
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusHistoryRecord.
@@ -732,6 +1597,20 @@ func (in *TaskStatus) DeepCopyInto(out *TaskStatus) {
 		*out = new(TaskResource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NextRoundTimes != nil {
+		in, out := &in.NextRoundTimes, &out.NextRoundTimes
+		*out = make([]v1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskStatus.
@@ -743,3 +1622,224 @@ func (in *TaskStatus) DeepCopy() *TaskStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskSuite) DeepCopyInto(out *TaskSuite) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSuite.
+func (in *TaskSuite) DeepCopy() *TaskSuite {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskSuite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskSuite) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskSuiteList) DeepCopyInto(out *TaskSuiteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TaskSuite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSuiteList.
+func (in *TaskSuiteList) DeepCopy() *TaskSuiteList {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskSuiteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskSuiteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskSuiteRoundRecord) DeepCopyInto(out *TaskSuiteRoundRecord) {
+	*out = *in
+	in.StartTimeStamp.DeepCopyInto(&out.StartTimeStamp)
+	if in.EndTimeStamp != nil {
+		in, out := &in.EndTimeStamp, &out.EndTimeStamp
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(string)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TaskSuiteStepStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSuiteRoundRecord.
+func (in *TaskSuiteRoundRecord) DeepCopy() *TaskSuiteRoundRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskSuiteRoundRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskSuiteSpec) DeepCopyInto(out *TaskSuiteSpec) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(SchedulePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]TaskSuiteStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSuiteSpec.
+func (in *TaskSuiteSpec) DeepCopy() *TaskSuiteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskSuiteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskSuiteStatus) DeepCopyInto(out *TaskSuiteStatus) {
+	*out = *in
+	if in.ExpectedRound != nil {
+		in, out := &in.ExpectedRound, &out.ExpectedRound
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DoneRound != nil {
+		in, out := &in.DoneRound, &out.DoneRound
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FinishTime != nil {
+		in, out := &in.FinishTime, &out.FinishTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRoundStatus != nil {
+		in, out := &in.LastRoundStatus, &out.LastRoundStatus
+		*out = new(string)
+		**out = **in
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]TaskSuiteRoundRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextRoundTimes != nil {
+		in, out := &in.NextRoundTimes, &out.NextRoundTimes
+		*out = make([]v1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSuiteStatus.
+func (in *TaskSuiteStatus) DeepCopy() *TaskSuiteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskSuiteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskSuiteStep) DeepCopyInto(out *TaskSuiteStep) {
+	*out = *in
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NetReach != nil {
+		in, out := &in.NetReach, &out.NetReach
+		*out = new(NetReachSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppHttpHealthy != nil {
+		in, out := &in.AppHttpHealthy, &out.AppHttpHealthy
+		*out = new(AppHttpHealthySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Netdns != nil {
+		in, out := &in.Netdns, &out.Netdns
+		*out = new(NetdnsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSuiteStep.
+func (in *TaskSuiteStep) DeepCopy() *TaskSuiteStep {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskSuiteStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskSuiteStepStatus) DeepCopyInto(out *TaskSuiteStepStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSuiteStepStatus.
+func (in *TaskSuiteStepStatus) DeepCopy() *TaskSuiteStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskSuiteStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,6 +17,7 @@ type AppHttpHealthyTask struct {
 	SystemResource   SystemResource             `json:"systemResource"`
 	TotalRunningLoad TotalRunningLoad           `json:"runningLoadTotal"`
 	Detail           []AppHttpHealthyTaskDetail `json:"roundTaskDetail"`
+	Detect           *HttpDetectResult          `json:"detect,omitempty"`
 }
 
 type AppHttpHealthyTaskDetail struct {
@@ -46,6 +47,26 @@ type HttpMetrics struct {
 	StatusCodes   map[int]int `json:"statusCodes"`
 }
 
+// HttpDetectResult is the result of ramping the qps step by step in the detect mode
+type HttpDetectResult struct {
+	// the maximum qps sustained by the target, 0 means even the first step breaks the threshold
+	KneeQPS int `json:"kneeQPS"`
+	// the reason why the ramp stops before reaching the maximum qps
+	BreakReason *string          `json:"breakReason,omitempty"`
+	Steps       []HttpDetectStep `json:"steps"`
+}
+
+type HttpDetectStep struct {
+	QPS           int                 `json:"qps"`
+	TPS           float64             `json:"tps"`
+	RequestCounts int64               `json:"requestCounts"`
+	SuccessCounts int64               `json:"successCounts"`
+	SucceedRate   float64             `json:"succeedRate"`
+	Latencies     LatencyDistribution `json:"latencies"`
+	Succeed       bool                `json:"succeed"`
+	FailureReason *string             `json:"failureReason,omitempty"`
+}
+
 func (h *AppHttpHealthyTask) KindTask() string {
 	return AppHttpHealthyTaskName
 }
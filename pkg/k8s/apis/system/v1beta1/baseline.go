@@ -0,0 +1,46 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// BaselineNodeName takes the place of the node name in the file name of the baseline comparison,
+// which is built by the controller for the task with the baseline
+const BaselineNodeName = "baseline"
+
+// the metrics compared with the baseline
+const (
+	BaselineMetricP50         = "p50"
+	BaselineMetricP99         = "p99"
+	BaselineMetricSuccessRate = "successRate"
+)
+
+// BaselineComparison is the comparison of the metrics of each target in a round with the baseline
+type BaselineComparison struct {
+	RoundNumber int64 `json:"roundNumber"`
+	// the rounds whose reports are the baseline
+	BaselineRounds []int64 `json:"baselineRounds"`
+	// whether any target gets worse than the baseline by more than the percentages
+	Regressed bool `json:"regressed"`
+	// the targets which get worse, in the format of "node/target: metric +12.3%"
+	RegressionReasons []string             `json:"regressionReasons,omitempty"`
+	Targets           []TargetBaselineDiff `json:"targets"`
+}
+
+// TargetBaselineDiff is the diff of the metrics of a target from an agent, and the change is the percentage of the baseline
+type TargetBaselineDiff struct {
+	NodeName   string `json:"nodeName"`
+	TargetName string `json:"name"`
+
+	P50InMs                  float32 `json:"p50InMs"`
+	BaselineP50InMs          float32 `json:"baselineP50InMs"`
+	P50ChangePercent         float64 `json:"p50ChangePercent"`
+	P99InMs                  float32 `json:"p99InMs"`
+	BaselineP99InMs          float32 `json:"baselineP99InMs"`
+	P99ChangePercent         float64 `json:"p99ChangePercent"`
+	SuccessRate              float64 `json:"successRate"`
+	BaselineSuccessRate      float64 `json:"baselineSuccessRate"`
+	SuccessRateChangePercent float64 `json:"successRateChangePercent"`
+
+	// the metrics which get worse than the baseline by more than the percentages
+	RegressedMetrics []string `json:"regressedMetrics,omitempty"`
+}
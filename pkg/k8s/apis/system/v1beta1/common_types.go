@@ -24,6 +24,9 @@ type TotalRunningLoad struct {
 	AppHttpHealthyQPS int64 `json:"appHttpHealthyQPS"`
 	NetReachQPS       int64 `json:"netReachQPS"`
 	NetDnsQPS         int64 `json:"netDnsQPS"`
+	NetTcpQPS         int64 `json:"netTcpQPS"`
+	NetUdpQPS         int64 `json:"netUdpQPS"`
+	NetDelayQPS       int64 `json:"netDelayQPS"`
 }
 
 type SystemResource struct {
@@ -26,6 +26,18 @@ type KdoctorReport struct {
 
 type Reports struct {
 	LatestRoundReport *[]Report `json:"latestRoundReport,omitempty"`
+
+	// the agent reports of the rounds selected by the query parameters round, startRound and endRound
+	RoundReports *[]Report `json:"roundReports,omitempty"`
+
+	// the node-to-node round-trip time of the latest round, only for the NetDelay task
+	NetDelayMatrix *NetDelayMatrix `json:"netDelayMatrix,omitempty"`
+
+	// the detect results of all agents combined of the latest round, only for the Netdns task in the detect mode
+	NetDnsDetect *NetDnsDetectSummary `json:"netDnsDetect,omitempty"`
+
+	// the comparison of the latest round with the baseline, only for the task with the baseline
+	BaselineComparison *BaselineComparison `json:"baselineComparison,omitempty"`
 }
 
 // KdoctorReportList
@@ -37,6 +49,31 @@ type KdoctorReportList struct {
 	Items []KdoctorReport `json:"items"`
 }
 
+// KdoctorReportRounds is the subresource kdoctorreports/<name>/rounds, which lists the summary of each round of the task
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KdoctorReportRounds struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	TaskName string `json:"taskName"`
+	TaskType string `json:"taskType"`
+
+	Rounds []RoundSummary `json:"rounds"`
+}
+
+type RoundSummary struct {
+	RoundNumber int64 `json:"roundNumber"`
+	// the result of the round from the controller, or else it is fail when any agent fails, and ongoing for the others
+	RoundResult    string       `json:"roundResult"`
+	FailedReason   *string      `json:"reasonsForFailure,omitempty"`
+	StartTimeStamp *metav1.Time `json:"roundStartTimeStamp,omitempty"`
+	EndTimeStamp   *metav1.Time `json:"roundEndTimeStamp,omitempty"`
+	// the number of the agent reports of the round
+	AgentNumber       int64    `json:"agentNumber"`
+	FailedAgentNumber int64    `json:"failedAgentNumber"`
+	FailedNodeList    []string `json:"failedNodeList,omitempty"`
+}
+
 type Report struct {
 	RoundNumber    int64       `json:"roundNumber"`
 	RoundResult    string      `json:"roundResult"`
@@ -52,6 +89,12 @@ type Report struct {
 	TaskAppHttpHealthy *AppHttpHealthyTask `json:"taskAppHealthy,omitempty"`
 
 	TaskNetDNS *NetDNSTask `json:"taskNetDns,omitempty"`
+
+	TaskNetTcp *NetTcpTask `json:"taskNetTcp,omitempty"`
+
+	TaskNetUdp *NetUdpTask `json:"taskNetUdp,omitempty"`
+
+	TaskNetDelay *NetDelayTask `json:"taskNetDelay,omitempty"`
 }
 
 type Status struct {
@@ -73,4 +116,10 @@ type TaskSpec struct {
 	AppHttpHealthyTaskSpec *v1beta1.AppHttpHealthySpec `json:"appHttpHealthy,omitempty"`
 
 	NetDNSTaskSpec *v1beta1.NetdnsSpec `json:"netDns,omitempty"`
+
+	NetTcpTaskSpec *v1beta1.NetTcpSpec `json:"netTcp,omitempty"`
+
+	NetUdpTaskSpec *v1beta1.NetUdpSpec `json:"netUdp,omitempty"`
+
+	NetDelayTaskSpec *v1beta1.NetDelaySpec `json:"netDelay,omitempty"`
 }
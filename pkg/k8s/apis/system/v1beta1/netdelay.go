@@ -0,0 +1,60 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+const NetDelayTaskName = "NetDelay"
+
+// NetDelayMatrixNodeName takes the place of the node name in the file name of the matrix report,
+// which is aggregated by the controller from the reports of all agents in a round
+const NetDelayMatrixNodeName = "matrix"
+
+type NetDelayTask struct {
+	TargetType       string               `json:"targetType"`
+	TargetNumber     int64                `json:"targetNumber"`
+	FailureReason    *string              `json:"reasonsForFailure,omitempty"`
+	Succeed          bool                 `json:"roundSucceed"`
+	SystemResource   SystemResource       `json:"systemResource"`
+	TotalRunningLoad TotalRunningLoad     `json:"runningLoadTotal"`
+	Detail           []NetDelayTaskDetail `json:"roundTaskDetail"`
+}
+
+type NetDelayTaskDetail struct {
+	SourceNode    string     `json:"sourceNode"`
+	TargetNode    string     `json:"targetNode"`
+	TargetName    string     `json:"name"`
+	TargetAddress string     `json:"address"`
+	Succeed       bool       `json:"requestSucceed"`
+	SucceedRate   float64    `json:"requestSucceedRate"`
+	P50           float32    `json:"p50InMs"`
+	P99           float32    `json:"p99InMs"`
+	FailureReason *string    `json:"failureReason,omitempty"`
+	Metrics       TcpMetrics `json:"requestTargetMetrics"`
+}
+
+func (n *NetDelayTask) KindTask() string {
+	return NetDelayTaskName
+}
+
+// NetDelayMatrix is the round-trip time between each pair of nodes in a round
+type NetDelayMatrix struct {
+	RoundNumber   int64   `json:"roundNumber"`
+	Succeed       bool    `json:"roundSucceed"`
+	FailureReason *string `json:"reasonsForFailure,omitempty"`
+	// all nodes reported by the agents, sorted by name
+	Nodes []string `json:"nodes"`
+	// the pair with the biggest p99 round-trip time
+	SlowestPair *NetDelayPair `json:"slowestPair,omitempty"`
+	// keyed by the source node and then the destination node
+	Matrix map[string]map[string]NetDelayPair `json:"matrix"`
+}
+
+type NetDelayPair struct {
+	SourceNode      string  `json:"sourceNode"`
+	DestinationNode string  `json:"destinationNode"`
+	Succeed         bool    `json:"succeed"`
+	SucceedRate     float64 `json:"succeedRate"`
+	P50             float32 `json:"p50InMs"`
+	P99             float32 `json:"p99InMs"`
+	FailureReason   *string `json:"failureReason,omitempty"`
+}
@@ -9,6 +9,10 @@ import (
 
 const NetDNSTaskName = "Netdns"
 
+// NetDnsDetectNodeName takes the place of the node name in the file name of the detect summary,
+// which is aggregated by the controller from the reports of all agents in a round
+const NetDnsDetectNodeName = "dnsdetect"
+
 type NetDNSTask struct {
 	TargetType       string             `json:"targetType"`
 	TargetNumber     int64              `json:"targetNumber"`
@@ -23,11 +27,15 @@ type NetDNSTaskDetail struct {
 	TargetName     string     `json:"name"`
 	TargetServer   string     `json:"requestServer"`
 	TargetProtocol string     `json:"protocol"`
+	TargetDomain   string     `json:"domain,omitempty"`
+	QueryType      string     `json:"queryType,omitempty"`
 	Succeed        bool       `json:"requestSucceed"`
 	FailureReason  *string    `json:"reasonsForFailure"`
 	MeanDelay      float32    `json:"requestMeanDelay"`
 	SucceedRate    float64    `json:"requestSucceedRate"`
 	Metrics        DNSMetrics `json:"requestTargetMetrics"`
+	// only for the detect mode
+	Detect *DnsDetectResult `json:"detect,omitempty"`
 }
 
 type DNSMetrics struct {
@@ -45,8 +53,73 @@ type DNSMetrics struct {
 	DNSMethod             string              `json:"method"`
 	FailedCounts          int64               `json:"failedCounts"`
 	ReplyCode             map[string]int      `json:"replyCode"`
+	// the replies whose answers do not match the expected answers, which are counted as failures
+	UnexpectedAnswerCounts int64 `json:"unexpectedAnswerCounts"`
+	// the replies which fail the dnssec check, which are counted as failures
+	DnssecFailedCounts int64 `json:"dnssecFailedCounts"`
 }
 
 func (n *NetDNSTask) KindTask() string {
 	return NetDNSTaskName
 }
+
+// DnsDetectResult is the result of ramping the qps step by step against a dns server in the detect mode
+type DnsDetectResult struct {
+	// the maximum qps sustained by the dns server, 0 means even the first step breaks the threshold
+	KneeQPS int `json:"kneeQPS"`
+	// the reason why the ramp stops before reaching the maximum qps
+	BreakReason *string         `json:"breakReason,omitempty"`
+	Steps       []DnsDetectStep `json:"steps"`
+}
+
+type DnsDetectStep struct {
+	QPS           int                 `json:"qps"`
+	TPS           float64             `json:"tps"`
+	RequestCounts int64               `json:"requestCounts"`
+	SuccessCounts int64               `json:"successCounts"`
+	SucceedRate   float64             `json:"succeedRate"`
+	Latencies     LatencyDistribution `json:"latencies"`
+	ReplyCode     map[string]int      `json:"replyCode"`
+	Succeed       bool                `json:"succeed"`
+	FailureReason *string             `json:"failureReason,omitempty"`
+}
+
+// NetDnsDetectSummary combines the detect results of all agents in a round
+type NetDnsDetectSummary struct {
+	RoundNumber   int64   `json:"roundNumber"`
+	Succeed       bool    `json:"roundSucceed"`
+	FailureReason *string `json:"reasonsForFailure,omitempty"`
+	// the number of agents which report the round
+	AgentNumber int                  `json:"agentNumber"`
+	Targets     []NetDnsDetectTarget `json:"targets"`
+}
+
+type NetDnsDetectTarget struct {
+	TargetName   string `json:"name"`
+	TargetServer string `json:"server"`
+	// the knee qps of each agent, keyed by the agent pod name
+	AgentKneeQPS map[string]int `json:"agentKneeQPS"`
+	// the largest qps of all agents combined which is handled by the dns server
+	ClusterKneeQPS int                    `json:"clusterKneeQPS"`
+	BreakReason    *string                `json:"breakReason,omitempty"`
+	Steps          []NetDnsDetectCombined `json:"steps"`
+}
+
+// NetDnsDetectCombined is a step of all agents combined, since all agents ramp the qps at the same pace
+type NetDnsDetectCombined struct {
+	// the qps of each agent
+	QPS int `json:"qps"`
+	// the number of agents which run the step
+	AgentNumber int `json:"agentNumber"`
+	// the sum of the qps of all agents
+	ClusterQPS    int     `json:"clusterQPS"`
+	TPS           float64 `json:"tps"`
+	RequestCounts int64   `json:"requestCounts"`
+	SuccessCounts int64   `json:"successCounts"`
+	SucceedRate   float64 `json:"succeedRate"`
+	// the biggest p99 latency among the agents
+	MaxP99 float32 `json:"maxP99InMs"`
+	// a step succeeds only when all agents succeed in the step
+	Succeed       bool    `json:"succeed"`
+	FailureReason *string `json:"failureReason,omitempty"`
+}
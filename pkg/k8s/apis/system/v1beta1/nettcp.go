@@ -0,0 +1,56 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const NetTcpTaskName = "NetTcp"
+
+type NetTcpTask struct {
+	TargetType       string             `json:"targetType"`
+	TargetNumber     int64              `json:"targetNumber"`
+	FailureReason    *string            `json:"reasonsForFailure,omitempty"`
+	Succeed          bool               `json:"roundSucceed"`
+	SystemResource   SystemResource     `json:"systemResource"`
+	TotalRunningLoad TotalRunningLoad   `json:"runningLoadTotal"`
+	Detail           []NetTcpTaskDetail `json:"roundTaskDetail"`
+}
+
+type NetTcpTaskDetail struct {
+	TargetName       string     `json:"name"`
+	TargetAddress    string     `json:"address"`
+	Succeed          bool       `json:"requestSucceed"`
+	MeanDelay        float32    `json:"requestMeanDelay"`
+	SucceedRate      float64    `json:"requestSucceedRate"`
+	ThroughputInKbps float64    `json:"throughputInKbps"`
+	FailureReason    *string    `json:"failureReason,omitempty"`
+	Metrics          TcpMetrics `json:"requestTargetMetrics"`
+}
+
+type TcpMetrics struct {
+	StartTime             metav1.Time    `json:"requestStartTime"`
+	EndTime               metav1.Time    `json:"requestEndTime"`
+	Duration              string         `json:"requestDuration"`
+	RequestCounts         int64          `json:"requestCounts"`
+	SuccessCounts         int64          `json:"successCounts"`
+	TPS                   float64        `json:"tps"`
+	Errors                map[string]int `json:"errors"`
+	ExistsNotSendRequests bool           `json:"existsNotSendRequests"`
+	TargetAddress         string         `json:"address"`
+
+	// the delay of tcp connecting
+	ConnectLatencies LatencyDistribution `json:"connectLatencies"`
+	// the data size sent through each connection
+	PayloadSize int64 `json:"payloadSizeInByte"`
+	// the total data size confirmed by the server
+	TotalDataSize int64 `json:"totalDataSizeInByte"`
+	// the mean throughput of a single connection
+	ThroughputInKbps float64 `json:"throughputInKbps"`
+}
+
+func (n *NetTcpTask) KindTask() string {
+	return NetTcpTaskName
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const NetUdpTaskName = "NetUdp"
+
+type NetUdpTask struct {
+	TargetType       string             `json:"targetType"`
+	TargetNumber     int64              `json:"targetNumber"`
+	FailureReason    *string            `json:"reasonsForFailure,omitempty"`
+	Succeed          bool               `json:"roundSucceed"`
+	SystemResource   SystemResource     `json:"systemResource"`
+	TotalRunningLoad TotalRunningLoad   `json:"runningLoadTotal"`
+	Detail           []NetUdpTaskDetail `json:"roundTaskDetail"`
+}
+
+type NetUdpTaskDetail struct {
+	TargetName       string     `json:"name"`
+	TargetAddress    string     `json:"address"`
+	Succeed          bool       `json:"requestSucceed"`
+	MeanDelay        float32    `json:"requestMeanDelay"`
+	LossPercentage   float64    `json:"lossPercentage"`
+	OutOfOrderCounts int64      `json:"outOfOrderCounts"`
+	DuplicateCounts  int64      `json:"duplicateCounts"`
+	JitterInMs       float64    `json:"jitterInMs"`
+	FailureReason    *string    `json:"failureReason,omitempty"`
+	Metrics          UdpMetrics `json:"requestTargetMetrics"`
+}
+
+type UdpMetrics struct {
+	StartTime             metav1.Time    `json:"requestStartTime"`
+	EndTime               metav1.Time    `json:"requestEndTime"`
+	Duration              string         `json:"requestDuration"`
+	SendCounts            int64          `json:"sendCounts"`
+	ReceivedCounts        int64          `json:"receivedCounts"`
+	PPS                   float64        `json:"pps"`
+	Errors                map[string]int `json:"errors"`
+	ExistsNotSendRequests bool           `json:"existsNotSendRequests"`
+	TargetAddress         string         `json:"address"`
+	PayloadSize           int64          `json:"payloadSizeInByte"`
+
+	// the percentage of the packets without echo in time
+	LossPercentage float64 `json:"lossPercentage"`
+	// the number of the packets received with a sequence smaller than the largest one received before
+	OutOfOrderCounts int64 `json:"outOfOrderCounts"`
+	// the number of the packets received more than once
+	DuplicateCounts int64 `json:"duplicateCounts"`
+	// the interarrival jitter of the round trip time, calculated as RFC 3550
+	JitterInMs float64 `json:"jitterInMs"`
+	// the round trip time of the packets
+	Latencies LatencyDistribution `json:"latencies"`
+}
+
+func (n *NetUdpTask) KindTask() string {
+	return NetUdpTaskName
+}
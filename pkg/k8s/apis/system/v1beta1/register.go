@@ -11,10 +11,11 @@ import (
 
 const (
 	// GroupName is the group name used in this package.
-	GroupName             = "system.kdoctor.io"
-	V1betaVersion         = "v1beta1"
-	KindKdoctorReport     = "KdoctorReport"
-	KindKdoctorReportList = "KdoctorReportList"
+	GroupName               = "system.kdoctor.io"
+	V1betaVersion           = "v1beta1"
+	KindKdoctorReport       = "KdoctorReport"
+	KindKdoctorReportList   = "KdoctorReportList"
+	KindKdoctorReportRounds = "KdoctorReportRounds"
 )
 
 // SchemeGroupVersion is group version used to register these objects.
@@ -32,6 +33,7 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&KdoctorReport{},
 		&KdoctorReportList{},
+		&KdoctorReportRounds{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
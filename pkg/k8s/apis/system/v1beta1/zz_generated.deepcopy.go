@@ -27,6 +27,11 @@ func (in *AppHttpHealthyTask) DeepCopyInto(out *AppHttpHealthyTask) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Detect != nil {
+		in, out := &in.Detect, &out.Detect
+		*out = new(HttpDetectResult)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppHttpHealthyTask.
@@ -60,6 +65,38 @@ func (in *AppHttpHealthyTaskDetail) DeepCopy() *AppHttpHealthyTaskDetail {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaselineComparison) DeepCopyInto(out *BaselineComparison) {
+	*out = *in
+	if in.BaselineRounds != nil {
+		in, out := &in.BaselineRounds, &out.BaselineRounds
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegressionReasons != nil {
+		in, out := &in.RegressionReasons, &out.RegressionReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]TargetBaselineDiff, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaselineComparison.
+func (in *BaselineComparison) DeepCopy() *BaselineComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(BaselineComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSMetrics) DeepCopyInto(out *DNSMetrics) {
 	*out = *in
@@ -92,6 +129,109 @@ func (in *DNSMetrics) DeepCopy() *DNSMetrics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DnsDetectResult) DeepCopyInto(out *DnsDetectResult) {
+	*out = *in
+	if in.BreakReason != nil {
+		in, out := &in.BreakReason, &out.BreakReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]DnsDetectStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DnsDetectResult.
+func (in *DnsDetectResult) DeepCopy() *DnsDetectResult {
+	if in == nil {
+		return nil
+	}
+	out := new(DnsDetectResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DnsDetectStep) DeepCopyInto(out *DnsDetectStep) {
+	*out = *in
+	out.Latencies = in.Latencies
+	if in.ReplyCode != nil {
+		in, out := &in.ReplyCode, &out.ReplyCode
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DnsDetectStep.
+func (in *DnsDetectStep) DeepCopy() *DnsDetectStep {
+	if in == nil {
+		return nil
+	}
+	out := new(DnsDetectStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpDetectResult) DeepCopyInto(out *HttpDetectResult) {
+	*out = *in
+	if in.BreakReason != nil {
+		in, out := &in.BreakReason, &out.BreakReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]HttpDetectStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpDetectResult.
+func (in *HttpDetectResult) DeepCopy() *HttpDetectResult {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpDetectResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HttpDetectStep) DeepCopyInto(out *HttpDetectStep) {
+	*out = *in
+	out.Latencies = in.Latencies
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HttpDetectStep.
+func (in *HttpDetectStep) DeepCopy() *HttpDetectStep {
+	if in == nil {
+		return nil
+	}
+	out := new(HttpDetectStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HttpMetrics) DeepCopyInto(out *HttpMetrics) {
 	*out = *in
@@ -184,6 +324,38 @@ func (in *KdoctorReportList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KdoctorReportRounds) DeepCopyInto(out *KdoctorReportRounds) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Rounds != nil {
+		in, out := &in.Rounds, &out.Rounds
+		*out = make([]RoundSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KdoctorReportRounds.
+func (in *KdoctorReportRounds) DeepCopy() *KdoctorReportRounds {
+	if in == nil {
+		return nil
+	}
+	out := new(KdoctorReportRounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KdoctorReportRounds) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LatencyDistribution) DeepCopyInto(out *LatencyDistribution) {
 	*out = *in
@@ -237,6 +409,11 @@ func (in *NetDNSTaskDetail) DeepCopyInto(out *NetDNSTaskDetail) {
 		**out = **in
 	}
 	in.Metrics.DeepCopyInto(&out.Metrics)
+	if in.Detect != nil {
+		in, out := &in.Detect, &out.Detect
+		*out = new(DnsDetectResult)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDNSTaskDetail.
@@ -249,6 +426,204 @@ func (in *NetDNSTaskDetail) DeepCopy() *NetDNSTaskDetail {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelayMatrix) DeepCopyInto(out *NetDelayMatrix) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SlowestPair != nil {
+		in, out := &in.SlowestPair, &out.SlowestPair
+		*out = new(NetDelayPair)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = make(map[string]map[string]NetDelayPair, len(*in))
+		for key, val := range *in {
+			var outVal map[string]NetDelayPair
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]NetDelayPair, len(*in))
+				for key, val := range *in {
+					(*out)[key] = *val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelayMatrix.
+func (in *NetDelayMatrix) DeepCopy() *NetDelayMatrix {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelayMatrix)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelayPair) DeepCopyInto(out *NetDelayPair) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelayPair.
+func (in *NetDelayPair) DeepCopy() *NetDelayPair {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelayPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelayTask) DeepCopyInto(out *NetDelayTask) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	out.SystemResource = in.SystemResource
+	out.TotalRunningLoad = in.TotalRunningLoad
+	if in.Detail != nil {
+		in, out := &in.Detail, &out.Detail
+		*out = make([]NetDelayTaskDetail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelayTask.
+func (in *NetDelayTask) DeepCopy() *NetDelayTask {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelayTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDelayTaskDetail) DeepCopyInto(out *NetDelayTaskDetail) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	in.Metrics.DeepCopyInto(&out.Metrics)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDelayTaskDetail.
+func (in *NetDelayTaskDetail) DeepCopy() *NetDelayTaskDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDelayTaskDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDnsDetectCombined) DeepCopyInto(out *NetDnsDetectCombined) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDnsDetectCombined.
+func (in *NetDnsDetectCombined) DeepCopy() *NetDnsDetectCombined {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDnsDetectCombined)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDnsDetectSummary) DeepCopyInto(out *NetDnsDetectSummary) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]NetDnsDetectTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDnsDetectSummary.
+func (in *NetDnsDetectSummary) DeepCopy() *NetDnsDetectSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDnsDetectSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetDnsDetectTarget) DeepCopyInto(out *NetDnsDetectTarget) {
+	*out = *in
+	if in.AgentKneeQPS != nil {
+		in, out := &in.AgentKneeQPS, &out.AgentKneeQPS
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.BreakReason != nil {
+		in, out := &in.BreakReason, &out.BreakReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]NetDnsDetectCombined, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetDnsDetectTarget.
+func (in *NetDnsDetectTarget) DeepCopy() *NetDnsDetectTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NetDnsDetectTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetReachTask) DeepCopyInto(out *NetReachTask) {
 	*out = *in
@@ -299,6 +674,106 @@ func (in *NetReachTaskDetail) DeepCopy() *NetReachTaskDetail {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetTcpTask) DeepCopyInto(out *NetTcpTask) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	out.SystemResource = in.SystemResource
+	out.TotalRunningLoad = in.TotalRunningLoad
+	if in.Detail != nil {
+		in, out := &in.Detail, &out.Detail
+		*out = make([]NetTcpTaskDetail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetTcpTask.
+func (in *NetTcpTask) DeepCopy() *NetTcpTask {
+	if in == nil {
+		return nil
+	}
+	out := new(NetTcpTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetTcpTaskDetail) DeepCopyInto(out *NetTcpTaskDetail) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	in.Metrics.DeepCopyInto(&out.Metrics)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetTcpTaskDetail.
+func (in *NetTcpTaskDetail) DeepCopy() *NetTcpTaskDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(NetTcpTaskDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetUdpTask) DeepCopyInto(out *NetUdpTask) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	out.SystemResource = in.SystemResource
+	out.TotalRunningLoad = in.TotalRunningLoad
+	if in.Detail != nil {
+		in, out := &in.Detail, &out.Detail
+		*out = make([]NetUdpTaskDetail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetUdpTask.
+func (in *NetUdpTask) DeepCopy() *NetUdpTask {
+	if in == nil {
+		return nil
+	}
+	out := new(NetUdpTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetUdpTaskDetail) DeepCopyInto(out *NetUdpTaskDetail) {
+	*out = *in
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(string)
+		**out = **in
+	}
+	in.Metrics.DeepCopyInto(&out.Metrics)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetUdpTaskDetail.
+func (in *NetUdpTaskDetail) DeepCopy() *NetUdpTaskDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(NetUdpTaskDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Report) DeepCopyInto(out *Report) {
 	*out = *in
@@ -324,6 +799,21 @@ func (in *Report) DeepCopyInto(out *Report) {
 		*out = new(NetDNSTask)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TaskNetTcp != nil {
+		in, out := &in.TaskNetTcp, &out.TaskNetTcp
+		*out = new(NetTcpTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TaskNetUdp != nil {
+		in, out := &in.TaskNetUdp, &out.TaskNetUdp
+		*out = new(NetUdpTask)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TaskNetDelay != nil {
+		in, out := &in.TaskNetDelay, &out.TaskNetDelay
+		*out = new(NetDelayTask)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Report.
@@ -350,6 +840,32 @@ func (in *Reports) DeepCopyInto(out *Reports) {
 			}
 		}
 	}
+	if in.RoundReports != nil {
+		in, out := &in.RoundReports, &out.RoundReports
+		*out = new([]Report)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]Report, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.NetDelayMatrix != nil {
+		in, out := &in.NetDelayMatrix, &out.NetDelayMatrix
+		*out = new(NetDelayMatrix)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetDnsDetect != nil {
+		in, out := &in.NetDnsDetect, &out.NetDnsDetect
+		*out = new(NetDnsDetectSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BaselineComparison != nil {
+		in, out := &in.BaselineComparison, &out.BaselineComparison
+		*out = new(BaselineComparison)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Reports.
@@ -362,6 +878,39 @@ func (in *Reports) DeepCopy() *Reports {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoundSummary) DeepCopyInto(out *RoundSummary) {
+	*out = *in
+	if in.FailedReason != nil {
+		in, out := &in.FailedReason, &out.FailedReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.StartTimeStamp != nil {
+		in, out := &in.StartTimeStamp, &out.StartTimeStamp
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTimeStamp != nil {
+		in, out := &in.EndTimeStamp, &out.EndTimeStamp
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedNodeList != nil {
+		in, out := &in.FailedNodeList, &out.FailedNodeList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoundSummary.
+func (in *RoundSummary) DeepCopy() *RoundSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RoundSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Status) DeepCopyInto(out *Status) {
 	*out = *in
@@ -392,6 +941,26 @@ func (in *SystemResource) DeepCopy() *SystemResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetBaselineDiff) DeepCopyInto(out *TargetBaselineDiff) {
+	*out = *in
+	if in.RegressedMetrics != nil {
+		in, out := &in.RegressedMetrics, &out.RegressedMetrics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetBaselineDiff.
+func (in *TargetBaselineDiff) DeepCopy() *TargetBaselineDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetBaselineDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskInfo) DeepCopyInto(out *TaskInfo) {
 	*out = *in
@@ -426,6 +995,21 @@ func (in *TaskSpec) DeepCopyInto(out *TaskSpec) {
 		*out = new(kdoctor_iov1beta1.NetdnsSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NetTcpTaskSpec != nil {
+		in, out := &in.NetTcpTaskSpec, &out.NetTcpTaskSpec
+		*out = new(kdoctor_iov1beta1.NetTcpSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetUdpTaskSpec != nil {
+		in, out := &in.NetUdpTaskSpec, &out.NetUdpTaskSpec
+		*out = new(kdoctor_iov1beta1.NetUdpSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetDelayTaskSpec != nil {
+		in, out := &in.NetDelayTaskSpec, &out.NetDelayTaskSpec
+		*out = new(kdoctor_iov1beta1.NetDelaySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskSpec.
@@ -438,6 +1022,31 @@ func (in *TaskSpec) DeepCopy() *TaskSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TcpMetrics) DeepCopyInto(out *TcpMetrics) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.ConnectLatencies = in.ConnectLatencies
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TcpMetrics.
+func (in *TcpMetrics) DeepCopy() *TcpMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(TcpMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TotalRunningLoad) DeepCopyInto(out *TotalRunningLoad) {
 	*out = *in
@@ -452,3 +1061,28 @@ func (in *TotalRunningLoad) DeepCopy() *TotalRunningLoad {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UdpMetrics) DeepCopyInto(out *UdpMetrics) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Latencies = in.Latencies
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UdpMetrics.
+func (in *UdpMetrics) DeepCopy() *UdpMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(UdpMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
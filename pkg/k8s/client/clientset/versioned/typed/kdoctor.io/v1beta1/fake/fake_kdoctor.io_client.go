@@ -19,14 +19,30 @@ func (c *FakeKdoctorV1beta1) AppHttpHealthies() v1beta1.AppHttpHealthyInterface
 	return &FakeAppHttpHealthies{c}
 }
 
+func (c *FakeKdoctorV1beta1) NetDelays() v1beta1.NetDelayInterface {
+	return &FakeNetDelays{c}
+}
+
 func (c *FakeKdoctorV1beta1) NetReaches() v1beta1.NetReachInterface {
 	return &FakeNetReaches{c}
 }
 
+func (c *FakeKdoctorV1beta1) NetTcps() v1beta1.NetTcpInterface {
+	return &FakeNetTcps{c}
+}
+
+func (c *FakeKdoctorV1beta1) NetUdps() v1beta1.NetUdpInterface {
+	return &FakeNetUdps{c}
+}
+
 func (c *FakeKdoctorV1beta1) Netdnses() v1beta1.NetdnsInterface {
 	return &FakeNetdnses{c}
 }
 
+func (c *FakeKdoctorV1beta1) TaskSuites() v1beta1.TaskSuiteInterface {
+	return &FakeTaskSuites{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeKdoctorV1beta1) RESTClient() rest.Interface {
@@ -7,6 +7,14 @@ package v1beta1
 
 type AppHttpHealthyExpansion interface{}
 
+type NetDelayExpansion interface{}
+
 type NetReachExpansion interface{}
 
+type NetTcpExpansion interface{}
+
+type NetUdpExpansion interface{}
+
 type NetdnsExpansion interface{}
+
+type TaskSuiteExpansion interface{}
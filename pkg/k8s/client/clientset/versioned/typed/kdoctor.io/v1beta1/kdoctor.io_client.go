@@ -16,8 +16,12 @@ import (
 type KdoctorV1beta1Interface interface {
 	RESTClient() rest.Interface
 	AppHttpHealthiesGetter
+	NetDelaysGetter
 	NetReachesGetter
+	NetTcpsGetter
+	NetUdpsGetter
 	NetdnsesGetter
+	TaskSuitesGetter
 }
 
 // KdoctorV1beta1Client is used to interact with features provided by the kdoctor.io group.
@@ -29,14 +33,30 @@ func (c *KdoctorV1beta1Client) AppHttpHealthies() AppHttpHealthyInterface {
 	return newAppHttpHealthies(c)
 }
 
+func (c *KdoctorV1beta1Client) NetDelays() NetDelayInterface {
+	return newNetDelays(c)
+}
+
 func (c *KdoctorV1beta1Client) NetReaches() NetReachInterface {
 	return newNetReaches(c)
 }
 
+func (c *KdoctorV1beta1Client) NetTcps() NetTcpInterface {
+	return newNetTcps(c)
+}
+
+func (c *KdoctorV1beta1Client) NetUdps() NetUdpInterface {
+	return newNetUdps(c)
+}
+
 func (c *KdoctorV1beta1Client) Netdnses() NetdnsInterface {
 	return newNetdnses(c)
 }
 
+func (c *KdoctorV1beta1Client) TaskSuites() TaskSuiteInterface {
+	return newTaskSuites(c)
+}
+
 // NewForConfig creates a new KdoctorV1beta1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -42,10 +42,18 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=kdoctor.io, Version=v1beta1
 	case v1beta1.SchemeGroupVersion.WithResource("apphttphealthies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kdoctor().V1beta1().AppHttpHealthies().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("netdelays"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kdoctor().V1beta1().NetDelays().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("netreaches"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kdoctor().V1beta1().NetReaches().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("nettcps"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kdoctor().V1beta1().NetTcps().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("netudps"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kdoctor().V1beta1().NetUdps().Informer()}, nil
 	case v1beta1.SchemeGroupVersion.WithResource("netdnses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kdoctor().V1beta1().Netdnses().Informer()}, nil
+	case v1beta1.SchemeGroupVersion.WithResource("tasksuites"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kdoctor().V1beta1().TaskSuites().Informer()}, nil
 
 	}
 
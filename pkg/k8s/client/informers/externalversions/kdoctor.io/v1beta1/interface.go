@@ -13,10 +13,18 @@ import (
 type Interface interface {
 	// AppHttpHealthies returns a AppHttpHealthyInformer.
 	AppHttpHealthies() AppHttpHealthyInformer
+	// NetDelays returns a NetDelayInformer.
+	NetDelays() NetDelayInformer
 	// NetReaches returns a NetReachInformer.
 	NetReaches() NetReachInformer
+	// NetTcps returns a NetTcpInformer.
+	NetTcps() NetTcpInformer
+	// NetUdps returns a NetUdpInformer.
+	NetUdps() NetUdpInformer
 	// Netdnses returns a NetdnsInformer.
 	Netdnses() NetdnsInformer
+	// TaskSuites returns a TaskSuiteInformer.
+	TaskSuites() TaskSuiteInformer
 }
 
 type version struct {
@@ -35,12 +43,32 @@ func (v *version) AppHttpHealthies() AppHttpHealthyInformer {
 	return &appHttpHealthyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// NetDelays returns a NetDelayInformer.
+func (v *version) NetDelays() NetDelayInformer {
+	return &netDelayInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NetReaches returns a NetReachInformer.
 func (v *version) NetReaches() NetReachInformer {
 	return &netReachInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// NetTcps returns a NetTcpInformer.
+func (v *version) NetTcps() NetTcpInformer {
+	return &netTcpInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// NetUdps returns a NetUdpInformer.
+func (v *version) NetUdps() NetUdpInformer {
+	return &netUdpInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Netdnses returns a NetdnsInformer.
 func (v *version) Netdnses() NetdnsInformer {
 	return &netdnsInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// TaskSuites returns a TaskSuiteInformer.
+func (v *version) TaskSuites() TaskSuiteInformer {
+	return &taskSuiteInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
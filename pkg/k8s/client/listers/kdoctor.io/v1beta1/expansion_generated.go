@@ -9,10 +9,26 @@ package v1beta1
 // AppHttpHealthyLister.
 type AppHttpHealthyListerExpansion interface{}
 
+// NetDelayListerExpansion allows custom methods to be added to
+// NetDelayLister.
+type NetDelayListerExpansion interface{}
+
 // NetReachListerExpansion allows custom methods to be added to
 // NetReachLister.
 type NetReachListerExpansion interface{}
 
+// NetTcpListerExpansion allows custom methods to be added to
+// NetTcpLister.
+type NetTcpListerExpansion interface{}
+
+// NetUdpListerExpansion allows custom methods to be added to
+// NetUdpLister.
+type NetUdpListerExpansion interface{}
+
 // NetdnsListerExpansion allows custom methods to be added to
 // NetdnsLister.
 type NetdnsListerExpansion interface{}
+
+// TaskSuiteListerExpansion allows custom methods to be added to
+// TaskSuiteLister.
+type TaskSuiteListerExpansion interface{}
@@ -0,0 +1,191 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/client/clientset/versioned"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// the kdoctorreports are served by the aggregated apiserver of kdoctor in the namespace default
+const reportPath = "/apis/" + v1beta1.GroupName + "/" + v1beta1.V1betaVersion + "/namespaces/default/kdoctorreports/"
+
+// Client accesses the tasks, the reports and the agents
+type Client struct {
+	Kdoctor versioned.Interface
+	Kube    kubernetes.Interface
+	// LogKube streams the pod logs without the timeout of the config, which would cut off following the logs
+	LogKube kubernetes.Interface
+	// the namespace where kdoctor is installed, which runs the agents
+	Namespace string
+}
+
+func NewClient(config *rest.Config, namespace string) (*Client, error) {
+	kdoctorClient, err := versioned.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client of kdoctor, error: %v", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client of kubernetes, error: %v", err)
+	}
+	logConfig := rest.CopyConfig(config)
+	logConfig.Timeout = 0
+	logClient, err := kubernetes.NewForConfig(logConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client of kubernetes, error: %v", err)
+	}
+	return &Client{Kdoctor: kdoctorClient, Kube: kubeClient, LogKube: logClient, Namespace: namespace}, nil
+}
+
+// CreateTask creates the task built by NewTask
+func (c *Client) CreateTask(ctx context.Context, obj client.Object) error {
+	var err error
+	switch t := obj.(type) {
+	case *crd.NetReach:
+		_, err = c.Kdoctor.KdoctorV1beta1().NetReaches().Create(ctx, t, metav1.CreateOptions{})
+	case *crd.AppHttpHealthy:
+		_, err = c.Kdoctor.KdoctorV1beta1().AppHttpHealthies().Create(ctx, t, metav1.CreateOptions{})
+	case *crd.Netdns:
+		_, err = c.Kdoctor.KdoctorV1beta1().Netdnses().Create(ctx, t, metav1.CreateOptions{})
+	default:
+		return fmt.Errorf("unsupported task %T", obj)
+	}
+	return err
+}
+
+// DeleteTask deletes the task of any kind
+func (c *Client) DeleteTask(ctx context.Context, kind, name string) error {
+	api := c.Kdoctor.KdoctorV1beta1()
+	switch kind {
+	case types.KindNameNetReach:
+		return api.NetReaches().Delete(ctx, name, metav1.DeleteOptions{})
+	case types.KindNameAppHttpHealthy:
+		return api.AppHttpHealthies().Delete(ctx, name, metav1.DeleteOptions{})
+	case types.KindNameNetdns:
+		return api.Netdnses().Delete(ctx, name, metav1.DeleteOptions{})
+	case types.KindNameNetTcp:
+		return api.NetTcps().Delete(ctx, name, metav1.DeleteOptions{})
+	case types.KindNameNetUdp:
+		return api.NetUdps().Delete(ctx, name, metav1.DeleteOptions{})
+	case types.KindNameNetDelay:
+		return api.NetDelays().Delete(ctx, name, metav1.DeleteOptions{})
+	}
+	return fmt.Errorf("unknown task kind %q", kind)
+}
+
+// PatchTask applies the json merge patch to the task of any kind
+func (c *Client) PatchTask(ctx context.Context, kind, name string, patch []byte) error {
+	api := c.Kdoctor.KdoctorV1beta1()
+	var err error
+	switch kind {
+	case types.KindNameNetReach:
+		_, err = api.NetReaches().Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	case types.KindNameAppHttpHealthy:
+		_, err = api.AppHttpHealthies().Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	case types.KindNameNetdns:
+		_, err = api.Netdnses().Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	case types.KindNameNetTcp:
+		_, err = api.NetTcps().Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	case types.KindNameNetUdp:
+		_, err = api.NetUdps().Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	case types.KindNameNetDelay:
+		_, err = api.NetDelays().Patch(ctx, name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("unknown task kind %q", kind)
+	}
+	return err
+}
+
+// GetTaskStatus returns the status of the task of any kind
+func (c *Client) GetTaskStatus(ctx context.Context, kind, name string) (*crd.TaskStatus, error) {
+	api := c.Kdoctor.KdoctorV1beta1()
+	switch kind {
+	case types.KindNameNetReach:
+		t, err := api.NetReaches().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &t.Status, nil
+	case types.KindNameAppHttpHealthy:
+		t, err := api.AppHttpHealthies().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &t.Status, nil
+	case types.KindNameNetdns:
+		t, err := api.Netdnses().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &t.Status, nil
+	case types.KindNameNetTcp:
+		t, err := api.NetTcps().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &t.Status, nil
+	case types.KindNameNetUdp:
+		t, err := api.NetUdps().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &t.Status, nil
+	case types.KindNameNetDelay:
+		t, err := api.NetDelays().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &t.Status, nil
+	}
+	return nil, fmt.Errorf("unknown task kind %q", kind)
+}
+
+// GetReport returns the kdoctorreport of the task, with the agent reports of the round when the round is bigger than 0
+func (c *Client) GetReport(ctx context.Context, kind, name string, round int64) (*v1beta1.KdoctorReport, error) {
+	req := c.Kube.Discovery().RESTClient().Get().AbsPath(reportPath + ReportName(kind, name))
+	if round > 0 {
+		req = req.Param("round", strconv.FormatInt(round, 10))
+	}
+	data, err := req.DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the report of %v %v, error: %v", kind, name, err)
+	}
+	report := &v1beta1.KdoctorReport{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to parse the report of %v %v, error: %v", kind, name, err)
+	}
+	return report, nil
+}
+
+// RoundReports returns the agent reports of the round in the kdoctorreport got with the round
+func RoundReports(report *v1beta1.KdoctorReport, round int64) []v1beta1.Report {
+	reports := report.Report.RoundReports
+	if reports == nil {
+		reports = report.Report.LatestRoundReport
+	}
+	if reports == nil {
+		return nil
+	}
+	result := []v1beta1.Report{}
+	for _, r := range *reports {
+		if round <= 0 || r.RoundNumber == round {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorctl
+
+import (
+	"sort"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/reportRender"
+)
+
+// NodeDiff is the result of an agent in the two rounds, and it is empty when the agent does not report in the round
+type NodeDiff struct {
+	NodeName   string
+	FromResult string
+	ToResult   string
+}
+
+// TargetDiff is the result of the requests from an agent to a target in the two rounds,
+// and From or To is nil when the target is not requested in the round
+type TargetDiff struct {
+	NodeName   string
+	TargetName string
+	From       *reportRender.TargetResult
+	To         *reportRender.TargetResult
+}
+
+// RoundDiff compares the agent reports of two rounds of a task
+type RoundDiff struct {
+	FromRound int64
+	ToRound   int64
+	Nodes     []NodeDiff
+	Targets   []TargetDiff
+}
+
+// ChangePercent returns the change from the value of the from round, and ok is false when it could not be computed
+func ChangePercent(from, to float64) (change float64, ok bool) {
+	if from == 0 {
+		return 0, false
+	}
+	return (to - from) / from * 100, true
+}
+
+// DiffRounds compares the results of each node and each target in the two rounds, in the order of the node and the target
+func DiffRounds(fromRound, toRound int64, fromReports, toReports []v1beta1.Report) *RoundDiff {
+	diff := &RoundDiff{FromRound: fromRound, ToRound: toRound}
+
+	nodes := map[string]*NodeDiff{}
+	targets := map[[2]string]*TargetDiff{}
+	collect := func(reports []v1beta1.Report, isFrom bool) {
+		for i := range reports {
+			node := reportRender.NewNodeResult(&reports[i])
+			n, ok := nodes[node.NodeName]
+			if !ok {
+				n = &NodeDiff{NodeName: node.NodeName}
+				nodes[node.NodeName] = n
+			}
+			if isFrom {
+				n.FromResult = node.RoundResult
+			} else {
+				n.ToResult = node.RoundResult
+			}
+			for j := range node.Targets {
+				key := [2]string{node.NodeName, node.Targets[j].Name}
+				t, ok := targets[key]
+				if !ok {
+					t = &TargetDiff{NodeName: node.NodeName, TargetName: node.Targets[j].Name}
+					targets[key] = t
+				}
+				if isFrom {
+					t.From = &node.Targets[j]
+				} else {
+					t.To = &node.Targets[j]
+				}
+			}
+		}
+	}
+	collect(fromReports, true)
+	collect(toReports, false)
+
+	for _, n := range nodes {
+		diff.Nodes = append(diff.Nodes, *n)
+	}
+	sort.Slice(diff.Nodes, func(i, j int) bool { return diff.Nodes[i].NodeName < diff.Nodes[j].NodeName })
+	for _, t := range targets {
+		diff.Targets = append(diff.Targets, *t)
+	}
+	sort.Slice(diff.Targets, func(i, j int) bool {
+		if diff.Targets[i].NodeName != diff.Targets[j].NodeName {
+			return diff.Targets[i].NodeName < diff.Targets[j].NodeName
+		}
+		return diff.Targets[i].TargetName < diff.Targets[j].TargetName
+	})
+	return diff
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+package kdoctorctl_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+)
+
+func netReachReport(round int64, node, result string, targets ...v1beta1.NetReachTaskDetail) v1beta1.Report {
+	return v1beta1.Report{
+		RoundNumber:  round,
+		NodeName:     node,
+		PodName:      node + "-agent",
+		RoundResult:  result,
+		TaskNetReach: &v1beta1.NetReachTask{Detail: targets},
+	}
+}
+
+func netReachTarget(name string, p50, p99 float32, rate float64) v1beta1.NetReachTaskDetail {
+	return v1beta1.NetReachTaskDetail{
+		TargetName:  name,
+		SucceedRate: rate,
+		Metrics:     v1beta1.HttpMetrics{Latencies: v1beta1.LatencyDistribution{P50: p50, P99: p99}},
+	}
+}
+
+var _ = Describe("test diff", Label("diff"), func() {
+
+	It("compare two rounds", func() {
+		from := []v1beta1.Report{
+			netReachReport(1, "node1", "succeed", netReachTarget("clusterIP", 1, 10, 1), netReachTarget("nodePort", 2, 4, 1)),
+			netReachReport(1, "node2", "succeed", netReachTarget("clusterIP", 1, 10, 1)),
+		}
+		to := []v1beta1.Report{
+			netReachReport(2, "node1", "fail", netReachTarget("clusterIP", 2, 25, 0.5), netReachTarget("loadBalancer", 3, 6, 1)),
+			netReachReport(2, "node3", "succeed", netReachTarget("clusterIP", 1, 10, 1)),
+		}
+
+		diff := kdoctorctl.DiffRounds(1, 2, from, to)
+		Expect(diff.Nodes).To(Equal([]kdoctorctl.NodeDiff{
+			{NodeName: "node1", FromResult: "succeed", ToResult: "fail"},
+			{NodeName: "node2", FromResult: "succeed"},
+			{NodeName: "node3", ToResult: "succeed"},
+		}))
+		Expect(diff.Targets).To(HaveLen(5))
+		Expect(diff.Targets[0].TargetName).To(Equal("clusterIP"))
+		Expect(diff.Targets[0].From.Latencies.P99).To(Equal(float32(10)))
+		Expect(diff.Targets[0].To.Latencies.P99).To(Equal(float32(25)))
+		Expect(diff.Targets[1].TargetName).To(Equal("loadBalancer"))
+		Expect(diff.Targets[1].From).To(BeNil())
+		Expect(diff.Targets[2].TargetName).To(Equal("nodePort"))
+		Expect(diff.Targets[2].To).To(BeNil())
+
+		out := &bytes.Buffer{}
+		kdoctorctl.PrintDiff(out, diff)
+		Expect(out.String()).To(ContainSubstring("Round 1 -> round 2"))
+		Expect(out.String()).To(ContainSubstring("+150.0%"))
+		Expect(out.String()).To(ContainSubstring("-50.0%"))
+		Expect(out.String()).To(ContainSubstring("added"))
+		Expect(out.String()).To(ContainSubstring("removed"))
+	})
+
+	It("compute the change", func() {
+		change, ok := kdoctorctl.ChangePercent(10, 15)
+		Expect(ok).To(BeTrue())
+		Expect(change).To(BeNumerically("~", 50, 0.001))
+		_, ok = kdoctorctl.ChangePercent(0, 15)
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,149 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorctl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// LogFilter selects the lines of the agent logs for the task, and the round when Round is bigger than 0
+type LogFilter struct {
+	Kind     string
+	TaskName string
+	Round    int64
+}
+
+// reportKind returns the kind of the agent report
+func reportKind(r *v1beta1.Report) string {
+	switch {
+	case r.TaskNetReach != nil:
+		return types.KindNameNetReach
+	case r.TaskAppHttpHealthy != nil:
+		return types.KindNameAppHttpHealthy
+	case r.TaskNetDNS != nil:
+		return types.KindNameNetdns
+	case r.TaskNetTcp != nil:
+		return types.KindNameNetTcp
+	case r.TaskNetUdp != nil:
+		return types.KindNameNetUdp
+	case r.TaskNetDelay != nil:
+		return types.KindNameNetDelay
+	}
+	return ""
+}
+
+// Match returns whether the line is the output of the task. The agent logs the round with the logger named
+// "<kind>.<name>.round<number>", and prints the report of the round to the stdout after the round finishes.
+// The report does not have the task name, so the reports of the other tasks of the same kind
+// are matched when the agents are shared by the tasks
+func (f LogFilter) Match(line string) bool {
+	line = strings.TrimSpace(line)
+	roundName := fmt.Sprintf("%s.%s.round", f.Kind, f.TaskName)
+	if i := strings.Index(line, roundName); i >= 0 {
+		if f.Round <= 0 {
+			return true
+		}
+		return strings.HasPrefix(line[i+len(roundName):], fmt.Sprintf("%d\"", f.Round))
+	}
+
+	if !strings.HasPrefix(line, "{") {
+		return false
+	}
+	r := v1beta1.Report{}
+	if err := json.Unmarshal([]byte(line), &r); err != nil || reportKind(&r) != f.Kind {
+		return false
+	}
+	return f.Round <= 0 || r.RoundNumber == f.Round
+}
+
+// AgentPods returns the agent pods running the task
+func (c *Client) AgentPods(ctx context.Context, status *crd.TaskStatus) ([]corev1.Pod, error) {
+	if status.Resource == nil {
+		return nil, fmt.Errorf("the agents of the task are not created yet")
+	}
+	var selector *metav1.LabelSelector
+	name := status.Resource.RuntimeName
+	switch {
+	case strings.EqualFold(status.Resource.RuntimeType, types.KindDaemonSet):
+		ds, err := c.Kube.AppsV1().DaemonSets(c.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = ds.Spec.Selector
+	case strings.EqualFold(status.Resource.RuntimeType, types.KindDeployment):
+		deploy, err := c.Kube.AppsV1().Deployments(c.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		selector = deploy.Spec.Selector
+	default:
+		return nil, fmt.Errorf("unknown agent runtime type %q", status.Resource.RuntimeType)
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if s.Empty() {
+		s = labels.Nothing()
+	}
+	pods, err := c.Kube.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: s.String()})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// StreamLogs writes the lines of the pod logs matched by the filter, and each line is prefixed with the node of the pod
+func (c *Client) StreamLogs(ctx context.Context, pods []corev1.Pod, follow bool, filter LogFilter, w io.Writer) error {
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(chan error, len(pods))
+
+	for i := range pods {
+		pod := pods[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stream, err := c.LogKube.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Follow: follow}).Stream(ctx)
+			if err != nil {
+				errs <- fmt.Errorf("failed to get the logs of pod %v, error: %v", pod.Name, err)
+				return
+			}
+			defer stream.Close()
+
+			scanner := bufio.NewScanner(stream)
+			scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+			for scanner.Scan() {
+				line := scanner.Text()
+				if !filter.Match(line) {
+					continue
+				}
+				lock.Lock()
+				fmt.Fprintf(w, "[%s] %s\n", pod.Spec.NodeName, strings.TrimSpace(line))
+				lock.Unlock()
+			}
+			if err := scanner.Err(); err != nil && ctx.Err() == nil {
+				errs <- fmt.Errorf("failed to read the logs of pod %v, error: %v", pod.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+package kdoctorctl_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+var _ = Describe("test logs and report", Label("logs"), func() {
+
+	It("match the output of the task", func() {
+		filter := kdoctorctl.LogFilter{Kind: types.KindNameNetReach, TaskName: "test", Round: 3}
+		Expect(filter.Match(`{"level":"INFO","agent":"agentReconciler.NetReach.test.round3","msg":"plugin begins to implement"}`)).To(BeTrue())
+		Expect(filter.Match(`{"level":"INFO","agent":"agentReconciler.NetReach.test.round30","msg":"plugin begins to implement"}`)).To(BeFalse())
+		Expect(filter.Match(`{"level":"INFO","agent":"agentReconciler.NetReach.other.round3","msg":"plugin begins to implement"}`)).To(BeFalse())
+
+		report, err := json.Marshal(netReachReport(3, "node1", "succeed"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filter.Match(" " + string(report))).To(BeTrue())
+		other, err := json.Marshal(v1beta1.Report{RoundNumber: 3, TaskNetDNS: &v1beta1.NetDNSTask{}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filter.Match(string(other))).To(BeFalse())
+		Expect(filter.Match("plain text")).To(BeFalse())
+
+		filter.Round = 0
+		Expect(filter.Match(`{"agent":"agentReconciler.NetReach.test.round30"}`)).To(BeTrue())
+	})
+
+	It("get the report of the round", func() {
+		var query string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			if r.URL.Path != "/apis/system.kdoctor.io/v1beta1/namespaces/default/kdoctorreports/netreach-test" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			reports := []v1beta1.Report{netReachReport(2, "node1", "succeed", netReachTarget("clusterIP", 1, 10, 1))}
+			report := v1beta1.KdoctorReport{
+				Task:   v1beta1.TaskInfo{TaskName: "test", TaskType: types.KindNameNetReach},
+				Status: v1beta1.Status{Status: "Finished", FinishedRoundNumber: 2, ToTalRoundNumber: 2},
+				Report: v1beta1.Reports{RoundReports: &reports},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(report)
+		}))
+		defer server.Close()
+
+		kube, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+		Expect(err).NotTo(HaveOccurred())
+		c := &kdoctorctl.Client{Kube: kube, LogKube: kube}
+		report, err := c.GetReport(context.Background(), types.KindNameNetReach, "test", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query).To(Equal("round=2"))
+		reports := kdoctorctl.RoundReports(report, 2)
+		Expect(reports).To(HaveLen(1))
+
+		out := &bytes.Buffer{}
+		kdoctorctl.PrintReport(out, report, reports)
+		Expect(out.String()).To(ContainSubstring("Task:      NetReach/test"))
+		Expect(out.String()).To(ContainSubstring("Node node1 (pod node1-agent): succeed"))
+		Expect(out.String()).To(MatchRegexp(`clusterIP\s+-\s+1.00\s+0/0\s+1.00\s+0.00\s+10.00`))
+
+		_, err = c.GetReport(context.Background(), types.KindNameNetReach, "missing", 0)
+		Expect(err).To(MatchError(ContainSubstring(fmt.Sprintf("%v %v", types.KindNameNetReach, "missing"))))
+	})
+})
@@ -0,0 +1,202 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorctl
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/reportRender"
+)
+
+func newTabWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+}
+
+func or(s, fallback string) string {
+	if len(s) == 0 {
+		return fallback
+	}
+	return s
+}
+
+func formatChange(from, to float64) string {
+	change, ok := ChangePercent(from, to)
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", change)
+}
+
+// PrintRound prints the result of the finished round, which is printed when the command run waits
+func PrintRound(w io.Writer, record crd.StatusHistoryRecord) {
+	msg := fmt.Sprintf("round %d %s", record.RoundNumber, record.Status)
+	if record.Duration != nil {
+		msg += " in " + *record.Duration
+	}
+	if len(record.FailedAgentNodeList) > 0 {
+		msg += fmt.Sprintf(", failed nodes %v", record.FailedAgentNodeList)
+	}
+	if len(record.FailureReason) > 0 {
+		msg += ": " + record.FailureReason
+	}
+	if record.Status == crd.StatusHistoryRecordStatusSkipped {
+		msg += ": " + record.AdmissionReason
+	}
+	if record.Regressed != nil && *record.Regressed {
+		msg += ", regressed: " + record.RegressionReason
+	}
+	fmt.Fprintln(w, msg)
+}
+
+// PrintStatus prints the rounds and the conditions of the task
+func PrintStatus(w io.Writer, kind, name string, status *crd.TaskStatus) {
+	r := ResultOf(status)
+	expected := "-"
+	if status.ExpectedRound != nil {
+		if *status.ExpectedRound == -1 {
+			expected = "unlimited"
+		} else {
+			expected = fmt.Sprintf("%d", *status.ExpectedRound)
+		}
+	}
+	fmt.Fprintf(w, "Task:      %s/%s\n", kind, name)
+	fmt.Fprintf(w, "Rounds:    %d done, %s expected\n", r.DoneRound, expected)
+	fmt.Fprintf(w, "Finished:  %v\n", status.Finish)
+	if len(r.FailedRounds) > 0 {
+		fmt.Fprintf(w, "Failed:    rounds %v\n", r.FailedRounds)
+	}
+	if len(status.NextRoundTimes) > 0 {
+		next := make([]string, 0, len(status.NextRoundTimes))
+		for _, t := range status.NextRoundTimes {
+			next = append(next, t.UTC().Format("2006-01-02T15:04:05Z"))
+		}
+		fmt.Fprintf(w, "Next:      %s\n", strings.Join(next, ", "))
+	}
+
+	if len(status.Conditions) > 0 {
+		fmt.Fprintln(w, "\nConditions:")
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "TYPE\tSTATUS\tREASON\tMESSAGE")
+		for _, c := range status.Conditions {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message)
+		}
+		_ = tw.Flush()
+	}
+
+	if len(status.History) > 0 {
+		fmt.Fprintln(w, "\nHistory:")
+		tw := newTabWriter(w)
+		fmt.Fprintln(tw, "ROUND\tSTATUS\tSTART\tDURATION\tFAILED NODES\tREGRESSED\tREASON")
+		for _, h := range status.History {
+			start := "-"
+			if !h.StartTimeStamp.IsZero() {
+				start = h.StartTimeStamp.UTC().Format("2006-01-02T15:04:05Z")
+			}
+			duration := "-"
+			if h.Duration != nil {
+				duration = *h.Duration
+			}
+			regressed := "-"
+			if h.Regressed != nil {
+				regressed = fmt.Sprintf("%v", *h.Regressed)
+			}
+			reason := h.FailureReason
+			if len(h.RegressionReason) > 0 {
+				reason = strings.TrimPrefix(reason+"; "+h.RegressionReason, "; ")
+			}
+			if len(h.AdmissionReason) > 0 {
+				reason = strings.TrimPrefix(reason+"; "+h.AdmissionReason, "; ")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", h.RoundNumber, h.Status, start, duration,
+				or(strings.Join(h.FailedAgentNodeList, ","), "-"), regressed, or(reason, "-"))
+		}
+		_ = tw.Flush()
+	}
+}
+
+// PrintReport prints the table of the targets of each node in the agent reports, in the order of the round and the node
+func PrintReport(w io.Writer, report *v1beta1.KdoctorReport, reports []v1beta1.Report) {
+	fmt.Fprintf(w, "Task:      %s/%s\n", report.Task.TaskType, report.Task.TaskName)
+	fmt.Fprintf(w, "Status:    %s, %d of %d rounds finished\n", report.Status.Status, report.Status.FinishedRoundNumber, report.Status.ToTalRoundNumber)
+
+	rounds := map[int64][]v1beta1.Report{}
+	numbers := []int64{}
+	for _, r := range reports {
+		if _, ok := rounds[r.RoundNumber]; !ok {
+			numbers = append(numbers, r.RoundNumber)
+		}
+		rounds[r.RoundNumber] = append(rounds[r.RoundNumber], r)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	if len(numbers) == 0 {
+		fmt.Fprintln(w, "\nNo agent report")
+	}
+
+	for _, n := range numbers {
+		round := reportRender.NewRound(v1beta1.RoundSummary{RoundNumber: n}, rounds[n])
+		fmt.Fprintf(w, "\nRound %d\n", n)
+		for _, node := range round.Nodes {
+			fmt.Fprintf(w, "\nNode %s (pod %s): %s\n", node.NodeName, node.PodName, node.RoundResult)
+			if len(node.FailedReason) > 0 {
+				fmt.Fprintf(w, "Failure reason: %s\n", node.FailedReason)
+			}
+			if len(node.Targets) == 0 {
+				continue
+			}
+			tw := newTabWriter(w)
+			fmt.Fprintln(tw, "TARGET\tADDRESS\tSUCCESS RATE\tREQUESTS\tP50(ms)\tP90(ms)\tP99(ms)\tFAILURE REASON")
+			for _, t := range node.Targets {
+				fmt.Fprintf(tw, "%s\t%s\t%.2f\t%d/%d\t%.2f\t%.2f\t%.2f\t%s\n", t.Name, or(t.Address, "-"), t.SucceedRate,
+					t.SuccessCounts, t.RequestCounts, t.Latencies.P50, t.Latencies.P90, t.Latencies.P99, or(t.FailureReason, "-"))
+			}
+			_ = tw.Flush()
+		}
+	}
+
+	if c := report.Report.BaselineComparison; c != nil {
+		fmt.Fprintf(w, "\nBaseline: round %d compared with rounds %v, regressed %v\n", c.RoundNumber, c.BaselineRounds, c.Regressed)
+		for _, reason := range c.RegressionReasons {
+			fmt.Fprintf(w, "  %s\n", reason)
+		}
+	}
+}
+
+// PrintDiff prints the results of the nodes and the metrics of the targets in the two rounds
+func PrintDiff(w io.Writer, diff *RoundDiff) {
+	fmt.Fprintf(w, "Round %d -> round %d\n\n", diff.FromRound, diff.ToRound)
+
+	tw := newTabWriter(w)
+	fmt.Fprintln(tw, "NODE\tFROM\tTO")
+	for _, n := range diff.Nodes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", n.NodeName, or(n.FromResult, "-"), or(n.ToResult, "-"))
+	}
+	_ = tw.Flush()
+	if len(diff.Targets) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	tw = newTabWriter(w)
+	fmt.Fprintln(tw, "NODE\tTARGET\tSUCCESS RATE\tCHANGE\tP50(ms)\tCHANGE\tP99(ms)\tCHANGE")
+	for _, t := range diff.Targets {
+		switch {
+		case t.From == nil:
+			fmt.Fprintf(tw, "%s\t%s\t%.2f\tadded\t%.2f\tadded\t%.2f\tadded\n", t.NodeName, t.TargetName, t.To.SucceedRate, t.To.Latencies.P50, t.To.Latencies.P99)
+		case t.To == nil:
+			fmt.Fprintf(tw, "%s\t%s\t%.2f\tremoved\t%.2f\tremoved\t%.2f\tremoved\n", t.NodeName, t.TargetName, t.From.SucceedRate, t.From.Latencies.P50, t.From.Latencies.P99)
+		default:
+			fmt.Fprintf(tw, "%s\t%s\t%.2f -> %.2f\t%s\t%.2f -> %.2f\t%s\t%.2f -> %.2f\t%s\n", t.NodeName, t.TargetName,
+				t.From.SucceedRate, t.To.SucceedRate, formatChange(t.From.SucceedRate, t.To.SucceedRate),
+				t.From.Latencies.P50, t.To.Latencies.P50, formatChange(float64(t.From.Latencies.P50), float64(t.To.Latencies.P50)),
+				t.From.Latencies.P99, t.To.Latencies.P99, formatChange(float64(t.From.Latencies.P99), float64(t.To.Latencies.P99)))
+		}
+	}
+	_ = tw.Flush()
+}
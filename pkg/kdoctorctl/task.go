@@ -0,0 +1,214 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kdoctorctl implements the commands of kdoctorctl, which creates the tasks, waits for them and summarizes their reports
+package kdoctorctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// RunKinds are the kinds of the task which could be created by the command run
+var RunKinds = []string{types.KindNameNetReach, types.KindNameAppHttpHealthy, types.KindNameNetdns}
+
+// NormalizeKind returns the kind of the task, which is case-insensitive
+func NormalizeKind(kind string) (string, error) {
+	for _, k := range types.TaskKinds {
+		if strings.EqualFold(k, kind) {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown task kind %q, it should be one of %v", kind, types.TaskKinds)
+}
+
+// ReportName returns the name of the kdoctorreport of the task
+func ReportName(kind, name string) string {
+	return strings.ToLower(kind) + "-" + name
+}
+
+// TaskOptions are the flags of the command run
+type TaskOptions struct {
+	Name string
+
+	// schedule
+	Rounds             int64
+	Schedule           string
+	RoundTimeoutMinute int64
+
+	// request
+	QPS                   int
+	DurationInSecond      int
+	PerRequestTimeoutInMS int
+
+	// success condition
+	SuccessRate         float64
+	MeanAccessDelayInMs int64
+
+	// AppHttpHealthy
+	Host   string
+	Method string
+
+	// Netdns
+	Server   string
+	Domain   string
+	Protocol string
+}
+
+func (o *TaskOptions) schedule() *crd.SchedulePlan {
+	plan := &crd.SchedulePlan{
+		RoundNumber:        o.Rounds,
+		RoundTimeoutMinute: o.RoundTimeoutMinute,
+	}
+	if len(o.Schedule) > 0 {
+		s := o.Schedule
+		plan.Schedule = &s
+	}
+	return plan
+}
+
+func (o *TaskOptions) successCondition() *crd.NetSuccessCondition {
+	c := &crd.NetSuccessCondition{}
+	if o.SuccessRate > 0 {
+		n := o.SuccessRate
+		c.SuccessRate = &n
+	}
+	if o.MeanAccessDelayInMs > 0 {
+		n := o.MeanAccessDelayInMs
+		c.MeanAccessDelayInMs = &n
+	}
+	return c
+}
+
+func (o *TaskOptions) httpRequest() *crd.NetHttpRequest {
+	return &crd.NetHttpRequest{
+		DurationInSecond:      o.DurationInSecond,
+		QPS:                   o.QPS,
+		PerRequestTimeoutInMS: o.PerRequestTimeoutInMS,
+	}
+}
+
+// NewTask builds the task of the kind from the options, and the fields not set are defaulted by the webhook of kdoctor
+func NewTask(kind string, o *TaskOptions) (client.Object, error) {
+	if len(o.Name) == 0 {
+		return nil, fmt.Errorf("the name of the task is required")
+	}
+	meta := metav1.ObjectMeta{Name: o.Name}
+
+	switch kind {
+	case types.KindNameNetReach:
+		return &crd.NetReach{
+			TypeMeta:   metav1.TypeMeta{Kind: kind, APIVersion: crd.GroupVersion.String()},
+			ObjectMeta: meta,
+			Spec: crd.NetReachSpec{
+				Schedule:         o.schedule(),
+				Request:          o.httpRequest(),
+				SuccessCondition: o.successCondition(),
+			},
+		}, nil
+
+	case types.KindNameAppHttpHealthy:
+		if len(o.Host) == 0 {
+			return nil, fmt.Errorf("the host of the AppHttpHealthy task is required")
+		}
+		method := strings.ToUpper(o.Method)
+		if len(method) == 0 {
+			method = "GET"
+		}
+		return &crd.AppHttpHealthy{
+			TypeMeta:   metav1.TypeMeta{Kind: kind, APIVersion: crd.GroupVersion.String()},
+			ObjectMeta: meta,
+			Spec: crd.AppHttpHealthySpec{
+				Schedule:         o.schedule(),
+				Target:           &crd.AppHttpHealthyTarget{Host: o.Host, Method: method},
+				Request:          o.httpRequest(),
+				SuccessCondition: o.successCondition(),
+			},
+		}, nil
+
+	case types.KindNameNetdns:
+		target := &crd.NetDnsTarget{}
+		if len(o.Server) > 0 {
+			server := o.Server
+			target.NetDnsTargetUser = &crd.NetDnsTargetUserSpec{Server: &server}
+		} else {
+			// the dns service of the cluster
+			target.NetDnsTargetDns = &crd.NetDnsTargetDnsSpec{}
+		}
+		request := &crd.NetdnsRequest{
+			DurationInSecond:      o.DurationInSecond,
+			QPS:                   o.QPS,
+			PerRequestTimeoutInMS: o.PerRequestTimeoutInMS,
+			Domain:                o.Domain,
+		}
+		if len(o.Protocol) > 0 {
+			protocol := o.Protocol
+			request.Protocol = &protocol
+		}
+		return &crd.Netdns{
+			TypeMeta:   metav1.TypeMeta{Kind: kind, APIVersion: crd.GroupVersion.String()},
+			ObjectMeta: meta,
+			Spec: crd.NetdnsSpec{
+				Schedule:         o.schedule(),
+				Target:           target,
+				Request:          request,
+				SuccessCondition: o.successCondition(),
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("the task kind %v could not be created by kdoctorctl, it should be one of %v", kind, RunKinds)
+}
+
+// TaskResult is the result of the finished rounds of the task
+type TaskResult struct {
+	Finished     bool
+	DoneRound    int64
+	FailedRounds []int
+}
+
+// Failed returns whether any finished round fails
+func (r *TaskResult) Failed() bool {
+	return len(r.FailedRounds) > 0
+}
+
+// ResultOf summarizes the status of the task
+func ResultOf(status *crd.TaskStatus) *TaskResult {
+	r := &TaskResult{Finished: status.Finish}
+	if status.DoneRound != nil {
+		r.DoneRound = *status.DoneRound
+	}
+	// the history is in the order of the newest first
+	for i := len(status.History) - 1; i >= 0; i-- {
+		if status.History[i].Status == crd.StatusHistoryRecordStatusFail {
+			r.FailedRounds = append(r.FailedRounds, status.History[i].RoundNumber)
+		}
+	}
+	return r
+}
+
+// SuspendPatch returns the json merge patch which suspends or resumes the scheduled rounds of the task
+func SuspendPatch(suspend bool) []byte {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"schedule": map[string]interface{}{"suspend": suspend},
+		},
+	})
+	return patch
+}
+
+// RunNowPatch returns the json merge patch which adds a manual round to the task, the value should differ from the last one
+func RunNowPatch(value string) []byte {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{crd.AnnotationRunNow: value},
+		},
+	})
+	return patch
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+package kdoctorctl_test
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/kdoctor-io/kdoctor/pkg/kdoctorctl"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+var _ = Describe("test task", Label("task"), func() {
+
+	It("normalize the kind", func() {
+		kind, err := kdoctorctl.NormalizeKind("netdns")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kind).To(Equal(types.KindNameNetdns))
+		_, err = kdoctorctl.NormalizeKind("pod")
+		Expect(err).To(HaveOccurred())
+		Expect(kdoctorctl.ReportName(types.KindNameAppHttpHealthy, "test")).To(Equal("apphttphealthy-test"))
+	})
+
+	It("build the task from the options", func() {
+		o := &kdoctorctl.TaskOptions{Name: "test", Rounds: 3, Schedule: "0 1", RoundTimeoutMinute: 5, QPS: 10, SuccessRate: 0.9}
+		obj, err := kdoctorctl.NewTask(types.KindNameNetReach, o)
+		Expect(err).NotTo(HaveOccurred())
+		netreach := obj.(*crd.NetReach)
+		Expect(netreach.Name).To(Equal("test"))
+		Expect(netreach.Spec.Schedule.RoundNumber).To(Equal(int64(3)))
+		Expect(*netreach.Spec.Schedule.Schedule).To(Equal("0 1"))
+		Expect(netreach.Spec.Request.QPS).To(Equal(10))
+		Expect(*netreach.Spec.SuccessCondition.SuccessRate).To(Equal(0.9))
+		Expect(netreach.Spec.SuccessCondition.MeanAccessDelayInMs).To(BeNil())
+
+		_, err = kdoctorctl.NewTask(types.KindNameAppHttpHealthy, o)
+		Expect(err).To(HaveOccurred())
+		o.Host = "http://app.default.svc"
+		o.Method = "post"
+		obj, err = kdoctorctl.NewTask(types.KindNameAppHttpHealthy, o)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.(*crd.AppHttpHealthy).Spec.Target.Method).To(Equal("POST"))
+
+		o.Domain = "kubernetes.default.svc.cluster.local"
+		obj, err = kdoctorctl.NewTask(types.KindNameNetdns, o)
+		Expect(err).NotTo(HaveOccurred())
+		netdns := obj.(*crd.Netdns)
+		Expect(netdns.Spec.Target.NetDnsTargetDns).NotTo(BeNil())
+		Expect(netdns.Spec.Request.Domain).To(Equal(o.Domain))
+		o.Server = "8.8.8.8"
+		obj, err = kdoctorctl.NewTask(types.KindNameNetdns, o)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*obj.(*crd.Netdns).Spec.Target.NetDnsTargetUser.Server).To(Equal("8.8.8.8"))
+
+		_, err = kdoctorctl.NewTask(types.KindNameNetTcp, o)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("wait for the task to finish", func() {
+		done, expected := int64(1), int64(2)
+		task := &crd.NetReach{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Status: crd.TaskStatus{
+				DoneRound:     &done,
+				ExpectedRound: &expected,
+				History: []crd.StatusHistoryRecord{
+					{RoundNumber: 2, Status: crd.StatusHistoryRecordStatusOngoing},
+					{RoundNumber: 1, Status: crd.StatusHistoryRecordStatusSucceed},
+				},
+			},
+		}
+		// the tracker guesses a wrong resource for the seeded objects, so create them by the client
+		clientset := fake.NewSimpleClientset()
+		_, err := clientset.KdoctorV1beta1().NetReaches().Create(context.Background(), task, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		c := &kdoctorctl.Client{Kdoctor: clientset}
+
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(50 * time.Millisecond)
+			finished := task.DeepCopy()
+			finished.Status.History[0].Status = crd.StatusHistoryRecordStatusFail
+			finished.Status.Finish = true
+			_, err := clientset.KdoctorV1beta1().NetReaches().UpdateStatus(context.Background(), finished, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}()
+
+		rounds := []int{}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		status, err := c.WaitForTask(ctx, types.KindNameNetReach, "test", 10*time.Millisecond, func(record crd.StatusHistoryRecord) {
+			rounds = append(rounds, record.RoundNumber)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rounds).To(Equal([]int{1, 2}))
+
+		result := kdoctorctl.ResultOf(status)
+		Expect(result.Finished).To(BeTrue())
+		Expect(result.Failed()).To(BeTrue())
+		Expect(result.FailedRounds).To(Equal([]int{2}))
+
+		out := &bytes.Buffer{}
+		kdoctorctl.PrintStatus(out, types.KindNameNetReach, "test", status)
+		Expect(out.String()).To(ContainSubstring("Rounds:    1 done, 2 expected"))
+		Expect(out.String()).To(ContainSubstring("Failed:    rounds [2]"))
+		Expect(out.String()).NotTo(ContainSubstring("Next:"))
+
+		status.NextRoundTimes = []metav1.Time{metav1.NewTime(time.Date(2023, 3, 26, 7, 0, 0, 0, time.UTC)), metav1.NewTime(time.Date(2023, 3, 27, 7, 0, 0, 0, time.UTC))}
+		out.Reset()
+		kdoctorctl.PrintStatus(out, types.KindNameNetReach, "test", status)
+		Expect(out.String()).To(ContainSubstring("Next:      2023-03-26T07:00:00Z, 2023-03-27T07:00:00Z"))
+	})
+
+	It("time out waiting for the task", func() {
+		clientset := fake.NewSimpleClientset()
+		_, err := clientset.KdoctorV1beta1().Netdnses().Create(context.Background(), &crd.Netdns{ObjectMeta: metav1.ObjectMeta{Name: "test"}}, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		c := &kdoctorctl.Client{Kdoctor: clientset}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = c.WaitForTask(ctx, types.KindNameNetdns, "test", 10*time.Millisecond, nil)
+		Expect(err).To(MatchError(ContainSubstring("timeout")))
+	})
+
+	It("suspend and trigger the task", func() {
+		clientset := fake.NewSimpleClientset()
+		schedule := "0 60"
+		task := &crd.NetReach{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec:       crd.NetReachSpec{Schedule: &crd.SchedulePlan{Schedule: &schedule, RoundNumber: 3}},
+		}
+		_, err := clientset.KdoctorV1beta1().NetReaches().Create(context.Background(), task, metav1.CreateOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		c := &kdoctorctl.Client{Kdoctor: clientset}
+
+		Expect(c.PatchTask(context.Background(), types.KindNameNetReach, "test", kdoctorctl.SuspendPatch(true))).To(Succeed())
+		Expect(c.PatchTask(context.Background(), types.KindNameNetReach, "test", kdoctorctl.RunNowPatch("now"))).To(Succeed())
+		latest, err := clientset.KdoctorV1beta1().NetReaches().Get(context.Background(), "test", metav1.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*latest.Spec.Schedule.Suspend).To(BeTrue())
+		Expect(*latest.Spec.Schedule.Schedule).To(Equal("0 60"))
+		Expect(latest.Annotations).To(HaveKeyWithValue(crd.AnnotationRunNow, "now"))
+
+		Expect(c.PatchTask(context.Background(), types.KindNameNetTcp, "test", kdoctorctl.SuspendPatch(false))).NotTo(Succeed())
+	})
+})
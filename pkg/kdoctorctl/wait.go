@@ -0,0 +1,47 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package kdoctorctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+)
+
+// WaitForTask polls the status of the task until it finishes, and calls onRound once for each finished round in the order of the round number
+func (c *Client) WaitForTask(ctx context.Context, kind, name string, interval time.Duration, onRound func(record crd.StatusHistoryRecord)) (*crd.TaskStatus, error) {
+	reported := map[int]bool{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := c.GetTaskStatus(ctx, kind, name)
+		if err != nil {
+			return nil, err
+		}
+		if onRound != nil {
+			for i := len(status.History) - 1; i >= 0; i-- {
+				record := status.History[i]
+				switch record.Status {
+				case crd.StatusHistoryRecordStatusSucceed, crd.StatusHistoryRecordStatusFail, crd.StatusHistoryRecordStatusSkipped:
+					if !reported[record.RoundNumber] {
+						reported[record.RoundNumber] = true
+						onRound(record)
+					}
+				}
+			}
+		}
+		if status.Finish {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("timeout waiting for %v %v to finish", kind, name)
+		case <-ticker.C:
+		}
+	}
+}
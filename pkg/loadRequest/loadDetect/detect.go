@@ -0,0 +1,41 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDetect
+
+// Steps describes how the qps of the detect mode is ramped step by step
+type Steps struct {
+	// the qps of the first step
+	StartQps int
+	// the qps increased by each step
+	StepQps int
+	// the qps of the last step
+	MaxQps             int
+	StepDurationSecond int
+}
+
+// StepNumber returns the number of steps to ramp the qps from StartQps to MaxQps
+func (s *Steps) StepNumber() int {
+	if s.StepQps <= 0 || s.MaxQps < s.StartQps {
+		return 0
+	}
+	n := (s.MaxQps - s.StartQps) / s.StepQps
+	if s.StartQps+n*s.StepQps < s.MaxQps {
+		n++
+	}
+	return n + 1
+}
+
+// StepQpsList returns the qps of each step, the last step is clamped to MaxQps
+func (s *Steps) StepQpsList() []int {
+	n := s.StepNumber()
+	list := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		qps := s.StartQps + i*s.StepQps
+		if qps > s.MaxQps {
+			qps = s.MaxQps
+		}
+		list = append(list, qps)
+	}
+	return list
+}
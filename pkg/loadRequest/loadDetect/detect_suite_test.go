@@ -0,0 +1,15 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+package loadDetect_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLoadDetect(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "load detect Suite")
+}
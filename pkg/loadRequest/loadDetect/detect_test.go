@@ -0,0 +1,28 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDetect_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDetect"
+)
+
+var _ = Describe("test detect steps ", Label("detect"), func() {
+
+	It("count the steps", func() {
+		Expect((&loadDetect.Steps{StartQps: 10, StepQps: 10, MaxQps: 30}).StepNumber()).To(Equal(3))
+		Expect((&loadDetect.Steps{StartQps: 10, StepQps: 10, MaxQps: 35}).StepNumber()).To(Equal(4))
+		Expect((&loadDetect.Steps{StartQps: 10, StepQps: 10, MaxQps: 10}).StepNumber()).To(Equal(1))
+		Expect((&loadDetect.Steps{StartQps: 10, StepQps: 10, MaxQps: 5}).StepNumber()).To(Equal(0))
+		Expect((&loadDetect.Steps{StartQps: 10, StepQps: 0, MaxQps: 30}).StepNumber()).To(Equal(0))
+	})
+
+	It("clamp the qps of the last step", func() {
+		Expect((&loadDetect.Steps{StartQps: 10, StepQps: 10, MaxQps: 35}).StepQpsList()).To(Equal([]int{10, 20, 30, 35}))
+		Expect((&loadDetect.Steps{StartQps: 10, StepQps: 10, MaxQps: 10}).StepQpsList()).To(Equal([]int{10}))
+		Expect((&loadDetect.Steps{StartQps: 10, StepQps: 10, MaxQps: 5}).StepQpsList()).To(BeEmpty())
+	})
+})
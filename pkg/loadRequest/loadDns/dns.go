@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	"github.com/miekg/dns"
 	"go.uber.org/zap"
 )
@@ -37,6 +38,10 @@ const (
 	RequestMethodUdp    = RequestProtocol("udp")
 	RequestMethodTcp    = RequestProtocol("tcp")
 	RequestMethodTcpTls = RequestProtocol("tcp-tls")
+	// DNS over HTTPS, RFC 8484
+	RequestMethodHttps = RequestProtocol("https")
+	// DNS over QUIC, RFC 9250
+	RequestMethodQuic = RequestProtocol("quic")
 
 	DefaultDnsConfPath = "/etc/resolv.conf"
 )
@@ -53,40 +58,115 @@ type DnsRequestData struct {
 	Qps                   int
 	DurationInSecond      int
 	EnableLatencyMetric   bool
+	// the latency of each succeeded request is recorded to the metric of the target when it is not nil
+	MetricTarget *metrics.TaskTarget
+	// GET or POST for the https protocol, POST by default
+	DohMethod string
+	// the path for the https protocol, DefaultDohPath when it is empty
+	DohPath string
+	// the DO bit is set in the requests and the replies are checked when it is not nil
+	Dnssec *DnssecOption
+	// the requests rotate through the queries when it is not empty, and TargetDomain and DnsType are ignored
+	Queries []DnsQuery
+}
+
+type DnsQuery struct {
+	// must be full domain
+	TargetDomain string
+	// dns.TypeA, dns.TypeSRV and so on
+	DnsType uint16
+	// the expected rdata of the answers, nothing is checked when it is empty
+	ExpectedAnswers []string
 }
 
 func DnsRequest(logger *zap.Logger, reqData *DnsRequestData) (result *v1beta1.DNSMetrics, err error) {
+	w, err := runDnsRequest(logger, reqData)
+	if err != nil {
+		return nil, err
+	}
+	// Collect metric reports
+	metrics := w.AggregateMetric()
 
-	logger.Sugar().Infof("dns ServerAddress=%v, request=%v, ", reqData.DnsServerAddr, reqData)
+	logger.Sugar().Infof("result : %v ", metrics)
+	return metrics, nil
+}
+
+// DnsRequestByQuery rotates through the queries of reqData, and returns the metric of each query
+func DnsRequestByQuery(logger *zap.Logger, reqData *DnsRequestData) (result []*v1beta1.DNSMetrics, err error) {
+	if len(reqData.Queries) == 0 {
+		return nil, fmt.Errorf("no query specified")
+	}
+	w, err := runDnsRequest(logger, reqData)
+	if err != nil {
+		return nil, err
+	}
+	metrics := w.AggregateQueryMetrics()
+
+	logger.Sugar().Infof("result of %d queries: %v ", len(metrics), metrics)
+	return metrics, nil
+}
 
-	if _, ok := dns.IsDomainName(reqData.TargetDomain); !ok {
-		return nil, fmt.Errorf("invalid domain name: %v", reqData.TargetDomain)
+func toFqdn(logger *zap.Logger, domain string) (string, error) {
+	if _, ok := dns.IsDomainName(domain); !ok {
+		return "", fmt.Errorf("invalid domain name: %v", domain)
 	}
 	// if not fqdn, the dns library will report error, so convert the format
-	if !dns.IsFqdn(reqData.TargetDomain) {
-		reqData.TargetDomain = dns.Fqdn(reqData.TargetDomain)
-		logger.Sugar().Debugf("convert target domain to fqdn %v", reqData.TargetDomain)
+	if !dns.IsFqdn(domain) {
+		domain = dns.Fqdn(domain)
+		logger.Sugar().Debugf("convert target domain to fqdn %v", domain)
 	}
+	return domain, nil
+}
+
+func runDnsRequest(logger *zap.Logger, reqData *DnsRequestData) (*Work, error) {
+
+	logger.Sugar().Infof("dns ServerAddress=%v, request=%v, ", reqData.DnsServerAddr, reqData)
+
 	duration := time.Duration(reqData.DurationInSecond) * time.Second
 
 	w := &Work{
 		RequestTimeSecond:   reqData.DurationInSecond,
 		QPS:                 reqData.Qps,
 		Timeout:             reqData.PerRequestTimeoutInMs,
-		Msg:                 new(dns.Msg).SetQuestion(reqData.TargetDomain, reqData.DnsType),
 		Protocol:            string(reqData.Protocol),
 		ServerAddr:          reqData.DnsServerAddr,
 		EnableLatencyMetric: reqData.EnableLatencyMetric,
+		MetricTarget:        reqData.MetricTarget,
+		DohMethod:           reqData.DohMethod,
+		DohPath:             reqData.DohPath,
+		Dnssec:              reqData.Dnssec,
 		Logger:              logger.Named("dns-client"),
 	}
+
+	if len(reqData.Queries) > 0 {
+		for _, q := range reqData.Queries {
+			domain, err := toFqdn(logger, q.TargetDomain)
+			if err != nil {
+				return nil, err
+			}
+			w.Msgs = append(w.Msgs, new(dns.Msg).SetQuestion(domain, q.DnsType))
+			w.ExpectedAnswers = append(w.ExpectedAnswers, q.ExpectedAnswers)
+		}
+	} else {
+		domain, err := toFqdn(logger, reqData.TargetDomain)
+		if err != nil {
+			return nil, err
+		}
+		reqData.TargetDomain = domain
+		w.Msg = new(dns.Msg).SetQuestion(reqData.TargetDomain, reqData.DnsType)
+	}
+
+	if w.Dnssec != nil {
+		for _, m := range append(w.Msgs, w.Msg) {
+			if m != nil {
+				m.SetEdns0(4096, true)
+			}
+		}
+	}
+
 	w.Init()
 	logger.Sugar().Infof("begin to request %v for duration %v ", w.ServerAddr, duration.String())
 	w.Run()
 	logger.Sugar().Infof("finish all request %v for %s ", w.report.totalCount, w.ServerAddr)
-	// Collect metric reports
-	metrics := w.AggregateMetric()
-
-	logger.Sugar().Infof("result : %v ", metrics)
-	return metrics, nil
-
+	return w, nil
 }
@@ -0,0 +1,65 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDns
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// AnswerRData returns the rdata of the record in the presentation format, for example,
+// "10.0.0.1" for A, "0 100 53 coredns.kube-system.svc.cluster.local." for SRV,
+// and the joined strings without quotation marks for TXT
+func AnswerRData(rr dns.RR) string {
+	if txt, ok := rr.(*dns.TXT); ok {
+		return strings.Join(txt.Txt, "")
+	}
+	return strings.TrimSpace(strings.TrimPrefix(rr.String(), rr.Header().String()))
+}
+
+// normalizeRData makes the comparison of rdata ignore the case and the trailing dot of domain names
+func normalizeRData(rdata string) string {
+	fields := strings.Fields(rdata)
+	for i := range fields {
+		fields[i] = strings.ToLower(strings.TrimSuffix(fields[i], "."))
+	}
+	return strings.Join(fields, " ")
+}
+
+// CheckAnswers compares the records of the query type in the answer section with the expected rdata, regardless of the order.
+// It returns the description of the mismatch, or empty if they match
+func CheckAnswers(reply *dns.Msg, qtype uint16, expected []string) string {
+	if reply == nil {
+		return "unexpected answer: no reply"
+	}
+
+	var actual []string
+	for _, rr := range reply.Answer {
+		// the CNAME records in the chain of the other types are not compared
+		if rr.Header().Rrtype != qtype {
+			continue
+		}
+		actual = append(actual, AnswerRData(rr))
+	}
+
+	want := make([]string, 0, len(expected))
+	for _, v := range expected {
+		want = append(want, normalizeRData(v))
+	}
+	got := make([]string, 0, len(actual))
+	for _, v := range actual {
+		got = append(got, normalizeRData(v))
+	}
+	sort.Strings(want)
+	sort.Strings(got)
+	if strings.Join(want, "\n") == strings.Join(got, "\n") {
+		return ""
+	}
+
+	sort.Strings(actual)
+	return fmt.Sprintf("unexpected %s answer [%s], expect [%s]", dns.TypeToString[qtype], strings.Join(actual, ", "), strings.Join(expected, ", "))
+}
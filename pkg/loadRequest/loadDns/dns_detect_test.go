@@ -0,0 +1,119 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDns_test
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDetect"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDns"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+)
+
+// newLimitedServer starts a local dns server which replies SERVFAIL to the requests beyond the limit in each second
+func newLimitedServer(limit int) (*dns.Server, string) {
+	var l sync.Mutex
+	var second int64
+	var counts int
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		l.Lock()
+		now := time.Now().Unix()
+		if now != second {
+			second = now
+			counts = 0
+		}
+		counts++
+		over := counts > limit
+		l.Unlock()
+
+		m := new(dns.Msg)
+		if over {
+			m.SetRcode(r, dns.RcodeServerFailure)
+		} else {
+			m.SetReply(r)
+			m.Answer = append(m.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+				A:   net.ParseIP("10.0.0.1"),
+			})
+		}
+		_ = w.WriteMsg(m)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	return server, pc.LocalAddr().String()
+}
+
+var _ = Describe("test dns detect ", Label("dns detect"), func() {
+	log := logger.NewStdoutLogger("debug", "test")
+
+	It("stop at the knee point with SERVFAIL", func() {
+		server, addr := newLimitedServer(25)
+		defer server.Shutdown()
+
+		successRate := float64(1)
+		req := &loadDns.DnsRequestData{
+			Protocol:              loadDns.RequestMethodUdp,
+			DnsType:               dns.TypeA,
+			TargetDomain:          "kdoctor.io",
+			DnsServerAddr:         addr,
+			PerRequestTimeoutInMs: 1000,
+		}
+		result, e := loadDns.DnsDetect(log, req, &loadDns.DnsDetectData{
+			Steps: loadDetect.Steps{
+				StartQps:           10,
+				StepQps:            10,
+				MaxQps:             50,
+				StepDurationSecond: 2,
+			},
+			SuccessRate: &successRate,
+		})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.KneeQPS).To(Equal(20))
+		Expect(result.BreakReason).NotTo(BeNil())
+		Expect(result.Steps).To(HaveLen(3))
+		Expect(result.Steps[1].Succeed).To(BeTrue())
+		Expect(result.Steps[2].Succeed).To(BeFalse())
+		Expect(result.Steps[2].ReplyCode).To(HaveKey(dns.RcodeToString[dns.RcodeServerFailure]))
+	})
+
+	It("reach the maximum qps", func() {
+		server, addr := newLimitedServer(1000)
+		defer server.Shutdown()
+
+		maxP99 := int64(1000)
+		req := &loadDns.DnsRequestData{
+			Protocol:              loadDns.RequestMethodUdp,
+			DnsType:               dns.TypeA,
+			TargetDomain:          "kdoctor.io",
+			DnsServerAddr:         addr,
+			PerRequestTimeoutInMs: 1000,
+		}
+		result, e := loadDns.DnsDetect(log, req, &loadDns.DnsDetectData{
+			Steps: loadDetect.Steps{
+				StartQps:           5,
+				StepQps:            10,
+				MaxQps:             20,
+				StepDurationSecond: 1,
+			},
+			MaxP99InMs: &maxP99,
+		})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.KneeQPS).To(Equal(20))
+		Expect(result.BreakReason).To(BeNil())
+		Expect(result.Steps).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,378 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDns
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/kdoctor-io/kdoctor/pkg/lock"
+)
+
+// DnssecOption sets the DO bit of the requests and checks the DNSSEC of the replies
+type DnssecOption struct {
+	// the reply without the AD flag is taken as a dnssec failure
+	CheckAD bool
+	// the DS or DNSKEY records of the trust anchors,
+	// the RRSIG chains of the replies are validated up to them when it is not empty
+	TrustAnchors []dns.RR
+}
+
+// ParseTrustAnchors parses the DS or DNSKEY records in the presentation format
+func ParseTrustAnchors(anchors []string) ([]dns.RR, error) {
+	result := make([]dns.RR, 0, len(anchors))
+	for _, v := range anchors {
+		rr, err := dns.NewRR(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trust anchor %q: %v", v, err)
+		}
+		if rr == nil {
+			return nil, fmt.Errorf("invalid trust anchor %q", v)
+		}
+		switch rr.(type) {
+		case *dns.DS, *dns.DNSKEY:
+		default:
+			return nil, fmt.Errorf("trust anchor %q is not DS or DNSKEY", v)
+		}
+		result = append(result, rr)
+	}
+	return result, nil
+}
+
+// the extended dns errors of RFC 8914 which are reported by a validating resolver
+var dnssecExtendedErrors = map[uint16]bool{
+	dns.ExtendedErrorCodeUnsupportedDNSKEYAlgorithm: true,
+	dns.ExtendedErrorCodeUnsupportedDSDigestType:    true,
+	dns.ExtendedErrorCodeDNSSECIndeterminate:        true,
+	dns.ExtendedErrorCodeDNSBogus:                   true,
+	dns.ExtendedErrorCodeSignatureExpired:           true,
+	dns.ExtendedErrorCodeSignatureNotYetValid:       true,
+	dns.ExtendedErrorCodeDNSKEYMissing:              true,
+	dns.ExtendedErrorCodeRRSIGsMissing:              true,
+	dns.ExtendedErrorCodeNoZoneKeyBitSet:            true,
+	dns.ExtendedErrorCodeNSECMissing:                true,
+}
+
+// the maximum number of zones from the answer to the trust anchor
+const maxDnssecChainDepth = 16
+
+// the failure to authenticate the keys of a zone is kept for a short time, so the next requests look up the keys again
+const dnssecFailureCacheTTL = 2 * time.Second
+
+// dnssecLookupError is the failure to look up the DNSKEY or DS records, which is not a failure of the validation
+type dnssecLookupError struct {
+	err error
+}
+
+func (e *dnssecLookupError) Error() string {
+	return e.err.Error()
+}
+
+type zoneKeys struct {
+	keys []*dns.DNSKEY
+	err  error
+	// the failure expires at the time, and the authenticated keys never expire
+	expireTime time.Time
+}
+
+// dnssecValidator checks the replies, and caches the authenticated DNSKEY records of the zones for the whole work
+type dnssecValidator struct {
+	option   *DnssecOption
+	exchange func(msg *dns.Msg) (*dns.Msg, error)
+
+	lock  lock.RWMutex
+	zones map[string]*zoneKeys
+	// the concurrent requests share one lookup of the keys of a zone
+	lookups singleflight.Group
+}
+
+func newDnssecValidator(option *DnssecOption, exchange func(msg *dns.Msg) (*dns.Msg, error)) *dnssecValidator {
+	return &dnssecValidator{
+		option:   option,
+		exchange: exchange,
+		zones:    make(map[string]*zoneKeys),
+	}
+}
+
+// check returns the description of the dnssec failure of the reply, or empty if it passes.
+// The error is returned when the keys could not be looked up, and the reply is not validated
+func (v *dnssecValidator) check(reply *dns.Msg) (string, error) {
+	if opt := reply.IsEdns0(); opt != nil {
+		for _, o := range opt.Option {
+			if ede, ok := o.(*dns.EDNS0_EDE); ok && dnssecExtendedErrors[ede.InfoCode] {
+				return fmt.Sprintf("dnssec failure: %s %s", dns.ExtendedErrorCodeToString[ede.InfoCode], ede.ExtraText), nil
+			}
+		}
+	}
+
+	// the other rcodes carry no answer to check, they are counted in the reply code
+	if reply.Rcode != dns.RcodeSuccess && reply.Rcode != dns.RcodeNameError {
+		return "", nil
+	}
+	if v.option.CheckAD && !reply.AuthenticatedData {
+		return "dnssec failure: the AD flag is not set", nil
+	}
+	if len(v.option.TrustAnchors) > 0 {
+		if err := v.validate(reply); err != nil {
+			var lookupErr *dnssecLookupError
+			if errors.As(err, &lookupErr) {
+				return "", lookupErr
+			}
+			return "dnssec failure: " + err.Error(), nil
+		}
+	}
+	return "", nil
+}
+
+// validate verifies the RRSIG of each RRset of the answer section, or of the authority section for the negative reply
+func (v *dnssecValidator) validate(reply *dns.Msg) error {
+	section := reply.Answer
+	if len(section) == 0 {
+		section = reply.Ns
+	}
+	rrsets, sigs := splitRRsets(section)
+	if len(rrsets) == 0 {
+		return fmt.Errorf("no record to validate")
+	}
+	for key, rrset := range rrsets {
+		if err := v.verifyRRset(rrset, sigs[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *dnssecValidator) verifyRRset(rrset []dns.RR, sigs []*dns.RRSIG) error {
+	name := rrset[0].Header().Name
+	rrType := dns.TypeToString[rrset[0].Header().Rrtype]
+	if len(sigs) == 0 {
+		return fmt.Errorf("no RRSIG for %s %s", name, rrType)
+	}
+
+	var lastErr error
+	for _, sig := range sigs {
+		// the RRset is signed by the zone of the owner name, or by one of its parent zones
+		signer := dns.CanonicalName(sig.SignerName)
+		if !dns.IsSubDomain(signer, dns.CanonicalName(name)) {
+			lastErr = fmt.Errorf("the signer %s is not the zone of %s", signer, name)
+			continue
+		}
+		keys, err := v.authenticatedKeys(signer, 0)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := verifySig(sig, keys, rrset); err != nil {
+			lastErr = err
+			continue
+		}
+		return nil
+	}
+	var lookupErr *dnssecLookupError
+	if errors.As(lastErr, &lookupErr) {
+		return lookupErr
+	}
+	return fmt.Errorf("invalid RRSIG for %s %s, %v", name, rrType, lastErr)
+}
+
+// authenticatedKeys returns the DNSKEY records of the zone which are authenticated from the trust anchors.
+// The lock is not held during the lookup, and the concurrent callers of a zone wait for the same lookup
+func (v *dnssecValidator) authenticatedKeys(zone string, depth int) ([]*dns.DNSKEY, error) {
+	v.lock.RLock()
+	z, ok := v.zones[zone]
+	v.lock.RUnlock()
+	if ok && (z.expireTime.IsZero() || time.Now().Before(z.expireTime)) {
+		return z.keys, z.err
+	}
+	if depth > maxDnssecChainDepth {
+		return nil, fmt.Errorf("the chain of trust of %s is too long", zone)
+	}
+
+	result, err, _ := v.lookups.Do(zone, func() (interface{}, error) {
+		keys, err := v.authenticateZone(zone, depth)
+		z := &zoneKeys{keys: keys, err: err}
+		if err != nil {
+			z.expireTime = time.Now().Add(dnssecFailureCacheTTL)
+		}
+		v.lock.Lock()
+		v.zones[zone] = z
+		v.lock.Unlock()
+		return keys, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*dns.DNSKEY), nil
+}
+
+func (v *dnssecValidator) authenticateZone(zone string, depth int) ([]*dns.DNSKEY, error) {
+	reply, err := v.query(zone, dns.TypeDNSKEY)
+	if err != nil {
+		return nil, err
+	}
+	rrsets, sigs := splitRRsets(reply.Answer)
+	key := rrsetKey(zone, dns.TypeDNSKEY)
+	keySet := rrsets[key]
+	keys := make([]*dns.DNSKEY, 0, len(keySet))
+	for _, rr := range keySet {
+		keys = append(keys, rr.(*dns.DNSKEY))
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no DNSKEY of %s", zone)
+	}
+
+	// the entry keys are trusted by the anchors of the zone, or by the DS records of the parent zone
+	var dsSet []*dns.DS
+	var anchorKeys []*dns.DNSKEY
+	for _, rr := range v.option.TrustAnchors {
+		if dns.CanonicalName(rr.Header().Name) != zone {
+			continue
+		}
+		switch t := rr.(type) {
+		case *dns.DS:
+			dsSet = append(dsSet, t)
+		case *dns.DNSKEY:
+			anchorKeys = append(anchorKeys, t)
+		}
+	}
+	if len(dsSet) == 0 && len(anchorKeys) == 0 {
+		dsSet, err = v.authenticatedDS(zone, depth)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var entryKeys []*dns.DNSKEY
+	for _, k := range keys {
+		if matchDnsKey(k, anchorKeys) || matchDS(k, dsSet) {
+			entryKeys = append(entryKeys, k)
+		}
+	}
+	if len(entryKeys) == 0 {
+		return nil, fmt.Errorf("no DNSKEY of %s matches the DS or the trust anchor", zone)
+	}
+
+	for _, sig := range sigs[key] {
+		if verifySig(sig, entryKeys, keySet) == nil {
+			return keys, nil
+		}
+	}
+	return nil, fmt.Errorf("the DNSKEY of %s is not signed by the trusted key", zone)
+}
+
+// authenticatedDS returns the DS records of the zone which are signed by the authenticated keys of the parent zone
+func (v *dnssecValidator) authenticatedDS(zone string, depth int) ([]*dns.DS, error) {
+	if zone == "." {
+		return nil, fmt.Errorf("no trust anchor for the chain")
+	}
+	reply, err := v.query(zone, dns.TypeDS)
+	if err != nil {
+		return nil, err
+	}
+	rrsets, sigs := splitRRsets(reply.Answer)
+	key := rrsetKey(zone, dns.TypeDS)
+	if len(rrsets[key]) == 0 {
+		return nil, fmt.Errorf("no DS of %s, it is an insecure delegation", zone)
+	}
+
+	lastErr := fmt.Errorf("no RRSIG for %s DS", zone)
+	for _, sig := range sigs[key] {
+		signer := dns.CanonicalName(sig.SignerName)
+		if signer == zone || !dns.IsSubDomain(signer, zone) {
+			lastErr = fmt.Errorf("unexpected signer %s of %s DS", signer, zone)
+			continue
+		}
+		parentKeys, err := v.authenticatedKeys(signer, depth+1)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := verifySig(sig, parentKeys, rrsets[key]); err != nil {
+			lastErr = err
+			continue
+		}
+		dsSet := make([]*dns.DS, 0, len(rrsets[key]))
+		for _, rr := range rrsets[key] {
+			dsSet = append(dsSet, rr.(*dns.DS))
+		}
+		return dsSet, nil
+	}
+	return nil, lastErr
+}
+
+func (v *dnssecValidator) query(name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg).SetQuestion(name, qtype)
+	msg.SetEdns0(4096, true)
+	reply, err := v.exchange(msg)
+	if err != nil {
+		return nil, &dnssecLookupError{err: fmt.Errorf("failed to query %s %s, %v", name, dns.TypeToString[qtype], err)}
+	}
+	if reply.Rcode != dns.RcodeSuccess {
+		return nil, &dnssecLookupError{err: fmt.Errorf("failed to query %s %s, rcode %s", name, dns.TypeToString[qtype], dns.RcodeToString[reply.Rcode])}
+	}
+	return reply, nil
+}
+
+func verifySig(sig *dns.RRSIG, keys []*dns.DNSKEY, rrset []dns.RR) error {
+	if !sig.ValidityPeriod(time.Now()) {
+		return fmt.Errorf("RRSIG of key %d is expired or not yet valid", sig.KeyTag)
+	}
+	for _, k := range keys {
+		if k.KeyTag() != sig.KeyTag || k.Algorithm != sig.Algorithm {
+			continue
+		}
+		if err := sig.Verify(k, rrset); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("no DNSKEY of %s verifies the RRSIG of key %d", sig.SignerName, sig.KeyTag)
+}
+
+func matchDnsKey(k *dns.DNSKEY, anchors []*dns.DNSKEY) bool {
+	for _, a := range anchors {
+		if k.Algorithm == a.Algorithm && k.Protocol == a.Protocol && k.Flags == a.Flags &&
+			strings.ReplaceAll(k.PublicKey, " ", "") == strings.ReplaceAll(a.PublicKey, " ", "") {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDS(k *dns.DNSKEY, dsSet []*dns.DS) bool {
+	for _, d := range dsSet {
+		ds := k.ToDS(d.DigestType)
+		if ds != nil && ds.KeyTag == d.KeyTag && ds.Algorithm == d.Algorithm && strings.EqualFold(ds.Digest, d.Digest) {
+			return true
+		}
+	}
+	return false
+}
+
+func rrsetKey(name string, rrType uint16) string {
+	return dns.CanonicalName(name) + "/" + dns.TypeToString[rrType]
+}
+
+// splitRRsets groups the records by the owner name and the type, and the RRSIG records by the type covered
+func splitRRsets(records []dns.RR) (map[string][]dns.RR, map[string][]*dns.RRSIG) {
+	rrsets := make(map[string][]dns.RR)
+	sigs := make(map[string][]*dns.RRSIG)
+	for _, rr := range records {
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			key := rrsetKey(sig.Header().Name, sig.TypeCovered)
+			sigs[key] = append(sigs[key], sig)
+			continue
+		}
+		if rr.Header().Rrtype == dns.TypeOPT {
+			continue
+		}
+		key := rrsetKey(rr.Header().Name, rr.Header().Rrtype)
+		rrsets[key] = append(rrsets[key], rr)
+	}
+	return rrsets, sigs
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDns_test
+
+import (
+	"crypto"
+	"net"
+	"time"
+
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDns"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+)
+
+// signedZone holds the records of the zones and their signatures
+type signedZone struct {
+	records map[string][]dns.RR
+}
+
+func rrKey(name string, rrType uint16) string {
+	return dns.CanonicalName(name) + "/" + dns.TypeToString[rrType]
+}
+
+func (z *signedZone) add(rrs ...dns.RR) {
+	for _, rr := range rrs {
+		key := rrKey(rr.Header().Name, rr.Header().Rrtype)
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			key = rrKey(sig.Header().Name, sig.TypeCovered)
+		}
+		z.records[key] = append(z.records[key], rr)
+	}
+}
+
+func newZoneKey(zone string) (*dns.DNSKEY, crypto.Signer) {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: zone, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     dns.ZONE | dns.SEP,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	Expect(err).NotTo(HaveOccurred())
+	return key, priv.(crypto.Signer)
+}
+
+func sign(key *dns.DNSKEY, priv crypto.Signer, rrset ...dns.RR) *dns.RRSIG {
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Ttl: 3600},
+		Algorithm:  key.Algorithm,
+		KeyTag:     key.KeyTag(),
+		SignerName: key.Hdr.Name,
+		Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+		Expiration: uint32(time.Now().Add(time.Hour).Unix()),
+	}
+	Expect(sig.Sign(priv, rrset)).To(Succeed())
+	return sig
+}
+
+// newSignedServer serves the zone kdoctor.io. and its child zone sub.kdoctor.io., and returns the DS of kdoctor.io. as the trust anchor.
+// The AD flag is set when ad is true
+func newSignedServer(ad bool) (*dns.Server, string, *dns.DS) {
+	zone := &signedZone{records: map[string][]dns.RR{}}
+
+	parentKey, parentPriv := newZoneKey("kdoctor.io.")
+	childKey, childPriv := newZoneKey("sub.kdoctor.io.")
+	ds := childKey.ToDS(dns.SHA256)
+	ds.Hdr = dns.RR_Header{Name: "sub.kdoctor.io.", Rrtype: dns.TypeDS, Class: dns.ClassINET, Ttl: 3600}
+
+	a := mustRR("kdoctor.io. 30 IN A 10.0.0.1")
+	childA := mustRR("www.sub.kdoctor.io. 30 IN A 10.0.0.2")
+	zone.add(parentKey, sign(parentKey, parentPriv, parentKey), a, sign(parentKey, parentPriv, a))
+	zone.add(ds, sign(parentKey, parentPriv, ds))
+	zone.add(childKey, sign(childKey, childPriv, childKey), childA, sign(childKey, childPriv, childA))
+	// the signature which does not match the record
+	bogus := mustRR("bogus.sub.kdoctor.io. 30 IN A 10.0.0.3")
+	bogusSig := sign(childKey, childPriv, mustRR("bogus.sub.kdoctor.io. 30 IN A 10.0.0.4"))
+	zone.add(bogus, bogusSig)
+	// the record of kdoctor.io. which is signed by the key of the child zone
+	other := mustRR("other.kdoctor.io. 30 IN A 10.0.0.5")
+	zone.add(other, sign(childKey, childPriv, other))
+	// the DNSKEY of the zone lame.kdoctor.io. could not be looked up
+	lameKey, lamePriv := newZoneKey("lame.kdoctor.io.")
+	lameA := mustRR("www.lame.kdoctor.io. 30 IN A 10.0.0.6")
+	zone.add(lameA, sign(lameKey, lamePriv, lameA))
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		m.AuthenticatedData = ad
+		m.Answer = zone.records[rrKey(r.Question[0].Name, r.Question[0].Qtype)]
+		if len(m.Answer) == 0 {
+			m.Rcode = dns.RcodeNameError
+		}
+		if rrKey(r.Question[0].Name, r.Question[0].Qtype) == rrKey("lame.kdoctor.io.", dns.TypeDNSKEY) {
+			m.Rcode = dns.RcodeServerFailure
+		}
+		m.SetEdns0(4096, true)
+		_ = w.WriteMsg(m)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+
+	anchor := parentKey.ToDS(dns.SHA256)
+	return server, pc.LocalAddr().String(), anchor
+}
+
+var _ = Describe("test dnssec ", Label("dns dnssec"), func() {
+	log := logger.NewStdoutLogger("debug", "test")
+
+	request := func(addr, domain string, option *loadDns.DnssecOption) *loadDns.DnsRequestData {
+		return &loadDns.DnsRequestData{
+			Protocol:              loadDns.RequestMethodUdp,
+			DnsType:               dns.TypeA,
+			TargetDomain:          domain,
+			DnsServerAddr:         addr,
+			PerRequestTimeoutInMs: 1000,
+			DurationInSecond:      1,
+			Qps:                   10,
+			Dnssec:                option,
+		}
+	}
+
+	It("check the AD flag", func() {
+		server, addr, _ := newSignedServer(false)
+		defer server.Shutdown()
+
+		result, e := loadDns.DnsRequest(log, request(addr, "kdoctor.io", &loadDns.DnssecOption{CheckAD: true}))
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.RequestCounts).To(BeNumerically(">=", 9))
+		Expect(result.DnssecFailedCounts).To(Equal(result.RequestCounts))
+		Expect(result.FailedCounts).To(Equal(result.RequestCounts))
+		Expect(result.ReplyCode).To(HaveKeyWithValue(dns.RcodeToString[dns.RcodeSuccess], int(result.RequestCounts)))
+
+		adServer, adAddr, _ := newSignedServer(true)
+		defer adServer.Shutdown()
+		result, e = loadDns.DnsRequest(log, request(adAddr, "kdoctor.io", &loadDns.DnssecOption{CheckAD: true}))
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.DnssecFailedCounts).To(BeZero())
+		Expect(result.FailedCounts).To(BeZero())
+	})
+
+	It("validate the RRSIG chain", func() {
+		server, addr, anchor := newSignedServer(false)
+		defer server.Shutdown()
+		option := &loadDns.DnssecOption{TrustAnchors: []dns.RR{anchor}}
+
+		result, e := loadDns.DnsRequest(log, request(addr, "www.sub.kdoctor.io", option))
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.RequestCounts).To(BeNumerically(">=", 9))
+		Expect(result.DnssecFailedCounts).To(BeZero())
+		Expect(result.FailedCounts).To(BeZero())
+
+		result, e = loadDns.DnsRequest(log, request(addr, "bogus.sub.kdoctor.io", option))
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.DnssecFailedCounts).To(Equal(result.RequestCounts))
+		Expect(result.ReplyCode).To(HaveKey(dns.RcodeToString[dns.RcodeSuccess]))
+
+		// the chain does not lead to the other anchor
+		otherKey, _ := newZoneKey("kdoctor.io.")
+		other := &loadDns.DnssecOption{TrustAnchors: []dns.RR{otherKey.ToDS(dns.SHA256)}}
+		result, e = loadDns.DnsRequest(log, request(addr, "www.sub.kdoctor.io", other))
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.DnssecFailedCounts).To(Equal(result.RequestCounts))
+	})
+
+	It("reject the signature from another zone", func() {
+		server, addr, anchor := newSignedServer(false)
+		defer server.Shutdown()
+		option := &loadDns.DnssecOption{TrustAnchors: []dns.RR{anchor}}
+
+		// the key of sub.kdoctor.io. is authenticated, but it could not sign the records of kdoctor.io.
+		result, e := loadDns.DnsRequest(log, request(addr, "other.kdoctor.io", option))
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.RequestCounts).To(BeNumerically(">=", 9))
+		Expect(result.DnssecFailedCounts).To(Equal(result.RequestCounts))
+		Expect(result.Errors).To(HaveKey(ContainSubstring("is not the zone of other.kdoctor.io.")))
+	})
+
+	It("count the failure to look up the keys as a failed request", func() {
+		server, addr, anchor := newSignedServer(false)
+		defer server.Shutdown()
+		option := &loadDns.DnssecOption{TrustAnchors: []dns.RR{anchor}}
+
+		result, e := loadDns.DnsRequest(log, request(addr, "www.lame.kdoctor.io", option))
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.RequestCounts).To(BeNumerically(">=", 9))
+		Expect(result.DnssecFailedCounts).To(BeZero())
+		Expect(result.FailedCounts).To(Equal(result.RequestCounts))
+		Expect(result.Errors).To(HaveKey(ContainSubstring("rcode SERVFAIL")))
+	})
+
+	It("parse the trust anchors", func() {
+		anchors, e := loadDns.ParseTrustAnchors([]string{". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(anchors).To(HaveLen(1))
+		_, e = loadDns.ParseTrustAnchors([]string{"kdoctor.io. IN A 10.0.0.1"})
+		Expect(e).To(HaveOccurred())
+	})
+})
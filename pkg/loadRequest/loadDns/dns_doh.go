@@ -0,0 +1,91 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDns
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// DefaultDohPath is the path of the uri template recommended by RFC 8484
+	DefaultDohPath = "/dns-query"
+	DohMediaType   = "application/dns-message"
+
+	DohMethodGet  = "GET"
+	DohMethodPost = "POST"
+)
+
+func (b *Work) initHttpsClient() {
+	b.httpClient = &http.Client{
+		Timeout: time.Duration(b.Timeout) * time.Millisecond,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true,
+			},
+			ForceAttemptHTTP2:   true,
+			MaxIdleConnsPerHost: b.QPS,
+		},
+	}
+}
+
+// exchangeHttps sends the dns message over https as RFC 8484
+func (b *Work) exchangeHttps(msg *dns.Msg) (r *dns.Msg, rtt time.Duration, err error) {
+	// the id should be 0 for the cache friendliness of http
+	m := msg.Copy()
+	m.Id = 0
+	buf, err := m.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to pack dns message, error=%v", err)
+	}
+
+	path := b.DohPath
+	if len(path) == 0 {
+		path = DefaultDohPath
+	}
+	url := "https://" + b.ServerAddr + path
+
+	var req *http.Request
+	if b.DohMethod == DohMethodGet {
+		req, err = http.NewRequest(http.MethodGet, url+"?dns="+base64.RawURLEncoding.EncodeToString(buf), nil)
+	} else {
+		req, err = http.NewRequest(http.MethodPost, url, bytes.NewReader(buf))
+		if req != nil {
+			req.Header.Set("Content-Type", DohMediaType)
+		}
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build https request, error=%v", err)
+	}
+	req.Header.Set("Accept", DohMediaType)
+
+	start := time.Now()
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, time.Since(start), err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	rtt = time.Since(start)
+	if err != nil {
+		return nil, rtt, fmt.Errorf("failed to read https response, error=%v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, rtt, fmt.Errorf("unexpected https status code %d", resp.StatusCode)
+	}
+
+	r = new(dns.Msg)
+	if err := r.Unpack(body); err != nil {
+		return nil, rtt, fmt.Errorf("failed to unpack dns message, error=%v", err)
+	}
+	r.Id = msg.Id
+	return r, rtt, nil
+}
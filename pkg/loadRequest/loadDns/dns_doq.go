@@ -0,0 +1,101 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDns
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+)
+
+// DoqALPN is the application-layer protocol negotiation token of RFC 9250
+const DoqALPN = "doq"
+
+// quicConnection returns the shared quic connection, and dials a new one when it is closed
+func (b *Work) quicConnection() (quic.Connection, error) {
+	b.quicLock.Lock()
+	defer b.quicLock.Unlock()
+
+	if b.quicConn != nil && b.quicConn.Context().Err() == nil {
+		return b.quicConn, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(b.Timeout)*time.Millisecond)
+	defer cancel()
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{DoqALPN},
+	}
+	conn, err := quic.DialAddr(ctx, b.ServerAddr, tlsConfig, &quic.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial quic %v, error=%v", b.ServerAddr, err)
+	}
+	b.quicConn = conn
+	return conn, nil
+}
+
+func (b *Work) closeQuicConnection() {
+	b.quicLock.Lock()
+	defer b.quicLock.Unlock()
+	if b.quicConn != nil {
+		_ = b.quicConn.CloseWithError(0, "")
+		b.quicConn = nil
+	}
+}
+
+// exchangeQuic sends the dns message over quic as RFC 9250, and each query takes a new stream of the shared connection
+func (b *Work) exchangeQuic(msg *dns.Msg) (r *dns.Msg, rtt time.Duration, err error) {
+	// the id must be 0 for DoQ
+	m := msg.Copy()
+	m.Id = 0
+	buf, err := m.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to pack dns message, error=%v", err)
+	}
+
+	start := time.Now()
+	conn, err := b.quicConnection()
+	if err != nil {
+		return nil, time.Since(start), err
+	}
+
+	timeout := time.Duration(b.Timeout) * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		return nil, time.Since(start), fmt.Errorf("failed to open quic stream, error=%v", err)
+	}
+	_ = stream.SetDeadline(start.Add(timeout))
+
+	// the message is prefixed with a 2-octet length field, and the client closes the sending side after the query
+	if _, err := stream.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(buf))), buf...)); err != nil {
+		stream.CancelRead(0)
+		return nil, time.Since(start), fmt.Errorf("failed to write quic stream, error=%v", err)
+	}
+	_ = stream.Close()
+
+	length := make([]byte, 2)
+	if _, err := io.ReadFull(stream, length); err != nil {
+		return nil, time.Since(start), fmt.Errorf("failed to read quic stream, error=%v", err)
+	}
+	body := make([]byte, binary.BigEndian.Uint16(length))
+	if _, err := io.ReadFull(stream, body); err != nil {
+		return nil, time.Since(start), fmt.Errorf("failed to read quic stream, error=%v", err)
+	}
+	rtt = time.Since(start)
+
+	r = new(dns.Msg)
+	if err := r.Unpack(body); err != nil {
+		return nil, rtt, fmt.Errorf("failed to unpack dns message, error=%v", err)
+	}
+	r.Id = msg.Id
+	return r, rtt, nil
+}
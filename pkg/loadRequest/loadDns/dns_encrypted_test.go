@@ -0,0 +1,134 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDns_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/quic-go/quic-go"
+
+	"github.com/kdoctor-io/kdoctor/pkg/agentDnsServer"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDns"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+)
+
+// newSelfSignedTlsConfig generates the certificate of 127.0.0.1
+func newSelfSignedTlsConfig() *tls.Config {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kdoctor"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	Expect(err).NotTo(HaveOccurred())
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+}
+
+var answerHandler = dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Answer = []dns.RR{mustRR(r.Question[0].Name + " 30 IN A 10.0.0.1")}
+	_ = w.WriteMsg(m)
+})
+
+var _ = Describe("test dns over https and quic ", Label("dns encrypted"), func() {
+	log := logger.NewStdoutLogger("debug", "test")
+
+	It("request over https", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		mux := http.NewServeMux()
+		mux.Handle(loadDns.DefaultDohPath, agentDnsServer.NewDohHandler(answerHandler))
+		mux.Handle("/resolve", agentDnsServer.NewDohHandler(answerHandler))
+		server := &http.Server{Handler: mux, TLSConfig: newSelfSignedTlsConfig(), ReadHeaderTimeout: time.Second}
+		go func() {
+			_ = server.ServeTLS(listener, "", "")
+		}()
+		defer server.Close()
+
+		for _, item := range []struct {
+			method string
+			path   string
+		}{
+			{method: loadDns.DohMethodGet},
+			{method: loadDns.DohMethodPost},
+			{method: loadDns.DohMethodPost, path: "/resolve"},
+		} {
+			req := &loadDns.DnsRequestData{
+				Protocol:              loadDns.RequestMethodHttps,
+				DohMethod:             item.method,
+				DohPath:               item.path,
+				DnsType:               dns.TypeA,
+				TargetDomain:          "kdoctor.io",
+				DnsServerAddr:         listener.Addr().String(),
+				PerRequestTimeoutInMs: 1000,
+				DurationInSecond:      1,
+				Qps:                   10,
+			}
+			result, e := loadDns.DnsRequest(log, req)
+			Expect(e).NotTo(HaveOccurred())
+			Expect(result.RequestCounts).To(BeNumerically(">=", 9))
+			Expect(result.FailedCounts).To(BeZero(), "method %v, path %v", item.method, item.path)
+			Expect(result.ReplyCode).To(HaveKey(dns.RcodeToString[dns.RcodeSuccess]))
+		}
+
+		// the path which is not served
+		result, e := loadDns.DnsRequest(log, &loadDns.DnsRequestData{
+			Protocol:              loadDns.RequestMethodHttps,
+			DohPath:               "/missing",
+			DnsType:               dns.TypeA,
+			TargetDomain:          "kdoctor.io",
+			DnsServerAddr:         listener.Addr().String(),
+			PerRequestTimeoutInMs: 1000,
+			DurationInSecond:      1,
+			Qps:                   10,
+		})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.SuccessCounts).To(BeZero())
+	})
+
+	It("request over quic", func() {
+		tlsConfig := newSelfSignedTlsConfig()
+		tlsConfig.NextProtos = []string{loadDns.DoqALPN}
+		listener, err := quic.ListenAddr("127.0.0.1:0", tlsConfig, &quic.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			_ = agentDnsServer.ServeDoq(log, listener, answerHandler)
+		}()
+		defer listener.Close()
+
+		req := &loadDns.DnsRequestData{
+			Protocol:              loadDns.RequestMethodQuic,
+			DnsType:               dns.TypeA,
+			TargetDomain:          "kdoctor.io",
+			DnsServerAddr:         listener.Addr().String(),
+			PerRequestTimeoutInMs: 1000,
+			DurationInSecond:      1,
+			Qps:                   10,
+		}
+		result, e := loadDns.DnsRequest(log, req)
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result.RequestCounts).To(BeNumerically(">=", 9))
+		Expect(result.FailedCounts).To(BeZero())
+		Expect(result.ReplyCode).To(HaveKey(dns.RcodeToString[dns.RcodeSuccess]))
+	})
+})
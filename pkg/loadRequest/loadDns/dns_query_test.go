@@ -0,0 +1,132 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadDns_test
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+
+	"github.com/miekg/dns"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDns"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+)
+
+// newRecordServer starts a local dns server which answers the records of the zone
+func newRecordServer(zone map[uint16][]dns.RR) (*dns.Server, string) {
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		for _, rr := range zone[r.Question[0].Qtype] {
+			if rr.Header().Name == r.Question[0].Name {
+				m.Answer = append(m.Answer, rr)
+			}
+		}
+		_ = w.WriteMsg(m)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	return server, pc.LocalAddr().String()
+}
+
+func mustRR(s string) dns.RR {
+	rr, err := dns.NewRR(s)
+	Expect(err).NotTo(HaveOccurred())
+	return rr
+}
+
+var _ = Describe("test dns queries ", Label("dns queries"), func() {
+	log := logger.NewStdoutLogger("debug", "test")
+
+	It("compare the answers", func() {
+		reply := new(dns.Msg)
+		reply.Answer = []dns.RR{
+			mustRR("_dns._udp.kube-dns.kube-system.svc.cluster.local. 30 IN SRV 0 50 53 10-0-0-2.kube-dns.kube-system.svc.cluster.local."),
+			mustRR("_dns._udp.kube-dns.kube-system.svc.cluster.local. 30 IN SRV 0 50 53 10-0-0-1.kube-dns.kube-system.svc.cluster.local."),
+		}
+		Expect(loadDns.CheckAnswers(reply, dns.TypeSRV, []string{
+			"0 50 53 10-0-0-1.kube-dns.kube-system.svc.cluster.local",
+			"0 50 53 10-0-0-2.KUBE-DNS.kube-system.svc.cluster.local.",
+		})).To(BeEmpty())
+		Expect(loadDns.CheckAnswers(reply, dns.TypeSRV, []string{
+			"0 50 53 10-0-0-1.kube-dns.kube-system.svc.cluster.local",
+		})).To(ContainSubstring("unexpected SRV answer"))
+
+		txt := new(dns.Msg)
+		txt.Answer = []dns.RR{mustRR(`kdoctor.io. 30 IN TXT "v=spf1 " "-all"`)}
+		Expect(loadDns.CheckAnswers(txt, dns.TypeTXT, []string{"v=spf1 -all"})).To(BeEmpty())
+		Expect(loadDns.CheckAnswers(nil, dns.TypeTXT, []string{"v=spf1 -all"})).NotTo(BeEmpty())
+	})
+
+	It("rotate through the queries", func() {
+		server, addr := newRecordServer(map[uint16][]dns.RR{
+			dns.TypeA:     {mustRR("kdoctor.io. 30 IN A 10.0.0.1")},
+			dns.TypeMX:    {mustRR("kdoctor.io. 30 IN MX 10 mail.kdoctor.io.")},
+			dns.TypeCNAME: {mustRR("www.kdoctor.io. 30 IN CNAME kdoctor.io.")},
+		})
+		defer server.Shutdown()
+
+		req := &loadDns.DnsRequestData{
+			Protocol:              loadDns.RequestMethodUdp,
+			DnsServerAddr:         addr,
+			PerRequestTimeoutInMs: 1000,
+			DurationInSecond:      2,
+			Qps:                   30,
+			Queries: []loadDns.DnsQuery{
+				{TargetDomain: "kdoctor.io", DnsType: dns.TypeA, ExpectedAnswers: []string{"10.0.0.1"}},
+				{TargetDomain: "kdoctor.io", DnsType: dns.TypeMX, ExpectedAnswers: []string{"10 mail.kdoctor.io"}},
+				{TargetDomain: "www.kdoctor.io", DnsType: dns.TypeCNAME, ExpectedAnswers: []string{"kdoctor.io.cn"}},
+			},
+		}
+		result, e := loadDns.DnsRequestByQuery(log, req)
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result).To(HaveLen(3))
+
+		for _, m := range result {
+			Expect(m.RequestCounts).To(BeNumerically(">=", 19))
+		}
+		Expect(result[0].TargetDomain).To(Equal("kdoctor.io."))
+		Expect(result[0].FailedCounts).To(BeZero())
+		Expect(result[1].FailedCounts).To(BeZero())
+		Expect(result[2].SuccessCounts).To(BeZero())
+		Expect(result[2].UnexpectedAnswerCounts).To(Equal(result[2].RequestCounts))
+		Expect(result[2].ReplyCode).To(HaveKey(dns.RcodeToString[dns.RcodeSuccess]))
+	})
+	It("report the query which sends no request", func() {
+		server, addr := newRecordServer(map[uint16][]dns.RR{
+			dns.TypeA: {mustRR("kdoctor.io. 30 IN A 10.0.0.1")},
+		})
+		defer server.Shutdown()
+
+		// only one request is sent in the duration, so the last query sends nothing
+		req := &loadDns.DnsRequestData{
+			Protocol:              loadDns.RequestMethodUdp,
+			DnsServerAddr:         addr,
+			PerRequestTimeoutInMs: 1000,
+			DurationInSecond:      1,
+			Qps:                   1,
+			Queries: []loadDns.DnsQuery{
+				{TargetDomain: "kdoctor.io", DnsType: dns.TypeA},
+				{TargetDomain: "www.kdoctor.io", DnsType: dns.TypeA},
+			},
+		}
+		result, e := loadDns.DnsRequestByQuery(log, req)
+		Expect(e).NotTo(HaveOccurred())
+		Expect(result).To(HaveLen(2))
+		Expect(result[1].RequestCounts).To(BeZero())
+		Expect(math.IsNaN(float64(result[1].Latencies.Mean))).To(BeFalse())
+		_, e = json.Marshal(result)
+		Expect(e).NotTo(HaveOccurred())
+	})
+})
@@ -26,6 +26,7 @@
 package loadDns
 
 import (
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	"github.com/miekg/dns"
 	"time"
 )
@@ -48,11 +49,21 @@ type report struct {
 	failedCount    int64
 	ReplyCode      map[string]int
 
+	// the replies whose answers do not match the expected answers
+	unexpectedAnswerCount int64
+	// the replies which fail the dnssec check
+	dnssecFailedCount int64
+
+	// the report of each query when the work rotates through multiple queries
+	queries []*report
+
 	existsNotSendRequests bool
+
+	metricTarget *metrics.TaskTarget
 }
 
-func newReport(results chan *result, enableLatencyMetric bool) *report {
-	return &report{
+func newReport(results chan *result, enableLatencyMetric bool, queryNumber int) *report {
+	r := &report{
 		results:             results,
 		done:                make(chan bool, 1),
 		errorDist:           make(map[string]int),
@@ -60,33 +71,63 @@ func newReport(results chan *result, enableLatencyMetric bool) *report {
 		ReplyCode:           make(map[string]int),
 		enableLatencyMetric: enableLatencyMetric,
 	}
+	for i := 0; i < queryNumber; i++ {
+		r.queries = append(r.queries, newReport(nil, enableLatencyMetric, 0))
+	}
+	return r
 }
 
 func runReporter(r *report) {
 	// Loop will continue until channel is closed
 	for res := range r.results {
-		r.totalCount++
-		if res.err != nil {
-			r.errorDist[res.err.Error()]++
+		r.add(res)
+		if res.err == nil {
+			r.metricTarget.ObserveLatency(res.duration)
+		}
+		if res.queryIndex < len(r.queries) {
+			r.queries[res.queryIndex].add(res)
+		}
+	}
+	// Signal reporter is done.
+	r.done <- true
+}
+
+func (r *report) add(res *result) {
+	r.totalCount++
+	if res.err != nil {
+		r.errorDist[res.err.Error()]++
+		r.failedCount++
+	} else {
+		r.avgTotal += res.duration.Seconds()
+		if r.enableLatencyMetric {
+			r.lats = append(r.lats, float32(res.duration.Milliseconds()))
+		} else {
+			r.totalLatencies += float32(res.duration.Milliseconds())
+		}
+		rcodeStr := dns.RcodeToString[res.msg.Rcode]
+		r.ReplyCode[rcodeStr]++
+		// the reply which fails the dnssec check or has unexpected answers is taken as a failure
+		if len(res.dnssecFailure) > 0 {
+			r.errorDist[res.dnssecFailure]++
+			r.dnssecFailedCount++
+			r.failedCount++
+		} else if len(res.unexpectedAnswer) > 0 {
+			r.errorDist[res.unexpectedAnswer]++
+			r.unexpectedAnswerCount++
 			r.failedCount++
 		} else {
-			r.avgTotal += res.duration.Seconds()
-			if r.enableLatencyMetric {
-				r.lats = append(r.lats, float32(res.duration.Milliseconds()))
-			} else {
-				r.totalLatencies += float32(res.duration.Milliseconds())
-			}
-			rcodeStr := dns.RcodeToString[res.msg.Rcode]
-			r.ReplyCode[rcodeStr]++
 			r.successCount++
 		}
 	}
-	// Signal reporter is done.
-	r.done <- true
 }
 
 func (r *report) finalize(total time.Duration) {
 	r.total = total
 	r.tps = float64(r.totalCount) / r.total.Seconds()
-	r.average = r.avgTotal / float64(len(r.lats))
+	if len(r.lats) > 0 {
+		r.average = r.avgTotal / float64(len(r.lats))
+	}
+	for _, q := range r.queries {
+		q.finalize(total)
+	}
 }
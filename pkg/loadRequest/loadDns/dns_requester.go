@@ -28,14 +28,18 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	"github.com/kdoctor-io/kdoctor/pkg/utils/stats"
 
 	"github.com/ii2day/connexus"
 	"github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -47,6 +51,12 @@ type result struct {
 	err      error
 	duration time.Duration
 	msg      *dns.Msg
+	// the index of the query in Work.Msgs
+	queryIndex int
+	// not empty when the answers of the reply do not match the expected answers
+	unexpectedAnswer string
+	// not empty when the reply fails the dnssec check
+	dnssecFailure string
 }
 
 type Work struct {
@@ -54,8 +64,17 @@ type Work struct {
 
 	Msg *dns.Msg
 
+	// the requests rotate through Msgs when it is not empty, and Msg is ignored
+	Msgs []*dns.Msg
+	// the expected rdata of the answers of each message in Msgs, nothing is checked when it is empty
+	ExpectedAnswers [][]string
+
 	Protocol string
 
+	// the method and the path of the https request, only for the https protocol
+	DohMethod string
+	DohPath   string
+
 	// Timeout in seconds.
 	Timeout int
 
@@ -67,6 +86,12 @@ type Work struct {
 
 	EnableLatencyMetric bool
 
+	// the latency of each succeeded request is recorded to the metric of the target when it is not nil
+	MetricTarget *metrics.TaskTarget
+
+	// the DO bit is set in the requests and the replies are checked when it is not nil
+	Dnssec *DnssecOption
+
 	Logger *zap.Logger
 
 	initOnce       sync.Once
@@ -77,6 +102,11 @@ type Work struct {
 	qosTokenBucket chan struct{}
 	startTime      metav1.Time
 	report         *report
+	queryCounter   uint64
+	httpClient     *http.Client
+	quicLock       sync.Mutex
+	quicConn       quic.Connection
+	validator      *dnssecValidator
 }
 
 // Init initializes internal data-structures
@@ -97,6 +127,14 @@ func (b *Work) Init() {
 		}
 		b.client = client
 
+		if RequestProtocol(b.Protocol) == RequestMethodHttps {
+			b.initHttpsClient()
+		}
+
+		if b.Dnssec != nil {
+			b.validator = newDnssecValidator(b.Dnssec, b.exchangeWithoutTruncation)
+		}
+
 		if RequestProtocol(b.Protocol) == RequestMethodUdp {
 			err := b.InitPool()
 			if err != nil {
@@ -111,7 +149,8 @@ func (b *Work) Init() {
 func (b *Work) Run() {
 	b.Init()
 	b.startTime = metav1.Now()
-	b.report = newReport(b.results, b.EnableLatencyMetric)
+	b.report = newReport(b.results, b.EnableLatencyMetric, len(b.Msgs))
+	b.report.metricTarget = b.MetricTarget
 	// Run the reporter first, it polls the result channel until it is closed.
 	go func() {
 		runReporter(b.report)
@@ -174,8 +213,14 @@ func (b *Work) makeRequest(wg *sync.WaitGroup) {
 	var rtt time.Duration
 	var err error
 
+	queryIndex := 0
 	msg := new(dns.Msg)
-	*msg = *b.Msg
+	if len(b.Msgs) > 0 {
+		queryIndex = int((atomic.AddUint64(&b.queryCounter, 1) - 1) % uint64(len(b.Msgs)))
+		*msg = *b.Msgs[queryIndex]
+	} else {
+		*msg = *b.Msg
+	}
 	msg.Id = dns.Id()
 
 	if RequestProtocol(b.Protocol) == RequestMethodUdp {
@@ -190,19 +235,52 @@ func (b *Work) makeRequest(wg *sync.WaitGroup) {
 		}
 		r, rtt, err = b.client.ExchangeWithConn(msg, conn.(*connexus.Connex).Conn.(*dns.Conn))
 	} else {
-		r, rtt, err = b.client.Exchange(msg, b.ServerAddr)
+		r, rtt, err = b.exchange(msg)
 	}
 
 	if rtt > time.Duration(b.Timeout)*time.Millisecond {
 		err = fmt.Errorf("request duration is %d,more than timeout %d", rtt.Milliseconds(), b.Timeout)
 	}
+	var unexpectedAnswer, dnssecFailure string
+	if err == nil && b.validator != nil {
+		// the failure to look up the keys is not a failure of the reply, and it is counted like a failed request
+		dnssecFailure, err = b.validator.check(r)
+	}
+	if err == nil && queryIndex < len(b.ExpectedAnswers) && len(b.ExpectedAnswers[queryIndex]) > 0 {
+		unexpectedAnswer = CheckAnswers(r, msg.Question[0].Qtype, b.ExpectedAnswers[queryIndex])
+	}
 	b.results <- &result{
-		duration: rtt,
-		err:      err,
-		msg:      r,
+		duration:         rtt,
+		err:              err,
+		msg:              r,
+		queryIndex:       queryIndex,
+		unexpectedAnswer: unexpectedAnswer,
+		dnssecFailure:    dnssecFailure,
+	}
+}
+
+// exchange sends the message without the connection pool
+func (b *Work) exchange(msg *dns.Msg) (*dns.Msg, time.Duration, error) {
+	switch RequestProtocol(b.Protocol) {
+	case RequestMethodHttps:
+		return b.exchangeHttps(msg)
+	case RequestMethodQuic:
+		return b.exchangeQuic(msg)
+	default:
+		return b.client.Exchange(msg, b.ServerAddr)
 	}
 }
 
+// exchangeWithoutTruncation retries the truncated udp reply over tcp, for the large DNSKEY and DS replies of the dnssec validation
+func (b *Work) exchangeWithoutTruncation(msg *dns.Msg) (*dns.Msg, error) {
+	r, _, err := b.exchange(msg)
+	if err == nil && r.Truncated {
+		client := &dns.Client{Net: string(RequestMethodTcp), Timeout: b.client.Timeout}
+		r, _, err = client.Exchange(msg, b.ServerAddr)
+	}
+	return r, err
+}
+
 func (b *Work) runWorker() {
 	wg := &sync.WaitGroup{}
 	for {
@@ -210,8 +288,13 @@ func (b *Work) runWorker() {
 		select {
 		case <-b.stopCh:
 			wg.Wait()
-			if RequestProtocol(b.Protocol) == RequestMethodUdp {
+			switch RequestProtocol(b.Protocol) {
+			case RequestMethodUdp:
 				b.pool.Close()
+			case RequestMethodHttps:
+				b.httpClient.CloseIdleConnections()
+			case RequestMethodQuic:
+				b.closeQuicConnection()
 			}
 			return
 		case <-b.qosTokenBucket:
@@ -223,52 +306,73 @@ func (b *Work) runWorker() {
 }
 
 func (b *Work) AggregateMetric() *v1beta1.DNSMetrics {
+	msg := b.Msg
+	if len(b.Msgs) > 0 {
+		msg = b.Msgs[0]
+	}
+	return b.metricOf(b.report, msg)
+}
+
+// AggregateQueryMetrics returns the metric of each query in Msgs
+func (b *Work) AggregateQueryMetrics() []*v1beta1.DNSMetrics {
+	metrics := make([]*v1beta1.DNSMetrics, 0, len(b.Msgs))
+	for i, msg := range b.Msgs {
+		metrics = append(metrics, b.metricOf(b.report.queries[i], msg))
+	}
+	return metrics
+}
+
+func (b *Work) metricOf(r *report, msg *dns.Msg) *v1beta1.DNSMetrics {
 	latency := v1beta1.LatencyDistribution{}
 
 	if b.EnableLatencyMetric {
-		t, _ := stats.Mean(b.report.lats)
+		t, _ := stats.Mean(r.lats)
 		latency.Mean = t
 
-		t, _ = stats.Max(b.report.lats)
+		t, _ = stats.Max(r.lats)
 		latency.Max = t
 
-		t, _ = stats.Min(b.report.lats)
+		t, _ = stats.Min(r.lats)
 		latency.Min = t
 
-		t, _ = stats.Percentile(b.report.lats, 50)
+		t, _ = stats.Percentile(r.lats, 50)
 		latency.P50 = t
 
-		t, _ = stats.Percentile(b.report.lats, 90)
+		t, _ = stats.Percentile(r.lats, 90)
 		latency.P90 = t
 
-		t, _ = stats.Percentile(b.report.lats, 95)
+		t, _ = stats.Percentile(r.lats, 95)
 		latency.P95 = t
 
-		t, _ = stats.Percentile(b.report.lats, 99)
+		t, _ = stats.Percentile(r.lats, 99)
 		latency.P99 = t
-	} else {
-		latency.Mean = b.report.totalLatencies / float32(b.report.totalCount)
+	} else if r.totalCount > 0 {
+		latency.Mean = r.totalLatencies / float32(r.totalCount)
 	}
 
 	metric := &v1beta1.DNSMetrics{
-		StartTime:             b.startTime,
-		EndTime:               metav1.NewTime(b.startTime.Add(b.report.total)),
-		Duration:              b.report.total.String(),
-		RequestCounts:         b.report.totalCount,
-		SuccessCounts:         b.report.successCount,
-		TPS:                   b.report.tps,
-		Errors:                b.report.errorDist,
-		Latencies:             latency,
-		TargetDomain:          b.Msg.Question[0].Name,
-		DNSServer:             b.ServerAddr,
-		DNSMethod:             b.Protocol,
-		FailedCounts:          b.report.failedCount,
-		ReplyCode:             b.report.ReplyCode,
+		StartTime:              b.startTime,
+		EndTime:                metav1.NewTime(b.startTime.Add(r.total)),
+		Duration:               r.total.String(),
+		RequestCounts:          r.totalCount,
+		SuccessCounts:          r.successCount,
+		TPS:                    r.tps,
+		Errors:                 r.errorDist,
+		Latencies:              latency,
+		TargetDomain:           msg.Question[0].Name,
+		DNSServer:              b.ServerAddr,
+		DNSMethod:              b.Protocol,
+		FailedCounts:           r.failedCount,
+		ReplyCode:              r.ReplyCode,
+		UnexpectedAnswerCounts: r.unexpectedAnswerCount,
+		DnssecFailedCounts:     r.dnssecFailedCount,
+		// the requests are not sent for the whole work, so it is shared by all queries
 		ExistsNotSendRequests: b.report.existsNotSendRequests,
 	}
 
 	return metric
 }
+
 func (b *Work) InitPool() error {
 	var err error
 	cfg := connexus.PoolConfig{
@@ -31,6 +31,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 )
 
 type HttpMethod string
@@ -61,6 +62,8 @@ type HttpRequestData struct {
 	DisableCompression  bool
 	ExpectStatusCode    *int
 	EnableLatencyMetric bool
+	// the latency of each succeeded request is recorded to the metric of the target when it is not nil
+	MetricTarget *metrics.TaskTarget
 }
 
 func HttpRequest(logger *zap.Logger, reqData *HttpRequestData) *v1beta1.HttpMetrics {
@@ -85,6 +88,7 @@ func HttpRequest(logger *zap.Logger, reqData *HttpRequestData) *v1beta1.HttpMetr
 		ExpectStatusCode:    reqData.ExpectStatusCode,
 		RequestBody:         reqData.Body,
 		EnableLatencyMetric: reqData.EnableLatencyMetric,
+		MetricTarget:        reqData.MetricTarget,
 		Logger:              logger.Named("http-client"),
 	}
 	logger.Sugar().Infof("do http requests work=%v", w)
@@ -0,0 +1,85 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadHttp
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/utils/pointer"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDetect"
+)
+
+type HttpDetectData struct {
+	loadDetect.Steps
+	// the ramp stops when the success rate of a step is lower than it
+	SuccessRate *float64
+	// the ramp stops when the p99 latency of a step is bigger than it
+	MaxP99InMs *int64
+}
+
+// HttpDetect ramps the qps of the request step by step with a fixed-rate run of each step,
+// until a step breaks the threshold or the maximum qps is reached.
+// The knee point is the qps of the last step which does not break the threshold
+func HttpDetect(logger *zap.Logger, reqData *HttpRequestData, detectData *HttpDetectData) *v1beta1.HttpDetectResult {
+	result := &v1beta1.HttpDetectResult{
+		Steps: make([]v1beta1.HttpDetectStep, 0, detectData.StepNumber()),
+	}
+
+	for i, qps := range detectData.StepQpsList() {
+		d := *reqData
+		d.Qps = qps
+		d.RequestTimeSecond = detectData.StepDurationSecond
+		// the p99 latency is required for the latency curve
+		d.EnableLatencyMetric = true
+
+		logger.Sugar().Infof("detect step %d with qps %d for %ds", i+1, qps, detectData.StepDurationSecond)
+		metrics := HttpRequest(logger.With(zap.Int("qps", qps)), &d)
+
+		step := v1beta1.HttpDetectStep{
+			QPS:           qps,
+			TPS:           metrics.TPS,
+			RequestCounts: metrics.RequestCounts,
+			SuccessCounts: metrics.SuccessCounts,
+			Latencies:     metrics.Latencies,
+		}
+		if metrics.RequestCounts > 0 {
+			step.SucceedRate = float64(metrics.SuccessCounts) / float64(metrics.RequestCounts)
+		}
+
+		failureReason := parseDetectStep(detectData, &step, metrics)
+		if len(failureReason) > 0 {
+			step.FailureReason = pointer.String(failureReason)
+			result.Steps = append(result.Steps, step)
+			result.BreakReason = pointer.String(fmt.Sprintf("qps %d: %s", qps, failureReason))
+			logger.Sugar().Infof("detect step with qps %d breaks the threshold, %v", qps, failureReason)
+			break
+		}
+
+		step.Succeed = true
+		result.Steps = append(result.Steps, step)
+		result.KneeQPS = qps
+	}
+
+	logger.Sugar().Infof("finish detecting, the knee qps is %d", result.KneeQPS)
+	return result
+}
+
+func parseDetectStep(detectData *HttpDetectData, step *v1beta1.HttpDetectStep, metrics *v1beta1.HttpMetrics) (failureReason string) {
+	switch {
+	case metrics.RequestCounts == 0:
+		failureReason = "no request has been sent"
+	case detectData.SuccessRate != nil && step.SucceedRate < *(detectData.SuccessRate):
+		failureReason = fmt.Sprintf("Success Rate %v is lower than request %v", step.SucceedRate, *(detectData.SuccessRate))
+	case detectData.MaxP99InMs != nil && step.Latencies.P99 > float32(*(detectData.MaxP99InMs)):
+		failureReason = fmt.Sprintf("p99 delay %v ms is bigger than request %v ms", step.Latencies.P99, *(detectData.MaxP99InMs))
+	case metrics.ExistsNotSendRequests:
+		failureReason = "There are unsent requests after the execution time has been reached"
+	default:
+		failureReason = ""
+	}
+	return
+}
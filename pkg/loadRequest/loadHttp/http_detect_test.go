@@ -0,0 +1,105 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadHttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDetect"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadHttp"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+)
+
+// newLimitedServer returns a server which responds 503 to the requests beyond the limit in each second
+func newLimitedServer(limit int) *httptest.Server {
+	var l sync.Mutex
+	var second int64
+	var counts int
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.Lock()
+		now := time.Now().Unix()
+		if now != second {
+			second = now
+			counts = 0
+		}
+		counts++
+		over := counts > limit
+		l.Unlock()
+
+		if over {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+var _ = Describe("test http detect ", Label("http detect"), func() {
+	log := logger.NewStdoutLogger("debug", "test")
+
+	It("stop at the knee point", func() {
+		server := newLimitedServer(25)
+		defer server.Close()
+
+		successRate := float64(1)
+		expectStatusCode := http.StatusOK
+		req := &loadHttp.HttpRequestData{
+			Method:              loadHttp.HttpMethodGet,
+			Url:                 server.URL,
+			PerRequestTimeoutMS: 1000,
+			ExpectStatusCode:    &expectStatusCode,
+		}
+		result := loadHttp.HttpDetect(log, req, &loadHttp.HttpDetectData{
+			Steps: loadDetect.Steps{
+				StartQps:           10,
+				StepQps:            10,
+				MaxQps:             50,
+				StepDurationSecond: 2,
+			},
+			SuccessRate: &successRate,
+		})
+
+		Expect(result.KneeQPS).To(Equal(20))
+		Expect(result.BreakReason).NotTo(BeNil())
+		Expect(result.Steps).To(HaveLen(3))
+		Expect(result.Steps[0].Succeed).To(BeTrue())
+		Expect(result.Steps[1].Succeed).To(BeTrue())
+		Expect(result.Steps[2].Succeed).To(BeFalse())
+		Expect(result.Steps[2].QPS).To(Equal(30))
+		Expect(result.Steps[2].SucceedRate).To(BeNumerically("<", 1))
+		Expect(result.Steps[1].RequestCounts).To(BeNumerically(">", 0))
+	})
+
+	It("reach the maximum qps", func() {
+		server := newLimitedServer(1000)
+		defer server.Close()
+
+		maxP99 := int64(1000)
+		req := &loadHttp.HttpRequestData{
+			Method:              loadHttp.HttpMethodGet,
+			Url:                 server.URL,
+			PerRequestTimeoutMS: 1000,
+		}
+		result := loadHttp.HttpDetect(log, req, &loadHttp.HttpDetectData{
+			Steps: loadDetect.Steps{
+				StartQps:           5,
+				StepQps:            10,
+				MaxQps:             20,
+				StepDurationSecond: 1,
+			},
+			MaxP99InMs: &maxP99,
+		})
+
+		Expect(result.KneeQPS).To(Equal(20))
+		Expect(result.BreakReason).To(BeNil())
+		Expect(result.Steps).To(HaveLen(3))
+		Expect(result.Steps[2].QPS).To(Equal(20))
+	})
+})
@@ -27,6 +27,8 @@ package loadHttp
 
 import (
 	"time"
+
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 )
 
 type report struct {
@@ -46,6 +48,8 @@ type report struct {
 	totalCount     int64
 
 	existsNotSendRequests bool
+
+	metricTarget *metrics.TaskTarget
 }
 
 func newReport(results chan *result, enableLatencyMetric bool) *report {
@@ -67,6 +71,7 @@ func runReporter(r *report) {
 		if res.err != nil {
 			r.errorDist[res.err.Error()]++
 		} else {
+			r.metricTarget.ObserveLatency(res.duration)
 			if r.enableLatencyMetric {
 				r.latencies = append(r.latencies, float32(res.duration.Milliseconds()))
 			} else {
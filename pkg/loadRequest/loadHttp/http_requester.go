@@ -38,6 +38,7 @@ import (
 	"time"
 
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	"github.com/kdoctor-io/kdoctor/pkg/utils/stats"
 	"go.uber.org/zap"
 
@@ -139,6 +140,10 @@ type Work struct {
 	// Optional.
 	EnableLatencyMetric bool
 
+	// MetricTarget records the latency of each succeeded request to the metric.
+	// Optional.
+	MetricTarget *metrics.TaskTarget
+
 	Logger *zap.Logger
 
 	initOnce       sync.Once
@@ -166,6 +171,7 @@ func (b *Work) Run() {
 	b.startTime = metav1.Now()
 	b.start = time.Since(b.startTime.Time)
 	b.report = newReport(b.results, b.EnableLatencyMetric)
+	b.report.metricTarget = b.MetricTarget
 	// Run the reporter first, it polls the result channel until it is closed.
 	go func() {
 		runReporter(b.report)
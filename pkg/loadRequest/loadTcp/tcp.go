@@ -0,0 +1,59 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadTcp
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+)
+
+type TcpRequestData struct {
+	// specified to be format "2.2.2.2:5720" or "[fd00::2]:5720"
+	ServerAddr            string
+	PerRequestTimeoutInMs int
+	Qps                   int
+	DurationInSecond      int
+	// the data size sent through each connection, 0 for only connecting
+	PayloadSizeInByte   int
+	EnableLatencyMetric bool
+}
+
+func TcpRequest(logger *zap.Logger, reqData *TcpRequestData) (result *v1beta1.TcpMetrics, err error) {
+	logger.Sugar().Infof("tcp request=%v", reqData)
+
+	if _, _, e := net.SplitHostPort(reqData.ServerAddr); e != nil {
+		return nil, fmt.Errorf("invalid server address %v: %v", reqData.ServerAddr, e)
+	}
+	if reqData.Qps <= 0 || reqData.DurationInSecond <= 0 {
+		return nil, fmt.Errorf("invalid qps %v or duration %v", reqData.Qps, reqData.DurationInSecond)
+	}
+	if reqData.PayloadSizeInByte < 0 {
+		return nil, fmt.Errorf("invalid payload size %v", reqData.PayloadSizeInByte)
+	}
+	duration := time.Duration(reqData.DurationInSecond) * time.Second
+
+	w := &Work{
+		ServerAddr:          reqData.ServerAddr,
+		PayloadSize:         reqData.PayloadSizeInByte,
+		RequestTimeSecond:   reqData.DurationInSecond,
+		QPS:                 reqData.Qps,
+		Timeout:             reqData.PerRequestTimeoutInMs,
+		EnableLatencyMetric: reqData.EnableLatencyMetric,
+		Logger:              logger.Named("tcp-client"),
+	}
+	w.Init()
+	logger.Sugar().Infof("begin to request %v for duration %v ", w.ServerAddr, duration.String())
+	w.Run()
+	logger.Sugar().Infof("finish all request %v for %s ", w.report.totalCount, w.ServerAddr)
+	// Collect metric reports
+	metrics := w.AggregateMetric()
+
+	logger.Sugar().Infof("result : %v ", metrics)
+	return metrics, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadTcp
+
+import (
+	"time"
+)
+
+type report struct {
+	enableLatencyMetric bool
+
+	tps              float64
+	throughputInKbps float64
+
+	results          chan *result
+	done             chan bool
+	total            time.Duration
+	errorDist        map[string]int
+	lats             []float32
+	totalLatencies   float32
+	totalCount       int64
+	connectedCount   int64
+	successCount     int64
+	sizeTotal        int64
+	transferDuration time.Duration
+
+	existsNotSendRequests bool
+}
+
+func newReport(results chan *result, enableLatencyMetric bool) *report {
+	return &report{
+		results:             results,
+		done:                make(chan bool, 1),
+		errorDist:           make(map[string]int),
+		lats:                make([]float32, 0),
+		enableLatencyMetric: enableLatencyMetric,
+	}
+}
+
+func runReporter(r *report) {
+	// Loop will continue until channel is closed
+	for res := range r.results {
+		r.totalCount++
+		// the connect latency makes sense once the handshake is done, even if the transfer fails
+		if res.err == nil || res.transferDuration > 0 {
+			r.connectedCount++
+			if r.enableLatencyMetric {
+				r.lats = append(r.lats, float32(res.connectDuration.Microseconds())/1000)
+			} else {
+				r.totalLatencies += float32(res.connectDuration.Microseconds()) / 1000
+			}
+		}
+		if res.err != nil {
+			r.errorDist[res.err.Error()]++
+			continue
+		}
+		r.successCount++
+		r.sizeTotal += res.size
+		r.transferDuration += res.transferDuration
+	}
+	// Signal reporter is done.
+	r.done <- true
+}
+
+func (r *report) finalize(total time.Duration) {
+	r.total = total
+	r.tps = float64(r.totalCount) / r.total.Seconds()
+	if r.transferDuration > 0 {
+		r.throughputInKbps = float64(r.sizeTotal) * 8 / 1000 / r.transferDuration.Seconds()
+	}
+}
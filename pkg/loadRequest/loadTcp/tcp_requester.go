@@ -0,0 +1,253 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadTcp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/utils/stats"
+)
+
+// Max size of the buffer of result channel.
+const maxResult = 1000000
+
+// the size of the acknowledgement sent back by the tcp server,
+// which carries the received data size in big endian
+const ackSize = 8
+
+type result struct {
+	err error
+	// the duration of tcp handshake
+	connectDuration time.Duration
+	// the duration from sending the payload to receiving the acknowledgement
+	transferDuration time.Duration
+	size             int64
+}
+
+type Work struct {
+	ServerAddr string
+
+	// the data size sent through each connection
+	PayloadSize int
+
+	// Timeout in millisecond for each connection.
+	Timeout int
+
+	/// RequestTimeSecond request in second
+	RequestTimeSecond int
+
+	// Qps is the rate limit of new connections per second.
+	QPS int
+
+	EnableLatencyMetric bool
+
+	Logger *zap.Logger
+
+	initOnce       sync.Once
+	payload        []byte
+	results        chan *result
+	stopCh         chan struct{}
+	qosTokenBucket chan struct{}
+	startTime      metav1.Time
+	report         *report
+}
+
+// Init initializes internal data-structures
+func (b *Work) Init() {
+	b.initOnce.Do(func() {
+		b.results = make(chan *result, maxResult)
+		b.stopCh = make(chan struct{}, 1)
+		b.qosTokenBucket = make(chan struct{}, b.QPS)
+		b.payload = make([]byte, b.PayloadSize)
+	})
+}
+
+// Run makes all the requests, prints the summary. It blocks until
+// all work is done.
+func (b *Work) Run() {
+	b.Init()
+	b.startTime = metav1.Now()
+	b.report = newReport(b.results, b.EnableLatencyMetric)
+	// Run the reporter first, it polls the result channel until it is closed.
+	go func() {
+		runReporter(b.report)
+	}()
+
+	// Send qps number of tokens to the channel qosTokenBucket every second to the coroutine for execution
+	go func() {
+		// Request token counter to avoid issuing multiple tokens due to errors
+		requestRound := 0
+
+		c := time.After(time.Duration(b.RequestTimeSecond) * time.Second)
+		ticker := time.NewTicker(time.Duration(1e9/(b.QPS)) * time.Nanosecond)
+		defer ticker.Stop()
+		// The request should be sent immediately at 0 seconds
+		b.qosTokenBucket <- struct{}{}
+		requestRound++
+		for {
+			select {
+			case <-c:
+				b.Logger.Sugar().Debugf("reach request duration time, stop request")
+				// Reach request duration stop request
+				if len(b.qosTokenBucket) > 0 {
+					b.Logger.Sugar().Errorf("request finish remaining number of tokens len: %d", len(b.qosTokenBucket))
+					b.report.existsNotSendRequests = true
+				}
+				b.Logger.Sugar().Debugf("send token %d times", requestRound)
+				b.Stop()
+				return
+			case <-ticker.C:
+				if requestRound >= b.QPS*b.RequestTimeSecond {
+					b.Logger.Sugar().Debugf("All request tokens have been sent and will not be sent again.")
+					continue
+				}
+				b.qosTokenBucket <- struct{}{}
+				requestRound++
+			}
+		}
+	}()
+	b.runWorker()
+	b.Finish()
+}
+
+func (b *Work) Stop() {
+	b.stopCh <- struct{}{}
+}
+
+func (b *Work) Finish() {
+	close(b.results)
+	close(b.qosTokenBucket)
+	total := metav1.Now().Sub(b.startTime.Time)
+	// Wait until the reporter is done.
+	<-b.report.done
+	b.report.finalize(total)
+}
+
+func (b *Work) makeRequest(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	timeout := time.Duration(b.Timeout) * time.Millisecond
+	deadline := time.Now().Add(timeout)
+
+	s := time.Now()
+	conn, err := net.DialTimeout("tcp", b.ServerAddr, timeout)
+	connectDuration := time.Since(s)
+	if err != nil {
+		b.results <- &result{
+			err:             err,
+			connectDuration: connectDuration,
+		}
+		return
+	}
+	defer conn.Close()
+
+	t := time.Now()
+	size, err := b.transfer(conn, deadline)
+	b.results <- &result{
+		err:              err,
+		connectDuration:  connectDuration,
+		transferDuration: time.Since(t),
+		size:             size,
+	}
+}
+
+// transfer sends the payload, then half-closes the connection and waits for
+// the server to acknowledge how many bytes it has received
+func (b *Work) transfer(conn net.Conn, deadline time.Time) (int64, error) {
+	if err := conn.SetDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	if len(b.payload) > 0 {
+		if _, err := conn.Write(b.payload); err != nil {
+			return 0, err
+		}
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		if err := tcpConn.CloseWrite(); err != nil {
+			return 0, err
+		}
+	}
+
+	ack := make([]byte, ackSize)
+	if _, err := io.ReadFull(conn, ack); err != nil {
+		return 0, fmt.Errorf("failed to read acknowledgement: %v", err)
+	}
+	received := int64(binary.BigEndian.Uint64(ack))
+	if received != int64(len(b.payload)) {
+		return received, fmt.Errorf("server received %d bytes, expected %d bytes", received, len(b.payload))
+	}
+	return received, nil
+}
+
+func (b *Work) runWorker() {
+	wg := &sync.WaitGroup{}
+	for {
+		// Check if application is stopped. Do not send into a closed channel.
+		select {
+		case <-b.stopCh:
+			wg.Wait()
+			return
+		case <-b.qosTokenBucket:
+			wg.Add(1)
+			go b.makeRequest(wg)
+		}
+	}
+}
+
+func (b *Work) AggregateMetric() *v1beta1.TcpMetrics {
+	latency := v1beta1.LatencyDistribution{}
+
+	if b.EnableLatencyMetric {
+		t, _ := stats.Mean(b.report.lats)
+		latency.Mean = t
+
+		t, _ = stats.Max(b.report.lats)
+		latency.Max = t
+
+		t, _ = stats.Min(b.report.lats)
+		latency.Min = t
+
+		t, _ = stats.Percentile(b.report.lats, 50)
+		latency.P50 = t
+
+		t, _ = stats.Percentile(b.report.lats, 90)
+		latency.P90 = t
+
+		t, _ = stats.Percentile(b.report.lats, 95)
+		latency.P95 = t
+
+		t, _ = stats.Percentile(b.report.lats, 99)
+		latency.P99 = t
+	} else if b.report.connectedCount > 0 {
+		latency.Mean = b.report.totalLatencies / float32(b.report.connectedCount)
+	}
+
+	metric := &v1beta1.TcpMetrics{
+		StartTime:             b.startTime,
+		EndTime:               metav1.NewTime(b.startTime.Add(b.report.total)),
+		Duration:              b.report.total.String(),
+		RequestCounts:         b.report.totalCount,
+		SuccessCounts:         b.report.successCount,
+		TPS:                   b.report.tps,
+		Errors:                b.report.errorDist,
+		ExistsNotSendRequests: b.report.existsNotSendRequests,
+		TargetAddress:         b.ServerAddr,
+		ConnectLatencies:      latency,
+		PayloadSize:           int64(b.PayloadSize),
+		TotalDataSize:         b.report.sizeTotal,
+		ThroughputInKbps:      b.report.throughputInKbps,
+	}
+
+	return metric
+}
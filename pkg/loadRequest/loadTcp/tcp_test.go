@@ -0,0 +1,95 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadTcp_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/agentTcpServer"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadTcp"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+)
+
+var _ = Describe("test tcp ", Label("tcp"), func() {
+	var serverAddr string
+
+	BeforeEach(func() {
+		l, e := net.Listen("tcp", "127.0.0.1:0")
+		Expect(e).NotTo(HaveOccurred(), "failed to listen, error=%v", e)
+		DeferCleanup(l.Close)
+		go agentTcpServer.Serve(logger.NewStdoutLogger("debug", "server"), l)
+		serverAddr = l.Addr().String()
+	})
+
+	It("test throughput ", func() {
+		req := &loadTcp.TcpRequestData{
+			ServerAddr:            serverAddr,
+			PerRequestTimeoutInMs: 1000,
+			Qps:                   10,
+			DurationInSecond:      2,
+			PayloadSizeInByte:     1024 * 1024,
+		}
+
+		log := logger.NewStdoutLogger("debug", "test")
+		result, e := loadTcp.TcpRequest(log, req)
+		Expect(e).NotTo(HaveOccurred(), "failed to execute , error=%v", e)
+		Expect(result.RequestCounts).To(BeNumerically(">", 0))
+		Expect(result.SuccessCounts).To(Equal(result.RequestCounts))
+		Expect(result.TotalDataSize).To(Equal(result.SuccessCounts * 1024 * 1024))
+		Expect(result.ThroughputInKbps).To(BeNumerically(">", 0))
+		Expect(result.Errors).To(BeEmpty())
+	})
+
+	It("test connect only with latency ", func() {
+		req := &loadTcp.TcpRequestData{
+			ServerAddr:            serverAddr,
+			PerRequestTimeoutInMs: 1000,
+			Qps:                   10,
+			DurationInSecond:      1,
+			PayloadSizeInByte:     0,
+			EnableLatencyMetric:   true,
+		}
+
+		log := logger.NewStdoutLogger("debug", "test")
+		result, e := loadTcp.TcpRequest(log, req)
+		Expect(e).NotTo(HaveOccurred(), "failed to execute , error=%v", e)
+		Expect(result.SuccessCounts).To(Equal(result.RequestCounts))
+		Expect(result.TotalDataSize).To(BeZero())
+		Expect(result.ConnectLatencies.Max).To(BeNumerically(">=", result.ConnectLatencies.Min))
+	})
+
+	It("test unreachable server ", func() {
+		l, e := net.Listen("tcp", "127.0.0.1:0")
+		Expect(e).NotTo(HaveOccurred())
+		addr := l.Addr().String()
+		Expect(l.Close()).To(Succeed())
+
+		req := &loadTcp.TcpRequestData{
+			ServerAddr:            addr,
+			PerRequestTimeoutInMs: 500,
+			Qps:                   5,
+			DurationInSecond:      1,
+			PayloadSizeInByte:     1024,
+		}
+
+		log := logger.NewStdoutLogger("debug", "test")
+		result, e := loadTcp.TcpRequest(log, req)
+		Expect(e).NotTo(HaveOccurred(), "failed to execute , error=%v", e)
+		Expect(result.SuccessCounts).To(BeZero())
+		Expect(result.Errors).NotTo(BeEmpty())
+	})
+
+	It("test invalid request ", func() {
+		req := &loadTcp.TcpRequestData{
+			ServerAddr:       "127.0.0.1",
+			Qps:              1,
+			DurationInSecond: 1,
+		}
+		_, e := loadTcp.TcpRequest(logger.NewStdoutLogger("debug", "test"), req)
+		Expect(e).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,61 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadUdp
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+)
+
+type UdpRequestData struct {
+	// specified to be format "2.2.2.2:5730" or "[fd00::2]:5730"
+	ServerAddr           string
+	PerPacketTimeoutInMs int
+	PacketRate           int
+	DurationInSecond     int
+	// the size of each packet, which should not be smaller than PacketHeaderSize
+	PayloadSizeInByte   int
+	EnableLatencyMetric bool
+}
+
+func UdpRequest(logger *zap.Logger, reqData *UdpRequestData) (result *v1beta1.UdpMetrics, err error) {
+	logger.Sugar().Infof("udp request=%v", reqData)
+
+	if _, _, e := net.SplitHostPort(reqData.ServerAddr); e != nil {
+		return nil, fmt.Errorf("invalid server address %v: %v", reqData.ServerAddr, e)
+	}
+	if reqData.PacketRate <= 0 || reqData.DurationInSecond <= 0 {
+		return nil, fmt.Errorf("invalid packet rate %v or duration %v", reqData.PacketRate, reqData.DurationInSecond)
+	}
+	if reqData.PayloadSizeInByte < PacketHeaderSize || reqData.PayloadSizeInByte > maxPacketSize {
+		return nil, fmt.Errorf("invalid payload size %v, it should be in range [%v, %v]", reqData.PayloadSizeInByte, PacketHeaderSize, maxPacketSize)
+	}
+	duration := time.Duration(reqData.DurationInSecond) * time.Second
+
+	w := &Work{
+		ServerAddr:          reqData.ServerAddr,
+		PayloadSize:         reqData.PayloadSizeInByte,
+		RequestTimeSecond:   reqData.DurationInSecond,
+		QPS:                 reqData.PacketRate,
+		Timeout:             reqData.PerPacketTimeoutInMs,
+		EnableLatencyMetric: reqData.EnableLatencyMetric,
+		Logger:              logger.Named("udp-client"),
+	}
+	w.Init()
+	logger.Sugar().Infof("begin to send packets to %v for duration %v ", w.ServerAddr, duration.String())
+	if e := w.Run(); e != nil {
+		return nil, e
+	}
+	logger.Sugar().Infof("finish sending %v packets to %s ", w.report.sendCount, w.ServerAddr)
+	// Collect metric reports
+	metrics := w.AggregateMetric()
+
+	logger.Sugar().Infof("result : %v ", metrics)
+	return metrics, nil
+}
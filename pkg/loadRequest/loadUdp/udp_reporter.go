@@ -0,0 +1,105 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadUdp
+
+import (
+	"time"
+
+	"github.com/kdoctor-io/kdoctor/pkg/lock"
+)
+
+const errEchoTimeout = "the echo arrives later than the timeout"
+
+type report struct {
+	enableLatencyMetric bool
+	timeout             time.Duration
+
+	pps            float64
+	lossPercentage float64
+
+	// received records whether the echo of each sequence has arrived
+	received        []bool
+	maxSeq          int64
+	total           time.Duration
+	errorLock       lock.Mutex
+	errorDist       map[string]int
+	lats            []float32
+	totalLatencies  float32
+	sendCount       int64
+	receivedCount   int64
+	outOfOrderCount int64
+	duplicateCount  int64
+
+	// RFC 3550 interarrival jitter in millisecond
+	jitter      float64
+	lastTransit time.Duration
+
+	existsNotSendRequests bool
+}
+
+func newReport(packetNum int, timeout time.Duration, enableLatencyMetric bool) *report {
+	return &report{
+		timeout:             timeout,
+		received:            make([]bool, packetNum),
+		maxSeq:              -1,
+		errorDist:           make(map[string]int),
+		lats:                make([]float32, 0),
+		enableLatencyMetric: enableLatencyMetric,
+	}
+}
+
+func (r *report) addError(reason string) {
+	r.errorLock.Lock()
+	r.errorDist[reason]++
+	r.errorLock.Unlock()
+}
+
+// record handles the echo of a packet, it is only called by the receiving goroutine
+func (r *report) record(seq uint64, rtt time.Duration) {
+	if seq >= uint64(len(r.received)) {
+		r.addError("unexpected packet sequence")
+		return
+	}
+	if r.received[seq] {
+		r.duplicateCount++
+		return
+	}
+	// the packet is considered as lost
+	if rtt > r.timeout {
+		r.addError(errEchoTimeout)
+		return
+	}
+
+	r.received[seq] = true
+	if int64(seq) < r.maxSeq {
+		r.outOfOrderCount++
+	} else {
+		r.maxSeq = int64(seq)
+	}
+
+	// J(i) = J(i-1) + (|D(i-1,i)| - J(i-1))/16
+	if r.receivedCount > 0 {
+		d := rtt - r.lastTransit
+		if d < 0 {
+			d = -d
+		}
+		r.jitter += (float64(d.Microseconds())/1000 - r.jitter) / 16
+	}
+	r.lastTransit = rtt
+	r.receivedCount++
+
+	if r.enableLatencyMetric {
+		r.lats = append(r.lats, float32(rtt.Microseconds())/1000)
+	} else {
+		r.totalLatencies += float32(rtt.Microseconds()) / 1000
+	}
+}
+
+func (r *report) finalize(total time.Duration) {
+	r.total = total
+	r.pps = float64(r.sendCount) / r.total.Seconds()
+	if r.sendCount > 0 {
+		r.lossPercentage = float64(r.sendCount-r.receivedCount) / float64(r.sendCount) * 100
+	}
+}
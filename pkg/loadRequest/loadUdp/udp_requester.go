@@ -0,0 +1,188 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadUdp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/utils/stats"
+)
+
+// PacketHeaderSize is the size of the header carried by each packet,
+// which consists of the magic number, the sequence and the send time in unix nanosecond
+const PacketHeaderSize = 20
+
+// the maximum payload size of a udp packet over ipv4
+const maxPacketSize = 65507
+
+const packetMagic uint32 = 0x6b647570
+
+type Work struct {
+	ServerAddr string
+
+	// the size of each packet
+	PayloadSize int
+
+	// Timeout in millisecond to wait for the echo of each packet.
+	Timeout int
+
+	/// RequestTimeSecond request in second
+	RequestTimeSecond int
+
+	// Qps is the number of packets sent per second.
+	QPS int
+
+	EnableLatencyMetric bool
+
+	Logger *zap.Logger
+
+	initOnce  sync.Once
+	packet    []byte
+	startTime metav1.Time
+	report    *report
+}
+
+// Init initializes internal data-structures
+func (b *Work) Init() {
+	b.initOnce.Do(func() {
+		b.packet = make([]byte, b.PayloadSize)
+		binary.BigEndian.PutUint32(b.packet[0:4], packetMagic)
+		b.report = newReport(b.QPS*b.RequestTimeSecond, time.Duration(b.Timeout)*time.Millisecond, b.EnableLatencyMetric)
+	})
+}
+
+// Run sends all the packets and collects the echoes. It blocks until
+// all work is done.
+func (b *Work) Run() error {
+	b.Init()
+	conn, err := net.Dial("udp", b.ServerAddr)
+	if err != nil {
+		return fmt.Errorf("failed to dial %v: %v", b.ServerAddr, err)
+	}
+	defer conn.Close()
+
+	b.startTime = metav1.Now()
+	done := make(chan struct{})
+	go func() {
+		b.receive(conn)
+		close(done)
+	}()
+
+	b.send(conn)
+
+	// wait for the echoes of the last packets
+	if err := conn.SetReadDeadline(time.Now().Add(time.Duration(b.Timeout) * time.Millisecond)); err != nil {
+		conn.Close()
+	}
+	<-done
+	b.report.finalize(metav1.Now().Sub(b.startTime.Time))
+	return nil
+}
+
+// send paces the packets evenly during the request duration
+func (b *Work) send(conn net.Conn) {
+	total := b.QPS * b.RequestTimeSecond
+	interval := time.Second / time.Duration(b.QPS)
+	deadline := b.startTime.Add(time.Duration(b.RequestTimeSecond) * time.Second)
+
+	for seq := 0; seq < total; seq++ {
+		if d := time.Until(b.startTime.Add(time.Duration(seq) * interval)); d > 0 {
+			time.Sleep(d)
+		}
+		if time.Now().After(deadline) {
+			b.Logger.Sugar().Errorf("reach request duration time, remaining %d packets are not sent", total-seq)
+			b.report.existsNotSendRequests = true
+			return
+		}
+		binary.BigEndian.PutUint64(b.packet[4:12], uint64(seq))
+		binary.BigEndian.PutUint64(b.packet[12:20], uint64(time.Now().UnixNano()))
+		b.report.sendCount++
+		if _, err := conn.Write(b.packet); err != nil {
+			b.report.addError(err.Error())
+		}
+	}
+	b.Logger.Sugar().Debugf("send packets %d times", b.report.sendCount)
+}
+
+func (b *Work) receive(conn net.Conn) {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, err := conn.Read(buf)
+		now := time.Now()
+		if err != nil {
+			var ne net.Error
+			if (errors.As(err, &ne) && ne.Timeout()) || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			// for example, connection refused when the icmp port unreachable is received
+			b.report.addError(err.Error())
+			continue
+		}
+		if n < PacketHeaderSize || binary.BigEndian.Uint32(buf[0:4]) != packetMagic {
+			b.report.addError("unexpected packet")
+			continue
+		}
+		seq := binary.BigEndian.Uint64(buf[4:12])
+		sendTime := time.Unix(0, int64(binary.BigEndian.Uint64(buf[12:20])))
+		b.report.record(seq, now.Sub(sendTime))
+	}
+}
+
+func (b *Work) AggregateMetric() *v1beta1.UdpMetrics {
+	latency := v1beta1.LatencyDistribution{}
+
+	if b.EnableLatencyMetric {
+		t, _ := stats.Mean(b.report.lats)
+		latency.Mean = t
+
+		t, _ = stats.Max(b.report.lats)
+		latency.Max = t
+
+		t, _ = stats.Min(b.report.lats)
+		latency.Min = t
+
+		t, _ = stats.Percentile(b.report.lats, 50)
+		latency.P50 = t
+
+		t, _ = stats.Percentile(b.report.lats, 90)
+		latency.P90 = t
+
+		t, _ = stats.Percentile(b.report.lats, 95)
+		latency.P95 = t
+
+		t, _ = stats.Percentile(b.report.lats, 99)
+		latency.P99 = t
+	} else if b.report.receivedCount > 0 {
+		latency.Mean = b.report.totalLatencies / float32(b.report.receivedCount)
+	}
+
+	metric := &v1beta1.UdpMetrics{
+		StartTime:             b.startTime,
+		EndTime:               metav1.NewTime(b.startTime.Add(b.report.total)),
+		Duration:              b.report.total.String(),
+		SendCounts:            b.report.sendCount,
+		ReceivedCounts:        b.report.receivedCount,
+		PPS:                   b.report.pps,
+		Errors:                b.report.errorDist,
+		ExistsNotSendRequests: b.report.existsNotSendRequests,
+		TargetAddress:         b.ServerAddr,
+		PayloadSize:           int64(b.PayloadSize),
+		LossPercentage:        b.report.lossPercentage,
+		OutOfOrderCounts:      b.report.outOfOrderCount,
+		DuplicateCounts:       b.report.duplicateCount,
+		JitterInMs:            b.report.jitter,
+		Latencies:             latency,
+	}
+
+	return metric
+}
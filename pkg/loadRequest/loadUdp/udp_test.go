@@ -0,0 +1,155 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package loadUdp_test
+
+import (
+	"encoding/binary"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/agentUdpServer"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadUdp"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+)
+
+// lossyCounts returns the echoes which lossyServe gives back for the first sent packets,
+// the pacing of the requester may send fewer packets than the rate during the duration
+func lossyCounts(sent int64) (received, outOfOrder, duplicate int64) {
+	for seq := int64(0); seq < sent; seq++ {
+		switch seq % 10 {
+		case 0:
+		case 3:
+			// the held packet is only echoed after the next one
+			if seq+1 < sent {
+				received++
+			}
+		case 4:
+			received++
+			outOfOrder++
+		case 5:
+			received++
+			duplicate++
+		default:
+			received++
+		}
+	}
+	return
+}
+
+// lossyServe drops, duplicates and reorders the echoes by the sequence of the packets
+func lossyServe(pc net.PacketConn) {
+	buf := make([]byte, 65535)
+	var held []byte
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		seq := binary.BigEndian.Uint64(buf[4:12])
+		packet := append([]byte{}, buf[:n]...)
+		switch seq % 10 {
+		case 0:
+			// drop it
+		case 3:
+			held = packet
+		case 4:
+			_, _ = pc.WriteTo(packet, addr)
+			_, _ = pc.WriteTo(held, addr)
+		case 5:
+			_, _ = pc.WriteTo(packet, addr)
+			_, _ = pc.WriteTo(packet, addr)
+		default:
+			_, _ = pc.WriteTo(packet, addr)
+		}
+	}
+}
+
+var _ = Describe("test udp ", Label("udp"), func() {
+
+	It("test echo server ", func() {
+		pc, e := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(e).NotTo(HaveOccurred(), "failed to listen, error=%v", e)
+		defer pc.Close()
+		go agentUdpServer.Serve(logger.NewStdoutLogger("debug", "server"), pc)
+
+		req := &loadUdp.UdpRequestData{
+			ServerAddr:           pc.LocalAddr().String(),
+			PerPacketTimeoutInMs: 1000,
+			PacketRate:           100,
+			DurationInSecond:     1,
+			PayloadSizeInByte:    1024,
+			EnableLatencyMetric:  true,
+		}
+
+		log := logger.NewStdoutLogger("debug", "test")
+		result, e := loadUdp.UdpRequest(log, req)
+		Expect(e).NotTo(HaveOccurred(), "failed to execute , error=%v", e)
+		Expect(result.SendCounts).To(BeNumerically("~", 100, 5))
+		Expect(result.ReceivedCounts).To(Equal(result.SendCounts))
+		Expect(result.LossPercentage).To(BeZero())
+		Expect(result.DuplicateCounts).To(BeZero())
+		Expect(result.ExistsNotSendRequests).To(Equal(result.SendCounts < 100))
+		Expect(result.Latencies.Max).To(BeNumerically(">=", result.Latencies.Min))
+	})
+
+	It("test loss, reordering and duplicates ", func() {
+		pc, e := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(e).NotTo(HaveOccurred(), "failed to listen, error=%v", e)
+		defer pc.Close()
+		go lossyServe(pc)
+
+		req := &loadUdp.UdpRequestData{
+			ServerAddr:           pc.LocalAddr().String(),
+			PerPacketTimeoutInMs: 1000,
+			PacketRate:           100,
+			DurationInSecond:     1,
+			PayloadSizeInByte:    loadUdp.PacketHeaderSize,
+		}
+
+		log := logger.NewStdoutLogger("debug", "test")
+		result, e := loadUdp.UdpRequest(log, req)
+		Expect(e).NotTo(HaveOccurred(), "failed to execute , error=%v", e)
+		Expect(result.SendCounts).To(BeNumerically("~", 100, 5))
+		received, outOfOrder, duplicate := lossyCounts(result.SendCounts)
+		Expect(result.ReceivedCounts).To(Equal(received))
+		Expect(result.LossPercentage).To(BeNumerically("~", float64(result.SendCounts-received)/float64(result.SendCounts)*100, 0.001))
+		Expect(result.OutOfOrderCounts).To(Equal(outOfOrder))
+		Expect(result.DuplicateCounts).To(Equal(duplicate))
+		Expect(result.JitterInMs).To(BeNumerically(">", 0))
+	})
+
+	It("test unreachable server ", func() {
+		pc, e := net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(e).NotTo(HaveOccurred())
+		addr := pc.LocalAddr().String()
+		Expect(pc.Close()).To(Succeed())
+
+		req := &loadUdp.UdpRequestData{
+			ServerAddr:           addr,
+			PerPacketTimeoutInMs: 200,
+			PacketRate:           10,
+			DurationInSecond:     1,
+			PayloadSizeInByte:    64,
+		}
+
+		log := logger.NewStdoutLogger("debug", "test")
+		result, e := loadUdp.UdpRequest(log, req)
+		Expect(e).NotTo(HaveOccurred(), "failed to execute , error=%v", e)
+		Expect(result.ReceivedCounts).To(BeZero())
+		Expect(result.LossPercentage).To(BeNumerically("~", 100, 0.001))
+	})
+
+	It("test invalid request ", func() {
+		req := &loadUdp.UdpRequestData{
+			ServerAddr:        "127.0.0.1:5730",
+			PacketRate:        1,
+			DurationInSecond:  1,
+			PayloadSizeInByte: loadUdp.PacketHeaderSize - 1,
+		}
+		_, e := loadUdp.UdpRequest(logger.NewStdoutLogger("debug", "test"), req)
+		Expect(e).To(HaveOccurred())
+	})
+})
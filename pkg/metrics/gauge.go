@@ -0,0 +1,77 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+)
+
+// Gauge keeps the latest value of each attribute set, which is observed when the metrics are collected
+type Gauge struct {
+	lock       sync.Mutex
+	values     map[attribute.Distinct]gaugeValue
+	instrument asyncfloat64.Gauge
+}
+
+type gaugeValue struct {
+	attrs []attribute.KeyValue
+	value float64
+}
+
+func (g *Gauge) Set(value float64, attrs ...attribute.KeyValue) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.values == nil {
+		g.values = map[attribute.Distinct]gaugeValue{}
+	}
+	set := attribute.NewSet(attrs...)
+	g.values[set.Equivalent()] = gaugeValue{attrs: attrs, value: value}
+}
+
+func (g *Gauge) Delete(attrs ...attribute.KeyValue) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	set := attribute.NewSet(attrs...)
+	delete(g.values, set.Equivalent())
+}
+
+// DeleteMatching removes all attribute sets which contain the attributes
+func (g *Gauge) DeleteMatching(attrs ...attribute.KeyValue) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for k, v := range g.values {
+		set := attribute.NewSet(v.attrs...)
+		matched := true
+		for _, a := range attrs {
+			if value, ok := set.Value(a.Key); !ok || value != a.Value {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			delete(g.values, k)
+		}
+	}
+}
+
+// Get returns the value of the attribute set
+func (g *Gauge) Get(attrs ...attribute.KeyValue) (float64, bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	set := attribute.NewSet(attrs...)
+	v, ok := g.values[set.Equivalent()]
+	return v.value, ok
+}
+
+func (g *Gauge) observe(ctx context.Context) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for _, v := range g.values {
+		g.instrument.Observe(ctx, v.value, v.attrs...)
+	}
+}
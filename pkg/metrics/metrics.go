@@ -54,6 +54,18 @@ func RegisterMetricInstance(metricMapping []MetricMappingType, meter metric.Mete
 			*r = t
 			logger.Info("new histogram metric: " + v.Name)
 
+		case *Gauge:
+			t, e := meter.AsyncFloat64().Gauge(v.Name, instrument.WithDescription(v.Description))
+			if e != nil {
+				logger.Sugar().Fatalf("failed to generate gauge metric %v, reason=%v", v.Name, e)
+			}
+			r := v.P.(*Gauge)
+			r.instrument = t
+			if e := meter.RegisterCallback([]instrument.Asynchronous{t}, r.observe); e != nil {
+				logger.Sugar().Fatalf("failed to register callback for gauge metric %v, reason=%v", v.Name, e)
+			}
+			logger.Info("new gauge metric: " + v.Name)
+
 		default:
 			logger.Sugar().Fatalf("unsupported metric: %+v", v)
 		}
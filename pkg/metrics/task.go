@@ -0,0 +1,201 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+)
+
+// the labels of the task metrics
+const (
+	LabelKind        = "kind"
+	LabelTask        = "task"
+	LabelTarget      = "target"
+	LabelNode        = "node"
+	LabelProtocol    = "protocol"
+	LabelResult      = "result"
+	LabelRuntimeKind = "runtime_kind"
+)
+
+var (
+	MetricCounterTaskRequest     syncfloat64.Counter
+	MetricCounterTaskFailure     syncfloat64.Counter
+	MetricHistogramTaskLatency   syncfloat64.Histogram
+	MetricCounterTaskRound       syncfloat64.Counter
+	MetricGaugeTaskSuccessRate   = &Gauge{}
+	MetricCounterTaskRoundResult syncfloat64.Counter
+	MetricGaugeTaskRuntimeReady  = &Gauge{}
+)
+
+// AgentTaskMetricMapping is the task metrics of the agent
+var AgentTaskMetricMapping = []MetricMappingType{
+	{P: &MetricCounterTaskRequest, Name: "task_request_counts", Description: "the request counter of the task target"},
+	{P: &MetricCounterTaskFailure, Name: "task_failure_counts", Description: "the failed request counter of the task target"},
+	{P: &MetricHistogramTaskLatency, Name: "task_request_latency_milliseconds", Description: "the latency histogram in millisecond of the succeeded requests of the task target"},
+	{P: &MetricCounterTaskRound, Name: "task_agent_round_counts", Description: "the round counter of the agent by the round result"},
+	{P: MetricGaugeTaskSuccessRate, Name: "task_success_rate", Description: "the request success rate of the task target in the latest round"},
+}
+
+// ControllerTaskMetricMapping is the task metrics of the controller
+var ControllerTaskMetricMapping = []MetricMappingType{
+	{P: &MetricCounterTaskRoundResult, Name: "task_round_counts", Description: "the round counter of the task by the round result"},
+	{P: MetricGaugeTaskRuntimeReady, Name: "task_runtime_ready", Description: "whether the agent runtime of the task is ready, 1 for ready and 0 for not"},
+}
+
+// TaskTarget identifies the requests to a target of the task
+type TaskTarget struct {
+	Kind     string
+	Task     string
+	Target   string
+	Node     string
+	Protocol string
+}
+
+func (t *TaskTarget) attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String(LabelKind, t.Kind),
+		attribute.String(LabelTask, t.Task),
+		attribute.String(LabelTarget, t.Target),
+		attribute.String(LabelNode, t.Node),
+		attribute.String(LabelProtocol, t.Protocol),
+	}
+}
+
+// ObserveLatency records the latency of a succeeded request, and it does nothing for the nil target
+func (t *TaskTarget) ObserveLatency(latency time.Duration) {
+	if t == nil || MetricHistogramTaskLatency == nil {
+		return
+	}
+	MetricHistogramTaskLatency.Record(context.Background(), float64(latency.Microseconds())/1000, t.attributes()...)
+}
+
+// RecordTargetRound records the requests of the target in a round
+func (t *TaskTarget) RecordTargetRound(requests, successes int64) {
+	ctx := context.Background()
+	attrs := t.attributes()
+	if MetricCounterTaskRequest != nil {
+		MetricCounterTaskRequest.Add(ctx, float64(requests), attrs...)
+	}
+	if MetricCounterTaskFailure != nil && requests > successes {
+		MetricCounterTaskFailure.Add(ctx, float64(requests-successes), attrs...)
+	}
+	if requests > 0 {
+		MetricGaugeTaskSuccessRate.Set(float64(successes)/float64(requests), attrs...)
+	}
+}
+
+// DeleteTaskSuccessRate removes the success rate of all targets of the task whose runtime or itself is deleted
+func DeleteTaskSuccessRate(kind, task string) {
+	MetricGaugeTaskSuccessRate.DeleteMatching(
+		attribute.String(LabelKind, kind),
+		attribute.String(LabelTask, task),
+	)
+}
+
+// URLProtocol returns the scheme of the url as the protocol
+func URLProtocol(rawURL string) string {
+	u, e := url.Parse(rawURL)
+	if e != nil || len(u.Scheme) == 0 {
+		return "http"
+	}
+	return strings.ToLower(u.Scheme)
+}
+
+type targetRound struct {
+	name      string
+	protocol  string
+	requests  int64
+	successes int64
+}
+
+// reportTargets returns the requests of all targets in the agent report
+func reportTargets(report *v1beta1.Report) []targetRound {
+	result := []targetRound{}
+	switch {
+	case report.TaskNetReach != nil:
+		for _, d := range report.TaskNetReach.Detail {
+			result = append(result, targetRound{d.TargetName, URLProtocol(d.TargetUrl), d.Metrics.RequestCounts, d.Metrics.SuccessCounts})
+		}
+	case report.TaskAppHttpHealthy != nil:
+		for _, d := range report.TaskAppHttpHealthy.Detail {
+			result = append(result, targetRound{d.TargetName, URLProtocol(d.TargetUrl), d.Metrics.RequestCounts, d.Metrics.SuccessCounts})
+		}
+	case report.TaskNetDNS != nil:
+		for _, d := range report.TaskNetDNS.Detail {
+			result = append(result, targetRound{d.TargetName, d.TargetProtocol, d.Metrics.RequestCounts, d.Metrics.SuccessCounts})
+		}
+	case report.TaskNetTcp != nil:
+		for _, d := range report.TaskNetTcp.Detail {
+			result = append(result, targetRound{d.TargetName, "tcp", d.Metrics.RequestCounts, d.Metrics.SuccessCounts})
+		}
+	case report.TaskNetUdp != nil:
+		for _, d := range report.TaskNetUdp.Detail {
+			result = append(result, targetRound{d.TargetName, "udp", d.Metrics.SendCounts, d.Metrics.ReceivedCounts})
+		}
+	case report.TaskNetDelay != nil:
+		for _, d := range report.TaskNetDelay.Detail {
+			result = append(result, targetRound{d.TargetName, "tcp", d.Metrics.RequestCounts, d.Metrics.SuccessCounts})
+		}
+	}
+	return result
+}
+
+// RecordAgentReport records the round result and the requests of all targets in the agent report
+func RecordAgentReport(kind, task string, report *v1beta1.Report) {
+	if MetricCounterTaskRound != nil {
+		MetricCounterTaskRound.Add(context.Background(), 1,
+			attribute.String(LabelKind, kind),
+			attribute.String(LabelTask, task),
+			attribute.String(LabelNode, report.NodeName),
+			attribute.String(LabelResult, report.RoundResult),
+		)
+	}
+	for _, t := range reportTargets(report) {
+		target := &TaskTarget{Kind: kind, Task: task, Target: t.name, Node: report.NodeName, Protocol: t.protocol}
+		target.RecordTargetRound(t.requests, t.successes)
+	}
+}
+
+// RecordRoundResult records the result of the round summarized by the controller
+func RecordRoundResult(kind, task, result string) {
+	if MetricCounterTaskRoundResult == nil {
+		return
+	}
+	MetricCounterTaskRoundResult.Add(context.Background(), 1,
+		attribute.String(LabelKind, kind),
+		attribute.String(LabelTask, task),
+		attribute.String(LabelResult, result),
+	)
+}
+
+// SetRuntimeReady records whether the agent runtime of the task is ready
+func SetRuntimeReady(kind, task, runtimeKind string, ready bool) {
+	attrs := []attribute.KeyValue{
+		attribute.String(LabelKind, kind),
+		attribute.String(LabelTask, task),
+		attribute.String(LabelRuntimeKind, runtimeKind),
+	}
+	var v float64
+	if ready {
+		v = 1
+	}
+	MetricGaugeTaskRuntimeReady.Set(v, attrs...)
+}
+
+// DeleteRuntimeReady removes the runtime readiness of the task whose runtime is deleted
+func DeleteRuntimeReady(kind, task, runtimeKind string) {
+	MetricGaugeTaskRuntimeReady.Delete(
+		attribute.String(LabelKind, kind),
+		attribute.String(LabelTask, task),
+		attribute.String(LabelRuntimeKind, runtimeKind),
+	)
+}
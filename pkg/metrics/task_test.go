@@ -0,0 +1,114 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+)
+
+var _ = Describe("test task metrics", Label("task metrics"), func() {
+
+	collect := func(reader metricsdk.Reader) map[string]metricdata.Aggregation {
+		data, err := reader.Collect(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		result := map[string]metricdata.Aggregation{}
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				result[m.Name] = m.Data
+			}
+		}
+		return result
+	}
+
+	It("keep the latest value of the gauge", func() {
+		g := &Gauge{}
+		attr := attribute.String(LabelTask, "a")
+		g.Set(1, attr)
+		g.Set(0.5, attr)
+		v, ok := g.Get(attr)
+		Expect(ok).To(BeTrue())
+		Expect(v).To(Equal(0.5))
+		g.Delete(attr)
+		_, ok = g.Get(attr)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("record the task metrics", func() {
+		reader := metricsdk.NewManualReader()
+		meter := metricsdk.NewMeterProvider(metricsdk.WithReader(reader)).Meter("test")
+		RegisterMetricInstance(append(append([]MetricMappingType{}, AgentTaskMetricMapping...), ControllerTaskMetricMapping...), meter, zap.NewNop())
+
+		detail := v1beta1.NetReachTaskDetail{TargetName: "AgentClusterV4IP_10.0.0.1", TargetUrl: "http://10.0.0.1:80"}
+		detail.Metrics.RequestCounts = 10
+		detail.Metrics.SuccessCounts = 8
+		RecordAgentReport(v1beta1.NetReachTaskName, "task", &v1beta1.Report{
+			NodeName:     "worker1",
+			RoundResult:  "fail",
+			TaskNetReach: &v1beta1.NetReachTask{Detail: []v1beta1.NetReachTaskDetail{detail}},
+		})
+		target := &TaskTarget{Kind: v1beta1.NetReachTaskName, Task: "task", Target: detail.TargetName, Node: "worker1", Protocol: "http"}
+		target.ObserveLatency(15 * time.Millisecond)
+		var nilTarget *TaskTarget
+		nilTarget.ObserveLatency(time.Millisecond)
+		RecordRoundResult(v1beta1.NetReachTaskName, "task", "fail")
+		SetRuntimeReady(v1beta1.NetReachTaskName, "task", "DaemonSet", true)
+
+		data := collect(reader)
+		targetAttrs := attribute.NewSet(target.attributes()...)
+
+		requests := data["task_request_counts"].(metricdata.Sum[float64])
+		Expect(requests.DataPoints).To(HaveLen(1))
+		Expect(requests.DataPoints[0].Value).To(Equal(float64(10)))
+		Expect(requests.DataPoints[0].Attributes.Equals(&targetAttrs)).To(BeTrue())
+
+		failures := data["task_failure_counts"].(metricdata.Sum[float64])
+		Expect(failures.DataPoints[0].Value).To(Equal(float64(2)))
+
+		rate := data["task_success_rate"].(metricdata.Gauge[float64])
+		Expect(rate.DataPoints[0].Value).To(Equal(0.8))
+
+		latency := data["task_request_latency_milliseconds"].(metricdata.Histogram)
+		Expect(latency.DataPoints).To(HaveLen(1))
+		Expect(latency.DataPoints[0].Count).To(BeEquivalentTo(1))
+		Expect(latency.DataPoints[0].Sum).To(Equal(float64(15)))
+
+		rounds := data["task_agent_round_counts"].(metricdata.Sum[float64])
+		v, _ := rounds.DataPoints[0].Attributes.Value(LabelResult)
+		Expect(v.AsString()).To(Equal("fail"))
+
+		Expect(data["task_round_counts"].(metricdata.Sum[float64]).DataPoints).To(HaveLen(1))
+		Expect(data["task_runtime_ready"].(metricdata.Gauge[float64]).DataPoints[0].Value).To(Equal(float64(1)))
+
+		DeleteRuntimeReady(v1beta1.NetReachTaskName, "task", "DaemonSet")
+		data = collect(reader)
+		Expect(data["task_runtime_ready"].(metricdata.Gauge[float64]).DataPoints).To(BeEmpty())
+
+		// only the success rate of the deleted task is removed
+		other := &TaskTarget{Kind: v1beta1.NetReachTaskName, Task: "other", Target: detail.TargetName, Node: "worker1", Protocol: "http"}
+		other.RecordTargetRound(10, 10)
+		DeleteTaskSuccessRate(v1beta1.NetReachTaskName, "task")
+		data = collect(reader)
+		rate = data["task_success_rate"].(metricdata.Gauge[float64])
+		Expect(rate.DataPoints).To(HaveLen(1))
+		Expect(rate.DataPoints[0].Value).To(Equal(float64(1)))
+		DeleteTaskSuccessRate(v1beta1.NetReachTaskName, "other")
+		data = collect(reader)
+		Expect(data["task_success_rate"].(metricdata.Gauge[float64]).DataPoints).To(BeEmpty())
+	})
+
+	It("parse the protocol of the url", func() {
+		Expect(URLProtocol("https://a.com")).To(Equal("https"))
+		Expect(URLProtocol("a.com")).To(Equal("http"))
+	})
+})
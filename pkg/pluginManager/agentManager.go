@@ -142,6 +142,12 @@ func checkTaskExist(mgr manager.Manager) error {
 		task = &crd.NetReach{}
 	case KindNameNetdns:
 		task = &crd.Netdns{}
+	case KindNameNetTcp:
+		task = &crd.NetTcp{}
+	case KindNameNetUdp:
+		task = &crd.NetUdp{}
+	case KindNameNetDelay:
+		task = &crd.NetDelay{}
 	}
 	err := mgr.GetClient().Get(context.TODO(), k8types.NamespacedName{Name: types.AgentConfig.TaskName}, task)
 	if nil != err {
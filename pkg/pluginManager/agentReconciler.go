@@ -9,12 +9,14 @@ import (
 	"reflect"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kdoctor-io/kdoctor/pkg/fileManager"
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
 	"github.com/kdoctor-io/kdoctor/pkg/taskStatusManager"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
@@ -57,6 +59,9 @@ func (s *pluginAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		instance := crd.NetReach{}
 		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
 			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			if errors.IsNotFound(err) {
+				metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			}
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 
@@ -71,8 +76,12 @@ func (s *pluginAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 		if instance.DeletionTimestamp != nil {
 			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
 			return ctrl.Result{}, nil
 		}
+		if instance.Status.Resource != nil && instance.Status.Resource.RuntimeStatus == crd.RuntimeDeleted {
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+		}
 
 		oldStatus := instance.Status.DeepCopy()
 		taskName := instance.Kind + "." + instance.Name
@@ -101,6 +110,9 @@ func (s *pluginAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		instance := crd.AppHttpHealthy{}
 		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
 			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			if errors.IsNotFound(err) {
+				metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			}
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 
@@ -115,8 +127,12 @@ func (s *pluginAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 		if instance.DeletionTimestamp != nil {
 			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
 			return ctrl.Result{}, nil
 		}
+		if instance.Status.Resource != nil && instance.Status.Resource.RuntimeStatus == crd.RuntimeDeleted {
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+		}
 
 		oldStatus := instance.Status.DeepCopy()
 		taskName := instance.Kind + "." + instance.Name
@@ -145,6 +161,59 @@ func (s *pluginAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		instance := crd.Netdns{}
 		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
 			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			if errors.IsNotFound(err) {
+				metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			}
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
+		logger.Sugar().Debugf("reconcile handle %v", instance)
+
+		// filter work agent
+		if instance.Spec.AgentSpec != nil && types.AgentConfig.DefaultAgent {
+			s.logger.Sugar().Debugf("general agent ignore custom agent task %v", req)
+			return ctrl.Result{}, nil
+		}
+
+		if instance.DeletionTimestamp != nil {
+			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			return ctrl.Result{}, nil
+		}
+		if instance.Status.Resource != nil && instance.Status.Resource.RuntimeStatus == crd.RuntimeDeleted {
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+		}
+
+		oldStatus := instance.Status.DeepCopy()
+		taskName := instance.Kind + "." + instance.Name
+		if result, newStatus, err := s.HandleAgentTaskRound(logger, ctx, oldStatus, instance.Spec.Schedule.DeepCopy(), &instance, taskName, instance.Spec.DeepCopy()); err != nil {
+			// requeue
+			logger.Sugar().Errorf("failed to HandleAgentTaskRound, will retry it, error=%v", err)
+			return ctrl.Result{}, err
+
+		} else {
+			if newStatus != nil && !reflect.DeepEqual(newStatus, oldStatus) {
+				instance.Status = *newStatus
+				if err := s.client.Status().Update(ctx, &instance); err != nil {
+					// requeue
+					logger.Sugar().Errorf("failed to update status, will retry it, error=%v", err)
+					return ctrl.Result{}, err
+				}
+				logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+			}
+
+			if result != nil {
+				return *result, nil
+			}
+		}
+
+	case KindNameNetTcp:
+		instance := crd.NetTcp{}
+		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
+			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			if errors.IsNotFound(err) {
+				metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			}
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
@@ -158,8 +227,112 @@ func (s *pluginAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 		if instance.DeletionTimestamp != nil {
 			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
 			return ctrl.Result{}, nil
 		}
+		if instance.Status.Resource != nil && instance.Status.Resource.RuntimeStatus == crd.RuntimeDeleted {
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+		}
+
+		oldStatus := instance.Status.DeepCopy()
+		taskName := instance.Kind + "." + instance.Name
+		if result, newStatus, err := s.HandleAgentTaskRound(logger, ctx, oldStatus, instance.Spec.Schedule.DeepCopy(), &instance, taskName, instance.Spec.DeepCopy()); err != nil {
+			// requeue
+			logger.Sugar().Errorf("failed to HandleAgentTaskRound, will retry it, error=%v", err)
+			return ctrl.Result{}, err
+
+		} else {
+			if newStatus != nil && !reflect.DeepEqual(newStatus, oldStatus) {
+				instance.Status = *newStatus
+				if err := s.client.Status().Update(ctx, &instance); err != nil {
+					// requeue
+					logger.Sugar().Errorf("failed to update status, will retry it, error=%v", err)
+					return ctrl.Result{}, err
+				}
+				logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+			}
+
+			if result != nil {
+				return *result, nil
+			}
+		}
+
+	case KindNameNetUdp:
+		instance := crd.NetUdp{}
+		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
+			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			if errors.IsNotFound(err) {
+				metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			}
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
+		logger.Sugar().Debugf("reconcile handle %v", instance)
+
+		// filter work agent
+		if instance.Spec.AgentSpec != nil && types.AgentConfig.DefaultAgent {
+			s.logger.Sugar().Debugf("general agent ignore custom agent task %v", req)
+			return ctrl.Result{}, nil
+		}
+
+		if instance.DeletionTimestamp != nil {
+			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			return ctrl.Result{}, nil
+		}
+		if instance.Status.Resource != nil && instance.Status.Resource.RuntimeStatus == crd.RuntimeDeleted {
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+		}
+
+		oldStatus := instance.Status.DeepCopy()
+		taskName := instance.Kind + "." + instance.Name
+		if result, newStatus, err := s.HandleAgentTaskRound(logger, ctx, oldStatus, instance.Spec.Schedule.DeepCopy(), &instance, taskName, instance.Spec.DeepCopy()); err != nil {
+			// requeue
+			logger.Sugar().Errorf("failed to HandleAgentTaskRound, will retry it, error=%v", err)
+			return ctrl.Result{}, err
+
+		} else {
+			if newStatus != nil && !reflect.DeepEqual(newStatus, oldStatus) {
+				instance.Status = *newStatus
+				if err := s.client.Status().Update(ctx, &instance); err != nil {
+					// requeue
+					logger.Sugar().Errorf("failed to update status, will retry it, error=%v", err)
+					return ctrl.Result{}, err
+				}
+				logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+			}
+
+			if result != nil {
+				return *result, nil
+			}
+		}
+
+	case KindNameNetDelay:
+		instance := crd.NetDelay{}
+		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
+			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			if errors.IsNotFound(err) {
+				metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			}
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
+		logger.Sugar().Debugf("reconcile handle %v", instance)
+
+		// filter work agent
+		if instance.Spec.AgentSpec != nil && types.AgentConfig.DefaultAgent {
+			s.logger.Sugar().Debugf("general agent ignore custom agent task %v", req)
+			return ctrl.Result{}, nil
+		}
+
+		if instance.DeletionTimestamp != nil {
+			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+			return ctrl.Result{}, nil
+		}
+		if instance.Status.Resource != nil && instance.Status.Resource.RuntimeStatus == crd.RuntimeDeleted {
+			metrics.DeleteTaskSuccessRate(s.crdKind, req.Name)
+		}
 
 		oldStatus := instance.Status.DeepCopy()
 		taskName := instance.Kind + "." + instance.Name
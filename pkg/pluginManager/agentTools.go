@@ -12,16 +12,20 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
 	systemv1beta1 "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
 	"github.com/kdoctor-io/kdoctor/pkg/taskStatusManager"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
 
@@ -30,64 +34,28 @@ func (s *pluginAgentReconciler) CallPluginImplementRoundTask(logger *zap.Logger,
 	taskRoundName := fmt.Sprintf("%s.round%d", taskName, roundNumber)
 
 	roundDuration := time.Duration(schedulePlan.RoundTimeoutMinute) * time.Minute
-	ctx, cancel := context.WithTimeout(context.Background(), roundDuration)
+
+	// all spans of the round join the trace shared with the controller and the other agents
+	instanceName := strings.TrimPrefix(taskName, s.crdKind+".")
+	var uid k8stypes.UID
+	if m, ok := obj.(metav1.Object); ok {
+		uid = m.GetUID()
+	}
+	roundCtx := tracing.RoundContext(context.Background(), s.crdKind, instanceName, uid, roundNumber)
+	roundCtx, roundSpan := tracing.Tracer().Start(roundCtx, "agent.round", trace.WithAttributes(
+		append(tracing.RoundAttributes(s.crdKind, instanceName, roundNumber),
+			tracing.AttrNode.String(s.localNodeName),
+			tracing.AttrPod.String(types.AgentConfig.PodName),
+		)...))
+
+	ctx, cancel := context.WithTimeout(roundCtx, roundDuration)
 	defer cancel()
 	taskSucceed := make(chan bool)
 	logger.Sugar().Infof("plugin begins to implement, expect deadline %v, ", roundDuration.String())
 	// get task qps
-	var qps int
-	switch s.crdKind {
-	case KindNameAppHttpHealthy:
-		app := obj.(*crd.AppHttpHealthy)
-		qps = app.Spec.Request.QPS
-	case KindNameNetReach:
-		app := obj.(*crd.NetReach)
-		caseNum := 0
-		// Multiple use cases of netreach are executed simultaneously, so qps needs to be multiplied by the number of use cases
-		if *app.Spec.Target.ClusterIP {
-			if *app.Spec.Target.IPv4 {
-				caseNum += 1
-			}
-			if *app.Spec.Target.IPv6 {
-				caseNum += 1
-			}
-		}
-		if *app.Spec.Target.LoadBalancer {
-			if *app.Spec.Target.IPv4 {
-				caseNum += 1
-			}
-			if *app.Spec.Target.IPv6 {
-				caseNum += 1
-			}
-		}
-		if *app.Spec.Target.Endpoint {
-			if *app.Spec.Target.IPv4 {
-				caseNum += 1
-			}
-			if *app.Spec.Target.IPv6 {
-				caseNum += 1
-			}
-		}
-		if *app.Spec.Target.NodePort {
-			if *app.Spec.Target.IPv4 {
-				caseNum += 1
-			}
-			if *app.Spec.Target.IPv6 {
-				caseNum += 1
-			}
-		}
-		if *app.Spec.Target.Ingress {
-			if *app.Spec.Target.IPv4 {
-				caseNum += 1
-			}
-		}
-		qps = app.Spec.Request.QPS * caseNum
-	case KindNameNetdns:
-		app := obj.(*crd.Netdns)
-		qps = app.Spec.Request.QPS
-	}
+	qps := TaskRequestQPS(s.crdKind, obj)
 	beforeQPS := s.runningTaskManager.QpsStats()
-	logger.Sugar().Debugf("Before the current task starts, the total qps of the tasks being executed is AppHttpHealth=%d,NetReach=%d,NetDNS=%d", beforeQPS.AppHttpHealthyQPS, beforeQPS.NetReachQPS, beforeQPS.NetDnsQPS)
+	logger.Sugar().Debugf("Before the current task starts, the total qps of the tasks being executed is AppHttpHealth=%d,NetReach=%d,NetDNS=%d,NetTcp=%d,NetUdp=%d,NetDelay=%d", beforeQPS.AppHttpHealthyQPS, beforeQPS.NetReachQPS, beforeQPS.NetDnsQPS, beforeQPS.NetTcpQPS, beforeQPS.NetUdpQPS, beforeQPS.NetDelayQPS)
 	s.runningTaskManager.SetTask(runningTask.Task{Name: taskName, Kind: s.crdKind, Qps: qps})
 
 	go func() {
@@ -99,7 +67,14 @@ func (s *pluginAgentReconciler) CallPluginImplementRoundTask(logger *zap.Logger,
 			FailedReason:   nil,
 			StartTimeStamp: startTime,
 		}
-		failureReason, report, e := s.plugin.AgentExecuteTask(logger, ctx, obj, s.runningTaskManager)
+		// the span ends when the plugin returns, so it shows how long the plugin stalls after the round timeout
+		executeCtx, executeSpan := tracing.Tracer().Start(ctx, "agent.execute")
+		failureReason, report, e := s.plugin.AgentExecuteTask(logger, executeCtx, obj, s.runningTaskManager)
+		if e != nil {
+			tracing.EndSpanWithError(executeSpan, e)
+		} else {
+			tracing.EndSpan(executeSpan, failureReason)
+		}
 
 		if e != nil {
 			logger.Sugar().Errorf("plugin failed to implement the round task, error=%v", e)
@@ -135,8 +110,15 @@ func (s *pluginAgentReconciler) CallPluginImplementRoundTask(logger *zap.Logger,
 				logger.Sugar().Errorf("failed to set task details to report, error: %v", err)
 			}
 		}
+		// export the round result and the requests of the targets as metrics
+		metrics.RecordAgentReport(s.crdKind, instanceName, msg)
+
+		_, writeSpan := tracing.Tracer().Start(roundCtx, "agent.report.write")
+		var writeErr error
+		defer func() { tracing.EndSpanWithError(writeSpan, writeErr) }()
 
 		if jsongByte, err := json.Marshal(msg); err != nil {
+			writeErr = err
 			logger.Sugar().Errorf("failed to generate round report , marsha json error=%v", err)
 		} else {
 			// print to stdout for human reading
@@ -146,6 +128,7 @@ func (s *pluginAgentReconciler) CallPluginImplementRoundTask(logger *zap.Logger,
 			if s.fm != nil {
 				var out bytes.Buffer
 				if e := json.Indent(&out, jsongByte, "", "\t"); e != nil {
+					writeErr = e
 					logger.Sugar().Errorf("failed to json Indent for report of %v, error=%v", taskRoundName, e)
 				} else {
 					kindName := strings.Split(taskName, ".")[0]
@@ -155,6 +138,7 @@ func (s *pluginAgentReconciler) CallPluginImplementRoundTask(logger *zap.Logger,
 
 					// file name format: fmt.Sprintf("%s_%s_round%d_%s_%s", kindName, taskName, roundNumber, nodeName, suffix)
 					if e := s.fm.WriteTaskFile(kindName, instanceName, roundNumber, s.localNodeName, time.Now().Add(t), out.Bytes()); e != nil {
+						writeErr = e
 						logger.Sugar().Errorf("failed to write report of %v, error=%v", taskRoundName, e)
 					} else {
 						logger.Sugar().Debugf("succeed to write report for %v", taskRoundName)
@@ -169,12 +153,18 @@ func (s *pluginAgentReconciler) CallPluginImplementRoundTask(logger *zap.Logger,
 	case <-ctx.Done():
 		logger.Sugar().Errorf("timeout for getting result from plugin, the round task failed")
 		s.taskRoundData.SetTask(taskRoundName, taskStatusManager.RoundStatusFail)
+		roundSpan.SetAttributes(tracing.AttrRoundResult.String(string(plugintypes.RoundResultFail)))
+		tracing.EndSpan(roundSpan, "implementing timeout")
 	case r := <-taskSucceed:
 		logger.Sugar().Infof("succeed to call plugin to implement round task, succeed=%v", r)
 		if r {
 			s.taskRoundData.SetTask(taskRoundName, taskStatusManager.RoundStatusSucceeded)
+			roundSpan.SetAttributes(tracing.AttrRoundResult.String(string(plugintypes.RoundResultSucceed)))
+			tracing.EndSpan(roundSpan, "")
 		} else {
 			s.taskRoundData.SetTask(taskRoundName, taskStatusManager.RoundStatusFail)
+			roundSpan.SetAttributes(tracing.AttrRoundResult.String(string(plugintypes.RoundResultFail)))
+			tracing.EndSpan(roundSpan, "the round task failed")
 		}
 	}
 
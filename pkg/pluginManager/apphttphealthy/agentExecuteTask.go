@@ -14,10 +14,14 @@ import (
 	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDetect"
 	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadHttp"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
 	"github.com/kdoctor-io/kdoctor/pkg/resource"
 	"github.com/kdoctor-io/kdoctor/pkg/runningTask"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
+	config "github.com/kdoctor-io/kdoctor/pkg/types"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
@@ -105,6 +109,13 @@ func (s *PluginAppHttpHealthy) AgentExecuteTask(logger *zap.Logger, ctx context.
 		Http2:               target.Http2,
 		ExpectStatusCode:    instance.Spec.SuccessCondition.StatusCode,
 		EnableLatencyMetric: instance.Spec.Target.EnableLatencyMetric,
+		MetricTarget: &metrics.TaskTarget{
+			Kind:     v1beta1.AppHttpHealthyTaskName,
+			Task:     instance.Name,
+			Target:   "HttpAppHealthy target",
+			Node:     config.AgentConfig.LocalNodeName,
+			Protocol: metrics.URLProtocol(target.Host),
+		},
 	}
 
 	// https cert
@@ -162,12 +173,37 @@ func (s *PluginAppHttpHealthy) AgentExecuteTask(logger *zap.Logger, ctx context.
 		d.Header = header
 	}
 
-	failureReason, itemReport := SendRequestAndReport(logger, "HttpAppHealthy target", d, successCondition)
-	if len(failureReason) > 0 {
-		finalfailureReason = fmt.Sprintf("test HttpAppHealthy target: %v", failureReason)
+	_, span := tracing.StartTargetSpan(ctx, "HttpAppHealthy target")
+	if detect := instance.Spec.Detect; detect != nil {
+		logger.Sugar().Infof("detect the maximum qps of target: startQPS=%v, stepQPS=%v, maxQPS=%v, stepDuration=%vs", detect.StartQPS, detect.StepQPS, detect.MaxQPS, detect.StepDurationInSecond)
+		task.Detect = loadHttp.HttpDetect(logger, d, &loadHttp.HttpDetectData{
+			Steps: loadDetect.Steps{
+				StartQps:           detect.StartQPS,
+				StepQps:            detect.StepQPS,
+				MaxQps:             detect.MaxQPS,
+				StepDurationSecond: detect.StepDurationInSecond,
+			},
+			SuccessRate: detect.SuccessRate,
+			MaxP99InMs:  detect.MaxP99InMs,
+		})
+		// the round only fails when even the first step breaks the threshold
+		if task.Detect.KneeQPS == 0 {
+			reason := "no step has been run"
+			if task.Detect.BreakReason != nil {
+				reason = *task.Detect.BreakReason
+			}
+			finalfailureReason = fmt.Sprintf("detect HttpAppHealthy target: %v", reason)
+		}
+		task.Detail = []v1beta1.AppHttpHealthyTaskDetail{}
+	} else {
+		failureReason, itemReport := SendRequestAndReport(logger, "HttpAppHealthy target", d, successCondition)
+		if len(failureReason) > 0 {
+			finalfailureReason = fmt.Sprintf("test HttpAppHealthy target: %v", failureReason)
+		}
+		task.Detail = []v1beta1.AppHttpHealthyTaskDetail{itemReport}
 	}
+	tracing.EndSpan(span, finalfailureReason)
 
-	task.Detail = []v1beta1.AppHttpHealthyTaskDetail{itemReport}
 	if len(finalfailureReason) > 0 {
 		logger.Sugar().Errorf("plugin finally failed, %v", finalfailureReason)
 		task.FailureReason = pointer.String(finalfailureReason)
@@ -16,6 +16,7 @@ import (
 
 	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDetect"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
@@ -111,6 +112,37 @@ func (s *PluginAppHttpHealthy) WebhookValidateCreate(logger *zap.Logger, ctx con
 		}
 	}
 
+	// validate detect
+	if r.Spec.Detect != nil {
+		detect := r.Spec.Detect
+		if detect.MaxQPS < detect.StartQPS {
+			s := fmt.Sprintf("HttpAppHealthy %v requires detect.maxQPS %v not smaller than detect.startQPS %v", r.Name, detect.MaxQPS, detect.StartQPS)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if detect.MaxQPS >= types.ControllerConfig.Configmap.AppHttpHealthyRequestMaxQPS {
+			s := fmt.Sprintf("HttpAppHealthy %v requires detect.maxQPS %v bigger than maximum %v", r.Name, detect.MaxQPS, types.ControllerConfig.Configmap.AppHttpHealthyRequestMaxQPS)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if detect.SuccessRate == nil && detect.MaxP99InMs == nil {
+			s := fmt.Sprintf("HttpAppHealthy %v, no threshold specified in the detect", r.Name)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if detect.SuccessRate != nil && (*(detect.SuccessRate) > 1 || *(detect.SuccessRate) < 0) {
+			s := fmt.Sprintf("HttpAppHealthy %v, detect.successRate %v must be between 0 and 1", r.Name, *(detect.SuccessRate))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		stepNumber := (&loadDetect.Steps{StartQps: detect.StartQPS, StepQps: detect.StepQPS, MaxQps: detect.MaxQPS}).StepNumber()
+		if stepNumber*detect.StepDurationInSecond > int(r.Spec.Schedule.RoundTimeoutMinute*60) {
+			s := fmt.Sprintf("HttpAppHealthy %v requires %v detect steps of %vs smaller than Schedule.RoundTimeoutMinute %vm ", r.Name, stepNumber, detect.StepDurationInSecond, r.Spec.Schedule.RoundTimeoutMinute)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
 	// validate target
 	if true {
 		// TODO (ii2day): validate host body method header
@@ -184,6 +216,24 @@ func (s *PluginAppHttpHealthy) WebhookValidateCreate(logger *zap.Logger, ctx con
 		}
 	}
 
+	// validate ReportSinks
+	if true {
+		if err := tools.ValidateReportSinks(r.Spec.ReportSinks); err != nil {
+			s := fmt.Sprintf("AppHttpHealthy %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate Baseline
+	if true {
+		if err := tools.ValidateBaseline(r.Spec.Baseline, r.Spec.Schedule, r.Spec.Target != nil && r.Spec.Target.EnableLatencyMetric); err != nil {
+			s := fmt.Sprintf("AppHttpHealthy %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
 	// validate AgentSpec
 	if true {
 		if r.Spec.AgentSpec != nil {
@@ -196,12 +246,15 @@ func (s *PluginAppHttpHealthy) WebhookValidateCreate(logger *zap.Logger, ctx con
 	return nil
 }
 
-// this will not be called, it is not allowed to modify crd
+// it is not allowed to modify crd, except the suspend of the schedule
 func (s *PluginAppHttpHealthy) WebhookValidateUpdate(logger *zap.Logger, ctx context.Context, oldObj, newObj runtime.Object) error {
 	oldHealthy := oldObj.(*crd.AppHttpHealthy)
 	newHealthy := newObj.(*crd.AppHttpHealthy)
 
-	if !reflect.DeepEqual(oldHealthy.Spec, newHealthy.Spec) {
+	// only the suspend of the schedule is allowed to modify
+	oldSpec := oldHealthy.Spec.DeepCopy()
+	tools.IgnoreScheduleSuspend(oldSpec.Schedule, newHealthy.Spec.Schedule)
+	if !reflect.DeepEqual(*oldSpec, newHealthy.Spec) {
 		return apierrors.NewBadRequest(fmt.Sprintf("it's not allowed to modify AppHttpHealthy %s Spec", oldHealthy.Name))
 	}
 
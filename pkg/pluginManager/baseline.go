@@ -0,0 +1,87 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+)
+
+// the number of the regression reasons kept in the status history, the full list is in the kdoctorreport
+const maxRegressionReasons = 3
+
+// taskStatusOf returns the status of the task object
+func taskStatusOf(obj client.Object) *crd.TaskStatus {
+	switch t := obj.(type) {
+	case *crd.NetReach:
+		return &t.Status
+	case *crd.AppHttpHealthy:
+		return &t.Status
+	case *crd.Netdns:
+		return &t.Status
+	case *crd.NetTcp:
+		return &t.Status
+	case *crd.NetUdp:
+		return &t.Status
+	case *crd.NetDelay:
+		return &t.Status
+	}
+	return nil
+}
+
+func regressionReason(comparison *v1beta1.BaselineComparison) string {
+	reasons := comparison.RegressionReasons
+	if len(reasons) <= maxRegressionReasons {
+		return strings.Join(reasons, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(reasons[:maxRegressionReasons], "; "), len(reasons)-maxRegressionReasons)
+}
+
+// markRoundRegressed records the comparison with the baseline in the status history of the round,
+// and records a Warning event on the task when the round regresses
+func (s *pluginControllerReconciler) markRoundRegressed(obj client.Object, comparison *v1beta1.BaselineComparison) {
+	regressed := comparison.Regressed
+	reason := regressionReason(comparison)
+	key := client.ObjectKeyFromObject(obj)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, ok := obj.DeepCopyObject().(client.Object)
+		if !ok {
+			return fmt.Errorf("unsupported task object %T", obj)
+		}
+		if err := s.client.Get(context.Background(), key, latest); err != nil {
+			return err
+		}
+		status := taskStatusOf(latest)
+		if status == nil {
+			return fmt.Errorf("unsupported task object %T", obj)
+		}
+		for i := range status.History {
+			if int64(status.History[i].RoundNumber) != comparison.RoundNumber {
+				continue
+			}
+			status.History[i].Regressed = &regressed
+			status.History[i].RegressionReason = reason
+			return s.client.Status().Update(context.Background(), latest)
+		}
+		// the record of the round is removed from the history
+		return nil
+	})
+	if err != nil {
+		s.logger.Sugar().Errorf("failed to mark round %v of %s %v with the baseline comparison, error=%v", comparison.RoundNumber, s.crdKind, key.Name, err)
+	}
+
+	if regressed && s.recorder != nil {
+		s.recorder.Event(obj, corev1.EventTypeWarning, crd.TaskReasonRoundRegressed,
+			fmt.Sprintf("round %d regressed from the baseline rounds %v: %s", comparison.RoundNumber, comparison.BaselineRounds, reason))
+	}
+}
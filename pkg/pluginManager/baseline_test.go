@@ -0,0 +1,75 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
+)
+
+var _ = Describe("test baseline", Label("baseline"), func() {
+
+	It("mark the regressed round in the status history", func() {
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+		task := &crd.NetReach{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Status: crd.TaskStatus{
+				History: []crd.StatusHistoryRecord{{RoundNumber: 3}, {RoundNumber: 2}, {RoundNumber: 1}},
+			},
+		}
+		recorder := record.NewFakeRecorder(10)
+		s := &pluginControllerReconciler{
+			client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(task).Build(),
+			logger:   logger.NewStdoutLogger("debug", "pluginManager Test"),
+			crdKind:  KindNameNetReach,
+			recorder: recorder,
+		}
+
+		s.markRoundRegressed(task, &v1beta1.BaselineComparison{
+			RoundNumber:    2,
+			BaselineRounds: []int64{1},
+			Regressed:      true,
+			RegressionReasons: []string{
+				"node1/a: p99 +60.0%", "node1/b: p99 +70.0%", "node2/a: p50 +80.0%", "node2/b: successRate -20.0%",
+			},
+		})
+		latest := &crd.NetReach{}
+		Expect(s.client.Get(context.Background(), client.ObjectKeyFromObject(task), latest)).To(Succeed())
+		record := latest.Status.History[1]
+		Expect(record.Regressed).NotTo(BeNil())
+		Expect(*record.Regressed).To(BeTrue())
+		Expect(record.RegressionReason).To(Equal("node1/a: p99 +60.0%; node1/b: p99 +70.0%; node2/a: p50 +80.0%; and 1 more"))
+		Expect(latest.Status.History[0].Regressed).To(BeNil())
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " " + crd.TaskReasonRoundRegressed + " round 2 regressed")))
+
+		s.markRoundRegressed(task, &v1beta1.BaselineComparison{RoundNumber: 3, BaselineRounds: []int64{1}})
+		Expect(s.client.Get(context.Background(), client.ObjectKeyFromObject(task), latest)).To(Succeed())
+		Expect(*latest.Status.History[0].Regressed).To(BeFalse())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("reject the latency thresholds without the latency metric", func() {
+		baseline := &crd.Baseline{Window: pointer.Int64(3), P99IncreasePercent: pointer.Float64(50)}
+		Expect(tools.ValidateBaseline(baseline, nil, false)).To(MatchError(ContainSubstring("EnableLatencyMetric")))
+		Expect(tools.ValidateBaseline(baseline, nil, true)).To(Succeed())
+
+		baseline = &crd.Baseline{Window: pointer.Int64(3), SuccessRateDecreasePercent: pointer.Float64(5)}
+		Expect(tools.ValidateBaseline(baseline, nil, false)).To(Succeed())
+	})
+})
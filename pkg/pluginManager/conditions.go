@@ -0,0 +1,117 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+)
+
+// latestFinishedRecord returns the latest round which succeeds or fails, the history is in the order of the newest first
+func latestFinishedRecord(status *crd.TaskStatus) *crd.StatusHistoryRecord {
+	for i := range status.History {
+		switch status.History[i].Status {
+		case crd.StatusHistoryRecordStatusSucceed, crd.StatusHistoryRecordStatusFail:
+			return &status.History[i]
+		}
+	}
+	return nil
+}
+
+func roundFailedMessage(record *crd.StatusHistoryRecord) string {
+	msg := fmt.Sprintf("round %d failed", record.RoundNumber)
+	if len(record.FailedAgentNodeList) > 0 {
+		msg += fmt.Sprintf(" on nodes %v", record.FailedAgentNodeList)
+	}
+	if len(record.FailureReason) > 0 {
+		msg += ": " + record.FailureReason
+	}
+	return msg
+}
+
+// updateTaskConditions keeps the Ready, Degraded and Finished conditions by the status of the task
+func updateTaskConditions(status *crd.TaskStatus, generation int64) {
+	var latest *finishedRound
+	if record := latestFinishedRecord(status); record != nil {
+		latest = &finishedRound{roundNumber: record.RoundNumber, succeeded: record.Status == crd.StatusHistoryRecordStatusSucceed, failedMessage: roundFailedMessage(record)}
+	}
+	updateRoundConditions(&status.Conditions, generation, "task", latest, status.DoneRound, status.ExpectedRound, status.Finish)
+}
+
+// finishedRound is the latest round of the task or the suite which succeeds or fails
+type finishedRound struct {
+	roundNumber   int
+	succeeded     bool
+	failedMessage string
+}
+
+// updateRoundConditions keeps the Ready, Degraded and Finished conditions by the latest finished round and the round counters,
+// and the object is the task or the suite in the messages
+func updateRoundConditions(conditions *[]metav1.Condition, generation int64, object string, latest *finishedRound, doneRound, expectedRound *int64, finish bool) {
+	set := func(conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			ObservedGeneration: generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	switch {
+	case latest == nil:
+		msg := fmt.Sprintf("no round of the %s is finished", object)
+		set(crd.TaskConditionReady, metav1.ConditionUnknown, crd.TaskReasonNoRoundFinished, msg)
+		set(crd.TaskConditionDegraded, metav1.ConditionFalse, crd.TaskReasonNoRoundFinished, msg)
+	case latest.succeeded:
+		msg := fmt.Sprintf("round %d succeeded", latest.roundNumber)
+		set(crd.TaskConditionReady, metav1.ConditionTrue, crd.TaskReasonRoundSucceeded, msg)
+		set(crd.TaskConditionDegraded, metav1.ConditionFalse, crd.TaskReasonRoundSucceeded, msg)
+	default:
+		set(crd.TaskConditionReady, metav1.ConditionFalse, crd.TaskReasonRoundFailed, latest.failedMessage)
+		set(crd.TaskConditionDegraded, metav1.ConditionTrue, crd.TaskReasonRoundFailed, latest.failedMessage)
+	}
+
+	var done int64
+	if doneRound != nil {
+		done = *doneRound
+	}
+	if finish {
+		set(crd.TaskConditionFinished, metav1.ConditionTrue, crd.TaskReasonAllRoundsDone, fmt.Sprintf("all %d rounds are done", done))
+	} else if expectedRound != nil && *expectedRound >= 0 {
+		set(crd.TaskConditionFinished, metav1.ConditionFalse, crd.TaskReasonRoundsRemaining, fmt.Sprintf("%d of %d rounds are done", done, *expectedRound))
+	} else {
+		set(crd.TaskConditionFinished, metav1.ConditionFalse, crd.TaskReasonRoundsRemaining, fmt.Sprintf("%d rounds are done, and the %s runs permanently", done, object))
+	}
+}
+
+// recordRoundEvents records the events of the rounds which fail in the new status.
+// It is called after the new status is written, so the reconcile retried for a conflict does not record the event twice
+func (s *pluginControllerReconciler) recordRoundEvents(obj client.Object, oldStatus, newStatus *crd.TaskStatus) {
+	failedRounds := map[int]bool{}
+	for _, record := range oldStatus.History {
+		if record.Status == crd.StatusHistoryRecordStatusFail {
+			failedRounds[record.RoundNumber] = true
+		}
+	}
+	for i := range newStatus.History {
+		if !failedRounds[newStatus.History[i].RoundNumber] {
+			s.recordRoundEvent(obj, &newStatus.History[i])
+		}
+	}
+}
+
+// recordRoundEvent records a Warning event on the task when the round fails
+func (s *pluginControllerReconciler) recordRoundEvent(obj client.Object, record *crd.StatusHistoryRecord) {
+	if s.recorder == nil || record.Status != crd.StatusHistoryRecordStatusFail {
+		return
+	}
+	s.recorder.Event(obj, corev1.EventTypeWarning, crd.TaskReasonRoundFailed, roundFailedMessage(record))
+}
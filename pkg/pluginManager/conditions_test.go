@@ -0,0 +1,80 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+)
+
+var _ = Describe("test task conditions", Label("conditions"), func() {
+
+	It("update the conditions by the status", func() {
+		expected, done := int64(3), int64(0)
+		status := &crd.TaskStatus{
+			ExpectedRound: &expected,
+			DoneRound:     &done,
+			History:       []crd.StatusHistoryRecord{{RoundNumber: 1, Status: crd.StatusHistoryRecordStatusOngoing}},
+		}
+		updateTaskConditions(status, 1)
+		Expect(meta.IsStatusConditionPresentAndEqual(status.Conditions, crd.TaskConditionReady, metav1.ConditionUnknown)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(status.Conditions, crd.TaskConditionDegraded)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(status.Conditions, crd.TaskConditionFinished)).To(BeTrue())
+
+		done = 1
+		status.History = append([]crd.StatusHistoryRecord{{RoundNumber: 2, Status: crd.StatusHistoryRecordStatusOngoing}},
+			crd.StatusHistoryRecord{RoundNumber: 1, Status: crd.StatusHistoryRecordStatusFail, FailedAgentNodeList: []string{"worker1"}, FailureReason: "some agents failed"})
+		updateTaskConditions(status, 1)
+		degraded := meta.FindStatusCondition(status.Conditions, crd.TaskConditionDegraded)
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal(crd.TaskReasonRoundFailed))
+		Expect(degraded.Message).To(Equal("round 1 failed on nodes [worker1]: some agents failed"))
+		Expect(meta.IsStatusConditionFalse(status.Conditions, crd.TaskConditionReady)).To(BeTrue())
+
+		done = 3
+		status.Finish = true
+		status.History[0].Status = crd.StatusHistoryRecordStatusSucceed
+		updateTaskConditions(status, 2)
+		Expect(meta.IsStatusConditionTrue(status.Conditions, crd.TaskConditionReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(status.Conditions, crd.TaskConditionDegraded)).To(BeTrue())
+		finished := meta.FindStatusCondition(status.Conditions, crd.TaskConditionFinished)
+		Expect(finished.Status).To(Equal(metav1.ConditionTrue))
+		Expect(finished.Reason).To(Equal(crd.TaskReasonAllRoundsDone))
+		Expect(finished.ObservedGeneration).To(BeEquivalentTo(2))
+	})
+
+	It("record the event of the failed round", func() {
+		recorder := record.NewFakeRecorder(10)
+		s := &pluginControllerReconciler{recorder: recorder}
+		obj := &crd.NetReach{}
+
+		s.recordRoundEvent(obj, &crd.StatusHistoryRecord{RoundNumber: 1, Status: crd.StatusHistoryRecordStatusSucceed})
+		Expect(recorder.Events).To(BeEmpty())
+
+		s.recordRoundEvent(obj, &crd.StatusHistoryRecord{RoundNumber: 2, Status: crd.StatusHistoryRecordStatusFail, FailedAgentNodeList: []string{"worker1", "worker2"}})
+		Expect(recorder.Events).To(Receive(Equal(corev1.EventTypeWarning + " " + crd.TaskReasonRoundFailed + " round 2 failed on nodes [worker1 worker2]")))
+
+		// only the round which fails in the written status is recorded
+		oldStatus := &crd.TaskStatus{History: []crd.StatusHistoryRecord{
+			{RoundNumber: 2, Status: crd.StatusHistoryRecordStatusOngoing},
+			{RoundNumber: 1, Status: crd.StatusHistoryRecordStatusFail},
+		}}
+		newStatus := &crd.TaskStatus{History: []crd.StatusHistoryRecord{
+			{RoundNumber: 3, Status: crd.StatusHistoryRecordStatusNotstarted},
+			{RoundNumber: 2, Status: crd.StatusHistoryRecordStatusFail, FailedAgentNodeList: []string{"worker1"}},
+			{RoundNumber: 1, Status: crd.StatusHistoryRecordStatusFail},
+		}}
+		s.recordRoundEvents(obj, oldStatus, newStatus)
+		Expect(recorder.Events).To(Receive(Equal(corev1.EventTypeWarning + " " + crd.TaskReasonRoundFailed + " round 2 failed on nodes [worker1]")))
+		Expect(recorder.Events).To(BeEmpty())
+		s.recordRoundEvents(obj, newStatus, newStatus)
+		Expect(recorder.Events).To(BeEmpty())
+	})
+})
@@ -6,6 +6,7 @@ package pluginManager
 import (
 	"context"
 	"fmt"
+	"path"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -18,9 +19,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
 	"github.com/kdoctor-io/kdoctor/pkg/fileManager"
+	"github.com/kdoctor-io/kdoctor/pkg/grpcManager"
 	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
 	"github.com/kdoctor-io/kdoctor/pkg/reportManager"
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
+	"github.com/kdoctor-io/kdoctor/pkg/roundAdmission"
 	"github.com/kdoctor-io/kdoctor/pkg/scheduler"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
@@ -100,8 +104,25 @@ func (s *pluginManager) RunControllerController(healthPort int, webhookPort int,
 		if e != nil {
 			logger.Sugar().Fatalf("failed to new fileManager , reason=%v", e)
 		}
+
+		// reportStore keeps the history of reports for the query of the apiserver
+		storeConfig := reportStore.Config{
+			Path:          path.Join(types.ControllerConfig.DirPathControllerReport, "store", "reports.db"),
+			MaxAge:        time.Duration(types.ControllerConfig.ReportAgeInDay*24) * time.Hour,
+			MaxSizeInMB:   int(types.ControllerConfig.ReportMaxSizeInMB),
+			CleanInterval: gcInterval,
+		}
+		logger.Sugar().Infof("save report history to %v, max size %vMB", storeConfig.Path, storeConfig.MaxSizeInMB)
+		if e := reportStore.InitReportStore(logger.Named("reportStore"), storeConfig); e != nil {
+			logger.Sugar().Fatalf("failed to init reportStore , reason=%v", e)
+		}
 	}
 
+	// the rounds of all tasks share the qps budget of the nodes
+	pollInterval := time.Duration(types.ControllerConfig.Configmap.TaskPollIntervalInSecond) * time.Second
+	admission := roundAdmission.NewQueue(types.ControllerConfig.Configmap.Admission.NodeQPSBudget, 3*pollInterval)
+	logger.Sugar().Infof("admit the rounds with the qps budget %v of each node", types.ControllerConfig.Configmap.Admission.NodeQPSBudget)
+
 	runtimeDB := make(map[string]scheduler.DB, len(s.chainingPlugins))
 	ctx, cancelFunc := context.WithCancel(context.TODO())
 	for name, plugin := range s.chainingPlugins {
@@ -131,6 +152,8 @@ func (s *pluginManager) RunControllerController(healthPort int, webhookPort int,
 			crdKindName:                name,
 			runtimeUniqueMatchLabelKey: uniqueMatchLabelKey,
 			tracker:                    tracker,
+			recorder:                   mgr.GetEventRecorderFor("kdoctor-controller"),
+			admission:                  admission,
 		}
 		k.tracker.Start(ctx)
 		if e := k.SetupWithManager(mgr); e != nil {
@@ -148,11 +171,39 @@ func (s *pluginManager) RunControllerController(healthPort int, webhookPort int,
 		}
 	}
 
+	// the suite creates the tasks of the plugins as its steps
+	suiteReconciler := &taskSuiteReconciler{
+		logger:   logger.Named(KindNameTaskSuite + "Reconciler"),
+		client:   mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("kdoctor-controller"),
+	}
+	if e := suiteReconciler.SetupWithManager(mgr); e != nil {
+		s.logger.Sugar().Fatalf("failed to builder reconcile for %v, error=%v", KindNameTaskSuite, e)
+	}
+	suiteWebhook := &taskSuiteWebhookHander{
+		logger:  logger.Named(KindNameTaskSuite + "Webhook"),
+		plugins: s.chainingPlugins,
+	}
+	if e := suiteWebhook.SetupWebhook(mgr); e != nil {
+		s.logger.Sugar().Fatalf("failed to builder webhook for %v, error=%v", KindNameTaskSuite, e)
+	}
+
 	if types.ControllerConfig.EnableAggregateAgentReport {
 		// reportManager takes charge of sync reports from remote agents
 		interval := time.Duration(types.ControllerConfig.CollectAgentReportIntervalInSecond) * time.Second
 		logger.Sugar().Infof("run report Sync manager, save to %v, collectInterval %v ", types.ControllerConfig.DirPathControllerReport, interval)
-		reportManager.InitReportManager(logger.Named("reportSyncManager"), types.ControllerConfig.DirPathControllerReport, interval, runtimeDB)
+		// the grpc of the agents requires the client certificate of the controller
+		files := grpcManager.TlsFiles{
+			CertPath: types.ControllerConfig.TlsClientCertPath,
+			KeyPath:  types.ControllerConfig.TlsClientKeyPath,
+			CaPath:   types.ControllerConfig.TlsAgentCaPath,
+		}
+		certReloader, e := grpcManager.NewCertReloader(logger.Named("grpcTls"), files, nil)
+		if e != nil {
+			s.logger.Sugar().Fatalf("failed to load the tls of grpc client, error=%v", e)
+		}
+		go certReloader.Run(context.Background(), grpcManager.DefaultCertReloadInterval)
+		reportManager.InitReportManager(logger.Named("reportSyncManager"), types.ControllerConfig.DirPathControllerReport, interval, runtimeDB, certReloader)
 	}
 
 	go func() {
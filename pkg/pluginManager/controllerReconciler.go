@@ -13,6 +13,7 @@ import (
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -20,6 +21,8 @@ import (
 	"github.com/kdoctor-io/kdoctor/pkg/fileManager"
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
 	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"github.com/kdoctor-io/kdoctor/pkg/reportManager"
+	"github.com/kdoctor-io/kdoctor/pkg/roundAdmission"
 	"github.com/kdoctor-io/kdoctor/pkg/scheduler"
 )
 
@@ -34,6 +37,9 @@ type pluginControllerReconciler struct {
 
 	runtimeUniqueMatchLabelKey string
 	tracker                    *scheduler.Tracker
+	recorder                   record.EventRecorder
+	// admits the rounds of the tasks of all kinds
+	admission *roundAdmission.Queue
 }
 
 // controller reconcile
@@ -41,6 +47,14 @@ type pluginControllerReconciler struct {
 // (2) update status result
 // (3) collect report from agent
 func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	// the kdoctorreport of the task is watched, so notify it only after the status is updated or the task is deleted,
+	// and the reconcile which changes nothing does not wake up the watchers
+	statusUpdated := false
+	defer func() {
+		if statusUpdated {
+			reportManager.NotifyReportUpdate(s.crdKind, req.Name)
+		}
+	}()
 
 	// ------ add crd ------
 	switch s.crdKind {
@@ -54,6 +68,10 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 			if errors.IsNotFound(err) && instance.DeletionTimestamp != nil && instance.Spec.AgentSpec != nil {
 				s.tracker.DB.Delete(scheduler.BuildItem(*instance.Status.Resource, KindNameNetReach, instance.Name, nil))
 			}
+			if errors.IsNotFound(err) {
+				s.releaseDeletedTask(req.Name)
+			}
+			statusUpdated = errors.IsNotFound(err)
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
@@ -61,6 +79,8 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 
 		if instance.DeletionTimestamp != nil {
 			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			s.releaseDeletedTask(req.Name)
+			statusUpdated = true
 			return ctrl.Result{}, nil
 		}
 
@@ -77,6 +97,7 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 				logger.Sugar().Errorf("failed to update %s/%s status with resource %v, error: %v", KindNameNetReach, instance.Name, newStatus.Resource, err)
 				return reconcile.Result{}, err
 			}
+			statusUpdated = true
 		}
 
 		// runtime creating status means the agent is not ready, so we don't need to initial the task right now.
@@ -99,7 +120,7 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 
 		oldStatus := instance.Status.DeepCopy()
 		taskName := instance.Kind + "." + instance.Name
-		if result, newStatus, err := s.UpdateStatus(logger, ctx, oldStatus, instance.Spec.Schedule.DeepCopy(), runtimePodMatchLabels, taskName); err != nil {
+		if result, newStatus, err := s.UpdateStatus(logger, ctx, &instance, oldStatus, instance.Spec.Schedule.DeepCopy(), instance.Spec.ReportSinks, instance.Spec.Baseline, runtimePodMatchLabels, taskName); err != nil {
 			// requeue
 			logger.Sugar().Errorf("failed to UpdateStatus, will retry it, error=%v", err)
 			return ctrl.Result{}, err
@@ -113,6 +134,8 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 						return ctrl.Result{}, err
 					}
 					logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+					statusUpdated = true
+					s.recordRoundEvents(&instance, oldStatus, newStatus)
 				}
 
 				// update tracker database
@@ -155,6 +178,10 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 				s.tracker.DB.Delete(scheduler.BuildItem(*instance.Status.Resource, KindNameAppHttpHealthy, instance.Name, nil))
 
 			}
+			if errors.IsNotFound(err) {
+				s.releaseDeletedTask(req.Name)
+			}
+			statusUpdated = errors.IsNotFound(err)
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
@@ -162,6 +189,8 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 
 		if instance.DeletionTimestamp != nil {
 			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			s.releaseDeletedTask(req.Name)
+			statusUpdated = true
 			return ctrl.Result{}, nil
 		}
 
@@ -178,6 +207,7 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 				logger.Sugar().Errorf("failed to update %s/%s status with resource %v, error: %v", KindNameAppHttpHealthy, instance.Name, newStatus.Resource, err)
 				return reconcile.Result{}, err
 			}
+			statusUpdated = true
 		}
 
 		// runtime creating status means the agent is not ready, so we don't need to initial the task right now.
@@ -200,7 +230,7 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 
 		oldStatus := instance.Status.DeepCopy()
 		taskName := instance.Kind + "." + instance.Name
-		if result, newStatus, err := s.UpdateStatus(logger, ctx, oldStatus, instance.Spec.Schedule.DeepCopy(), runtimePodMatchLabels, taskName); err != nil {
+		if result, newStatus, err := s.UpdateStatus(logger, ctx, &instance, oldStatus, instance.Spec.Schedule.DeepCopy(), instance.Spec.ReportSinks, instance.Spec.Baseline, runtimePodMatchLabels, taskName); err != nil {
 			// requeue
 			logger.Sugar().Errorf("failed to UpdateStatus, will retry it, error=%v", err)
 			return ctrl.Result{}, err
@@ -214,6 +244,8 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 						return ctrl.Result{}, err
 					}
 					logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+					statusUpdated = true
+					s.recordRoundEvents(&instance, oldStatus, newStatus)
 				}
 
 				// update tracker database
@@ -255,6 +287,10 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 			if errors.IsNotFound(err) && instance.DeletionTimestamp != nil && instance.Spec.AgentSpec != nil {
 				s.tracker.DB.Delete(scheduler.BuildItem(*instance.Status.Resource, KindNameNetdns, instance.Name, nil))
 			}
+			if errors.IsNotFound(err) {
+				s.releaseDeletedTask(req.Name)
+			}
+			statusUpdated = errors.IsNotFound(err)
 			return ctrl.Result{}, client.IgnoreNotFound(err)
 		}
 		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
@@ -262,6 +298,8 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 
 		if instance.DeletionTimestamp != nil {
 			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			s.releaseDeletedTask(req.Name)
+			statusUpdated = true
 			return ctrl.Result{}, nil
 		}
 
@@ -278,6 +316,7 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 				logger.Sugar().Errorf("failed to update %s/%s status with resource %v, error: %v", KindNameNetdns, instance.Name, newStatus.Resource, err)
 				return reconcile.Result{}, err
 			}
+			statusUpdated = true
 		}
 
 		// runtime creating status means the agent is not ready, so we don't need to initial the task right now.
@@ -300,7 +339,7 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 
 		oldStatus := instance.Status.DeepCopy()
 		taskName := instance.Kind + "." + instance.Name
-		if result, newStatus, err := s.UpdateStatus(logger, ctx, oldStatus, instance.Spec.Schedule.DeepCopy(), runtimePodMatchLabels, taskName); err != nil {
+		if result, newStatus, err := s.UpdateStatus(logger, ctx, &instance, oldStatus, instance.Spec.Schedule.DeepCopy(), instance.Spec.ReportSinks, instance.Spec.Baseline, runtimePodMatchLabels, taskName); err != nil {
 			// requeue
 			logger.Sugar().Errorf("failed to UpdateStatus, will retry it, error=%v", err)
 			return ctrl.Result{}, err
@@ -314,6 +353,8 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 						return ctrl.Result{}, err
 					}
 					logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+					statusUpdated = true
+					s.recordRoundEvents(&instance, oldStatus, newStatus)
 				}
 
 				// update tracker database
@@ -344,6 +385,330 @@ func (s *pluginControllerReconciler) Reconcile(ctx context.Context, req reconcil
 			}
 		}
 
+	case KindNameNetTcp:
+		// ------ add crd ------
+		instance := crd.NetTcp{}
+
+		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
+			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			// since we have OwnerReference for task corresponding runtime and service, we could just delete the tracker DB record directly
+			if errors.IsNotFound(err) && instance.DeletionTimestamp != nil && instance.Spec.AgentSpec != nil {
+				s.tracker.DB.Delete(scheduler.BuildItem(*instance.Status.Resource, KindNameNetTcp, instance.Name, nil))
+			}
+			if errors.IsNotFound(err) {
+				s.releaseDeletedTask(req.Name)
+			}
+			statusUpdated = errors.IsNotFound(err)
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
+		logger.Sugar().Debugf("reconcile handle %v", instance)
+
+		if instance.DeletionTimestamp != nil {
+			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			s.releaseDeletedTask(req.Name)
+			statusUpdated = true
+			return ctrl.Result{}, nil
+		}
+
+		newStatus, err := s.TaskResourceReconcile(ctx, KindNameNetTcp, &instance, instance.Spec.AgentSpec, instance.Status.DeepCopy(), logger)
+		if nil != err {
+			logger.Sugar().Errorf(err.Error())
+			return ctrl.Result{}, err
+		}
+		if !reflect.DeepEqual(newStatus, instance.Status.DeepCopy()) {
+			instance.Status = *newStatus
+			logger.Sugar().Infof("try to update %s/%s status with resource %v", KindNameNetTcp, instance.Name, newStatus.Resource)
+			err := s.client.Status().Update(ctx, &instance)
+			if nil != err {
+				logger.Sugar().Errorf("failed to update %s/%s status with resource %v, error: %v", KindNameNetTcp, instance.Name, newStatus.Resource, err)
+				return reconcile.Result{}, err
+			}
+			statusUpdated = true
+		}
+
+		// runtime creating status means the agent is not ready, so we don't need to initial the task right now.
+		// the tracker DB will update the status asynchronously, and we would receive the task event after it updated.
+		if instance.Status.Resource.RuntimeStatus == crd.RuntimeCreating {
+			return ctrl.Result{}, nil
+		}
+
+		// the task corresponding agent pods have this unique label
+		var runtimePodMatchLabels client.MatchingLabels
+		if instance.Spec.AgentSpec == nil {
+			runtimePodMatchLabels = client.MatchingLabels{
+				scheduler.UniqueMatchLabelKey: types.ControllerConfig.DefaultAgentName,
+			}
+		} else {
+			runtimePodMatchLabels = client.MatchingLabels{
+				s.runtimeUniqueMatchLabelKey: scheduler.UniqueMatchLabelValue(KindNameNetTcp, instance.Name),
+			}
+		}
+
+		oldStatus := instance.Status.DeepCopy()
+		taskName := instance.Kind + "." + instance.Name
+		if result, newStatus, err := s.UpdateStatus(logger, ctx, &instance, oldStatus, instance.Spec.Schedule.DeepCopy(), instance.Spec.ReportSinks, instance.Spec.Baseline, runtimePodMatchLabels, taskName); err != nil {
+			// requeue
+			logger.Sugar().Errorf("failed to UpdateStatus, will retry it, error=%v", err)
+			return ctrl.Result{}, err
+		} else {
+			if newStatus != nil {
+				if !reflect.DeepEqual(newStatus, oldStatus) {
+					instance.Status = *newStatus
+					if err := s.client.Status().Update(ctx, &instance); err != nil {
+						// requeue
+						logger.Sugar().Errorf("failed to update status, will retry it, error=%v", err)
+						return ctrl.Result{}, err
+					}
+					logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+					statusUpdated = true
+					s.recordRoundEvents(&instance, oldStatus, newStatus)
+				}
+
+				// update tracker database
+				var deletionTime *metav1.Time
+				if newStatus.FinishTime != nil && instance.Spec.AgentSpec != nil {
+					deletionTime = newStatus.FinishTime.DeepCopy()
+					if instance.Spec.AgentSpec.TerminationGracePeriodMinutes != nil {
+						newTime := metav1.NewTime(deletionTime.Add(time.Duration(*instance.Spec.AgentSpec.TerminationGracePeriodMinutes) * time.Minute))
+						deletionTime = newTime.DeepCopy()
+					}
+					logger.Sugar().Debugf("task finish time '%s' and runtime deletion time '%s'", newStatus.FinishTime, deletionTime)
+					// record the task resource to the tracker DB, and the tracker will update the task subresource resource status asynchronously
+					err := s.tracker.DB.Apply(scheduler.BuildItem(*instance.Status.Resource, KindNameNetTcp, instance.Name, deletionTime))
+					if nil != err {
+						logger.Error(err.Error())
+						return ctrl.Result{}, err
+					}
+				} else if newStatus.FinishTime != nil && instance.Spec.AgentSpec == nil {
+					err := s.tracker.DB.Apply(scheduler.BuildItem(*instance.Status.Resource, KindNameNetTcp, instance.Name, deletionTime))
+					if nil != err {
+						logger.Error(err.Error())
+						return ctrl.Result{}, err
+					}
+				}
+			}
+			if result != nil {
+				return *result, nil
+			}
+		}
+
+	case KindNameNetUdp:
+		// ------ add crd ------
+		instance := crd.NetUdp{}
+
+		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
+			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			// since we have OwnerReference for task corresponding runtime and service, we could just delete the tracker DB record directly
+			if errors.IsNotFound(err) && instance.DeletionTimestamp != nil && instance.Spec.AgentSpec != nil {
+				s.tracker.DB.Delete(scheduler.BuildItem(*instance.Status.Resource, KindNameNetUdp, instance.Name, nil))
+			}
+			if errors.IsNotFound(err) {
+				s.releaseDeletedTask(req.Name)
+			}
+			statusUpdated = errors.IsNotFound(err)
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
+		logger.Sugar().Debugf("reconcile handle %v", instance)
+
+		if instance.DeletionTimestamp != nil {
+			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			s.releaseDeletedTask(req.Name)
+			statusUpdated = true
+			return ctrl.Result{}, nil
+		}
+
+		newStatus, err := s.TaskResourceReconcile(ctx, KindNameNetUdp, &instance, instance.Spec.AgentSpec, instance.Status.DeepCopy(), logger)
+		if nil != err {
+			logger.Sugar().Errorf(err.Error())
+			return ctrl.Result{}, err
+		}
+		if !reflect.DeepEqual(newStatus, instance.Status.DeepCopy()) {
+			instance.Status = *newStatus
+			logger.Sugar().Infof("try to update %s/%s status with resource %v", KindNameNetUdp, instance.Name, newStatus.Resource)
+			err := s.client.Status().Update(ctx, &instance)
+			if nil != err {
+				logger.Sugar().Errorf("failed to update %s/%s status with resource %v, error: %v", KindNameNetUdp, instance.Name, newStatus.Resource, err)
+				return reconcile.Result{}, err
+			}
+			statusUpdated = true
+		}
+
+		// runtime creating status means the agent is not ready, so we don't need to initial the task right now.
+		// the tracker DB will update the status asynchronously, and we would receive the task event after it updated.
+		if instance.Status.Resource.RuntimeStatus == crd.RuntimeCreating {
+			return ctrl.Result{}, nil
+		}
+
+		// the task corresponding agent pods have this unique label
+		var runtimePodMatchLabels client.MatchingLabels
+		if instance.Spec.AgentSpec == nil {
+			runtimePodMatchLabels = client.MatchingLabels{
+				scheduler.UniqueMatchLabelKey: types.ControllerConfig.DefaultAgentName,
+			}
+		} else {
+			runtimePodMatchLabels = client.MatchingLabels{
+				s.runtimeUniqueMatchLabelKey: scheduler.UniqueMatchLabelValue(KindNameNetUdp, instance.Name),
+			}
+		}
+
+		oldStatus := instance.Status.DeepCopy()
+		taskName := instance.Kind + "." + instance.Name
+		if result, newStatus, err := s.UpdateStatus(logger, ctx, &instance, oldStatus, instance.Spec.Schedule.DeepCopy(), instance.Spec.ReportSinks, instance.Spec.Baseline, runtimePodMatchLabels, taskName); err != nil {
+			// requeue
+			logger.Sugar().Errorf("failed to UpdateStatus, will retry it, error=%v", err)
+			return ctrl.Result{}, err
+		} else {
+			if newStatus != nil {
+				if !reflect.DeepEqual(newStatus, oldStatus) {
+					instance.Status = *newStatus
+					if err := s.client.Status().Update(ctx, &instance); err != nil {
+						// requeue
+						logger.Sugar().Errorf("failed to update status, will retry it, error=%v", err)
+						return ctrl.Result{}, err
+					}
+					logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+					statusUpdated = true
+					s.recordRoundEvents(&instance, oldStatus, newStatus)
+				}
+
+				// update tracker database
+				var deletionTime *metav1.Time
+				if newStatus.FinishTime != nil && instance.Spec.AgentSpec != nil {
+					deletionTime = newStatus.FinishTime.DeepCopy()
+					if instance.Spec.AgentSpec.TerminationGracePeriodMinutes != nil {
+						newTime := metav1.NewTime(deletionTime.Add(time.Duration(*instance.Spec.AgentSpec.TerminationGracePeriodMinutes) * time.Minute))
+						deletionTime = newTime.DeepCopy()
+					}
+					logger.Sugar().Debugf("task finish time '%s' and runtime deletion time '%s'", newStatus.FinishTime, deletionTime)
+					// record the task resource to the tracker DB, and the tracker will update the task subresource resource status asynchronously
+					err := s.tracker.DB.Apply(scheduler.BuildItem(*instance.Status.Resource, KindNameNetUdp, instance.Name, deletionTime))
+					if nil != err {
+						logger.Error(err.Error())
+						return ctrl.Result{}, err
+					}
+				} else if newStatus.FinishTime != nil && instance.Spec.AgentSpec == nil {
+					err := s.tracker.DB.Apply(scheduler.BuildItem(*instance.Status.Resource, KindNameNetUdp, instance.Name, deletionTime))
+					if nil != err {
+						logger.Error(err.Error())
+						return ctrl.Result{}, err
+					}
+				}
+			}
+			if result != nil {
+				return *result, nil
+			}
+		}
+
+	case KindNameNetDelay:
+		// ------ add crd ------
+		instance := crd.NetDelay{}
+
+		if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
+			s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+			// since we have OwnerReference for task corresponding runtime and service, we could just delete the tracker DB record directly
+			if errors.IsNotFound(err) && instance.DeletionTimestamp != nil && instance.Spec.AgentSpec != nil {
+				s.tracker.DB.Delete(scheduler.BuildItem(*instance.Status.Resource, KindNameNetDelay, instance.Name, nil))
+			}
+			if errors.IsNotFound(err) {
+				s.releaseDeletedTask(req.Name)
+			}
+			statusUpdated = errors.IsNotFound(err)
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		logger := s.logger.With(zap.String(instance.Kind, instance.Name))
+		logger.Sugar().Debugf("reconcile handle %v", instance)
+
+		if instance.DeletionTimestamp != nil {
+			s.logger.Sugar().Debugf("ignore deleting task %v", req)
+			s.releaseDeletedTask(req.Name)
+			statusUpdated = true
+			return ctrl.Result{}, nil
+		}
+
+		newStatus, err := s.TaskResourceReconcile(ctx, KindNameNetDelay, &instance, instance.Spec.AgentSpec, instance.Status.DeepCopy(), logger)
+		if nil != err {
+			logger.Sugar().Errorf(err.Error())
+			return ctrl.Result{}, err
+		}
+		if !reflect.DeepEqual(newStatus, instance.Status.DeepCopy()) {
+			instance.Status = *newStatus
+			logger.Sugar().Infof("try to update %s/%s status with resource %v", KindNameNetDelay, instance.Name, newStatus.Resource)
+			err := s.client.Status().Update(ctx, &instance)
+			if nil != err {
+				logger.Sugar().Errorf("failed to update %s/%s status with resource %v, error: %v", KindNameNetDelay, instance.Name, newStatus.Resource, err)
+				return reconcile.Result{}, err
+			}
+			statusUpdated = true
+		}
+
+		// runtime creating status means the agent is not ready, so we don't need to initial the task right now.
+		// the tracker DB will update the status asynchronously, and we would receive the task event after it updated.
+		if instance.Status.Resource.RuntimeStatus == crd.RuntimeCreating {
+			return ctrl.Result{}, nil
+		}
+
+		// the task corresponding agent pods have this unique label
+		var runtimePodMatchLabels client.MatchingLabels
+		if instance.Spec.AgentSpec == nil {
+			runtimePodMatchLabels = client.MatchingLabels{
+				scheduler.UniqueMatchLabelKey: types.ControllerConfig.DefaultAgentName,
+			}
+		} else {
+			runtimePodMatchLabels = client.MatchingLabels{
+				s.runtimeUniqueMatchLabelKey: scheduler.UniqueMatchLabelValue(KindNameNetDelay, instance.Name),
+			}
+		}
+
+		oldStatus := instance.Status.DeepCopy()
+		taskName := instance.Kind + "." + instance.Name
+		if result, newStatus, err := s.UpdateStatus(logger, ctx, &instance, oldStatus, instance.Spec.Schedule.DeepCopy(), instance.Spec.ReportSinks, instance.Spec.Baseline, runtimePodMatchLabels, taskName); err != nil {
+			// requeue
+			logger.Sugar().Errorf("failed to UpdateStatus, will retry it, error=%v", err)
+			return ctrl.Result{}, err
+		} else {
+			if newStatus != nil {
+				if !reflect.DeepEqual(newStatus, oldStatus) {
+					instance.Status = *newStatus
+					if err := s.client.Status().Update(ctx, &instance); err != nil {
+						// requeue
+						logger.Sugar().Errorf("failed to update status, will retry it, error=%v", err)
+						return ctrl.Result{}, err
+					}
+					logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+					statusUpdated = true
+					s.recordRoundEvents(&instance, oldStatus, newStatus)
+				}
+
+				// update tracker database
+				var deletionTime *metav1.Time
+				if newStatus.FinishTime != nil && instance.Spec.AgentSpec != nil {
+					deletionTime = newStatus.FinishTime.DeepCopy()
+					if instance.Spec.AgentSpec.TerminationGracePeriodMinutes != nil {
+						newTime := metav1.NewTime(deletionTime.Add(time.Duration(*instance.Spec.AgentSpec.TerminationGracePeriodMinutes) * time.Minute))
+						deletionTime = newTime.DeepCopy()
+					}
+					logger.Sugar().Debugf("task finish time '%s' and runtime deletion time '%s'", newStatus.FinishTime, deletionTime)
+					// record the task resource to the tracker DB, and the tracker will update the task subresource resource status asynchronously
+					err := s.tracker.DB.Apply(scheduler.BuildItem(*instance.Status.Resource, KindNameNetDelay, instance.Name, deletionTime))
+					if nil != err {
+						logger.Error(err.Error())
+						return ctrl.Result{}, err
+					}
+				} else if newStatus.FinishTime != nil && instance.Spec.AgentSpec == nil {
+					err := s.tracker.DB.Apply(scheduler.BuildItem(*instance.Status.Resource, KindNameNetDelay, instance.Name, deletionTime))
+					if nil != err {
+						logger.Error(err.Error())
+						return ctrl.Result{}, err
+					}
+				}
+			}
+			if result != nil {
+				return *result, nil
+			}
+		}
+
 	default:
 		s.logger.Sugar().Fatalf("unknown crd type , support kind=%v, detail=%+v", s.crdKind, req)
 	}
@@ -0,0 +1,83 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/reportManager"
+	"github.com/kdoctor-io/kdoctor/pkg/roundAdmission"
+)
+
+var _ = Describe("test controller reconciler", Label("controllerReconciler"), func() {
+
+	It("notify the report watchers when the task is deleted", func() {
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+		now := metav1.Now()
+		deleting := &crd.Netdns{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleting", DeletionTimestamp: &now, Finalizers: []string{"kdoctor.io/test"}},
+		}
+		s := &pluginControllerReconciler{
+			client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(deleting).Build(),
+			logger:  logger.NewStdoutLogger("debug", "pluginManager Test"),
+			crdKind: KindNameNetdns,
+		}
+		ctx := context.Background()
+
+		for _, name := range []string{"deleted", "deleting"} {
+			revision := reportManager.CurrentRevision()
+			_, err := s.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reportManager.UpdatesSince(revision)).To(ConsistOf(HaveField("TaskName", name)))
+		}
+	})
+
+	It("release the running round of the task when the task is deleted", func() {
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+		now := metav1.Now()
+		deleting := &crd.Netdns{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleting", DeletionTimestamp: &now, Finalizers: []string{"kdoctor.io/test"}},
+		}
+		admission := roundAdmission.NewQueue(0, time.Minute)
+		s := &pluginControllerReconciler{
+			client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(deleting).Build(),
+			logger:    logger.NewStdoutLogger("debug", "pluginManager Test"),
+			crdKind:   KindNameNetdns,
+			admission: admission,
+		}
+		ctx := context.Background()
+
+		for _, item := range []struct {
+			name   string
+			policy string
+		}{
+			{name: "deleted", policy: roundAdmission.PolicyQueue},
+			{name: "deleting", policy: roundAdmission.PolicyForbid},
+		} {
+			task := KindNameNetdns + "." + item.name
+			admission.Resume(roundAdmission.Round{Task: task, Policy: item.policy, Deadline: time.Now().Add(time.Hour)}, time.Now())
+			other := roundAdmission.Round{Task: KindNameNetReach + ".other", Deadline: time.Now().Add(time.Hour)}
+			Expect(admission.Admit(other, time.Now()).Admitted).To(BeFalse())
+
+			_, err := s.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: item.name}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(admission.IsRunning(task)).To(BeFalse())
+			Expect(admission.Admit(other, time.Now()).Admitted).To(BeTrue())
+			admission.Release(other.Task)
+		}
+	})
+})
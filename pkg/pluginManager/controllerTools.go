@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,14 +20,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
 	"github.com/kdoctor-io/kdoctor/pkg/reportManager"
 	"github.com/kdoctor-io/kdoctor/pkg/scheduler"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
 
-func (s *pluginControllerReconciler) GetSpiderAgentNodeNotInRecord(ctx context.Context, succeedNodeList []string, podMatchLabel client.MatchingLabels) ([]string, error) {
-	allNodeList, failNodeList := []string{}, []string{}
+func (s *pluginControllerReconciler) GetSpiderAgentNodeList(ctx context.Context, podMatchLabel client.MatchingLabels) ([]string, error) {
+	allNodeList := []string{}
 
 	podList := corev1.PodList{}
 
@@ -50,6 +54,17 @@ func (s *pluginControllerReconciler) GetSpiderAgentNodeNotInRecord(ctx context.C
 	allNodeList = RemoveDuplicates[string](allNodeList)
 	s.logger.Sugar().Debugf("all agent nodes: %v", allNodeList)
 
+	return allNodeList, nil
+}
+
+func (s *pluginControllerReconciler) GetSpiderAgentNodeNotInRecord(ctx context.Context, succeedNodeList []string, podMatchLabel client.MatchingLabels) ([]string, error) {
+	failNodeList := []string{}
+
+	allNodeList, err := s.GetSpiderAgentNodeList(ctx, podMatchLabel)
+	if nil != err {
+		return nil, err
+	}
+
 	// gather the failure Node list
 	failNodeList = slices.Filter(failNodeList, allNodeList, func(s string) bool {
 		return !slices.Contains(succeedNodeList, s)
@@ -120,10 +135,12 @@ func (s *pluginControllerReconciler) UpdateRoundFinalStatus(logger *zap.Logger,
 	return true, nil
 }
 
-func (s *pluginControllerReconciler) WriteSummaryReport(taskName string, roundNumber int, newStatus *crd.TaskStatus) {
+func (s *pluginControllerReconciler) WriteSummaryReport(ctx context.Context, obj client.Object, taskName string, roundNumber int, newStatus *crd.TaskStatus, reportSinks []crd.ReportSink, baseline *crd.Baseline) {
 	if s.fm == nil {
 		return
 	}
+	_, span := tracing.Tracer().Start(ctx, "controller.report.summary", trace.WithAttributes(roundAttributes(taskName, roundNumber)...))
+	defer span.End()
 
 	kindName := strings.Split(taskName, ".")[0]
 	instanceName := strings.TrimPrefix(taskName, kindName+".")
@@ -131,8 +148,15 @@ func (s *pluginControllerReconciler) WriteSummaryReport(taskName string, roundNu
 	endTime := newStatus.History[0].StartTimeStamp.Add(t)
 
 	if !s.fm.CheckTaskFileExisted(kindName, instanceName, roundNumber) {
-		// add to workqueue to collect all report of last round, for node latestRecord.FailedAgentNodeList and latestRecord.SucceedAgentNodeList
-		reportManager.TriggerSyncReport(fmt.Sprintf("%s.%d", taskName, roundNumber))
+		// push the round summary to the report sinks, after all report of last round are collected
+		reportManager.RegisterRoundSinks(fmt.Sprintf("%s.%d", taskName, roundNumber), newStatus.History[0], reportSinks)
+		// compare the round with the baseline, after all report of last round are collected
+		reportManager.RegisterRoundBaseline(fmt.Sprintf("%s.%d", taskName, roundNumber), baseline, func(comparison *v1beta1.BaselineComparison) {
+			s.markRoundRegressed(obj, comparison)
+		})
+		// the collection of the reports joins the trace of the round
+		reportManager.RegisterRoundTrace(fmt.Sprintf("%s.%d", taskName, roundNumber), trace.SpanContextFromContext(ctx))
+		metrics.RecordRoundResult(kindName, instanceName, newStatus.History[0].Status)
 		// TODO (Icarus9913): change to use v1beta1.Report ?
 		// write controller summary report
 		msg := plugintypes.PluginReport{
@@ -165,20 +189,41 @@ func (s *pluginControllerReconciler) WriteSummaryReport(taskName string, roundNu
 					s.logger.Sugar().Errorf("failed to generate round summary report for kind %v task %v round %v, write file error=%v", kindName, instanceName, roundNumber, e)
 				} else {
 					s.logger.Sugar().Debugf("succeeded to generate round summary report for kind %v task %v round %v", kindName, instanceName, roundNumber)
+					if e := reportManager.SaveReportToStore(kindName, instanceName, roundNumber, "summary", jsongByte); e != nil {
+						s.logger.Sugar().Errorf("failed to save round summary report for kind %v task %v round %v to store, error=%v", kindName, instanceName, roundNumber, e)
+					}
 				}
 			}
 		}
+
+		// add to workqueue to collect all report of last round, for node latestRecord.FailedAgentNodeList and latestRecord.SucceedAgentNodeList.
+		// It is triggered after the summary is written, so the collection, the sinks and the export of the round could read the summary
+		reportManager.TriggerSyncReport(fmt.Sprintf("%s.%d", taskName, roundNumber))
 	}
 }
 
-func (s *pluginControllerReconciler) UpdateStatus(logger *zap.Logger, ctx context.Context, oldStatus *crd.TaskStatus, schedulePlan *crd.SchedulePlan, runtimePodMatchLabels client.MatchingLabels, taskName string) (result *reconcile.Result, taskStatus *crd.TaskStatus, e error) {
+func (s *pluginControllerReconciler) UpdateStatus(logger *zap.Logger, ctx context.Context, obj client.Object, oldStatus *crd.TaskStatus, schedulePlan *crd.SchedulePlan, reportSinks []crd.ReportSink, baseline *crd.Baseline, runtimePodMatchLabels client.MatchingLabels, taskName string) (result *reconcile.Result, taskStatus *crd.TaskStatus, e error) {
 	newStatus := oldStatus.DeepCopy()
+	scheduler, e := NewSchedule(schedulePlan)
+	if e != nil {
+		logger.Sugar().Errorf("failed to parse the schedule of task %v, error=%v", taskName, e)
+		return nil, nil, e
+	}
+	defer func() {
+		if taskStatus != nil {
+			var latestRecord *crd.StatusHistoryRecord
+			if len(taskStatus.History) > 0 {
+				latestRecord = &taskStatus.History[0]
+			}
+			taskStatus.NextRoundTimes = plannedRoundTimes(scheduler, schedulePlan, taskStatus.Finish, latestRecord, taskStatus.ExpectedRound, taskStatus.DoneRound)
+			updateTaskConditions(taskStatus, obj.GetGeneration())
+		}
+	}()
 	nextInterval := time.Duration(types.ControllerConfig.Configmap.TaskPollIntervalInSecond) * time.Second
 	nowTime := time.Now()
 	var startTime time.Time
 
 	// init new instance first
-	scheduler := NewSchedule(*schedulePlan.Schedule)
 	if newStatus.ExpectedRound == nil || len(newStatus.History) == 0 {
 		startTime = scheduler.StartTime(nowTime)
 		m := int64(0)
@@ -196,6 +241,11 @@ func (s *pluginControllerReconciler) UpdateStatus(logger *zap.Logger, ctx contex
 		return result, newStatus, nil
 	}
 
+	// the run-now annotation adds a manual round, even if all rounds are done
+	if s.runNow(logger, obj, newStatus, schedulePlan, nowTime) {
+		return &reconcile.Result{Requeue: true}, newStatus, nil
+	}
+
 	// done task
 	if *newStatus.DoneRound == *newStatus.ExpectedRound {
 		return nil, nil, nil
@@ -206,16 +256,33 @@ func (s *pluginControllerReconciler) UpdateStatus(logger *zap.Logger, ctx contex
 	logger.Sugar().Debugf("current time:%v , latest history record: %+v", nowTime, latestRecord)
 	logger.Sugar().Debugf("all history record: %+v", newStatus.History)
 
+	// the suspended task does not start the scheduled rounds, but the manual rounds
+	if scheduleSuspended(schedulePlan) && latestRecord.Status == crd.StatusHistoryRecordStatusNotstarted && !latestRecord.Manual {
+		suspendRound(latestRecord, scheduler, schedulePlan, nowTime)
+		logger.Sugar().Debugf("task %v is suspended, round %v waits until %v", taskName, roundNumber, latestRecord.StartTimeStamp)
+		return &reconcile.Result{RequeueAfter: time.Until(latestRecord.StartTimeStamp.Time)}, newStatus, nil
+	}
+
+	// the round waits for the admission when it is about to start
+	if s.admission != nil && latestRecord.Status == crd.StatusHistoryRecordStatusNotstarted && !nowTime.Before(latestRecord.StartTimeStamp.Time) {
+		if result, e := s.admitRound(logger, ctx, obj, newStatus, scheduler, schedulePlan, runtimePodMatchLabels, taskName, nowTime); e != nil || result != nil {
+			return result, newStatus, e
+		}
+	}
+
 	switch {
 	case nowTime.After(latestRecord.StartTimeStamp.Time) && nowTime.Before(latestRecord.DeadLineTimeStamp.Time):
 		if latestRecord.Status == crd.StatusHistoryRecordStatusNotstarted {
 			latestRecord.Status = crd.StatusHistoryRecordStatusOngoing
+			traceRoundScheduled(ctx, obj, taskName, latestRecord)
 			// requeue immediately to make sure the update succeed , not conflicted
 			result = &reconcile.Result{
 				Requeue: true,
 			}
 
 		} else if latestRecord.Status == crd.StatusHistoryRecordStatusOngoing {
+			// the ongoing round is running after the controller restarts
+			s.resumeRound(ctx, obj, schedulePlan, runtimePodMatchLabels, latestRecord)
 			logger.Debug("try to poll the status of task " + taskName)
 			roundDone, e := s.UpdateRoundFinalStatus(logger, ctx, newStatus, runtimePodMatchLabels, false)
 			if e != nil {
@@ -226,30 +293,13 @@ func (s *pluginControllerReconciler) UpdateStatus(logger *zap.Logger, ctx contex
 				logger.Sugar().Infof("round %v get reports from all agents ", roundNumber)
 
 				// before insert new record, write summary of last round
-				s.WriteSummaryReport(taskName, roundNumber, newStatus)
+				roundCtx, roundSpan := startRoundSpan(ctx, obj, taskName, latestRecord)
+				s.WriteSummaryReport(roundCtx, obj, taskName, roundNumber, newStatus, reportSinks, baseline)
+				endRoundSpan(roundSpan, latestRecord)
+				s.releaseRound(obj)
 
 				// add new round record
-				if *(newStatus.DoneRound) < *(newStatus.ExpectedRound) || *newStatus.ExpectedRound == -1 {
-					n := *(newStatus.DoneRound) + 1
-					newStatus.DoneRound = &n
-					startTime = scheduler.Next(latestRecord.StartTimeStamp.Time)
-					if n < *(newStatus.ExpectedRound) || *newStatus.ExpectedRound == -1 {
-
-						newRecord := NewStatusHistoryRecord(startTime, int(n+1), schedulePlan)
-
-						tmp := append([]crd.StatusHistoryRecord{*newRecord}, newStatus.History...)
-						if len(tmp) > types.ControllerConfig.Configmap.CrdMaxHistory {
-							tmp = tmp[:(types.ControllerConfig.Configmap.CrdMaxHistory)]
-						}
-						newStatus.History = tmp
-
-						logger.Sugar().Infof("insert new record for next round : %+v", *newRecord)
-					} else {
-						newStatus.Finish = true
-						now := metav1.Now()
-						newStatus.FinishTime = &now
-					}
-				}
+				insertNextRound(logger, newStatus, scheduler, schedulePlan)
 
 				// requeue immediately to make sure the update succeed , not conflicted
 				result = &reconcile.Result{
@@ -293,29 +343,13 @@ func (s *pluginControllerReconciler) UpdateStatus(logger *zap.Logger, ctx contex
 					logger.Sugar().Infof("round %v got reports from all agents, try to summarize", roundNumber)
 
 					// before insert new record, write summary of last round
-					s.WriteSummaryReport(taskName, roundNumber, newStatus)
+					roundCtx, roundSpan := startRoundSpan(ctx, obj, taskName, latestRecord)
+					s.WriteSummaryReport(roundCtx, obj, taskName, roundNumber, newStatus, reportSinks, baseline)
+					endRoundSpan(roundSpan, latestRecord)
+					s.releaseRound(obj)
 
 					// add new round record
-					if *(newStatus.DoneRound) < *(newStatus.ExpectedRound) || *newStatus.ExpectedRound == -1 {
-						n := *(newStatus.DoneRound) + 1
-						newStatus.DoneRound = &n
-
-						if n < *(newStatus.ExpectedRound) || *newStatus.ExpectedRound == -1 {
-							startTime = scheduler.Next(latestRecord.StartTimeStamp.Time)
-							newRecord := NewStatusHistoryRecord(startTime, int(n+1), schedulePlan)
-							tmp := append([]crd.StatusHistoryRecord{*newRecord}, newStatus.History...)
-							if len(tmp) > types.ControllerConfig.Configmap.CrdMaxHistory {
-								tmp = tmp[:(types.ControllerConfig.Configmap.CrdMaxHistory)]
-							}
-							newStatus.History = tmp
-
-							logger.Sugar().Infof("insert new record for next round : %+v", *newRecord)
-						} else {
-							newStatus.Finish = true
-							now := metav1.Now()
-							newStatus.FinishTime = &now
-						}
-					}
+					insertNextRound(logger, newStatus, scheduler, schedulePlan)
 
 					// requeue immediately to make sure the update succeed , not conflicted
 					result = &reconcile.Result{
@@ -378,6 +412,11 @@ func (s *pluginControllerReconciler) TaskResourceReconcile(ctx context.Context,
 	}
 
 	taskStatus.Resource = &resource
+	if resource.RuntimeStatus == crd.RuntimeDeleted {
+		metrics.DeleteRuntimeReady(taskKind, ownerTask.GetName(), resource.RuntimeType)
+	} else {
+		metrics.SetRuntimeReady(taskKind, ownerTask.GetName(), resource.RuntimeType, resource.RuntimeStatus == crd.RuntimeCreated)
+	}
 
 	// record the task resource to the tracker DB, and the tracker will update the task subresource resource status or delete corresponding runtime asynchronously
 	err = s.tracker.DB.Apply(scheduler.BuildItem(resource, taskKind, ownerTask.GetName(), deletionTime))
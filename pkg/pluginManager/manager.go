@@ -6,8 +6,11 @@ package pluginManager
 import (
 	"github.com/kdoctor-io/kdoctor/pkg/lock"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/apphttphealthy"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/netdelay"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/netdns"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/netreach"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/nettcp"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/netudp"
 	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
 	"go.uber.org/zap"
 )
@@ -41,6 +44,10 @@ const (
 	KindNameAppHttpHealthy = "AppHttpHealthy"
 	KindNameNetReach       = "NetReach"
 	KindNameNetdns         = "Netdns"
+	KindNameNetTcp         = "NetTcp"
+	KindNameNetUdp         = "NetUdp"
+	KindNameNetDelay       = "NetDelay"
+	KindNameTaskSuite      = "TaskSuite"
 )
 
 func init() {
@@ -52,5 +59,8 @@ func init() {
 	globalPluginManager.chainingPlugins[KindNameAppHttpHealthy] = &apphttphealthy.PluginAppHttpHealthy{}
 	globalPluginManager.chainingPlugins[KindNameNetReach] = &netreach.PluginNetReach{}
 	globalPluginManager.chainingPlugins[KindNameNetdns] = &netdns.PluginNetDns{}
+	globalPluginManager.chainingPlugins[KindNameNetTcp] = &nettcp.PluginNetTcp{}
+	globalPluginManager.chainingPlugins[KindNameNetUdp] = &netudp.PluginNetUdp{}
+	globalPluginManager.chainingPlugins[KindNameNetDelay] = &netdelay.PluginNetDelay{}
 
 }
@@ -0,0 +1,234 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package netdelay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadTcp"
+	"github.com/kdoctor-io/kdoctor/pkg/lock"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"github.com/kdoctor-io/kdoctor/pkg/resource"
+	"github.com/kdoctor-io/kdoctor/pkg/runningTask"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
+	config "github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+func ParseSuccessCondition(successCondition *crd.NetDelaySuccessCondition, metricResult *v1beta1.TcpMetrics) (failureReason string) {
+	switch {
+	case metricResult.RequestCounts == 0:
+		failureReason = "no request has been sent"
+	case successCondition.SuccessRate != nil && float64(metricResult.SuccessCounts)/float64(metricResult.RequestCounts) < *(successCondition.SuccessRate):
+		failureReason = fmt.Sprintf("Success Rate %v is lower than request %v", float64(metricResult.SuccessCounts)/float64(metricResult.RequestCounts), *(successCondition.SuccessRate))
+	case successCondition.MaxPairP50InMs != nil && metricResult.ConnectLatencies.P50 > float32(*(successCondition.MaxPairP50InMs)):
+		failureReason = fmt.Sprintf("p50 delay %v ms is bigger than request %v ms", metricResult.ConnectLatencies.P50, *(successCondition.MaxPairP50InMs))
+	case successCondition.MaxPairP99InMs != nil && metricResult.ConnectLatencies.P99 > float32(*(successCondition.MaxPairP99InMs)):
+		failureReason = fmt.Sprintf("p99 delay %v ms is bigger than request %v ms", metricResult.ConnectLatencies.P99, *(successCondition.MaxPairP99InMs))
+	case metricResult.ExistsNotSendRequests:
+		failureReason = "There are unsent requests after the execution time has been reached"
+	default:
+		failureReason = ""
+	}
+	return
+}
+
+func SendRequestAndReport(logger *zap.Logger, t TestTarget, req *loadTcp.TcpRequestData, successCondition *crd.NetDelaySuccessCondition) (failureReason string, report v1beta1.NetDelayTaskDetail) {
+	report.SourceNode = config.AgentConfig.LocalNodeName
+	report.TargetNode = t.NodeName
+	report.TargetName = t.Name
+	report.TargetAddress = req.ServerAddr
+
+	result, err := loadTcp.TcpRequest(logger, req)
+	if err != nil {
+		logger.Sugar().Errorf("internal error for target %v, error=%v", req.ServerAddr, err)
+		failureReason = err.Error()
+		report.FailureReason = pointer.String(failureReason)
+		return
+	}
+
+	report.P50 = result.ConnectLatencies.P50
+	report.P99 = result.ConnectLatencies.P99
+	if result.RequestCounts > 0 {
+		report.SucceedRate = float64(result.SuccessCounts) / float64(result.RequestCounts)
+	}
+
+	failureReason = ParseSuccessCondition(successCondition, result)
+
+	// generate report
+	// notice , upper case for first character of key, or else fail to parse json
+	report.Metrics = *result
+	if len(failureReason) == 0 {
+		report.FailureReason = nil
+		report.Succeed = true
+		logger.Sugar().Infof("succeed to test %v", req.ServerAddr)
+	} else {
+		report.FailureReason = pointer.String(failureReason)
+		report.Succeed = false
+		logger.Sugar().Warnf("failed to test %v", req.ServerAddr)
+	}
+
+	return
+}
+
+type TestTarget struct {
+	Name     string
+	NodeName string
+	Addr     string
+}
+
+func (s *PluginNetDelay) AgentExecuteTask(logger *zap.Logger, ctx context.Context, obj runtime.Object, rt *runningTask.RunningTask) (finalfailureReason string, finalReport types.Task, err error) {
+	// process mem cpu stats
+	resourceStats := resource.InitResource(ctx)
+	resourceStats.RunResourceCollector()
+
+	finalfailureReason = ""
+	err = nil
+
+	instance, ok := obj.(*crd.NetDelay)
+	if !ok {
+		msg := "failed to get instance"
+		logger.Error(msg)
+		err = errors.New(msg)
+		return
+	}
+
+	logger.Sugar().Infof("plugin implement task round, instance=%+v", instance)
+
+	target := instance.Spec.Target
+	request := instance.Spec.Request
+	successCondition := instance.Spec.SuccessCondition
+	runtimeResource := instance.Status.Resource
+
+	testTargetList := []*TestTarget{}
+
+	// probe all the other agents, and the round-trip time is measured by the tcp handshake with the app tcp server
+	logger.Sugar().Infof("load test the other kdoctor Agent pods: qps=%v, PerRequestTimeout=%vms, Duration=%vs", request.QPS, request.PerRequestTimeoutInMS, request.DurationInSecond)
+	finalfailureReason = ""
+
+	agentPort := strconv.Itoa(int(config.AgentConfig.AppTcpPort))
+	podNodes, podIPs, e := getPeerPods(ctx, runtimeResource.RuntimeName, *target.MultusInterface)
+	if e != nil {
+		logger.Sugar().Errorf("failed to get agent pod ip, error=%v", e)
+		finalfailureReason = fmt.Sprintf("failed to get agent pod ip, error=%v", e)
+	} else {
+		logger.Sugar().Debugf("test agent pod ip: %v", podIPs)
+		for podname, ips := range podIPs {
+			nodeName, ok := podNodes[podname]
+			if !ok || nodeName == config.AgentConfig.LocalNodeName {
+				continue
+			}
+			for _, podips := range ips {
+				if len(podips.IPv4) > 0 && (target.IPv4 == nil || (target.IPv4 != nil && *target.IPv4)) {
+					testTargetList = append(testTargetList, &TestTarget{
+						Name:     "AgentPodV4IP_" + podname + "_" + podips.IPv4,
+						NodeName: nodeName,
+						Addr:     net.JoinHostPort(podips.IPv4, agentPort),
+					})
+				}
+				if len(podips.IPv6) > 0 && (target.IPv6 == nil || (target.IPv6 != nil && *target.IPv6)) {
+					testTargetList = append(testTargetList, &TestTarget{
+						Name:     "AgentPodV6IP_" + podname + "_" + podips.IPv6,
+						NodeName: nodeName,
+						Addr:     net.JoinHostPort(podips.IPv6, agentPort),
+					})
+				}
+			}
+		}
+	}
+
+	// ------------------------ implement for all the other agents
+	reportList := make([]v1beta1.NetDelayTaskDetail, 0, len(testTargetList))
+
+	var wg sync.WaitGroup
+	var l lock.Mutex
+	for _, item := range testTargetList {
+		wg.Add(1)
+		go func(wg *sync.WaitGroup, l *lock.Mutex, t TestTarget) {
+			d := &loadTcp.TcpRequestData{
+				ServerAddr:            t.Addr,
+				PerRequestTimeoutInMs: request.PerRequestTimeoutInMS,
+				Qps:                   request.QPS,
+				DurationInSecond:      request.DurationInSecond,
+				// only connect, the round-trip time is the duration of tcp handshake
+				PayloadSizeInByte: 0,
+				// the percentiles are required for the matrix
+				EnableLatencyMetric: true,
+			}
+			logger.Sugar().Debugf("implement test %v, request %v ", t.Name, *d)
+			_, span := tracing.StartTargetSpan(ctx, t.Name)
+			failureReason, itemReport := SendRequestAndReport(logger.With(zap.String("address", t.Addr)), t, d, successCondition)
+			tracing.EndSpan(span, failureReason)
+			l.Lock()
+			if len(failureReason) > 0 {
+				finalfailureReason = fmt.Sprintf("test %v on node %v: %v", t.Name, t.NodeName, failureReason)
+			}
+			reportList = append(reportList, itemReport)
+			l.Unlock()
+			wg.Done()
+		}(&wg, &l, *item)
+	}
+	wg.Wait()
+
+	logger.Sugar().Infof("plugin finished all delay request tests")
+
+	// ----------------------- aggregate report
+	task := &v1beta1.NetDelayTask{}
+	task.Detail = reportList
+	task.TargetType = v1beta1.NetDelayTaskName
+	task.TargetNumber = int64(len(testTargetList))
+	if len(finalfailureReason) > 0 {
+		logger.Sugar().Errorf("plugin finally failed, %v", finalfailureReason)
+		task.FailureReason = pointer.String(finalfailureReason)
+		task.Succeed = false
+	} else {
+		task.Succeed = true
+	}
+
+	task.SystemResource = resourceStats.Stats()
+	resourceStats.Stop()
+	task.TotalRunningLoad = rt.QpsStats()
+	return finalfailureReason, task, err
+}
+
+func (s *PluginNetDelay) SetReportWithTask(report *v1beta1.Report, task types.Task) error {
+	netDelayTask, ok := task.(*v1beta1.NetDelayTask)
+	if !ok {
+		return fmt.Errorf("task type %v doesn't match NetDelayTask", task.KindTask())
+	}
+	report.TaskNetDelay = netDelayTask
+	return nil
+}
+
+// getPeerPods returns the node of each agent pod and the ip of each agent pod
+func getPeerPods(ctx context.Context, runtimeName string, multus bool) (map[string]string, k8sObjManager.PodIps, error) {
+	pods, err := k8sObjManager.GetK8sObjManager().ListDaemonsetPod(ctx, runtimeName, config.AgentConfig.PodNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	podNodes := make(map[string]string, len(pods))
+	for _, v := range pods {
+		podNodes[v.Name] = v.Spec.NodeName
+	}
+
+	var podIPs k8sObjManager.PodIps
+	if multus {
+		podIPs, err = k8sObjManager.GetK8sObjManager().ListDaemonsetPodMultusIPs(ctx, runtimeName, config.AgentConfig.PodNamespace)
+	} else {
+		podIPs, err = k8sObjManager.GetK8sObjManager().ListDaemonsetPodIPs(ctx, runtimeName, config.AgentConfig.PodNamespace)
+	}
+
+	return podNodes, podIPs, err
+}
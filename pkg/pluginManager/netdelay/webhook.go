@@ -0,0 +1,200 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package netdelay
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+const (
+	defaultDurationInSecond      = 10
+	defaultQPS                   = 1
+	defaultPerRequestTimeoutInMS = 1000
+)
+
+func (s *PluginNetDelay) WebhookMutating(logger *zap.Logger, ctx context.Context, obj runtime.Object) error {
+	req, ok := obj.(*crd.NetDelay)
+	if !ok {
+		s := "failed to get NetDelay obj"
+		logger.Error(s)
+		return apierrors.NewBadRequest(s)
+	}
+
+	if req.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if req.Spec.Target == nil {
+		enableIpv4 := types.ControllerConfig.Configmap.EnableIPv4
+		enableIpv6 := types.ControllerConfig.Configmap.EnableIPv6
+		disable := false
+		m := &crd.NetDelayTarget{
+			MultusInterface: &disable,
+			IPv6:            &enableIpv6,
+			IPv4:            &enableIpv4,
+		}
+		req.Spec.Target = m
+		logger.Sugar().Debugf("set default target for NetDelay %v", req.Name)
+	}
+
+	if req.Spec.Schedule == nil {
+		req.Spec.Schedule = tools.GetDefaultSchedule()
+		logger.Sugar().Debugf("set default SchedulePlan for NetDelay %v", req.Name)
+	}
+
+	if req.Spec.Request == nil {
+		m := &crd.NetDelayRequest{
+			DurationInSecond:      defaultDurationInSecond,
+			QPS:                   defaultQPS,
+			PerRequestTimeoutInMS: defaultPerRequestTimeoutInMS,
+		}
+		req.Spec.Request = m
+		logger.Sugar().Debugf("set default Request for NetDelay %v", req.Name)
+	}
+
+	if req.Spec.SuccessCondition == nil {
+		req.Spec.SuccessCondition = tools.GetDefaultNetDelaySuccessCondition()
+		logger.Sugar().Debugf("set default SuccessCondition for NetDelay %v", req.Name)
+	}
+
+	// agentSpec
+	if true {
+		if req.Spec.AgentSpec != nil {
+			if req.Spec.AgentSpec.TerminationGracePeriodMinutes == nil {
+				req.Spec.AgentSpec.TerminationGracePeriodMinutes = &types.ControllerConfig.Configmap.AgentDefaultTerminationGracePeriodMinutes
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PluginNetDelay) WebhookValidateCreate(logger *zap.Logger, ctx context.Context, obj runtime.Object) error {
+	r, ok := obj.(*crd.NetDelay)
+	if !ok {
+		s := "failed to get NetDelay obj"
+		logger.Error(s)
+		return apierrors.NewBadRequest(s)
+	}
+	logger.Sugar().Debugf("NetDelay: %+v", r)
+
+	// validate Schedule
+	if true {
+		if err := tools.ValidataCrdSchedule(r.Spec.Schedule); err != nil {
+			s := fmt.Sprintf("NetDelay %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate request
+	if true {
+		if r.Spec.Request.QPS >= types.ControllerConfig.Configmap.NetDelayRequestMaxQPS {
+			s := fmt.Sprintf("NetDelay %v requires qps %v bigger than maximum %v", r.Name, r.Spec.Request.QPS, types.ControllerConfig.Configmap.NetDelayRequestMaxQPS)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.PerRequestTimeoutInMS > int(r.Spec.Schedule.RoundTimeoutMinute*60*1000) {
+			s := fmt.Sprintf("NetDelay %v requires PerRequestTimeoutInMS %v ms smaller than Schedule.RoundTimeoutMinute %vm ", r.Name, r.Spec.Request.PerRequestTimeoutInMS, r.Spec.Schedule.RoundTimeoutMinute)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.DurationInSecond > int(r.Spec.Schedule.RoundTimeoutMinute*60) {
+			s := fmt.Sprintf("NetDelay %v requires request.DurationInSecond %vs smaller than Schedule.RoundTimeoutMinute %vm ", r.Name, r.Spec.Request.DurationInSecond, r.Spec.Schedule.RoundTimeoutMinute)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate target
+	if true {
+		if r.Spec.Target != nil {
+			if r.Spec.Target.IPv4 != nil && *(r.Spec.Target.IPv4) && !types.ControllerConfig.Configmap.EnableIPv4 {
+				s := fmt.Sprintf("NetDelay %v TestIPv4, but kdoctor ipv4 feature is disabled", r.Name)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+			if r.Spec.Target.IPv6 != nil && *(r.Spec.Target.IPv6) && !types.ControllerConfig.Configmap.EnableIPv6 {
+				s := fmt.Sprintf("NetDelay %v TestIPv6, but kdoctor ipv6 feature is disabled", r.Name)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+		}
+	}
+
+	// validate SuccessCondition
+	if true {
+		c := r.Spec.SuccessCondition
+		if c.SuccessRate == nil && c.MaxPairP50InMs == nil && c.MaxPairP99InMs == nil {
+			s := fmt.Sprintf("NetDelay %v, no SuccessCondition specified in the spec", r.Name)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if c.SuccessRate != nil && (*(c.SuccessRate) > 1 || *(c.SuccessRate) < 0) {
+			s := fmt.Sprintf("NetDelay %v, SuccessCondition.SuccessRate %v must be in range [0, 1]", r.Name, *(c.SuccessRate))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if c.MaxPairP50InMs != nil && c.MaxPairP99InMs != nil && *(c.MaxPairP50InMs) > *(c.MaxPairP99InMs) {
+			s := fmt.Sprintf("NetDelay %v, SuccessCondition.MaxPairP50InMs %v must not be bigger than SuccessCondition.MaxPairP99InMs %v", r.Name, *(c.MaxPairP50InMs), *(c.MaxPairP99InMs))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate ReportSinks
+	if true {
+		if err := tools.ValidateReportSinks(r.Spec.ReportSinks); err != nil {
+			s := fmt.Sprintf("NetDelay %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate Baseline
+	if true {
+		// the agent always records the latency of NetDelay
+		if err := tools.ValidateBaseline(r.Spec.Baseline, r.Spec.Schedule, true); err != nil {
+			s := fmt.Sprintf("NetDelay %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate AgentSpec
+	if true {
+		if r.Spec.AgentSpec != nil {
+			// every node should probe all the other nodes, which requires an agent on each node
+			if r.Spec.AgentSpec.Kind != types.KindDaemonSet {
+				return apierrors.NewBadRequest(fmt.Sprintf("Invalid agent runtime kind %s, NetDelay only supports %s", r.Spec.AgentSpec.Kind, types.KindDaemonSet))
+			}
+		}
+	}
+
+	return nil
+}
+
+// it is not allowed to modify crd, except the suspend of the schedule
+func (s *PluginNetDelay) WebhookValidateUpdate(logger *zap.Logger, ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldNetDelay := oldObj.(*crd.NetDelay)
+	newNetDelay := newObj.(*crd.NetDelay)
+
+	// only the suspend of the schedule is allowed to modify
+	oldSpec := oldNetDelay.Spec.DeepCopy()
+	tools.IgnoreScheduleSuspend(oldSpec.Schedule, newNetDelay.Spec.Schedule)
+	if !reflect.DeepEqual(*oldSpec, newNetDelay.Spec) {
+		return apierrors.NewBadRequest(fmt.Sprintf("it's not allowed to modify NetDelay %s Spec", oldNetDelay.Name))
+	}
+
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	"github.com/kdoctor-io/kdoctor/pkg/runningTask"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
 
 	"github.com/miekg/dns"
 	"go.uber.org/zap"
@@ -21,16 +22,29 @@ import (
 	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDetect"
 	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDns"
 	"github.com/kdoctor-io/kdoctor/pkg/lock"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
 	"github.com/kdoctor-io/kdoctor/pkg/resource"
+	config "github.com/kdoctor-io/kdoctor/pkg/types"
 )
 
+// SucceedRate returns the rate of the succeeded requests, and it is 0 when no request is sent
+func SucceedRate(metricResult *v1beta1.DNSMetrics) float64 {
+	if metricResult.RequestCounts == 0 {
+		return 0
+	}
+	return float64(metricResult.SuccessCounts) / float64(metricResult.RequestCounts)
+}
+
 func ParseSuccessCondition(successCondition *crd.NetSuccessCondition, metricResult *v1beta1.DNSMetrics) (failureReason string) {
 	switch {
-	case successCondition.SuccessRate != nil && float64(metricResult.SuccessCounts)/float64(metricResult.RequestCounts) < *(successCondition.SuccessRate):
-		failureReason = fmt.Sprintf("Success Rate %v is lower than request %v", float64(metricResult.SuccessCounts)/float64(metricResult.RequestCounts), *(successCondition.SuccessRate))
+	case metricResult.RequestCounts == 0:
+		failureReason = fmt.Sprintf("no request sent for query %v", metricResult.TargetDomain)
+	case successCondition.SuccessRate != nil && SucceedRate(metricResult) < *(successCondition.SuccessRate):
+		failureReason = fmt.Sprintf("Success Rate %v is lower than request %v", SucceedRate(metricResult), *(successCondition.SuccessRate))
 	case successCondition.MeanAccessDelayInMs != nil && int64(metricResult.Latencies.Mean) > *(successCondition.MeanAccessDelayInMs):
 		failureReason = fmt.Sprintf("mean delay %v ms is bigger than request %v ms", metricResult.Latencies.Mean, *(successCondition.MeanAccessDelayInMs))
 	case metricResult.ExistsNotSendRequests:
@@ -46,6 +60,8 @@ func SendRequestAndReport(logger *zap.Logger, targetName string, req *loadDns.Dn
 	report.TargetName = targetName
 	report.TargetServer = req.DnsServerAddr
 	report.TargetProtocol = string(req.Protocol)
+	report.TargetDomain = req.TargetDomain
+	report.QueryType = dns.TypeToString[req.DnsType]
 
 	result, err := loadDns.DnsRequest(logger, req)
 	if err != nil {
@@ -55,7 +71,7 @@ func SendRequestAndReport(logger *zap.Logger, targetName string, req *loadDns.Dn
 	}
 
 	report.MeanDelay = result.Latencies.Mean
-	report.SucceedRate = float64(result.SuccessCounts) / float64(result.RequestCounts)
+	report.SucceedRate = SucceedRate(result)
 
 	failureReason = ParseSuccessCondition(successCondition, result)
 
@@ -75,6 +91,131 @@ func SendRequestAndReport(logger *zap.Logger, targetName string, req *loadDns.Dn
 	return
 }
 
+// DetectAndReport ramps the qps against the dns server in the detect mode, and the round fails only when even the first step breaks the threshold
+func DetectAndReport(logger *zap.Logger, targetName string, req *loadDns.DnsRequestData, detect *crd.NetdnsDetect) (failureReason string, report v1beta1.NetDNSTaskDetail) {
+	report.TargetName = targetName
+	report.TargetServer = req.DnsServerAddr
+	report.TargetProtocol = string(req.Protocol)
+
+	result, err := loadDns.DnsDetect(logger, req, &loadDns.DnsDetectData{
+		Steps: loadDetect.Steps{
+			StartQps:           detect.StartQPS,
+			StepQps:            detect.StepQPS,
+			MaxQps:             detect.MaxQPS,
+			StepDurationSecond: detect.StepDurationInSecond,
+		},
+		SuccessRate: detect.SuccessRate,
+		MaxP99InMs:  detect.MaxP99InMs,
+	})
+	if err != nil {
+		logger.Sugar().Errorf("internal error for target %v, error=%v", req.DnsServerAddr, err)
+		report.FailureReason = pointer.String(err.Error())
+		return err.Error(), report
+	}
+	report.Detect = result
+
+	if result.KneeQPS == 0 {
+		failureReason = "no step has been run"
+		if result.BreakReason != nil {
+			failureReason = *result.BreakReason
+		}
+		report.FailureReason = pointer.String(failureReason)
+		report.Succeed = false
+		logger.Sugar().Warnf("failed to detect %v", req.DnsServerAddr)
+	} else {
+		// take the last succeeded step as the result of the target
+		step := result.Steps[len(result.Steps)-1]
+		if !step.Succeed {
+			step = result.Steps[len(result.Steps)-2]
+		}
+		report.MeanDelay = step.Latencies.Mean
+		report.SucceedRate = step.SucceedRate
+		report.Succeed = true
+		logger.Sugar().Infof("succeed to detect %v, the knee qps is %v", req.DnsServerAddr, result.KneeQPS)
+	}
+
+	return
+}
+
+// SendQueriesAndReport rotates through the queries of the request, and reports each query separately
+func SendQueriesAndReport(logger *zap.Logger, targetName string, req *loadDns.DnsRequestData, successCondition *crd.NetSuccessCondition) (failureReason string, reports []v1beta1.NetDNSTaskDetail) {
+	results, err := loadDns.DnsRequestByQuery(logger, req)
+	if err != nil {
+		logger.Sugar().Errorf("internal error for target %v, error=%v", req.DnsServerAddr, err)
+		return err.Error(), []v1beta1.NetDNSTaskDetail{{
+			TargetName:     targetName,
+			TargetServer:   req.DnsServerAddr,
+			TargetProtocol: string(req.Protocol),
+			FailureReason:  pointer.String(err.Error()),
+		}}
+	}
+
+	for i, result := range results {
+		query := req.Queries[i]
+		report := v1beta1.NetDNSTaskDetail{
+			TargetName:     "type" + dns.TypeToString[query.DnsType] + "_" + req.DnsServerAddr + "_" + query.TargetDomain,
+			TargetServer:   req.DnsServerAddr,
+			TargetProtocol: string(req.Protocol),
+			TargetDomain:   query.TargetDomain,
+			QueryType:      dns.TypeToString[query.DnsType],
+			MeanDelay:      result.Latencies.Mean,
+			SucceedRate:    SucceedRate(result),
+			Metrics:        *result,
+		}
+
+		itemFailureReason := ParseSuccessCondition(successCondition, result)
+		if len(itemFailureReason) == 0 {
+			report.Succeed = true
+			logger.Sugar().Infof("succeed to test %v", report.TargetName)
+		} else {
+			report.FailureReason = pointer.String(itemFailureReason)
+			failureReason = fmt.Sprintf("%v: %v", report.TargetName, itemFailureReason)
+			logger.Sugar().Warnf("failed to test %v", report.TargetName)
+		}
+		reports = append(reports, report)
+	}
+
+	return
+}
+
+// BuildQueries converts the queries in the spec to the queries of loadDns,
+// and the ip address of the PTR query is converted to the reverse domain
+func BuildQueries(queries []crd.NetdnsQuery) ([]loadDns.DnsQuery, error) {
+	result := make([]loadDns.DnsQuery, 0, len(queries))
+	for _, q := range queries {
+		dnsType, ok := dns.StringToType[q.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown query type %v", q.Type)
+		}
+		domain := q.Domain
+		if dnsType == dns.TypePTR && net.ParseIP(domain) != nil {
+			reverse, err := dns.ReverseAddr(domain)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the reverse domain of %v, error=%v", domain, err)
+			}
+			domain = reverse
+		}
+		result = append(result, loadDns.DnsQuery{
+			TargetDomain:    domain,
+			DnsType:         dnsType,
+			ExpectedAnswers: q.ExpectedAnswers,
+		})
+	}
+	return result, nil
+}
+
+// BuildDnssecOption converts the dnssec in the spec to the option of loadDns
+func BuildDnssecOption(dnssec *crd.NetDnsDnssec) (*loadDns.DnssecOption, error) {
+	anchors, err := loadDns.ParseTrustAnchors(dnssec.TrustAnchors)
+	if err != nil {
+		return nil, err
+	}
+	return &loadDns.DnssecOption{
+		CheckAD:      dnssec.CheckAD == nil || *dnssec.CheckAD,
+		TrustAnchors: anchors,
+	}, nil
+}
+
 type testTarget struct {
 	Name    string
 	Request *loadDns.DnsRequestData
@@ -200,6 +341,41 @@ func (s *PluginNetDns) AgentExecuteTask(logger *zap.Logger, ctx context.Context,
 
 	}
 
+	if instance.Spec.Request.DohMethod != nil {
+		for _, t := range testTargetList {
+			t.Request.DohMethod = *instance.Spec.Request.DohMethod
+		}
+	}
+
+	if instance.Spec.Request.DohPath != nil {
+		for _, t := range testTargetList {
+			t.Request.DohPath = *instance.Spec.Request.DohPath
+		}
+	}
+
+	if instance.Spec.Target.Dnssec != nil {
+		dnssec, e := BuildDnssecOption(instance.Spec.Target.Dnssec)
+		if e != nil {
+			finalfailureReason = fmt.Sprintf("invalid dnssec: %v", e)
+			testTargetList = nil
+		}
+		for _, t := range testTargetList {
+			t.Request.Dnssec = dnssec
+		}
+	}
+
+	// the requests to each server rotate through the queries
+	if len(instance.Spec.Request.Queries) > 0 {
+		queries, e := BuildQueries(instance.Spec.Request.Queries)
+		if e != nil {
+			finalfailureReason = fmt.Sprintf("invalid queries: %v", e)
+			testTargetList = nil
+		}
+		for _, t := range testTargetList {
+			t.Request.Queries = queries
+		}
+	}
+
 	reportList := make([]v1beta1.NetDNSTaskDetail, 0, len(testTargetList))
 
 	var wg sync.WaitGroup
@@ -207,13 +383,35 @@ func (s *PluginNetDns) AgentExecuteTask(logger *zap.Logger, ctx context.Context,
 	for _, item := range testTargetList {
 		wg.Add(1)
 		go func(wg *sync.WaitGroup, l *lock.Mutex, t testTarget) {
+			t.Request.MetricTarget = &metrics.TaskTarget{
+				Kind:     v1beta1.NetDNSTaskName,
+				Task:     instance.Name,
+				Target:   t.Name,
+				Node:     config.AgentConfig.LocalNodeName,
+				Protocol: string(t.Request.Protocol),
+			}
 			logger.Sugar().Debugf("implement test %v, request %v ", t.Name, *t.Request)
-			failureReason, itemReport := SendRequestAndReport(logger, t.Name, t.Request, instance.Spec.SuccessCondition)
+			_, span := tracing.StartTargetSpan(ctx, t.Name)
+			var failureReason string
+			var itemReports []v1beta1.NetDNSTaskDetail
+			switch {
+			case instance.Spec.Detect != nil:
+				var itemReport v1beta1.NetDNSTaskDetail
+				failureReason, itemReport = DetectAndReport(logger, t.Name, t.Request, instance.Spec.Detect)
+				itemReports = append(itemReports, itemReport)
+			case len(t.Request.Queries) > 0:
+				failureReason, itemReports = SendQueriesAndReport(logger, t.Name, t.Request, instance.Spec.SuccessCondition)
+			default:
+				var itemReport v1beta1.NetDNSTaskDetail
+				failureReason, itemReport = SendRequestAndReport(logger, t.Name, t.Request, instance.Spec.SuccessCondition)
+				itemReports = append(itemReports, itemReport)
+			}
+			tracing.EndSpan(span, failureReason)
 			l.Lock()
 			if failureReason != "" {
 				finalfailureReason = fmt.Sprintf("test %v: %v", t.Name, failureReason)
 			}
-			reportList = append(reportList, itemReport)
+			reportList = append(reportList, itemReports...)
 			l.Unlock()
 			wg.Done()
 		}(&wg, &l, *item)
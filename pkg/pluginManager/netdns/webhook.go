@@ -6,14 +6,19 @@ package netdns
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"reflect"
+	"strings"
 
+	"github.com/miekg/dns"
 	"go.uber.org/zap"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/strings/slices"
 
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDetect"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadDns"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 )
@@ -79,6 +84,60 @@ func (s *PluginNetDns) WebhookValidateCreate(logger *zap.Logger, ctx context.Con
 		}
 	}
 
+	// validate queries
+	if true {
+		queries, err := BuildQueries(r.Spec.Request.Queries)
+		if err != nil {
+			s := fmt.Sprintf("netdns %v, %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		for _, q := range queries {
+			if _, ok := dns.IsDomainName(q.TargetDomain); !ok {
+				s := fmt.Sprintf("netdns %v, invalid domain %v in the queries", r.Name, q.TargetDomain)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+		}
+		// each query needs one request at least, or else its success rate could not be computed
+		if r.Spec.Detect == nil && r.Spec.Request.QPS*r.Spec.Request.DurationInSecond < len(queries) {
+			s := fmt.Sprintf("netdns %v requires request.qps %v * request.durationInSecond %v not smaller than the %v queries", r.Name, r.Spec.Request.QPS, r.Spec.Request.DurationInSecond, len(queries))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate detect
+	if r.Spec.Detect != nil {
+		detect := r.Spec.Detect
+		if detect.MaxQPS < detect.StartQPS {
+			s := fmt.Sprintf("netdns %v requires detect.maxQPS %v not smaller than detect.startQPS %v", r.Name, detect.MaxQPS, detect.StartQPS)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if detect.MaxQPS >= types.ControllerConfig.Configmap.NetDnsRequestMaxQPS {
+			s := fmt.Sprintf("netdns %v requires detect.maxQPS %v bigger than maximum %v", r.Name, detect.MaxQPS, types.ControllerConfig.Configmap.NetDnsRequestMaxQPS)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if detect.SuccessRate == nil && detect.MaxP99InMs == nil {
+			s := fmt.Sprintf("netdns %v, no threshold specified in the detect", r.Name)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if detect.SuccessRate != nil && (*(detect.SuccessRate) > 1 || *(detect.SuccessRate) < 0) {
+			s := fmt.Sprintf("netdns %v, detect.successRate %v must be between 0 and 1", r.Name, *(detect.SuccessRate))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		stepNumber := (&loadDetect.Steps{StartQps: detect.StartQPS, StepQps: detect.StepQPS, MaxQps: detect.MaxQPS}).StepNumber()
+		if stepNumber*detect.StepDurationInSecond > int(r.Spec.Schedule.RoundTimeoutMinute*60) {
+			s := fmt.Sprintf("netdns %v requires %v detect steps of %vs smaller than Schedule.RoundTimeoutMinute %vm ", r.Name, stepNumber, detect.StepDurationInSecond, r.Spec.Schedule.RoundTimeoutMinute)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
 	// validate target
 	if true {
 		if r.Spec.Target.NetDnsTargetDns != nil && r.Spec.Target.NetDnsTargetUser != nil {
@@ -86,6 +145,39 @@ func (s *PluginNetDns) WebhookValidateCreate(logger *zap.Logger, ctx context.Con
 			logger.Error(s)
 			return apierrors.NewBadRequest(s)
 		}
+		// the cluster dns service is requested on the port 53, which does not serve https or quic
+		if r.Spec.Request.Protocol != nil && r.Spec.Target.NetDnsTargetUser == nil &&
+			(*r.Spec.Request.Protocol == string(loadDns.RequestMethodHttps) || *r.Spec.Request.Protocol == string(loadDns.RequestMethodQuic)) {
+			s := fmt.Sprintf("netdns %v, protocol %v requires TargetUser", r.Name, *r.Spec.Request.Protocol)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.DohPath != nil {
+			if r.Spec.Request.Protocol == nil || *r.Spec.Request.Protocol != string(loadDns.RequestMethodHttps) {
+				s := fmt.Sprintf("netdns %v, request.dohPath only works with the protocol %v", r.Name, loadDns.RequestMethodHttps)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+			if u, e := url.Parse(*r.Spec.Request.DohPath); e != nil || !strings.HasPrefix(u.Path, "/") || len(u.RawQuery) > 0 || len(u.Host) > 0 {
+				s := fmt.Sprintf("netdns %v, request.dohPath %v must be an absolute path without the query", r.Name, *r.Spec.Request.DohPath)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+		}
+	}
+
+	// validate dnssec
+	if r.Spec.Target.Dnssec != nil {
+		if _, e := loadDns.ParseTrustAnchors(r.Spec.Target.Dnssec.TrustAnchors); e != nil {
+			s := fmt.Sprintf("netdns %v, invalid dnssec.trustAnchors: %v", r.Name, e)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Target.Dnssec.CheckAD != nil && !*r.Spec.Target.Dnssec.CheckAD && len(r.Spec.Target.Dnssec.TrustAnchors) == 0 {
+			s := fmt.Sprintf("netdns %v, dnssec requires checkAD or trustAnchors", r.Name)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
 	}
 
 	// validate SuccessCondition
@@ -107,6 +199,24 @@ func (s *PluginNetDns) WebhookValidateCreate(logger *zap.Logger, ctx context.Con
 		}
 	}
 
+	// validate ReportSinks
+	if true {
+		if err := tools.ValidateReportSinks(r.Spec.ReportSinks); err != nil {
+			s := fmt.Sprintf("Netdns %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate Baseline
+	if true {
+		if err := tools.ValidateBaseline(r.Spec.Baseline, r.Spec.Schedule, r.Spec.Target != nil && r.Spec.Target.EnableLatencyMetric); err != nil {
+			s := fmt.Sprintf("Netdns %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
 	// validate AgentSpec
 	if true {
 		if r.Spec.AgentSpec != nil {
@@ -118,13 +228,16 @@ func (s *PluginNetDns) WebhookValidateCreate(logger *zap.Logger, ctx context.Con
 	return nil
 }
 
-// this will not be called, it is not allowed to modify crd
+// it is not allowed to modify crd, except the suspend of the schedule
 
 func (s *PluginNetDns) WebhookValidateUpdate(logger *zap.Logger, ctx context.Context, oldObj, newObj runtime.Object) error {
 	oldNetdns := oldObj.(*crd.Netdns)
 	newNetdns := newObj.(*crd.Netdns)
 
-	if !reflect.DeepEqual(oldNetdns.Spec, newNetdns.Spec) {
+	// only the suspend of the schedule is allowed to modify
+	oldSpec := oldNetdns.Spec.DeepCopy()
+	tools.IgnoreScheduleSuspend(oldSpec.Schedule, newNetdns.Spec.Schedule)
+	if !reflect.DeepEqual(*oldSpec, newNetdns.Spec) {
 		return apierrors.NewBadRequest(fmt.Sprintf("it's not allowed to modify Netdns %s Spec", oldNetdns.Name))
 	}
 
@@ -11,6 +11,7 @@ import (
 
 	"github.com/kdoctor-io/kdoctor/pkg/resource"
 	"github.com/kdoctor-io/kdoctor/pkg/runningTask"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
 	networkingv1 "k8s.io/api/networking/v1"
 
 	"go.uber.org/zap"
@@ -22,6 +23,7 @@ import (
 	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
 	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadHttp"
 	"github.com/kdoctor-io/kdoctor/pkg/lock"
+	"github.com/kdoctor-io/kdoctor/pkg/metrics"
 	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
 	config "github.com/kdoctor-io/kdoctor/pkg/types"
 	runtimetype "github.com/kdoctor-io/kdoctor/pkg/types"
@@ -300,9 +302,18 @@ func (s *PluginNetReach) AgentExecuteTask(logger *zap.Logger, ctx context.Contex
 				PerRequestTimeoutMS: request.PerRequestTimeoutInMS,
 				RequestTimeSecond:   request.DurationInSecond,
 				EnableLatencyMetric: instance.Spec.Target.EnableLatencyMetric,
+				MetricTarget: &metrics.TaskTarget{
+					Kind:     v1beta1.NetReachTaskName,
+					Task:     instance.Name,
+					Target:   t.Name,
+					Node:     config.AgentConfig.LocalNodeName,
+					Protocol: metrics.URLProtocol(t.Url),
+				},
 			}
 			logger.Sugar().Debugf("implement test %v, request %v ", t.Name, *d)
+			_, span := tracing.StartTargetSpan(ctx, t.Name)
 			failureReason, itemReport := SendRequestAndReport(logger.With(zap.String("url", t.Url)), t.Name, d, successCondition)
+			tracing.EndSpan(span, failureReason)
 			l.Lock()
 			if len(failureReason) > 0 {
 				finalfailureReason = fmt.Sprintf("test %v: %v", t.Name, failureReason)
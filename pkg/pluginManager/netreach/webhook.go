@@ -191,6 +191,24 @@ func (s *PluginNetReach) WebhookValidateCreate(logger *zap.Logger, ctx context.C
 		}
 	}
 
+	// validate ReportSinks
+	if true {
+		if err := tools.ValidateReportSinks(r.Spec.ReportSinks); err != nil {
+			s := fmt.Sprintf("NetReach %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate Baseline
+	if true {
+		if err := tools.ValidateBaseline(r.Spec.Baseline, r.Spec.Schedule, r.Spec.Target != nil && r.Spec.Target.EnableLatencyMetric); err != nil {
+			s := fmt.Sprintf("NetReach %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
 	// validate AgentSpec
 	if true {
 		if r.Spec.AgentSpec != nil {
@@ -203,12 +221,15 @@ func (s *PluginNetReach) WebhookValidateCreate(logger *zap.Logger, ctx context.C
 	return nil
 }
 
-// this will not be called, it is not allowed to modify crd
+// it is not allowed to modify crd, except the suspend of the schedule
 func (s *PluginNetReach) WebhookValidateUpdate(logger *zap.Logger, ctx context.Context, oldObj, newObj runtime.Object) error {
 	oldNetReach := oldObj.(*crd.NetReach)
 	newNetReach := newObj.(*crd.NetReach)
 
-	if !reflect.DeepEqual(oldNetReach.Spec, newNetReach.Spec) {
+	// only the suspend of the schedule is allowed to modify
+	oldSpec := oldNetReach.Spec.DeepCopy()
+	tools.IgnoreScheduleSuspend(oldSpec.Schedule, newNetReach.Spec.Schedule)
+	if !reflect.DeepEqual(*oldSpec, newNetReach.Spec) {
 		return apierrors.NewBadRequest(fmt.Sprintf("it's not allowed to modify NetReach %s Spec", oldNetReach.Name))
 	}
 
@@ -0,0 +1,19 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package nettcp
+
+import (
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type PluginNetTcp struct {
+}
+
+var _ types.ChainingPlugin = &PluginNetTcp{}
+
+func (s *PluginNetTcp) GetApiType() client.Object {
+	return &crd.NetTcp{}
+}
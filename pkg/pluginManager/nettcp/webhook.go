@@ -0,0 +1,209 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package nettcp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/strings/slices"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+const defaultPayloadSizeInByte = 65536
+
+func (s *PluginNetTcp) WebhookMutating(logger *zap.Logger, ctx context.Context, obj runtime.Object) error {
+	req, ok := obj.(*crd.NetTcp)
+	if !ok {
+		s := "failed to get NetTcp obj"
+		logger.Error(s)
+		return apierrors.NewBadRequest(s)
+	}
+
+	if req.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if req.Spec.Target == nil {
+		enableIpv4 := types.ControllerConfig.Configmap.EnableIPv4
+		enableIpv6 := types.ControllerConfig.Configmap.EnableIPv6
+		enable := true
+		disable := false
+		m := &crd.NetTcpTarget{
+			Endpoint:        &enable,
+			MultusInterface: &disable,
+			ClusterIP:       &enable,
+			NodePort:        &enable,
+			IPv6:            &enableIpv6,
+			IPv4:            &enableIpv4,
+		}
+		req.Spec.Target = m
+		logger.Sugar().Debugf("set default target for NetTcp %v", req.Name)
+	}
+
+	if req.Spec.Schedule == nil {
+		req.Spec.Schedule = tools.GetDefaultSchedule()
+		logger.Sugar().Debugf("set default SchedulePlan for NetTcp %v", req.Name)
+	}
+
+	if req.Spec.Request == nil {
+		m := &crd.NetTcpRequest{
+			DurationInSecond:      types.ControllerConfig.Configmap.NetHttpDefaultRequestDurationInSecond,
+			QPS:                   types.ControllerConfig.Configmap.NetHttpDefaultRequestQPS,
+			PerRequestTimeoutInMS: types.ControllerConfig.Configmap.NetHttpDefaultRequestPerRequestTimeoutInMS,
+			PayloadSizeInByte:     defaultPayloadSizeInByte,
+		}
+		req.Spec.Request = m
+		logger.Sugar().Debugf("set default Request for NetTcp %v", req.Name)
+	}
+
+	if req.Spec.SuccessCondition == nil {
+		req.Spec.SuccessCondition = tools.GetDefaultNetTcpSuccessCondition()
+		logger.Sugar().Debugf("set default SuccessCondition for NetTcp %v", req.Name)
+	}
+
+	// agentSpec
+	if true {
+		if req.Spec.AgentSpec != nil {
+			if req.Spec.AgentSpec.TerminationGracePeriodMinutes == nil {
+				req.Spec.AgentSpec.TerminationGracePeriodMinutes = &types.ControllerConfig.Configmap.AgentDefaultTerminationGracePeriodMinutes
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PluginNetTcp) WebhookValidateCreate(logger *zap.Logger, ctx context.Context, obj runtime.Object) error {
+	r, ok := obj.(*crd.NetTcp)
+	if !ok {
+		s := "failed to get NetTcp obj"
+		logger.Error(s)
+		return apierrors.NewBadRequest(s)
+	}
+	logger.Sugar().Debugf("NetTcp: %+v", r)
+
+	// validate Schedule
+	if true {
+		if err := tools.ValidataCrdSchedule(r.Spec.Schedule); err != nil {
+			s := fmt.Sprintf("NetTcp %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate request
+	if true {
+		if r.Spec.Request.QPS >= types.ControllerConfig.Configmap.NetTcpRequestMaxQPS {
+			s := fmt.Sprintf("NetTcp %v requires qps %v bigger than maximum %v", r.Name, r.Spec.Request.QPS, types.ControllerConfig.Configmap.NetTcpRequestMaxQPS)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.PerRequestTimeoutInMS > int(r.Spec.Schedule.RoundTimeoutMinute*60*1000) {
+			s := fmt.Sprintf("NetTcp %v requires PerRequestTimeoutInMS %v ms smaller than Schedule.RoundTimeoutMinute %vm ", r.Name, r.Spec.Request.PerRequestTimeoutInMS, r.Spec.Schedule.RoundTimeoutMinute)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.DurationInSecond > int(r.Spec.Schedule.RoundTimeoutMinute*60) {
+			s := fmt.Sprintf("NetTcp %v requires request.DurationInSecond %vs smaller than Schedule.RoundTimeoutMinute %vm ", r.Name, r.Spec.Request.DurationInSecond, r.Spec.Schedule.RoundTimeoutMinute)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.PayloadSizeInByte < 0 {
+			s := fmt.Sprintf("NetTcp %v, request.PayloadSizeInByte %v must not be smaller than 0", r.Name, r.Spec.Request.PayloadSizeInByte)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate target
+	if true {
+		if r.Spec.Target != nil {
+			if r.Spec.Target.IPv4 != nil && *(r.Spec.Target.IPv4) && !types.ControllerConfig.Configmap.EnableIPv4 {
+				s := fmt.Sprintf("NetTcp %v TestIPv4, but kdoctor ipv4 feature is disabled", r.Name)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+			if r.Spec.Target.IPv6 != nil && *(r.Spec.Target.IPv6) && !types.ControllerConfig.Configmap.EnableIPv6 {
+				s := fmt.Sprintf("NetTcp %v TestIPv6, but kdoctor ipv6 feature is disabled", r.Name)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+		}
+	}
+
+	// validate SuccessCondition
+	if true {
+		if r.Spec.SuccessCondition.SuccessRate == nil && r.Spec.SuccessCondition.MeanAccessDelayInMs == nil && r.Spec.SuccessCondition.MinThroughputInKbps == nil {
+			s := fmt.Sprintf("NetTcp %v, no SuccessCondition specified in the spec", r.Name)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.SuccessCondition.SuccessRate != nil && (*(r.Spec.SuccessCondition.SuccessRate) > 1) {
+			s := fmt.Sprintf("NetTcp %v, SuccessCondition.SuccessRate %v must not be bigger than 1", r.Name, *(r.Spec.SuccessCondition.SuccessRate))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.SuccessCondition.SuccessRate != nil && (*(r.Spec.SuccessCondition.SuccessRate) < 0) {
+			s := fmt.Sprintf("NetTcp %v, SuccessCondition.SuccessRate %v must not be smaller than 0 ", r.Name, *(r.Spec.SuccessCondition.SuccessRate))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.SuccessCondition.MinThroughputInKbps != nil && r.Spec.Request.PayloadSizeInByte == 0 {
+			s := fmt.Sprintf("NetTcp %v, SuccessCondition.MinThroughputInKbps requires request.PayloadSizeInByte bigger than 0", r.Name)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate ReportSinks
+	if true {
+		if err := tools.ValidateReportSinks(r.Spec.ReportSinks); err != nil {
+			s := fmt.Sprintf("NetTcp %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate Baseline
+	if true {
+		if err := tools.ValidateBaseline(r.Spec.Baseline, r.Spec.Schedule, r.Spec.Target != nil && r.Spec.Target.EnableLatencyMetric); err != nil {
+			s := fmt.Sprintf("NetTcp %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate AgentSpec
+	if true {
+		if r.Spec.AgentSpec != nil {
+			if !slices.Contains(types.TaskRuntimes, r.Spec.AgentSpec.Kind) {
+				return apierrors.NewBadRequest(fmt.Sprintf("Invalid agent runtime kind %s", r.Spec.AgentSpec.Kind))
+			}
+		}
+	}
+
+	return nil
+}
+
+// it is not allowed to modify crd, except the suspend of the schedule
+func (s *PluginNetTcp) WebhookValidateUpdate(logger *zap.Logger, ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldNetTcp := oldObj.(*crd.NetTcp)
+	newNetTcp := newObj.(*crd.NetTcp)
+
+	// only the suspend of the schedule is allowed to modify
+	oldSpec := oldNetTcp.Spec.DeepCopy()
+	tools.IgnoreScheduleSuspend(oldSpec.Schedule, newNetTcp.Spec.Schedule)
+	if !reflect.DeepEqual(*oldSpec, newNetTcp.Spec) {
+		return apierrors.NewBadRequest(fmt.Sprintf("it's not allowed to modify NetTcp %s Spec", oldNetTcp.Name))
+	}
+
+	return nil
+}
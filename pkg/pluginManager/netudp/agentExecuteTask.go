@@ -0,0 +1,317 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package netudp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadUdp"
+	"github.com/kdoctor-io/kdoctor/pkg/lock"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"github.com/kdoctor-io/kdoctor/pkg/resource"
+	"github.com/kdoctor-io/kdoctor/pkg/runningTask"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
+	config "github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// the name of the agent service port for the app udp server
+const serviceAccessPortName = "udp"
+
+func ParseSuccessCondition(successCondition *crd.NetUdpSuccessCondition, metricResult *v1beta1.UdpMetrics) (failureReason string) {
+	switch {
+	case metricResult.SendCounts == 0:
+		failureReason = "no packet has been sent"
+	case successCondition.MaxLossPercentage != nil && metricResult.LossPercentage > *(successCondition.MaxLossPercentage):
+		failureReason = fmt.Sprintf("loss percentage %v%% is bigger than request %v%%", metricResult.LossPercentage, *(successCondition.MaxLossPercentage))
+	case successCondition.MaxJitterInMs != nil && metricResult.JitterInMs > *(successCondition.MaxJitterInMs):
+		failureReason = fmt.Sprintf("jitter %v ms is bigger than request %v ms", metricResult.JitterInMs, *(successCondition.MaxJitterInMs))
+	case successCondition.MaxOutOfOrderCounts != nil && metricResult.OutOfOrderCounts > *(successCondition.MaxOutOfOrderCounts):
+		failureReason = fmt.Sprintf("%v packets are out of order, bigger than request %v", metricResult.OutOfOrderCounts, *(successCondition.MaxOutOfOrderCounts))
+	case successCondition.MaxDuplicateCounts != nil && metricResult.DuplicateCounts > *(successCondition.MaxDuplicateCounts):
+		failureReason = fmt.Sprintf("%v packets are duplicate, bigger than request %v", metricResult.DuplicateCounts, *(successCondition.MaxDuplicateCounts))
+	case successCondition.MeanAccessDelayInMs != nil && int64(metricResult.Latencies.Mean) > *(successCondition.MeanAccessDelayInMs):
+		failureReason = fmt.Sprintf("mean delay %v ms is bigger than request %v ms", metricResult.Latencies.Mean, *(successCondition.MeanAccessDelayInMs))
+	case metricResult.ExistsNotSendRequests:
+		failureReason = "There are unsent packets after the execution time has been reached"
+	default:
+		failureReason = ""
+	}
+	return
+}
+
+func SendRequestAndReport(logger *zap.Logger, targetName string, req *loadUdp.UdpRequestData, successCondition *crd.NetUdpSuccessCondition) (failureReason string, report v1beta1.NetUdpTaskDetail) {
+	report.TargetName = targetName
+	report.TargetAddress = req.ServerAddr
+
+	result, err := loadUdp.UdpRequest(logger, req)
+	if err != nil {
+		logger.Sugar().Errorf("internal error for target %v, error=%v", req.ServerAddr, err)
+		failureReason = err.Error()
+		report.FailureReason = pointer.String(failureReason)
+		return
+	}
+
+	report.MeanDelay = result.Latencies.Mean
+	report.LossPercentage = result.LossPercentage
+	report.OutOfOrderCounts = result.OutOfOrderCounts
+	report.DuplicateCounts = result.DuplicateCounts
+	report.JitterInMs = result.JitterInMs
+
+	failureReason = ParseSuccessCondition(successCondition, result)
+
+	// generate report
+	// notice , upper case for first character of key, or else fail to parse json
+	report.Metrics = *result
+	if len(failureReason) == 0 {
+		report.FailureReason = nil
+		report.Succeed = true
+		logger.Sugar().Infof("succeed to test %v", req.ServerAddr)
+	} else {
+		report.FailureReason = pointer.String(failureReason)
+		report.Succeed = false
+		logger.Sugar().Warnf("failed to test %v", req.ServerAddr)
+	}
+
+	return
+}
+
+type TestTarget struct {
+	Name string
+	Addr string
+}
+
+func (s *PluginNetUdp) AgentExecuteTask(logger *zap.Logger, ctx context.Context, obj runtime.Object, rt *runningTask.RunningTask) (finalfailureReason string, finalReport types.Task, err error) {
+	// process mem cpu stats
+	resourceStats := resource.InitResource(ctx)
+	resourceStats.RunResourceCollector()
+
+	finalfailureReason = ""
+	err = nil
+	var e error
+
+	instance, ok := obj.(*crd.NetUdp)
+	if !ok {
+		msg := "failed to get instance"
+		logger.Error(msg)
+		err = errors.New(msg)
+		return
+	}
+
+	logger.Sugar().Infof("plugin implement task round, instance=%+v", instance)
+
+	target := instance.Spec.Target
+	request := instance.Spec.Request
+	successCondition := instance.Spec.SuccessCondition
+	runtimeResource := instance.Status.Resource
+
+	testTargetList := []*TestTarget{}
+
+	// test kdoctor agent
+	logger.Sugar().Infof("load test kdoctor Agent pod: packetRate=%v, PerPacketTimeout=%vms, Duration=%vs, PayloadSize=%vB", request.PacketRate, request.PerPacketTimeoutInMS, request.DurationInSecond, request.PayloadSizeInByte)
+	finalfailureReason = ""
+
+	agentPort := strconv.Itoa(int(config.AgentConfig.AppUdpPort))
+	if *target.Endpoint {
+		podIPs, e := getTargetPodIP(ctx, runtimeResource.RuntimeName, runtimeResource.RuntimeType, *target.MultusInterface)
+		if e != nil {
+			logger.Sugar().Errorf("failed to get agent pod ip, error=%v", e)
+			finalfailureReason = fmt.Sprintf("failed to get agent pod ip, error=%v", e)
+		} else {
+			logger.Sugar().Debugf("test agent pod ip: %v", podIPs)
+			for podname, ips := range podIPs {
+				for _, podips := range ips {
+					if len(podips.IPv4) > 0 && (target.IPv4 == nil || (target.IPv4 != nil && *target.IPv4)) {
+						testTargetList = append(testTargetList, &TestTarget{
+							Name: "AgentPodV4IP_" + podname + "_" + podips.IPv4,
+							Addr: net.JoinHostPort(podips.IPv4, agentPort),
+						})
+					}
+					if len(podips.IPv6) > 0 && (target.IPv6 == nil || (target.IPv6 != nil && *target.IPv6)) {
+						testTargetList = append(testTargetList, &TestTarget{
+							Name: "AgentPodV6IP_" + podname + "_" + podips.IPv6,
+							Addr: net.JoinHostPort(podips.IPv6, agentPort),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	// get service
+	var agentV4Url, agentV6Url *k8sObjManager.ServiceAccessUrl
+	if config.AgentConfig.Configmap.EnableIPv4 {
+		agentV4Url, e = k8sObjManager.GetK8sObjManager().GetServiceAccessUrl(ctx, config.AgentConfig.ServiceV4Name, config.AgentConfig.PodNamespace, serviceAccessPortName)
+		if e != nil {
+			logger.Sugar().Errorf("failed to get agent ipv4 service url , error=%v", e)
+		}
+	}
+	if config.AgentConfig.Configmap.EnableIPv6 {
+		agentV6Url, e = k8sObjManager.GetK8sObjManager().GetServiceAccessUrl(ctx, config.AgentConfig.ServiceV6Name, config.AgentConfig.PodNamespace, serviceAccessPortName)
+		if e != nil {
+			logger.Sugar().Errorf("failed to get agent ipv6 service url , error=%v", e)
+		}
+	}
+
+	if *target.ClusterIP {
+		// ----------------------- test clusterIP ipv4
+		if target.IPv4 != nil && *(target.IPv4) {
+			if agentV4Url != nil && len(agentV4Url.ClusterIPUrl) > 0 {
+				testTargetList = append(testTargetList, &TestTarget{
+					Name: "AgentClusterV4IP_" + agentV4Url.ClusterIPUrl[0],
+					Addr: agentV4Url.ClusterIPUrl[0],
+				})
+			} else {
+				finalfailureReason = "failed to get cluster IPv4 IP"
+			}
+		} else {
+			logger.Sugar().Debugf("ignore test agent cluster ipv4 ip")
+		}
+
+		// ----------------------- test clusterIP ipv6
+		if target.IPv6 != nil && *(target.IPv6) {
+			if agentV6Url != nil && len(agentV6Url.ClusterIPUrl) > 0 {
+				testTargetList = append(testTargetList, &TestTarget{
+					Name: "AgentClusterV6IP_" + agentV6Url.ClusterIPUrl[0],
+					Addr: agentV6Url.ClusterIPUrl[0],
+				})
+			} else {
+				finalfailureReason = "failed to get cluster IPv6 IP"
+			}
+		} else {
+			logger.Sugar().Debugf("ignore test agent cluster ipv6 ip")
+		}
+	}
+
+	if *target.NodePort {
+		// get node ip
+		localNodeIpv4, localNodeIpv6, e := k8sObjManager.GetK8sObjManager().GetNodeIP(ctx, config.AgentConfig.LocalNodeName)
+		if e != nil {
+			logger.Sugar().Errorf("failed to get local node %v ip, error=%v", config.AgentConfig.LocalNodeName, e)
+		} else {
+			logger.Sugar().Debugf("local node %v ip: ipv4=%v, ipv6=%v", config.AgentConfig.LocalNodeName, localNodeIpv4, localNodeIpv6)
+		}
+
+		// ----------------------- test node port
+		if target.IPv4 != nil && *(target.IPv4) {
+			if agentV4Url != nil && agentV4Url.NodePort != 0 && len(localNodeIpv4) != 0 {
+				testTargetList = append(testTargetList, &TestTarget{
+					Name: "AgentNodePortV4IP_" + localNodeIpv4 + "_" + fmt.Sprintf("%v", agentV4Url.NodePort),
+					Addr: net.JoinHostPort(localNodeIpv4, fmt.Sprintf("%d", agentV4Url.NodePort)),
+				})
+			} else {
+				finalfailureReason = "failed to get nodePort IPv4 address"
+			}
+		} else {
+			logger.Sugar().Debugf("ignore test agent nodePort ipv4")
+		}
+
+		if target.IPv6 != nil && *(target.IPv6) {
+			if agentV6Url != nil && agentV6Url.NodePort != 0 && len(localNodeIpv6) != 0 {
+				testTargetList = append(testTargetList, &TestTarget{
+					Name: "AgentNodePortV6IP_" + localNodeIpv6 + "_" + fmt.Sprintf("%v", agentV6Url.NodePort),
+					Addr: net.JoinHostPort(localNodeIpv6, fmt.Sprintf("%d", agentV6Url.NodePort)),
+				})
+			} else {
+				finalfailureReason = "failed to get nodePort IPv6 address"
+			}
+		} else {
+			logger.Sugar().Debugf("ignore test agent nodePort ipv6")
+		}
+	}
+
+	// ------------------------ implement for agent case and selected-pod case
+	reportList := make([]v1beta1.NetUdpTaskDetail, 0, len(testTargetList))
+
+	var wg sync.WaitGroup
+	var l lock.Mutex
+	for _, item := range testTargetList {
+		wg.Add(1)
+		go func(wg *sync.WaitGroup, l *lock.Mutex, t TestTarget) {
+			d := &loadUdp.UdpRequestData{
+				ServerAddr:           t.Addr,
+				PerPacketTimeoutInMs: request.PerPacketTimeoutInMS,
+				PacketRate:           request.PacketRate,
+				DurationInSecond:     request.DurationInSecond,
+				PayloadSizeInByte:    request.PayloadSizeInByte,
+				EnableLatencyMetric:  target.EnableLatencyMetric,
+			}
+			logger.Sugar().Debugf("implement test %v, request %v ", t.Name, *d)
+			_, span := tracing.StartTargetSpan(ctx, t.Name)
+			failureReason, itemReport := SendRequestAndReport(logger.With(zap.String("address", t.Addr)), t.Name, d, successCondition)
+			tracing.EndSpan(span, failureReason)
+			l.Lock()
+			if len(failureReason) > 0 {
+				finalfailureReason = fmt.Sprintf("test %v: %v", t.Name, failureReason)
+			}
+			reportList = append(reportList, itemReport)
+			l.Unlock()
+			wg.Done()
+		}(&wg, &l, *item)
+	}
+	wg.Wait()
+
+	logger.Sugar().Infof("plugin finished all udp request tests")
+
+	// ----------------------- aggregate report
+	task := &v1beta1.NetUdpTask{}
+	task.Detail = reportList
+	task.TargetType = v1beta1.NetUdpTaskName
+	task.TargetNumber = int64(len(testTargetList))
+	if len(finalfailureReason) > 0 {
+		logger.Sugar().Errorf("plugin finally failed, %v", finalfailureReason)
+		task.FailureReason = pointer.String(finalfailureReason)
+		task.Succeed = false
+	} else {
+		task.Succeed = true
+	}
+
+	task.SystemResource = resourceStats.Stats()
+	resourceStats.Stop()
+	task.TotalRunningLoad = rt.QpsStats()
+	return finalfailureReason, task, err
+}
+
+func (s *PluginNetUdp) SetReportWithTask(report *v1beta1.Report, task types.Task) error {
+	netUdpTask, ok := task.(*v1beta1.NetUdpTask)
+	if !ok {
+		return fmt.Errorf("task type %v doesn't match NetUdpTask", task.KindTask())
+	}
+	report.TaskNetUdp = netUdpTask
+	return nil
+}
+
+func getTargetPodIP(ctx context.Context, runtimeName, runtimeKind string, multus bool) (k8sObjManager.PodIps, error) {
+	var podIPs k8sObjManager.PodIps
+	var err error
+	switch runtimeKind {
+	case config.KindDaemonSet:
+		if multus {
+			podIPs, err = k8sObjManager.GetK8sObjManager().ListDaemonsetPodMultusIPs(ctx, runtimeName, config.AgentConfig.PodNamespace)
+		} else {
+			podIPs, err = k8sObjManager.GetK8sObjManager().ListDaemonsetPodIPs(ctx, runtimeName, config.AgentConfig.PodNamespace)
+		}
+	case config.KindDeployment:
+		if multus {
+			podIPs, err = k8sObjManager.GetK8sObjManager().ListDeployPodMultusIPs(ctx, runtimeName, config.AgentConfig.PodNamespace)
+		} else {
+			podIPs, err = k8sObjManager.GetK8sObjManager().ListDeploymentPodIPs(ctx, runtimeName, config.AgentConfig.PodNamespace)
+		}
+	default:
+		return podIPs, fmt.Errorf("runtime kind %s not support ", runtimeKind)
+	}
+
+	return podIPs, err
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package netudp
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/strings/slices"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/loadRequest/loadUdp"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+const (
+	defaultPacketRate           = 100
+	defaultPerPacketTimeoutInMS = 1000
+	defaultPayloadSizeInByte    = 64
+)
+
+func (s *PluginNetUdp) WebhookMutating(logger *zap.Logger, ctx context.Context, obj runtime.Object) error {
+	req, ok := obj.(*crd.NetUdp)
+	if !ok {
+		s := "failed to get NetUdp obj"
+		logger.Error(s)
+		return apierrors.NewBadRequest(s)
+	}
+
+	if req.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if req.Spec.Target == nil {
+		enableIpv4 := types.ControllerConfig.Configmap.EnableIPv4
+		enableIpv6 := types.ControllerConfig.Configmap.EnableIPv6
+		enable := true
+		disable := false
+		m := &crd.NetUdpTarget{
+			Endpoint:        &enable,
+			MultusInterface: &disable,
+			ClusterIP:       &enable,
+			NodePort:        &enable,
+			IPv6:            &enableIpv6,
+			IPv4:            &enableIpv4,
+		}
+		req.Spec.Target = m
+		logger.Sugar().Debugf("set default target for NetUdp %v", req.Name)
+	}
+
+	if req.Spec.Schedule == nil {
+		req.Spec.Schedule = tools.GetDefaultSchedule()
+		logger.Sugar().Debugf("set default SchedulePlan for NetUdp %v", req.Name)
+	}
+
+	if req.Spec.Request == nil {
+		m := &crd.NetUdpRequest{
+			DurationInSecond:     types.ControllerConfig.Configmap.NetHttpDefaultRequestDurationInSecond,
+			PacketRate:           defaultPacketRate,
+			PerPacketTimeoutInMS: defaultPerPacketTimeoutInMS,
+			PayloadSizeInByte:    defaultPayloadSizeInByte,
+		}
+		req.Spec.Request = m
+		logger.Sugar().Debugf("set default Request for NetUdp %v", req.Name)
+	}
+
+	if req.Spec.SuccessCondition == nil {
+		req.Spec.SuccessCondition = tools.GetDefaultNetUdpSuccessCondition()
+		logger.Sugar().Debugf("set default SuccessCondition for NetUdp %v", req.Name)
+	}
+
+	// agentSpec
+	if true {
+		if req.Spec.AgentSpec != nil {
+			if req.Spec.AgentSpec.TerminationGracePeriodMinutes == nil {
+				req.Spec.AgentSpec.TerminationGracePeriodMinutes = &types.ControllerConfig.Configmap.AgentDefaultTerminationGracePeriodMinutes
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PluginNetUdp) WebhookValidateCreate(logger *zap.Logger, ctx context.Context, obj runtime.Object) error {
+	r, ok := obj.(*crd.NetUdp)
+	if !ok {
+		s := "failed to get NetUdp obj"
+		logger.Error(s)
+		return apierrors.NewBadRequest(s)
+	}
+	logger.Sugar().Debugf("NetUdp: %+v", r)
+
+	// validate Schedule
+	if true {
+		if err := tools.ValidataCrdSchedule(r.Spec.Schedule); err != nil {
+			s := fmt.Sprintf("NetUdp %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate request
+	if true {
+		if r.Spec.Request.PacketRate >= types.ControllerConfig.Configmap.NetUdpRequestMaxQPS {
+			s := fmt.Sprintf("NetUdp %v requires packetRate %v bigger than maximum %v", r.Name, r.Spec.Request.PacketRate, types.ControllerConfig.Configmap.NetUdpRequestMaxQPS)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.PerPacketTimeoutInMS > int(r.Spec.Schedule.RoundTimeoutMinute*60*1000) {
+			s := fmt.Sprintf("NetUdp %v requires PerPacketTimeoutInMS %v ms smaller than Schedule.RoundTimeoutMinute %vm ", r.Name, r.Spec.Request.PerPacketTimeoutInMS, r.Spec.Schedule.RoundTimeoutMinute)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.DurationInSecond > int(r.Spec.Schedule.RoundTimeoutMinute*60) {
+			s := fmt.Sprintf("NetUdp %v requires request.DurationInSecond %vs smaller than Schedule.RoundTimeoutMinute %vm ", r.Name, r.Spec.Request.DurationInSecond, r.Spec.Schedule.RoundTimeoutMinute)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if r.Spec.Request.PayloadSizeInByte < loadUdp.PacketHeaderSize {
+			s := fmt.Sprintf("NetUdp %v, request.PayloadSizeInByte %v must not be smaller than %v", r.Name, r.Spec.Request.PayloadSizeInByte, loadUdp.PacketHeaderSize)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate target
+	if true {
+		if r.Spec.Target != nil {
+			if r.Spec.Target.IPv4 != nil && *(r.Spec.Target.IPv4) && !types.ControllerConfig.Configmap.EnableIPv4 {
+				s := fmt.Sprintf("NetUdp %v TestIPv4, but kdoctor ipv4 feature is disabled", r.Name)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+			if r.Spec.Target.IPv6 != nil && *(r.Spec.Target.IPv6) && !types.ControllerConfig.Configmap.EnableIPv6 {
+				s := fmt.Sprintf("NetUdp %v TestIPv6, but kdoctor ipv6 feature is disabled", r.Name)
+				logger.Error(s)
+				return apierrors.NewBadRequest(s)
+			}
+		}
+	}
+
+	// validate SuccessCondition
+	if true {
+		c := r.Spec.SuccessCondition
+		if c.MaxLossPercentage == nil && c.MaxJitterInMs == nil && c.MaxOutOfOrderCounts == nil && c.MaxDuplicateCounts == nil && c.MeanAccessDelayInMs == nil {
+			s := fmt.Sprintf("NetUdp %v, no SuccessCondition specified in the spec", r.Name)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if c.MaxLossPercentage != nil && (*(c.MaxLossPercentage) > 100 || *(c.MaxLossPercentage) < 0) {
+			s := fmt.Sprintf("NetUdp %v, SuccessCondition.MaxLossPercentage %v must be in range [0, 100]", r.Name, *(c.MaxLossPercentage))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if c.MaxJitterInMs != nil && *(c.MaxJitterInMs) < 0 {
+			s := fmt.Sprintf("NetUdp %v, SuccessCondition.MaxJitterInMs %v must not be smaller than 0", r.Name, *(c.MaxJitterInMs))
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate ReportSinks
+	if true {
+		if err := tools.ValidateReportSinks(r.Spec.ReportSinks); err != nil {
+			s := fmt.Sprintf("NetUdp %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate Baseline
+	if true {
+		if err := tools.ValidateBaseline(r.Spec.Baseline, r.Spec.Schedule, r.Spec.Target != nil && r.Spec.Target.EnableLatencyMetric); err != nil {
+			s := fmt.Sprintf("NetUdp %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate AgentSpec
+	if true {
+		if r.Spec.AgentSpec != nil {
+			if !slices.Contains(types.TaskRuntimes, r.Spec.AgentSpec.Kind) {
+				return apierrors.NewBadRequest(fmt.Sprintf("Invalid agent runtime kind %s", r.Spec.AgentSpec.Kind))
+			}
+		}
+	}
+
+	return nil
+}
+
+// it is not allowed to modify crd, except the suspend of the schedule
+func (s *PluginNetUdp) WebhookValidateUpdate(logger *zap.Logger, ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldNetUdp := oldObj.(*crd.NetUdp)
+	newNetUdp := newObj.(*crd.NetUdp)
+
+	// only the suspend of the schedule is allowed to modify
+	oldSpec := oldNetUdp.Spec.DeepCopy()
+	tools.IgnoreScheduleSuspend(oldSpec.Schedule, newNetUdp.Spec.Schedule)
+	if !reflect.DeepEqual(*oldSpec, newNetUdp.Spec) {
+		return apierrors.NewBadRequest(fmt.Sprintf("it's not allowed to modify NetUdp %s Spec", oldNetUdp.Name))
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
+	"github.com/kdoctor-io/kdoctor/pkg/roundAdmission"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// insertNextRound counts the latest round as done, and inserts the record of the next round or finishes the task
+func insertNextRound(logger *zap.Logger, newStatus *crd.TaskStatus, scheduler Schedule, schedulePlan *crd.SchedulePlan) {
+	if *(newStatus.DoneRound) >= *(newStatus.ExpectedRound) && *newStatus.ExpectedRound != -1 {
+		return
+	}
+	latestRecord := newStatus.History[0]
+	n := *(newStatus.DoneRound) + 1
+	newStatus.DoneRound = &n
+
+	if n < *(newStatus.ExpectedRound) || *newStatus.ExpectedRound == -1 {
+		startTime := nextRoundStartTime(scheduler, &latestRecord)
+		newRecord := NewStatusHistoryRecord(startTime, int(n+1), schedulePlan)
+		tmp := append([]crd.StatusHistoryRecord{*newRecord}, newStatus.History...)
+		if len(tmp) > types.ControllerConfig.Configmap.CrdMaxHistory {
+			tmp = tmp[:(types.ControllerConfig.Configmap.CrdMaxHistory)]
+		}
+		newStatus.History = tmp
+
+		logger.Sugar().Infof("insert new record for next round : %+v", *newRecord)
+	} else {
+		newStatus.Finish = true
+		now := metav1.Now()
+		newStatus.FinishTime = &now
+	}
+}
+
+func (s *pluginControllerReconciler) admissionTaskName(obj client.Object) string {
+	return s.crdKind + "." + obj.GetName()
+}
+
+// admissionRoundOf returns the round of the task which asks for the admission
+func (s *pluginControllerReconciler) admissionRoundOf(ctx context.Context, obj client.Object, schedulePlan *crd.SchedulePlan, runtimePodMatchLabels client.MatchingLabels, deadline time.Time) (roundAdmission.Round, error) {
+	// the priority class removed from the configmap is the default priority
+	priority, _ := tools.PriorityOfClass(schedulePlan.PriorityClassName)
+	round := roundAdmission.Round{
+		Task:     s.admissionTaskName(obj),
+		Policy:   schedulePlan.ConcurrencyPolicy,
+		Priority: priority,
+		QPS:      TaskRequestQPS(s.crdKind, obj),
+		Deadline: deadline,
+	}
+	// only the qps budget needs the nodes of the agents
+	if types.ControllerConfig.Configmap.Admission.NodeQPSBudget > 0 {
+		nodes, e := s.GetSpiderAgentNodeList(ctx, runtimePodMatchLabels)
+		if e != nil {
+			return round, e
+		}
+		round.Nodes = nodes
+	}
+	return round, nil
+}
+
+// admitRound asks for the admission of the round which is about to start, and returns the result when the round is delayed or skipped
+func (s *pluginControllerReconciler) admitRound(logger *zap.Logger, ctx context.Context, obj client.Object, newStatus *crd.TaskStatus, scheduler Schedule, schedulePlan *crd.SchedulePlan, runtimePodMatchLabels client.MatchingLabels, taskName string, nowTime time.Time) (*reconcile.Result, error) {
+	latestRecord := &(newStatus.History[0])
+	roundTimeout := time.Duration(schedulePlan.RoundTimeoutMinute) * time.Minute
+
+	// the late round has the whole round timeout after it starts
+	late := latestRecord.ScheduledTimeStamp != nil || !nowTime.Before(latestRecord.DeadLineTimeStamp.Time)
+	deadline := latestRecord.DeadLineTimeStamp.Time
+	if late {
+		deadline = nowTime.Add(roundTimeout)
+	}
+	round, e := s.admissionRoundOf(ctx, obj, schedulePlan, runtimePodMatchLabels, deadline)
+	if e != nil {
+		logger.Sugar().Errorf("failed to get the admission of round %v, error=%v", latestRecord.RoundNumber, e)
+		return nil, e
+	}
+
+	decision := s.admission.Admit(round, nowTime)
+	switch {
+	case decision.Skipped:
+		logger.Sugar().Infof("round %v is skipped, reason=%v", latestRecord.RoundNumber, decision.Reason)
+		latestRecord.Status = crd.StatusHistoryRecordStatusSkipped
+		latestRecord.AdmissionReason = decision.Reason
+		latestRecord.EndTimeStamp = &metav1.Time{Time: nowTime}
+		s.recordAdmissionEvent(obj, crd.TaskReasonRoundSkipped, fmt.Sprintf("round %d skipped: %s", latestRecord.RoundNumber, decision.Reason))
+		insertNextRound(logger, newStatus, scheduler, schedulePlan)
+		return &reconcile.Result{Requeue: true}, nil
+
+	case !decision.Admitted:
+		logger.Sugar().Debugf("round %v is delayed, reason=%v", latestRecord.RoundNumber, decision.Reason)
+		if latestRecord.ScheduledTimeStamp == nil {
+			scheduled := latestRecord.StartTimeStamp
+			latestRecord.ScheduledTimeStamp = &scheduled
+		}
+		if len(latestRecord.AdmissionReason) == 0 {
+			s.recordAdmissionEvent(obj, crd.TaskReasonRoundDelayed, fmt.Sprintf("round %d delayed: %s", latestRecord.RoundNumber, decision.Reason))
+		}
+		latestRecord.AdmissionReason = decision.Reason
+		return &reconcile.Result{
+			RequeueAfter: time.Duration(types.ControllerConfig.Configmap.TaskPollIntervalInSecond) * time.Second,
+		}, nil
+
+	case late:
+		logger.Sugar().Infof("round %v starts late at %v", latestRecord.RoundNumber, nowTime)
+		if latestRecord.ScheduledTimeStamp == nil {
+			scheduled := latestRecord.StartTimeStamp
+			latestRecord.ScheduledTimeStamp = &scheduled
+		}
+		latestRecord.StartTimeStamp = metav1.NewTime(nowTime)
+		latestRecord.DeadLineTimeStamp = metav1.NewTime(deadline)
+		latestRecord.Status = crd.StatusHistoryRecordStatusOngoing
+		traceRoundScheduled(ctx, obj, taskName, latestRecord)
+		return &reconcile.Result{Requeue: true}, nil
+	}
+
+	// the round admitted on time starts as scheduled
+	return nil, nil
+}
+
+// resumeRound records the ongoing round in the admission, which is lost when the controller restarts
+func (s *pluginControllerReconciler) resumeRound(ctx context.Context, obj client.Object, schedulePlan *crd.SchedulePlan, runtimePodMatchLabels client.MatchingLabels, record *crd.StatusHistoryRecord) {
+	if s.admission == nil || s.admission.IsRunning(s.admissionTaskName(obj)) {
+		return
+	}
+	round, e := s.admissionRoundOf(ctx, obj, schedulePlan, runtimePodMatchLabels, record.DeadLineTimeStamp.Time)
+	if e != nil {
+		s.logger.Sugar().Errorf("failed to resume the admission of round %v of %v, error=%v", record.RoundNumber, s.admissionTaskName(obj), e)
+		return
+	}
+	s.admission.Resume(round, time.Now())
+}
+
+// releaseRound lets the waiting rounds start after the round finishes
+func (s *pluginControllerReconciler) releaseRound(obj client.Object) {
+	if s.admission == nil {
+		return
+	}
+	s.admission.Release(s.admissionTaskName(obj))
+}
+
+// releaseDeletedTask removes the running and the waiting rounds of the deleted task, so they do not block the other rounds
+func (s *pluginControllerReconciler) releaseDeletedTask(name string) {
+	if s.admission == nil {
+		return
+	}
+	s.admission.Release(s.crdKind + "." + name)
+}
+
+func (s *pluginControllerReconciler) recordAdmissionEvent(obj client.Object, reason, message string) {
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.Event(obj, corev1.EventTypeNormal, reason, message)
+}
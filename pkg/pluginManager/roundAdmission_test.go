@@ -0,0 +1,101 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/roundAdmission"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+var _ = Describe("test round admission", Label("admission"), func() {
+
+	newTask := func(name, policy string, start time.Time) (*crd.Netdns, *crd.TaskStatus) {
+		schedule := "0 10"
+		task := &crd.Netdns{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: crd.NetdnsSpec{
+				Schedule: &crd.SchedulePlan{Schedule: &schedule, RoundTimeoutMinute: 5, RoundNumber: 3, ConcurrencyPolicy: policy},
+				Request:  &crd.NetdnsRequest{QPS: 10},
+			},
+		}
+		done, expected := int64(0), int64(3)
+		status := &crd.TaskStatus{
+			DoneRound:     &done,
+			ExpectedRound: &expected,
+			History:       []crd.StatusHistoryRecord{*NewStatusHistoryRecord(start, 1, task.Spec.Schedule)},
+		}
+		return task, status
+	}
+
+	It("delay and skip the rounds", func() {
+		configmap := types.ControllerConfig.Configmap
+		DeferCleanup(func() { types.ControllerConfig.Configmap = configmap })
+		types.ControllerConfig.Configmap.CrdMaxHistory = 10
+		types.ControllerConfig.Configmap.TaskPollIntervalInSecond = 5
+
+		recorder := record.NewFakeRecorder(10)
+		queue := roundAdmission.NewQueue(0, time.Minute)
+		s := &pluginControllerReconciler{
+			logger:    logger.NewStdoutLogger("debug", "pluginManager Test"),
+			crdKind:   KindNameNetdns,
+			recorder:  recorder,
+			admission: queue,
+		}
+		log := s.logger
+		ctx := context.Background()
+		scheduled := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+		// another task runs alone
+		queue.Resume(roundAdmission.Round{Task: "NetReach.upgrade", Policy: crd.ConcurrencyPolicyQueue, Deadline: time.Now().Add(time.Hour)}, time.Now())
+
+		task, status := newTask("queued", crd.ConcurrencyPolicyAllow, scheduled)
+		result, status, err := s.UpdateStatus(log, ctx, task, status, task.Spec.Schedule, nil, nil, nil, "Netdns.queued")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+		Expect(status.History[0].Status).To(Equal(crd.StatusHistoryRecordStatusNotstarted))
+		Expect(status.History[0].AdmissionReason).To(Equal("the round of NetReach.upgrade with policy Queue is running"))
+		Expect(status.History[0].ScheduledTimeStamp.Time).To(Equal(scheduled))
+		Expect(recorder.Events).To(Receive(Equal(corev1.EventTypeNormal + " " + crd.TaskReasonRoundDelayed + " round 1 delayed: the round of NetReach.upgrade with policy Queue is running")))
+
+		// the round of Forbid is skipped, and the next round is scheduled
+		forbidden, forbiddenStatus := newTask("forbidden", crd.ConcurrencyPolicyForbid, scheduled)
+		_, forbiddenStatus, err = s.UpdateStatus(log, ctx, forbidden, forbiddenStatus, forbidden.Spec.Schedule, nil, nil, nil, "Netdns.forbidden")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*forbiddenStatus.DoneRound).To(Equal(int64(1)))
+		Expect(forbiddenStatus.History).To(HaveLen(2))
+		Expect(forbiddenStatus.History[1].Status).To(Equal(crd.StatusHistoryRecordStatusSkipped))
+		Expect(forbiddenStatus.History[1].AdmissionReason).To(Equal("the round of NetReach.upgrade with policy Queue is running"))
+		Expect(forbiddenStatus.History[0].StartTimeStamp.Time).To(Equal(scheduled.Add(10 * time.Minute)))
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeNormal + " " + crd.TaskReasonRoundSkipped + " round 1 skipped")))
+
+		// the delayed round starts after the running round finishes, with the whole round timeout
+		queue.Release("NetReach.upgrade")
+		result, status, err = s.UpdateStatus(log, ctx, task, status, task.Spec.Schedule, nil, nil, nil, "Netdns.queued")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		record := status.History[0]
+		Expect(record.Status).To(Equal(crd.StatusHistoryRecordStatusOngoing))
+		Expect(record.StartTimeStamp.Time).To(BeTemporally(">", scheduled))
+		Expect(record.DeadLineTimeStamp.Sub(record.StartTimeStamp.Time)).To(Equal(5 * time.Minute))
+		Expect(queue.IsRunning("Netdns.queued")).To(BeTrue())
+
+		// the next round keeps the schedule
+		status.History[0].Status = crd.StatusHistoryRecordStatusSucceed
+		scheduler, err := NewSchedule(task.Spec.Schedule)
+		Expect(err).NotTo(HaveOccurred())
+		insertNextRound(log, status, scheduler, task.Spec.Schedule)
+		Expect(status.History[0].StartTimeStamp.Time).To(Equal(scheduled.Add(10 * time.Minute)))
+	})
+})
@@ -0,0 +1,109 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+func scheduleSuspended(schedulePlan *crd.SchedulePlan) bool {
+	return schedulePlan.Suspend != nil && *schedulePlan.Suspend
+}
+
+// suspendRound moves the scheduled round which is not started to its next time in the schedule after now,
+// so the time passed during the suspension is not made up after the task resumes
+func suspendRound(record *crd.StatusHistoryRecord, scheduler Schedule, schedulePlan *crd.SchedulePlan, nowTime time.Time) {
+	start := record.StartTimeStamp.Time
+	if record.ScheduledTimeStamp != nil {
+		start = record.ScheduledTimeStamp.Time
+	}
+	if start.After(nowTime) {
+		return
+	}
+	*record = *NewStatusHistoryRecord(nextScheduleAfter(scheduler, start, nowTime), record.RoundNumber, schedulePlan)
+}
+
+// nextScheduleAfter returns the first time after now in the schedule from the start
+func nextScheduleAfter(scheduler Schedule, start, nowTime time.Time) time.Time {
+	for !start.After(nowTime) {
+		next := scheduler.Next(start)
+		// the crontab never matches again
+		if !next.After(start) {
+			break
+		}
+		start = next
+	}
+	return start
+}
+
+// runNow adds the manual round when the run-now annotation of the task changes, and returns whether the status is changed.
+// The manual round takes the number of the scheduled round which is not started, and the scheduled round runs after it
+func (s *pluginControllerReconciler) runNow(logger *zap.Logger, obj client.Object, newStatus *crd.TaskStatus, schedulePlan *crd.SchedulePlan, nowTime time.Time) bool {
+	value := obj.GetAnnotations()[crd.AnnotationRunNow]
+	if len(value) == 0 || value == newStatus.LastRunNow {
+		return false
+	}
+	latestRecord := &(newStatus.History[0])
+	// the manual round waits for the running round
+	if latestRecord.Status == crd.StatusHistoryRecordStatusOngoing {
+		return false
+	}
+	newStatus.LastRunNow = value
+
+	if newStatus.Resource != nil && newStatus.Resource.RuntimeStatus == crd.RuntimeDeleted {
+		msg := fmt.Sprintf("the runtime %v of the task is deleted, ignore the run-now %v", newStatus.Resource.RuntimeName, value)
+		logger.Warn(msg)
+		s.recordTriggerEvent(obj, corev1.EventTypeWarning, msg)
+		return true
+	}
+
+	// the manual round which is not started yet, for example held by the admission, already counts in the expected rounds
+	pendingManual := latestRecord.Manual && latestRecord.Status == crd.StatusHistoryRecordStatusNotstarted
+	if *newStatus.ExpectedRound != -1 && !pendingManual {
+		n := *newStatus.ExpectedRound + 1
+		newStatus.ExpectedRound = &n
+	}
+	var record *crd.StatusHistoryRecord
+	if latestRecord.Status == crd.StatusHistoryRecordStatusNotstarted {
+		scheduled := latestRecord.StartTimeStamp
+		if latestRecord.ScheduledTimeStamp != nil {
+			scheduled = *latestRecord.ScheduledTimeStamp
+		}
+		record = NewStatusHistoryRecord(nowTime, latestRecord.RoundNumber, schedulePlan)
+		record.ScheduledTimeStamp = &scheduled
+		record.Manual = true
+		newStatus.History[0] = *record
+	} else {
+		// all rounds of the task are done
+		newStatus.Finish = false
+		newStatus.FinishTime = nil
+		record = NewStatusHistoryRecord(nowTime, latestRecord.RoundNumber+1, schedulePlan)
+		record.Manual = true
+		tmp := append([]crd.StatusHistoryRecord{*record}, newStatus.History...)
+		if len(tmp) > types.ControllerConfig.Configmap.CrdMaxHistory {
+			tmp = tmp[:(types.ControllerConfig.Configmap.CrdMaxHistory)]
+		}
+		newStatus.History = tmp
+	}
+
+	msg := fmt.Sprintf("round %d is added by the run-now %v", record.RoundNumber, value)
+	logger.Info(msg)
+	s.recordTriggerEvent(obj, corev1.EventTypeNormal, msg)
+	return true
+}
+
+func (s *pluginControllerReconciler) recordTriggerEvent(obj client.Object, eventType, message string) {
+	if s.recorder == nil {
+		return
+	}
+	s.recorder.Event(obj, eventType, crd.TaskReasonRunNow, message)
+}
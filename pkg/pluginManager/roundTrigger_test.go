@@ -0,0 +1,133 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+var _ = Describe("test suspend and run-now", Label("trigger"), func() {
+
+	It("suspend the scheduled rounds and add the manual rounds", func() {
+		configmap := types.ControllerConfig.Configmap
+		DeferCleanup(func() { types.ControllerConfig.Configmap = configmap })
+		types.ControllerConfig.Configmap.CrdMaxHistory = 10
+		types.ControllerConfig.Configmap.TaskPollIntervalInSecond = 5
+
+		recorder := record.NewFakeRecorder(10)
+		s := &pluginControllerReconciler{
+			logger:   logger.NewStdoutLogger("debug", "pluginManager Test"),
+			crdKind:  KindNameNetdns,
+			recorder: recorder,
+		}
+		log := s.logger
+		ctx := context.Background()
+
+		schedule := "0 10"
+		task := &crd.Netdns{
+			ObjectMeta: metav1.ObjectMeta{Name: "maintenance"},
+			Spec: crd.NetdnsSpec{
+				Schedule: &crd.SchedulePlan{Schedule: &schedule, RoundTimeoutMinute: 5, RoundNumber: 2, Suspend: pointer.Bool(true)},
+			},
+		}
+		scheduled := time.Now().Add(-time.Minute).Truncate(time.Second)
+		done, expected := int64(0), int64(2)
+		status := &crd.TaskStatus{
+			DoneRound:     &done,
+			ExpectedRound: &expected,
+			History:       []crd.StatusHistoryRecord{*NewStatusHistoryRecord(scheduled, 1, task.Spec.Schedule)},
+		}
+
+		// the suspended round moves to the next time in the schedule
+		result, status, err := s.UpdateStatus(log, ctx, task, status, task.Spec.Schedule, nil, nil, nil, "Netdns.maintenance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 8*time.Minute))
+		Expect(status.History[0].Status).To(Equal(crd.StatusHistoryRecordStatusNotstarted))
+		Expect(status.History[0].StartTimeStamp.Time).To(Equal(scheduled.Add(10 * time.Minute)))
+
+		// the manual round runs even if the task is suspended, and takes the number of the scheduled round
+		task.Annotations = map[string]string{crd.AnnotationRunNow: "1"}
+		_, status, err = s.UpdateStatus(log, ctx, task, status, task.Spec.Schedule, nil, nil, nil, "Netdns.maintenance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*status.ExpectedRound).To(Equal(int64(3)))
+		Expect(status.LastRunNow).To(Equal("1"))
+		Expect(status.History).To(HaveLen(1))
+		Expect(status.History[0].Manual).To(BeTrue())
+		Expect(status.History[0].RoundNumber).To(Equal(1))
+		Expect(status.History[0].ScheduledTimeStamp.Time).To(Equal(scheduled.Add(10 * time.Minute)))
+		Expect(recorder.Events).To(Receive(Equal(corev1.EventTypeNormal + " " + crd.TaskReasonRunNow + " round 1 is added by the run-now 1")))
+
+		time.Sleep(10 * time.Millisecond)
+		_, status, err = s.UpdateStatus(log, ctx, task, status, task.Spec.Schedule, nil, nil, nil, "Netdns.maintenance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.History[0].Status).To(Equal(crd.StatusHistoryRecordStatusOngoing))
+		Expect(*status.ExpectedRound).To(Equal(int64(3)))
+
+		// the scheduled round keeps its time after the manual round
+		status.History[0].Status = crd.StatusHistoryRecordStatusSucceed
+		scheduler, err := NewSchedule(task.Spec.Schedule)
+		Expect(err).NotTo(HaveOccurred())
+		insertNextRound(log, status, scheduler, task.Spec.Schedule)
+		Expect(status.History[0].RoundNumber).To(Equal(2))
+		Expect(status.History[0].Manual).To(BeFalse())
+		Expect(status.History[0].StartTimeStamp.Time).To(Equal(scheduled.Add(10 * time.Minute)))
+
+		// the manual round is added after all rounds are done
+		finished := int64(3)
+		status.DoneRound = &finished
+		status.History[0].Status = crd.StatusHistoryRecordStatusSucceed
+		status.Finish = true
+		task.Annotations[crd.AnnotationRunNow] = "2"
+		_, status, err = s.UpdateStatus(log, ctx, task, status, task.Spec.Schedule, nil, nil, nil, "Netdns.maintenance")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*status.ExpectedRound).To(Equal(int64(4)))
+		Expect(status.Finish).To(BeFalse())
+		Expect(status.History[0].RoundNumber).To(Equal(3))
+		Expect(status.History[0].Manual).To(BeTrue())
+	})
+
+	It("count the manual round once for the run-nows before it starts", func() {
+		s := &pluginControllerReconciler{
+			logger:   logger.NewStdoutLogger("debug", "pluginManager Test"),
+			crdKind:  KindNameNetdns,
+			recorder: record.NewFakeRecorder(10),
+		}
+		schedule := "0 10"
+		plan := &crd.SchedulePlan{Schedule: &schedule, RoundTimeoutMinute: 5, RoundNumber: 2}
+		task := &crd.Netdns{ObjectMeta: metav1.ObjectMeta{Name: "queued"}}
+		scheduled := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+		done, expected := int64(0), int64(2)
+		status := &crd.TaskStatus{
+			DoneRound:     &done,
+			ExpectedRound: &expected,
+			History:       []crd.StatusHistoryRecord{*NewStatusHistoryRecord(scheduled, 1, plan)},
+		}
+
+		task.Annotations = map[string]string{crd.AnnotationRunNow: "1"}
+		Expect(s.runNow(s.logger, task, status, plan, time.Now())).To(BeTrue())
+		Expect(*status.ExpectedRound).To(Equal(int64(3)))
+
+		// the manual round is held, for example by the admission, when the second run-now comes
+		task.Annotations[crd.AnnotationRunNow] = "2"
+		Expect(s.runNow(s.logger, task, status, plan, time.Now())).To(BeTrue())
+		Expect(*status.ExpectedRound).To(Equal(int64(3)))
+		Expect(status.LastRunNow).To(Equal("2"))
+		Expect(status.History).To(HaveLen(1))
+		Expect(status.History[0].Manual).To(BeTrue())
+		Expect(status.History[0].RoundNumber).To(Equal(1))
+		Expect(status.History[0].ScheduledTimeStamp.Time).To(Equal(scheduled))
+	})
+})
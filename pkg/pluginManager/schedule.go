@@ -4,12 +4,21 @@
 package pluginManager
 
 import (
-	"github.com/robfig/cron"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/robfig/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
 )
 
+// maxPlannedRounds is the number of the next rounds shown in the status
+const maxPlannedRounds = 5
+
 type Schedule interface {
 	Next(time.Time) time.Time
 	StartTime(time.Time) time.Time
@@ -20,33 +29,51 @@ type schedule struct {
 	StartAfterMinute int
 	IntervalMinute   int
 	CronSchedule     cron.Schedule
+	// the crontab matches the time in the location
+	Location *time.Location
 }
 
-func NewSchedule(s string) Schedule {
+func NewSchedule(schedulePlan *crd.SchedulePlan) (Schedule, error) {
+	if schedulePlan == nil || schedulePlan.Schedule == nil {
+		return nil, fmt.Errorf("schedule is empty")
+	}
 	scheduler := &schedule{}
-	args := strings.Split(s, " ")
+	s := *schedulePlan.Schedule
+	args := strings.Fields(s)
 
 	// crontab
 	if len(args) == 5 {
 		scheduler.IsCron = true
-		cronSchedule, _ := cron.ParseStandard(s)
+		cronSchedule, location, err := tools.ParseCrontab(s, schedulePlan.TimeZone)
+		if err != nil {
+			return nil, err
+		}
 		scheduler.CronSchedule = cronSchedule
+		scheduler.Location = location
 		// simple
 	} else if len(args) == 2 {
 		scheduler.IsCron = false
-		StartAfterMinute, _ := strconv.Atoi(args[0])
+		StartAfterMinute, err := strconv.Atoi(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("the format of the schedule is incorrect, it should be number, err: %v", err)
+		}
 		scheduler.StartAfterMinute = StartAfterMinute
-		intervalMinute, _ := strconv.Atoi(args[1])
+		intervalMinute, err := strconv.Atoi(args[1])
+		if err != nil {
+			return nil, fmt.Errorf("the format of the schedule is incorrect, it should be number, err: %v", err)
+		}
 		scheduler.IntervalMinute = intervalMinute
+	} else {
+		return nil, fmt.Errorf("the format of the schedule %q is incorrect, it should be two or five fields", s)
 	}
-	return scheduler
+	return scheduler, nil
 
 }
 
 func (s *schedule) Next(t time.Time) time.Time {
 
 	if s.IsCron {
-		return s.CronSchedule.Next(t)
+		return s.cronNext(t)
 	}
 
 	return t.Add(time.Duration(s.IntervalMinute) * time.Minute)
@@ -55,8 +82,80 @@ func (s *schedule) Next(t time.Time) time.Time {
 func (s *schedule) StartTime(t time.Time) time.Time {
 
 	if s.IsCron {
-		return s.CronSchedule.Next(t)
+		return s.cronNext(t)
 	}
 
 	return t.Add(time.Duration(s.StartAfterMinute) * time.Minute)
 }
+
+// cronNext matches the crontab with the wall clock of its time zone, and returns the time in the local time zone like the other times of the status.
+// The wall clock skipped by the DST change runs at the same wall clock of the new offset, for example, 02:30 runs at 03:30 in the day when
+// the summer time begins, and the wall clock repeated by the DST change only runs once.
+func (s *schedule) cronNext(t time.Time) time.Time {
+	// the wall clock is matched in UTC, where each wall clock occurs exactly once
+	wall := wallClock(t.In(s.Location))
+	for {
+		wall = s.CronSchedule.Next(wall)
+		if wall.IsZero() {
+			return wall
+		}
+		if next := s.firstTimeOfWallClock(wall); next.After(t) {
+			return next.Local()
+		}
+	}
+}
+
+// wallClock returns the wall clock of the time in UTC
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// firstTimeOfWallClock returns the time of the wall clock in the location, and the earlier one when the wall clock is repeated
+func (s *schedule) firstTimeOfWallClock(wall time.Time) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), s.Location)
+	_, offset := t.Zone()
+	// the offset before the DST change is bigger when the wall clock is repeated, and no DST change moves the clock by more than 3 hours
+	if _, before := t.Add(-3 * time.Hour).Zone(); before > offset {
+		earlier := t.Add(-time.Duration(before-offset) * time.Second)
+		if wallClock(earlier).Equal(wall) {
+			return earlier
+		}
+	}
+	return t
+}
+
+// nextRoundStartTime returns the start time of the round after the record
+func nextRoundStartTime(scheduler Schedule, record *crd.StatusHistoryRecord) time.Time {
+	// the manual round runs ahead of the scheduled round, which keeps its time
+	if record.Manual && record.ScheduledTimeStamp != nil {
+		return record.ScheduledTimeStamp.Time
+	}
+	// the delayed round does not shift the schedule of the next rounds
+	if record.ScheduledTimeStamp != nil {
+		return scheduler.Next(record.ScheduledTimeStamp.Time)
+	}
+	return scheduler.Next(record.StartTimeStamp.Time)
+}
+
+// plannedRoundTimes returns the start times of the next rounds which are not started, at most maxPlannedRounds,
+// by the latest round and the counters of the rounds of the task or the suite
+func plannedRoundTimes(scheduler Schedule, schedulePlan *crd.SchedulePlan, finish bool, latestRecord *crd.StatusHistoryRecord, expectedRound, doneRound *int64) []metav1.Time {
+	if finish || scheduleSuspended(schedulePlan) || latestRecord == nil || expectedRound == nil || doneRound == nil {
+		return nil
+	}
+	first := latestRecord.StartTimeStamp.Time
+	count := *expectedRound - *doneRound
+	if latestRecord.Status != crd.StatusHistoryRecordStatusNotstarted {
+		first = nextRoundStartTime(scheduler, latestRecord)
+		count--
+	}
+	// the rounds never end
+	if *expectedRound == -1 || count > maxPlannedRounds {
+		count = maxPlannedRounds
+	}
+	var times []metav1.Time
+	for t := first; int64(len(times)) < count && !t.IsZero(); t = scheduler.Next(t) {
+		times = append(times, metav1.NewTime(t))
+	}
+	return times
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+var _ = Describe("test schedule", Label("schedule"), func() {
+
+	It("match the crontab in the time zone across the DST change", func() {
+		crontab := "0 9 * * *"
+		scheduler, err := NewSchedule(&crd.SchedulePlan{Schedule: &crontab, TimeZone: "Europe/Berlin"})
+		Expect(err).NotTo(HaveOccurred())
+
+		// the DST of Europe/Berlin begins at 2023-03-26
+		start := time.Date(2023, 3, 25, 7, 0, 0, 0, time.UTC)
+		first := scheduler.Next(start)
+		Expect(first.UTC()).To(Equal(time.Date(2023, 3, 25, 8, 0, 0, 0, time.UTC)))
+		Expect(first.Location()).To(Equal(time.Local))
+		// 09:00 of the summer time is one hour earlier in UTC
+		second := scheduler.Next(first)
+		Expect(second.UTC()).To(Equal(time.Date(2023, 3, 26, 7, 0, 0, 0, time.UTC)))
+
+		// the time zone without DST
+		shanghai, err := NewSchedule(&crd.SchedulePlan{Schedule: &crontab, TimeZone: "Asia/Shanghai"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shanghai.StartTime(start).UTC()).To(Equal(time.Date(2023, 3, 26, 1, 0, 0, 0, time.UTC)))
+	})
+
+	It("run the round in the hour skipped by the DST change", func() {
+		crontab := "30 2 * * *"
+		scheduler := mustSchedule(&crd.SchedulePlan{Schedule: &crontab, TimeZone: "Europe/Berlin"})
+
+		// the clock of Europe/Berlin jumps from 02:00 to 03:00 at 2023-03-26, and the round runs at 03:30 of the summer time
+		first := scheduler.Next(time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC))
+		Expect(first.UTC()).To(Equal(time.Date(2023, 3, 26, 1, 30, 0, 0, time.UTC)))
+		second := scheduler.Next(first)
+		Expect(second.UTC()).To(Equal(time.Date(2023, 3, 27, 0, 30, 0, 0, time.UTC)))
+	})
+
+	It("run the round once in the hour repeated by the DST change", func() {
+		crontab := "30 2 * * *"
+		scheduler := mustSchedule(&crd.SchedulePlan{Schedule: &crontab, TimeZone: "Europe/Berlin"})
+
+		// the clock of Europe/Berlin goes back from 03:00 to 02:00 at 2023-10-29, and the round only runs at the first 02:30
+		first := scheduler.Next(time.Date(2023, 10, 28, 12, 0, 0, 0, time.UTC))
+		Expect(first.UTC()).To(Equal(time.Date(2023, 10, 29, 0, 30, 0, 0, time.UTC)))
+		second := scheduler.Next(first)
+		Expect(second.UTC()).To(Equal(time.Date(2023, 10, 30, 1, 30, 0, 0, time.UTC)))
+		// the time in the repeated hour does not run the round again
+		Expect(scheduler.Next(time.Date(2023, 10, 29, 1, 10, 0, 0, time.UTC))).To(Equal(second))
+
+		hourly := "30 * * * *"
+		scheduler = mustSchedule(&crd.SchedulePlan{Schedule: &hourly, TimeZone: "Europe/Berlin"})
+		Expect(scheduler.Next(time.Date(2023, 10, 29, 0, 30, 0, 0, time.UTC)).UTC()).To(Equal(time.Date(2023, 10, 29, 2, 30, 0, 0, time.UTC)))
+	})
+
+	It("report the error of the schedule", func() {
+		for _, item := range []struct {
+			schedule string
+			timeZone string
+			reason   string
+		}{
+			{schedule: "0 61 * * *", reason: "crontab configuration error"},
+			{schedule: "0 9 * * *", timeZone: "Mars/Olympus", reason: "is not a valid IANA time zone"},
+			{schedule: "0 0 30 2 *", reason: "never matches"},
+			{schedule: "a 1", reason: "it should be number"},
+			{schedule: "0 1 2", reason: "it should be two or five fields"},
+		} {
+			_, err := NewSchedule(&crd.SchedulePlan{Schedule: pointer.String(item.schedule), TimeZone: item.timeZone})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(item.reason))
+
+			err = tools.ValidataCrdSchedule(&crd.SchedulePlan{Schedule: pointer.String(item.schedule), TimeZone: item.timeZone, RoundTimeoutMinute: 1})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(item.reason))
+		}
+
+		err := tools.ValidataCrdSchedule(&crd.SchedulePlan{Schedule: pointer.String("0 10"), TimeZone: "Asia/Shanghai", RoundTimeoutMinute: 1})
+		Expect(err).To(MatchError(ContainSubstring("only works with the crontab schedule")))
+		err = tools.ValidataCrdSchedule(&crd.SchedulePlan{Schedule: pointer.String("30  2 * * *"), TimeZone: "Asia/Tokyo", RoundTimeoutMinute: 1})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("show the next planned rounds in the status", func() {
+		configmap := types.ControllerConfig.Configmap
+		DeferCleanup(func() { types.ControllerConfig.Configmap = configmap })
+		types.ControllerConfig.Configmap.CrdMaxHistory = 10
+		types.ControllerConfig.Configmap.TaskPollIntervalInSecond = 5
+
+		s := &pluginControllerReconciler{
+			logger:  logger.NewStdoutLogger("debug", "pluginManager Test"),
+			crdKind: KindNameNetdns,
+		}
+		log := s.logger
+		ctx := context.Background()
+
+		schedule := "5 10"
+		task := &crd.Netdns{
+			ObjectMeta: metav1.ObjectMeta{Name: "planned"},
+			Spec: crd.NetdnsSpec{
+				Schedule: &crd.SchedulePlan{Schedule: &schedule, RoundTimeoutMinute: 5, RoundNumber: 3},
+			},
+		}
+		_, status, err := s.UpdateStatus(log, ctx, task, &crd.TaskStatus{}, task.Spec.Schedule, nil, nil, nil, "Netdns.planned")
+		Expect(err).NotTo(HaveOccurred())
+		start := status.History[0].StartTimeStamp.Time
+		Expect(status.NextRoundTimes).To(HaveLen(3))
+		Expect(status.NextRoundTimes[0].Time).To(Equal(start))
+		Expect(status.NextRoundTimes[2].Time).To(Equal(start.Add(20 * time.Minute)))
+
+		// the running round is not planned
+		status.History[0].Status = crd.StatusHistoryRecordStatusOngoing
+		Expect(plannedRoundTimes(mustSchedule(task.Spec.Schedule), task.Spec.Schedule, status.Finish, &status.History[0], status.ExpectedRound, status.DoneRound)).To(HaveLen(2))
+
+		// the endless task shows the next 5 rounds
+		task.Spec.Schedule.RoundNumber = -1
+		endless := int64(-1)
+		status.ExpectedRound = &endless
+		planned := plannedRoundTimes(mustSchedule(task.Spec.Schedule), task.Spec.Schedule, status.Finish, &status.History[0], status.ExpectedRound, status.DoneRound)
+		Expect(planned).To(HaveLen(5))
+		Expect(planned[0].Time).To(Equal(start.Add(10 * time.Minute)))
+
+		// the suspended task has no planned round
+		task.Spec.Schedule.Suspend = pointer.Bool(true)
+		Expect(plannedRoundTimes(mustSchedule(task.Spec.Schedule), task.Spec.Schedule, status.Finish, &status.History[0], status.ExpectedRound, status.DoneRound)).To(BeEmpty())
+	})
+})
+
+func mustSchedule(plan *crd.SchedulePlan) Schedule {
+	scheduler, err := NewSchedule(plan)
+	Expect(err).NotTo(HaveOccurred())
+	return scheduler
+}
@@ -0,0 +1,308 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+)
+
+const (
+	// the labels of the tasks created by the suite
+	SuiteLabelName = "kdoctor.io/suite"
+	SuiteLabelStep = "kdoctor.io/suite-step"
+)
+
+// suiteStepKind returns the kind of the task of the step, and it is empty when the step has no spec
+func suiteStepKind(step *crd.TaskSuiteStep) string {
+	switch {
+	case step.NetReach != nil:
+		return KindNameNetReach
+	case step.AppHttpHealthy != nil:
+		return KindNameAppHttpHealthy
+	case step.Netdns != nil:
+		return KindNameNetdns
+	}
+	return ""
+}
+
+// suiteStepTaskName returns the name of the task of the step in the round of the suite
+func suiteStepTaskName(suiteName, stepName string, roundNumber int) string {
+	return fmt.Sprintf("%s-%s-round%d", suiteName, stepName, roundNumber)
+}
+
+// ValidateSuiteTaskNames checks the names of the tasks of the steps in the last round, which must be valid label values,
+// and the names are checked with the largest round number of int32 when the suite runs permanently
+func ValidateSuiteTaskNames(suiteName string, roundNumber int64, steps []crd.TaskSuiteStep) error {
+	lastRound := roundNumber
+	if lastRound <= 0 {
+		lastRound = math.MaxInt32
+	}
+	for _, step := range steps {
+		name := suiteStepTaskName(suiteName, step.Name, int(lastRound))
+		if len(name) > k8svalidation.DNS1123LabelMaxLength {
+			return fmt.Errorf("the name %q of the task of step %q is longer than %d characters", name, step.Name, k8svalidation.DNS1123LabelMaxLength)
+		}
+	}
+	return nil
+}
+
+// newSuiteStepTask builds the task of the step for the round of the suite, which runs one round right after it is created
+func newSuiteStepTask(suite *crd.TaskSuite, step *crd.TaskSuiteStep, roundNumber int) client.Object {
+	schedule := fmt.Sprintf("0 %d", suite.Spec.Schedule.RoundTimeoutMinute)
+	plan := &crd.SchedulePlan{
+		Schedule:           &schedule,
+		RoundTimeoutMinute: suite.Spec.Schedule.RoundTimeoutMinute,
+		RoundNumber:        1,
+		// the tasks of the steps are admitted like the suite
+		ConcurrencyPolicy: suite.Spec.Schedule.ConcurrencyPolicy,
+		PriorityClassName: suite.Spec.Schedule.PriorityClassName,
+	}
+	meta := metav1.ObjectMeta{
+		Name: suiteStepTaskName(suite.Name, step.Name, roundNumber),
+		Labels: map[string]string{
+			SuiteLabelName: suite.Name,
+			SuiteLabelStep: step.Name,
+		},
+		OwnerReferences: []metav1.OwnerReference{
+			*metav1.NewControllerRef(suite, crd.GroupVersion.WithKind(KindNameTaskSuite)),
+		},
+	}
+
+	switch {
+	case step.NetReach != nil:
+		spec := step.NetReach.DeepCopy()
+		spec.Schedule = plan
+		return &crd.NetReach{ObjectMeta: meta, Spec: *spec}
+	case step.AppHttpHealthy != nil:
+		spec := step.AppHttpHealthy.DeepCopy()
+		spec.Schedule = plan
+		return &crd.AppHttpHealthy{ObjectMeta: meta, Spec: *spec}
+	case step.Netdns != nil:
+		spec := step.Netdns.DeepCopy()
+		spec.Schedule = plan
+		return &crd.Netdns{ObjectMeta: meta, Spec: *spec}
+	}
+	return nil
+}
+
+// newSuiteTaskObject returns the empty task object of the kind
+func newSuiteTaskObject(kind string) client.Object {
+	switch kind {
+	case KindNameNetReach:
+		return &crd.NetReach{}
+	case KindNameAppHttpHealthy:
+		return &crd.AppHttpHealthy{}
+	case KindNameNetdns:
+		return &crd.Netdns{}
+	}
+	return nil
+}
+
+// ValidateSuiteSteps checks the names, the specs and the dependencies of the steps, and the dependencies must not have a cycle
+func ValidateSuiteSteps(steps []crd.TaskSuiteStep) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("no step in the suite")
+	}
+
+	index := map[string]int{}
+	for i, step := range steps {
+		if errs := k8svalidation.IsDNS1123Label(step.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid step name %q: %v", step.Name, strings.Join(errs, ", "))
+		}
+		if _, ok := index[step.Name]; ok {
+			return fmt.Errorf("duplicated step name %q", step.Name)
+		}
+		index[step.Name] = i
+
+		n := 0
+		for _, set := range []bool{step.NetReach != nil, step.AppHttpHealthy != nil, step.Netdns != nil} {
+			if set {
+				n++
+			}
+		}
+		if n != 1 {
+			return fmt.Errorf("step %q requires exactly one of netReach, appHttpHealthy and netdns", step.Name)
+		}
+	}
+
+	for _, step := range steps {
+		seen := map[string]bool{}
+		for _, dep := range step.DependsOn {
+			if dep == step.Name {
+				return fmt.Errorf("step %q depends on itself", step.Name)
+			}
+			if _, ok := index[dep]; !ok {
+				return fmt.Errorf("step %q depends on unknown step %q", step.Name, dep)
+			}
+			if seen[dep] {
+				return fmt.Errorf("step %q depends on step %q more than once", step.Name, dep)
+			}
+			seen[dep] = true
+		}
+	}
+
+	// depth-first search for the cycle, the state is 1 when the step is in the path and 2 when it is checked
+	state := make([]int, len(steps))
+	var path []string
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case 1:
+			start := 0
+			for j, name := range path {
+				if name == steps[i].Name {
+					start = j
+				}
+			}
+			return fmt.Errorf("the dependencies of the steps have a cycle: %s", strings.Join(append(path[start:], steps[i].Name), " -> "))
+		case 2:
+			return nil
+		}
+		state[i] = 1
+		path = append(path, steps[i].Name)
+		for _, dep := range steps[i].DependsOn {
+			if err := visit(index[dep]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = 2
+		return nil
+	}
+	for i := range steps {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewSuiteRoundRecord returns the record of the round of the suite, whose steps are all pending
+func NewSuiteRoundRecord(startTime time.Time, roundNumber int, steps []crd.TaskSuiteStep) *crd.TaskSuiteRoundRecord {
+	record := &crd.TaskSuiteRoundRecord{
+		Status:         crd.StatusHistoryRecordStatusNotstarted,
+		RoundNumber:    roundNumber,
+		StartTimeStamp: metav1.NewTime(startTime),
+		Steps:          make([]crd.TaskSuiteStepStatus, 0, len(steps)),
+	}
+	for i := range steps {
+		record.Steps = append(record.Steps, crd.TaskSuiteStepStatus{
+			Name:   steps[i].Name,
+			Kind:   suiteStepKind(&steps[i]),
+			Status: crd.TaskSuiteStepStatusPending,
+		})
+	}
+	return record
+}
+
+func suiteStepDone(status string) bool {
+	switch status {
+	case crd.TaskSuiteStepStatusSucceed, crd.TaskSuiteStepStatusFail, crd.TaskSuiteStepStatusSkipped:
+		return true
+	}
+	return false
+}
+
+// readySuiteSteps skips the pending steps whose dependencies are skipped or fail without continueOnFailure,
+// and returns the pending steps whose dependencies are all done, in the order of the steps
+func readySuiteSteps(steps []crd.TaskSuiteStep, record *crd.TaskSuiteRoundRecord) []string {
+	states := map[string]*crd.TaskSuiteStepStatus{}
+	for i := range record.Steps {
+		states[record.Steps[i].Name] = &record.Steps[i]
+	}
+	continueOnFailure := map[string]bool{}
+	for _, step := range steps {
+		continueOnFailure[step.Name] = step.ContinueOnFailure
+	}
+
+	// the skipped step skips the steps depending on it, so repeat until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, step := range steps {
+			state, ok := states[step.Name]
+			if !ok || state.Status != crd.TaskSuiteStepStatusPending {
+				continue
+			}
+			for _, dep := range step.DependsOn {
+				d, ok := states[dep]
+				if !ok {
+					continue
+				}
+				if d.Status == crd.TaskSuiteStepStatusSkipped || (d.Status == crd.TaskSuiteStepStatusFail && !continueOnFailure[dep]) {
+					state.Status = crd.TaskSuiteStepStatusSkipped
+					state.FailureReason = fmt.Sprintf("dependency %s is %s", dep, d.Status)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	ready := []string{}
+	for _, step := range steps {
+		state, ok := states[step.Name]
+		if !ok || state.Status != crd.TaskSuiteStepStatusPending {
+			continue
+		}
+		done := true
+		for _, dep := range step.DependsOn {
+			if d, ok := states[dep]; ok && !suiteStepDone(d.Status) {
+				done = false
+				break
+			}
+		}
+		if done {
+			ready = append(ready, step.Name)
+		}
+	}
+	return ready
+}
+
+// suiteStepResult returns the result of the task of the step after all its rounds are done
+func suiteStepResult(status *crd.TaskStatus) (done bool, result, failureReason string) {
+	if !status.Finish {
+		return false, "", ""
+	}
+	record := latestFinishedRecord(status)
+	switch {
+	case record == nil:
+		return true, crd.TaskSuiteStepStatusFail, "no round of the task is finished"
+	case record.Status == crd.StatusHistoryRecordStatusSucceed:
+		return true, crd.TaskSuiteStepStatusSucceed, ""
+	}
+	return true, crd.TaskSuiteStepStatusFail, roundFailedMessage(record)
+}
+
+// finishSuiteRound sets the result of the round of the suite when all steps are done, and the round fails when any step fails or is skipped
+func finishSuiteRound(record *crd.TaskSuiteRoundRecord, now time.Time) bool {
+	reasons := []string{}
+	for _, step := range record.Steps {
+		if !suiteStepDone(step.Status) {
+			return false
+		}
+		if step.Status != crd.TaskSuiteStepStatusSucceed {
+			reasons = append(reasons, fmt.Sprintf("step %s %s", step.Name, step.Status))
+		}
+	}
+
+	record.Status = crd.StatusHistoryRecordStatusSucceed
+	if len(reasons) > 0 {
+		record.Status = crd.StatusHistoryRecordStatusFail
+		record.FailureReason = strings.Join(reasons, ", ")
+	}
+	end := metav1.NewTime(now)
+	record.EndTimeStamp = &end
+	duration := now.Sub(record.StartTimeStamp.Time).String()
+	record.Duration = &duration
+	return true
+}
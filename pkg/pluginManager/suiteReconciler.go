@@ -0,0 +1,270 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// taskSuiteReconciler runs the rounds of the suite, and each round creates the tasks of the steps in the order of the dependencies
+type taskSuiteReconciler struct {
+	client   client.Client
+	logger   *zap.Logger
+	recorder record.EventRecorder
+}
+
+func (s *taskSuiteReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	instance := crd.TaskSuite{}
+	if err := s.client.Get(ctx, req.NamespacedName, &instance); err != nil {
+		s.logger.Sugar().Errorf("unable to fetch obj , error=%v", err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	logger := s.logger.With(zap.String(KindNameTaskSuite, instance.Name))
+	logger.Sugar().Debugf("reconcile handle %v", instance)
+
+	if instance.DeletionTimestamp != nil {
+		s.logger.Sugar().Debugf("ignore deleting suite %v", req)
+		return ctrl.Result{}, nil
+	}
+
+	oldStatus := instance.Status.DeepCopy()
+	result, newStatus, err := s.UpdateSuiteStatus(logger, ctx, &instance)
+	if err != nil {
+		logger.Sugar().Errorf("failed to UpdateSuiteStatus, will retry it, error=%v", err)
+		return ctrl.Result{}, err
+	}
+	if newStatus != nil && !reflect.DeepEqual(newStatus, oldStatus) {
+		instance.Status = *newStatus
+		if err := s.client.Status().Update(ctx, &instance); err != nil {
+			logger.Sugar().Errorf("failed to update status, will retry it, error=%v", err)
+			return ctrl.Result{}, err
+		}
+		logger.Sugar().Debugf("succeeded update status, newStatus=%+v", newStatus)
+		s.recordSuiteEvents(&instance, oldStatus, newStatus)
+	}
+	if result != nil {
+		return *result, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// UpdateSuiteStatus starts the steps whose dependencies are done, collects the results of the running steps,
+// and moves to the next round after all steps of the round are done
+func (s *taskSuiteReconciler) UpdateSuiteStatus(logger *zap.Logger, ctx context.Context, suite *crd.TaskSuite) (result *reconcile.Result, suiteStatus *crd.TaskSuiteStatus, e error) {
+	newStatus := suite.Status.DeepCopy()
+	plan := suite.Spec.Schedule
+	scheduler, e := NewSchedule(plan)
+	if e != nil {
+		logger.Sugar().Errorf("failed to parse the schedule of suite %v, error=%v", suite.Name, e)
+		return nil, nil, e
+	}
+	defer func() {
+		if suiteStatus != nil {
+			var latestRecord *crd.StatusHistoryRecord
+			if len(suiteStatus.History) > 0 {
+				latestRecord = &crd.StatusHistoryRecord{Status: suiteStatus.History[0].Status, StartTimeStamp: suiteStatus.History[0].StartTimeStamp}
+			}
+			suiteStatus.NextRoundTimes = plannedRoundTimes(scheduler, plan, suiteStatus.Finish, latestRecord, suiteStatus.ExpectedRound, suiteStatus.DoneRound)
+			updateSuiteConditions(suiteStatus, suite.GetGeneration())
+		}
+	}()
+	nextInterval := time.Duration(types.ControllerConfig.Configmap.TaskPollIntervalInSecond) * time.Second
+	nowTime := time.Now()
+
+	// init new instance first
+	if newStatus.ExpectedRound == nil || len(newStatus.History) == 0 {
+		m := int64(0)
+		newStatus.DoneRound = &m
+		newStatus.ExpectedRound = &plan.RoundNumber
+		newRecord := NewSuiteRoundRecord(scheduler.StartTime(nowTime), 1, suite.Spec.Steps)
+		newStatus.History = append(newStatus.History, *newRecord)
+		logger.Sugar().Debugf("initialize the first round of suite %v: %+v", suite.Name, *newRecord)
+		return &reconcile.Result{Requeue: true}, newStatus, nil
+	}
+
+	// done suite
+	if newStatus.Finish {
+		return nil, nil, nil
+	}
+
+	latestRecord := &(newStatus.History[0])
+	if latestRecord.Status == crd.StatusHistoryRecordStatusNotstarted {
+		// the suspended suite does not start the round, and the time passed is not made up after it resumes
+		if scheduleSuspended(plan) {
+			latestRecord.StartTimeStamp = metav1.NewTime(nextScheduleAfter(scheduler, latestRecord.StartTimeStamp.Time, nowTime))
+			logger.Sugar().Debugf("suite %v is suspended, round %v waits until %v", suite.Name, latestRecord.RoundNumber, latestRecord.StartTimeStamp)
+			return &reconcile.Result{RequeueAfter: time.Until(latestRecord.StartTimeStamp.Time)}, newStatus, nil
+		}
+		if nowTime.Before(latestRecord.StartTimeStamp.Time) {
+			logger.Sugar().Infof("suite %v wait for next round %v at %v", suite.Name, latestRecord.RoundNumber, latestRecord.StartTimeStamp)
+			return &reconcile.Result{RequeueAfter: time.Until(latestRecord.StartTimeStamp.Time)}, newStatus, nil
+		}
+		latestRecord.Status = crd.StatusHistoryRecordStatusOngoing
+	}
+
+	// collect the results of the running steps
+	for i := range latestRecord.Steps {
+		step := &latestRecord.Steps[i]
+		if step.Status != crd.TaskSuiteStepStatusRunning {
+			continue
+		}
+		task := newSuiteTaskObject(step.Kind)
+		if task == nil {
+			return nil, nil, fmt.Errorf("unsupported kind %v of step %v", step.Kind, step.Name)
+		}
+		if err := s.client.Get(ctx, client.ObjectKey{Name: step.TaskName}, task); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, nil, err
+			}
+			step.Status = crd.TaskSuiteStepStatusFail
+			step.FailureReason = fmt.Sprintf("%s %s is deleted", step.Kind, step.TaskName)
+		} else if done, stepResult, reason := suiteStepResult(taskStatusOf(task)); done {
+			step.Status = stepResult
+			step.FailureReason = reason
+		} else {
+			continue
+		}
+		logger.Sugar().Infof("step %v of round %v %s", step.Name, latestRecord.RoundNumber, step.Status)
+	}
+
+	// start the steps whose dependencies are done
+	for _, name := range readySuiteSteps(suite.Spec.Steps, latestRecord) {
+		var step *crd.TaskSuiteStep
+		for i := range suite.Spec.Steps {
+			if suite.Spec.Steps[i].Name == name {
+				step = &suite.Spec.Steps[i]
+			}
+		}
+		task := newSuiteStepTask(suite, step, latestRecord.RoundNumber)
+		if err := s.client.Create(ctx, task); err != nil && !errors.IsAlreadyExists(err) {
+			return nil, nil, fmt.Errorf("failed to create %v %v for step %v, error=%v", suiteStepKind(step), task.GetName(), name, err)
+		}
+		logger.Sugar().Infof("start step %v of round %v with %v %v", name, latestRecord.RoundNumber, suiteStepKind(step), task.GetName())
+		for i := range latestRecord.Steps {
+			if latestRecord.Steps[i].Name == name {
+				latestRecord.Steps[i].Status = crd.TaskSuiteStepStatusRunning
+				latestRecord.Steps[i].TaskName = task.GetName()
+			}
+		}
+	}
+
+	if !finishSuiteRound(latestRecord, nowTime) {
+		// the tasks of the steps are watched, and poll them in case of the missed event
+		return &reconcile.Result{RequeueAfter: nextInterval}, newStatus, nil
+	}
+
+	logger.Sugar().Infof("round %v of suite %v %s", latestRecord.RoundNumber, suite.Name, latestRecord.Status)
+	lastRoundStatus := latestRecord.Status
+	newStatus.LastRoundStatus = &lastRoundStatus
+
+	n := *(newStatus.DoneRound) + 1
+	newStatus.DoneRound = &n
+	if n < *(newStatus.ExpectedRound) || *newStatus.ExpectedRound == -1 {
+		startTime := scheduler.Next(latestRecord.StartTimeStamp.Time)
+		newRecord := NewSuiteRoundRecord(startTime, int(n+1), suite.Spec.Steps)
+		tmp := append([]crd.TaskSuiteRoundRecord{*newRecord}, newStatus.History...)
+		if len(tmp) > types.ControllerConfig.Configmap.CrdMaxHistory {
+			// the tasks of the rounds removed from the history are not needed any more
+			s.deleteSuiteRoundTasks(logger, ctx, tmp[types.ControllerConfig.Configmap.CrdMaxHistory:])
+			tmp = tmp[:(types.ControllerConfig.Configmap.CrdMaxHistory)]
+		}
+		newStatus.History = tmp
+		logger.Sugar().Infof("insert new record for next round : %+v", *newRecord)
+	} else {
+		newStatus.Finish = true
+		now := metav1.Now()
+		newStatus.FinishTime = &now
+	}
+
+	return &reconcile.Result{Requeue: true}, newStatus, nil
+}
+
+func (s *taskSuiteReconciler) deleteSuiteRoundTasks(logger *zap.Logger, ctx context.Context, records []crd.TaskSuiteRoundRecord) {
+	for _, r := range records {
+		for _, step := range r.Steps {
+			if len(step.TaskName) == 0 {
+				continue
+			}
+			task := newSuiteTaskObject(step.Kind)
+			if task == nil {
+				continue
+			}
+			task.SetName(step.TaskName)
+			if err := s.client.Delete(ctx, task, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				logger.Sugar().Errorf("failed to delete %v %v of round %v, error=%v", step.Kind, step.TaskName, r.RoundNumber, err)
+			}
+		}
+	}
+}
+
+// recordSuiteEvents records the events of the steps and the rounds which fail in the new status.
+// It is called after the new status is written, so the reconcile retried for a conflict does not record the event twice
+func (s *taskSuiteReconciler) recordSuiteEvents(suite *crd.TaskSuite, oldStatus, newStatus *crd.TaskSuiteStatus) {
+	if s.recorder == nil {
+		return
+	}
+	oldRecords := map[int]*crd.TaskSuiteRoundRecord{}
+	for i := range oldStatus.History {
+		oldRecords[oldStatus.History[i].RoundNumber] = &oldStatus.History[i]
+	}
+	for i := len(newStatus.History) - 1; i >= 0; i-- {
+		record := &newStatus.History[i]
+		oldRecord := oldRecords[record.RoundNumber]
+		failedSteps := map[string]bool{}
+		if oldRecord != nil {
+			for _, step := range oldRecord.Steps {
+				failedSteps[step.Name] = step.Status == crd.TaskSuiteStepStatusFail
+			}
+		}
+		for _, step := range record.Steps {
+			if step.Status == crd.TaskSuiteStepStatusFail && !failedSteps[step.Name] {
+				s.recorder.Event(suite, corev1.EventTypeWarning, crd.TaskSuiteReasonStepFailed,
+					fmt.Sprintf("step %s of round %d failed: %s", step.Name, record.RoundNumber, step.FailureReason))
+			}
+		}
+		if record.Status == crd.StatusHistoryRecordStatusFail && (oldRecord == nil || oldRecord.Status != crd.StatusHistoryRecordStatusFail) {
+			s.recorder.Event(suite, corev1.EventTypeWarning, crd.TaskReasonRoundFailed, suiteRoundFailedMessage(record))
+		}
+	}
+}
+
+func suiteRoundFailedMessage(record *crd.TaskSuiteRoundRecord) string {
+	return fmt.Sprintf("round %d failed: %s", record.RoundNumber, record.FailureReason)
+}
+
+// updateSuiteConditions keeps the Ready, Degraded and Finished conditions by the status of the suite
+func updateSuiteConditions(status *crd.TaskSuiteStatus, generation int64) {
+	var latest *finishedRound
+	for i := range status.History {
+		record := &status.History[i]
+		if record.Status == crd.StatusHistoryRecordStatusSucceed || record.Status == crd.StatusHistoryRecordStatusFail {
+			latest = &finishedRound{roundNumber: record.RoundNumber, succeeded: record.Status == crd.StatusHistoryRecordStatusSucceed, failedMessage: suiteRoundFailedMessage(record)}
+			break
+		}
+	}
+	updateRoundConditions(&status.Conditions, generation, "suite", latest, status.DoneRound, status.ExpectedRound, status.Finish)
+}
+
+func (s *taskSuiteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).For(&crd.TaskSuite{}).
+		Owns(&crd.NetReach{}).
+		Owns(&crd.AppHttpHealthy{}).
+		Owns(&crd.Netdns{}).
+		Complete(s)
+}
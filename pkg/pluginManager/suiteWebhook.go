@@ -0,0 +1,130 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/pluginManager/tools"
+	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+)
+
+type taskSuiteWebhookHander struct {
+	logger *zap.Logger
+	// the plugins validate the tasks of the steps
+	plugins map[string]plugintypes.ChainingPlugin
+}
+
+var _ webhook.CustomValidator = (*taskSuiteWebhookHander)(nil)
+
+// mutating webhook
+func (s *taskSuiteWebhookHander) Default(ctx context.Context, obj runtime.Object) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%s : %w", MsgErrCtx, err)
+	}
+	s.logger.Sugar().Debugf("mutating kind %v", req.Kind.Kind)
+
+	r, ok := obj.(*crd.TaskSuite)
+	if !ok {
+		msg := "failed to get TaskSuite obj"
+		s.logger.Error(msg)
+		return apierrors.NewBadRequest(msg)
+	}
+	// run one round right after the suite is created
+	if r.Spec.Schedule == nil {
+		r.Spec.Schedule = tools.GetDefaultSchedule()
+		schedule := fmt.Sprintf("0 %d", r.Spec.Schedule.RoundTimeoutMinute)
+		r.Spec.Schedule.Schedule = &schedule
+		s.logger.Sugar().Debugf("set default SchedulePlan for TaskSuite %v", r.Name)
+	}
+	return nil
+}
+
+func (s *taskSuiteWebhookHander) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	logger := s.logger.Named("validatingCreateWebhook")
+	r, ok := obj.(*crd.TaskSuite)
+	if !ok {
+		s := "failed to get TaskSuite obj"
+		logger.Error(s)
+		return apierrors.NewBadRequest(s)
+	}
+	logger.Sugar().Infof("obj: %+v", r)
+
+	// validate Schedule
+	if true {
+		if err := tools.ValidataCrdSchedule(r.Spec.Schedule); err != nil {
+			s := fmt.Sprintf("TaskSuite %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate Steps
+	if true {
+		if err := ValidateSuiteSteps(r.Spec.Steps); err != nil {
+			s := fmt.Sprintf("TaskSuite %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+		if err := ValidateSuiteTaskNames(r.Name, r.Spec.Schedule.RoundNumber, r.Spec.Steps); err != nil {
+			s := fmt.Sprintf("TaskSuite %v : %v", r.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	// validate the tasks of the steps, which are created with the schedule of the suite
+	for i := range r.Spec.Steps {
+		step := &r.Spec.Steps[i]
+		plugin, ok := s.plugins[suiteStepKind(step)]
+		if !ok {
+			continue
+		}
+		task := newSuiteStepTask(r, step, 1)
+		if err := plugin.WebhookMutating(logger, ctx, task); err != nil {
+			return err
+		}
+		if err := plugin.WebhookValidateCreate(logger, ctx, task); err != nil {
+			s := fmt.Sprintf("TaskSuite %v, step %v : %v", r.Name, step.Name, err)
+			logger.Error(s)
+			return apierrors.NewBadRequest(s)
+		}
+	}
+
+	return nil
+}
+
+// the rounds of the suite run the steps of the spec, so it is not allowed to modify the spec except the suspend
+func (s *taskSuiteWebhookHander) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldSuite := oldObj.(*crd.TaskSuite)
+	newSuite := newObj.(*crd.TaskSuite)
+
+	// only the suspend of the schedule is allowed to modify
+	oldSpec := oldSuite.Spec.DeepCopy()
+	tools.IgnoreScheduleSuspend(oldSpec.Schedule, newSuite.Spec.Schedule)
+	if !reflect.DeepEqual(*oldSpec, newSuite.Spec) {
+		return apierrors.NewBadRequest(fmt.Sprintf("it's not allowed to modify TaskSuite %s Spec", oldSuite.Name))
+	}
+	return nil
+}
+
+// not registered in ValidatingWebhookConfiguration
+func (s *taskSuiteWebhookHander) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (s *taskSuiteWebhookHander) SetupWebhook(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&crd.TaskSuite{}).WithDefaulter(s).WithValidator(s).RecoverPanic().Complete()
+}
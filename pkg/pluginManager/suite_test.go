@@ -0,0 +1,243 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// the post-upgrade acceptance: dns first, then pod reachability, then app health
+func acceptanceSteps() []crd.TaskSuiteStep {
+	return []crd.TaskSuiteStep{
+		{Name: "dns", Netdns: &crd.NetdnsSpec{}},
+		{Name: "reach", DependsOn: []string{"dns"}, NetReach: &crd.NetReachSpec{}},
+		{Name: "app", DependsOn: []string{"reach"}, AppHttpHealthy: &crd.AppHttpHealthySpec{}},
+	}
+}
+
+func finishSuiteStepTask(c client.Client, kind, name, roundStatus string) {
+	task := newSuiteTaskObject(kind)
+	Expect(c.Get(context.Background(), client.ObjectKey{Name: name}, task)).To(Succeed())
+	status := taskStatusOf(task)
+	status.Finish = true
+	status.History = []crd.StatusHistoryRecord{{RoundNumber: 1, Status: roundStatus, FailureReason: "timeout"}}
+	Expect(c.Status().Update(context.Background(), task)).To(Succeed())
+}
+
+var _ = Describe("test task suite", Label("suite"), func() {
+
+	It("validate the steps", func() {
+		Expect(ValidateSuiteSteps(acceptanceSteps())).To(Succeed())
+
+		Expect(ValidateSuiteSteps(nil)).To(MatchError("no step in the suite"))
+
+		steps := acceptanceSteps()
+		steps[2].Name = "dns"
+		Expect(ValidateSuiteSteps(steps)).To(MatchError(`duplicated step name "dns"`))
+
+		steps = acceptanceSteps()
+		steps[0].NetReach = &crd.NetReachSpec{}
+		Expect(ValidateSuiteSteps(steps)).To(MatchError(`step "dns" requires exactly one of netReach, appHttpHealthy and netdns`))
+
+		steps = acceptanceSteps()
+		steps[1].DependsOn = []string{"upgrade"}
+		Expect(ValidateSuiteSteps(steps)).To(MatchError(`step "reach" depends on unknown step "upgrade"`))
+
+		steps = acceptanceSteps()
+		steps[0].DependsOn = []string{"app"}
+		Expect(ValidateSuiteSteps(steps)).To(MatchError("the dependencies of the steps have a cycle: dns -> app -> reach -> dns"))
+
+		steps = acceptanceSteps()
+		steps[1].Name = "Reach"
+		Expect(ValidateSuiteSteps(steps)).NotTo(Succeed())
+	})
+
+	It("validate the names of the tasks of the steps", func() {
+		Expect(ValidateSuiteTaskNames("upgrade", 3, acceptanceSteps())).To(Succeed())
+		Expect(ValidateSuiteTaskNames("upgrade", -1, acceptanceSteps())).To(Succeed())
+
+		suiteName := strings.Repeat("a", 50)
+		Expect(ValidateSuiteTaskNames(suiteName, 9, acceptanceSteps())).To(Succeed())
+		Expect(ValidateSuiteTaskNames(suiteName, 10, acceptanceSteps())).To(MatchError(ContainSubstring(`of step "reach" is longer than 63 characters`)))
+		Expect(ValidateSuiteTaskNames(suiteName, -1, acceptanceSteps())).NotTo(Succeed())
+	})
+
+	It("start the steps after the dependencies", func() {
+		steps := acceptanceSteps()
+		steps = append(steps, crd.TaskSuiteStep{Name: "pods", NetReach: &crd.NetReachSpec{}})
+		record := NewSuiteRoundRecord(time.Now(), 1, steps)
+		Expect(readySuiteSteps(steps, record)).To(Equal([]string{"dns", "pods"}))
+
+		record.Steps[0].Status = crd.TaskSuiteStepStatusRunning
+		record.Steps[3].Status = crd.TaskSuiteStepStatusRunning
+		Expect(readySuiteSteps(steps, record)).To(BeEmpty())
+
+		record.Steps[0].Status = crd.TaskSuiteStepStatusSucceed
+		Expect(readySuiteSteps(steps, record)).To(Equal([]string{"reach"}))
+	})
+
+	It("skip the steps after the failed step", func() {
+		steps := acceptanceSteps()
+		record := NewSuiteRoundRecord(time.Now(), 1, steps)
+		record.Steps[0].Status = crd.TaskSuiteStepStatusFail
+		Expect(readySuiteSteps(steps, record)).To(BeEmpty())
+		Expect(record.Steps[1].Status).To(Equal(crd.TaskSuiteStepStatusSkipped))
+		Expect(record.Steps[1].FailureReason).To(Equal("dependency dns is fail"))
+		Expect(record.Steps[2].Status).To(Equal(crd.TaskSuiteStepStatusSkipped))
+		Expect(record.Steps[2].FailureReason).To(Equal("dependency reach is skipped"))
+
+		Expect(finishSuiteRound(record, time.Now())).To(BeTrue())
+		Expect(record.Status).To(Equal(crd.StatusHistoryRecordStatusFail))
+		Expect(record.FailureReason).To(Equal("step dns fail, step reach skipped, step app skipped"))
+		status := &crd.TaskSuiteStatus{History: []crd.TaskSuiteRoundRecord{*record}}
+		updateSuiteConditions(status, 1)
+		degraded := meta.FindStatusCondition(status.Conditions, crd.TaskConditionDegraded)
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Message).To(Equal("round 1 failed: step dns fail, step reach skipped, step app skipped"))
+
+		steps[0].ContinueOnFailure = true
+		record = NewSuiteRoundRecord(time.Now(), 1, steps)
+		record.Steps[0].Status = crd.TaskSuiteStepStatusFail
+		Expect(readySuiteSteps(steps, record)).To(Equal([]string{"reach"}))
+		Expect(finishSuiteRound(record, time.Now())).To(BeFalse())
+	})
+
+	It("run the rounds of the suite", func() {
+		configmap := types.ControllerConfig.Configmap
+		DeferCleanup(func() { types.ControllerConfig.Configmap = configmap })
+		types.ControllerConfig.Configmap.CrdMaxHistory = 2
+		types.ControllerConfig.Configmap.TaskPollIntervalInSecond = 5
+		scheme := runtime.NewScheme()
+		Expect(crd.AddToScheme(scheme)).To(Succeed())
+		schedule := "0 1"
+		suite := &crd.TaskSuite{
+			ObjectMeta: metav1.ObjectMeta{Name: "upgrade", UID: "1234"},
+			Spec: crd.TaskSuiteSpec{
+				Schedule: &crd.SchedulePlan{Schedule: &schedule, RoundTimeoutMinute: 1, RoundNumber: 3},
+				Steps:    acceptanceSteps(),
+			},
+		}
+		recorder := record.NewFakeRecorder(10)
+		s := &taskSuiteReconciler{
+			client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(suite).Build(),
+			logger:   logger.NewStdoutLogger("debug", "pluginManager Test"),
+			recorder: recorder,
+		}
+		ctx := context.Background()
+		reconcileSuite := func() *crd.TaskSuiteStatus {
+			_, err := s.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(suite)})
+			Expect(err).NotTo(HaveOccurred())
+			latest := &crd.TaskSuite{}
+			Expect(s.client.Get(ctx, client.ObjectKeyFromObject(suite), latest)).To(Succeed())
+			return &latest.Status
+		}
+
+		status := reconcileSuite()
+		Expect(*status.ExpectedRound).To(Equal(int64(3)))
+		Expect(status.History).To(HaveLen(1))
+		Expect(status.History[0].Status).To(Equal(crd.StatusHistoryRecordStatusNotstarted))
+
+		status = reconcileSuite()
+		Expect(status.History[0].Status).To(Equal(crd.StatusHistoryRecordStatusOngoing))
+		Expect(status.History[0].Steps[0].Status).To(Equal(crd.TaskSuiteStepStatusRunning))
+		Expect(status.History[0].Steps[0].TaskName).To(Equal("upgrade-dns-round1"))
+		Expect(status.History[0].Steps[1].Status).To(Equal(crd.TaskSuiteStepStatusPending))
+
+		dns := &crd.Netdns{}
+		Expect(s.client.Get(ctx, client.ObjectKey{Name: "upgrade-dns-round1"}, dns)).To(Succeed())
+		Expect(dns.Spec.Schedule.RoundNumber).To(Equal(int64(1)))
+		Expect(*dns.Spec.Schedule.Schedule).To(Equal("0 1"))
+		Expect(dns.Labels).To(HaveKeyWithValue(SuiteLabelName, "upgrade"))
+		Expect(metav1.IsControlledBy(dns, suite)).To(BeTrue())
+
+		// the running step waits for its task
+		status = reconcileSuite()
+		Expect(status.History[0].Steps[1].Status).To(Equal(crd.TaskSuiteStepStatusPending))
+
+		finishSuiteStepTask(s.client, KindNameNetdns, "upgrade-dns-round1", crd.StatusHistoryRecordStatusSucceed)
+		status = reconcileSuite()
+		Expect(status.History[0].Steps[0].Status).To(Equal(crd.TaskSuiteStepStatusSucceed))
+		Expect(status.History[0].Steps[1].Status).To(Equal(crd.TaskSuiteStepStatusRunning))
+		Expect(status.History[0].Steps[2].Status).To(Equal(crd.TaskSuiteStepStatusPending))
+
+		finishSuiteStepTask(s.client, KindNameNetReach, "upgrade-reach-round1", crd.StatusHistoryRecordStatusFail)
+		status = reconcileSuite()
+		Expect(status.DoneRound).NotTo(BeNil())
+		Expect(*status.DoneRound).To(Equal(int64(1)))
+		Expect(*status.LastRoundStatus).To(Equal(crd.StatusHistoryRecordStatusFail))
+		Expect(status.History).To(HaveLen(2))
+		Expect(status.History[0].RoundNumber).To(Equal(2))
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " " + crd.TaskSuiteReasonStepFailed + " step reach of round 1 failed: round 1 failed: timeout")))
+		Expect(recorder.Events).To(Receive(Equal(corev1.EventTypeWarning + " " + crd.TaskReasonRoundFailed + " round 1 failed: step reach fail, step app skipped")))
+
+		// the events are not recorded again by the later reconcile
+		status = reconcileSuite()
+		Expect(status.History[0].Status).To(Equal(crd.StatusHistoryRecordStatusNotstarted))
+		Expect(recorder.Events).To(BeEmpty())
+
+		// start the second round now
+		latest := &crd.TaskSuite{}
+		Expect(s.client.Get(ctx, client.ObjectKeyFromObject(suite), latest)).To(Succeed())
+		latest.Status.History[0].StartTimeStamp = metav1.NewTime(time.Now().Add(-time.Minute))
+		Expect(s.client.Status().Update(ctx, latest)).To(Succeed())
+		reconcileSuite()
+		finishSuiteStepTask(s.client, KindNameNetdns, "upgrade-dns-round2", crd.StatusHistoryRecordStatusSucceed)
+		reconcileSuite()
+		finishSuiteStepTask(s.client, KindNameNetReach, "upgrade-reach-round2", crd.StatusHistoryRecordStatusFail)
+		status = reconcileSuite()
+		Expect(*status.DoneRound).To(Equal(int64(2)))
+		Expect(status.History).To(HaveLen(2))
+		Expect(status.History[0].RoundNumber).To(Equal(3))
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " " + crd.TaskSuiteReasonStepFailed + " step reach of round 2 failed")))
+		Expect(recorder.Events).To(Receive(HavePrefix(corev1.EventTypeWarning + " " + crd.TaskReasonRoundFailed + " round 2 failed")))
+		Expect(recorder.Events).To(BeEmpty())
+
+		// the tasks of the round removed from the history are deleted
+		Expect(s.client.Get(ctx, client.ObjectKey{Name: "upgrade-dns-round1"}, &crd.Netdns{})).NotTo(Succeed())
+		Expect(s.client.Get(ctx, client.ObjectKey{Name: "upgrade-reach-round1"}, &crd.NetReach{})).NotTo(Succeed())
+		Expect(s.client.Get(ctx, client.ObjectKey{Name: "upgrade-dns-round2"}, &crd.Netdns{})).To(Succeed())
+	})
+
+	It("record the failed steps and rounds once", func() {
+		recorder := record.NewFakeRecorder(10)
+		s := &taskSuiteReconciler{recorder: recorder}
+		suite := &crd.TaskSuite{ObjectMeta: metav1.ObjectMeta{Name: "upgrade"}}
+
+		oldStatus := &crd.TaskSuiteStatus{History: []crd.TaskSuiteRoundRecord{*NewSuiteRoundRecord(time.Now(), 1, acceptanceSteps())}}
+		oldStatus.History[0].Status = crd.StatusHistoryRecordStatusOngoing
+		oldStatus.History[0].Steps[0].Status = crd.TaskSuiteStepStatusFail
+		oldStatus.History[0].Steps[0].FailureReason = "timeout"
+		newStatus := oldStatus.DeepCopy()
+		newStatus.History[0].Steps[1].Status = crd.TaskSuiteStepStatusFail
+		newStatus.History[0].Steps[1].FailureReason = "timeout"
+		newStatus.History[0].Status = crd.StatusHistoryRecordStatusFail
+		newStatus.History[0].FailureReason = "step dns fail, step reach fail, step app skipped"
+
+		s.recordSuiteEvents(suite, oldStatus, newStatus)
+		Expect(recorder.Events).To(Receive(Equal(corev1.EventTypeWarning + " " + crd.TaskSuiteReasonStepFailed + " step reach of round 1 failed: timeout")))
+		Expect(recorder.Events).To(Receive(Equal(corev1.EventTypeWarning + " " + crd.TaskReasonRoundFailed + " round 1 failed: step dns fail, step reach fail, step app skipped")))
+		Expect(recorder.Events).To(BeEmpty())
+
+		// the reconcile retried after a conflict writes the same status
+		s.recordSuiteEvents(suite, newStatus, newStatus)
+		Expect(recorder.Events).To(BeEmpty())
+	})
+})
@@ -20,8 +20,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	// the time zones of the schedule do not rely on the tzdata of the image
+	_ "time/tzdata"
 
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
 	"github.com/robfig/cron"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
@@ -30,20 +34,20 @@ var DomainRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*\.[a-zA-
 
 func ValidataCrdSchedule(plan *crd.SchedulePlan) error {
 
-	if plan == nil {
+	if plan == nil || plan.Schedule == nil {
 		return errors.New("schedule is empty")
 	}
 
-	args := strings.Split(*plan.Schedule, " ")
+	args := strings.Fields(*plan.Schedule)
 
 	if len(args) == 2 {
 		startAfterMinute, err := strconv.Atoi(args[0])
 		if err != nil {
-			return errors.New("the format of the schedule is incorrect, it should be number")
+			return fmt.Errorf("the format of the schedule is incorrect, it should be number, err: %v", err)
 		}
 		intervalMinute, err := strconv.Atoi(args[1])
 		if err != nil {
-			return errors.New("the format of the schedule is incorrect, it should be number")
+			return fmt.Errorf("the format of the schedule is incorrect, it should be number, err: %v", err)
 		}
 		if startAfterMinute < 0 {
 			return fmt.Errorf("Schedule.StartAfterMinute %v must not be smaller than 0 ", startAfterMinute)
@@ -57,23 +61,73 @@ func ValidataCrdSchedule(plan *crd.SchedulePlan) error {
 			return fmt.Errorf("Schedule.RoundTimeoutMinute %v must not be bigger than Schedule.IntervalMinute %v ", plan.RoundTimeoutMinute, intervalMinute)
 		}
 
+		if len(plan.TimeZone) > 0 {
+			return fmt.Errorf("Schedule.TimeZone %v only works with the crontab schedule", plan.TimeZone)
+		}
+
 	} else if len(args) == 5 {
-		_, err := cron.ParseStandard(*plan.Schedule)
-		if err != nil {
-			return fmt.Errorf("crontab configuration error,err: %v", err)
+		if _, _, err := ParseCrontab(*plan.Schedule, plan.TimeZone); err != nil {
+			return err
 		}
 
 	} else {
-		return errors.New("the format of the schedule is incorrect, it should be two or five")
+		return fmt.Errorf("the format of the schedule %q is incorrect, it should be two or five fields", *plan.Schedule)
 	}
 
 	if plan.RoundTimeoutMinute < 1 {
 		return fmt.Errorf("Schedule.RoundTimeoutMinute %v must not be smaller than 1 ", plan.RoundTimeoutMinute)
 	}
 
+	if len(plan.PriorityClassName) > 0 {
+		if _, ok := PriorityOfClass(plan.PriorityClassName); !ok {
+			return fmt.Errorf("Schedule.PriorityClassName %v is not in the priorityClasses of the configmap", plan.PriorityClassName)
+		}
+	}
+
 	return nil
 }
 
+// ParseCrontab parses the crontab schedule in the time zone, and the empty time zone is the local time zone of the controller
+func ParseCrontab(schedule, timeZone string) (cron.Schedule, *time.Location, error) {
+	location := time.Local
+	if len(timeZone) > 0 {
+		l, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Schedule.TimeZone %v is not a valid IANA time zone, err: %v", timeZone, err)
+		}
+		location = l
+	}
+	cronSchedule, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("crontab configuration error,err: %v", err)
+	}
+	// the crontab like "0 0 30 2 *" never matches
+	if cronSchedule.Next(time.Now().In(location)).IsZero() {
+		return nil, nil, fmt.Errorf("crontab %v never matches in the next five years", schedule)
+	}
+	return cronSchedule, location, nil
+}
+
+// IgnoreScheduleSuspend sets the suspend of the old schedule to the new one, since it is the only field of the spec allowed to modify
+func IgnoreScheduleSuspend(oldSchedule, newSchedule *crd.SchedulePlan) {
+	if oldSchedule != nil && newSchedule != nil {
+		oldSchedule.Suspend = newSchedule.Suspend
+	}
+}
+
+// PriorityOfClass returns the value of the priority class in the configmap, and the empty class is 0
+func PriorityOfClass(name string) (int, bool) {
+	if len(name) == 0 {
+		return 0, true
+	}
+	for _, c := range types.ControllerConfig.Configmap.Admission.PriorityClasses {
+		if c.Name == name {
+			return c.Value, true
+		}
+	}
+	return 0, false
+}
+
 // ValidataAppHttpHealthyHost check host protocol,ipv4 and ipv6 addr
 func ValidataAppHttpHealthyHost(r *crd.AppHttpHealthy) error {
 	var ip string
@@ -121,6 +175,70 @@ func ValidataAppHttpHealthyHost(r *crd.AppHttpHealthy) error {
 	return nil
 }
 
+// ValidateReportSinks check the url and the hmac secret of the report sinks
+func ValidateReportSinks(sinks []crd.ReportSink) error {
+	for i, sink := range sinks {
+		u, err := url.Parse(sink.URL)
+		if err != nil {
+			return fmt.Errorf("ReportSinks[%d].URL %v is invalid, err: %v", i, sink.URL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return fmt.Errorf("ReportSinks[%d].URL %v must be a http or https url", i, sink.URL)
+		}
+		if sink.HmacSecret != nil && (len(sink.HmacSecret.Name) == 0 || len(sink.HmacSecret.Key) == 0) {
+			return fmt.Errorf("ReportSinks[%d].HmacSecret requires both name and key", i)
+		}
+		if sink.MaxRetries != nil && *sink.MaxRetries < 0 {
+			return fmt.Errorf("ReportSinks[%d].MaxRetries %v must not be smaller than 0", i, *sink.MaxRetries)
+		}
+		if sink.TimeoutInSecond != nil && *sink.TimeoutInSecond < 1 {
+			return fmt.Errorf("ReportSinks[%d].TimeoutInSecond %v must not be smaller than 1", i, *sink.TimeoutInSecond)
+		}
+	}
+	return nil
+}
+
+// ValidateBaseline check the baseline rounds and the thresholds of the regression
+func ValidateBaseline(baseline *crd.Baseline, schedule *crd.SchedulePlan, enableLatencyMetric bool) error {
+	if baseline == nil {
+		return nil
+	}
+	if baseline.Round != nil && baseline.Window != nil {
+		return fmt.Errorf("Baseline.Round and Baseline.Window could not be set together")
+	}
+	if baseline.Round == nil && baseline.Window == nil {
+		return fmt.Errorf("Baseline requires Round or Window")
+	}
+	if baseline.Round != nil {
+		if *baseline.Round < 1 {
+			return fmt.Errorf("Baseline.Round %v must not be smaller than 1", *baseline.Round)
+		}
+		if schedule != nil && schedule.RoundNumber != -1 && *baseline.Round >= schedule.RoundNumber {
+			return fmt.Errorf("Baseline.Round %v must be smaller than Schedule.RoundNumber %v", *baseline.Round, schedule.RoundNumber)
+		}
+	}
+	if baseline.Window != nil && *baseline.Window < 1 {
+		return fmt.Errorf("Baseline.Window %v must not be smaller than 1", *baseline.Window)
+	}
+	if baseline.P50IncreasePercent == nil && baseline.P99IncreasePercent == nil && baseline.SuccessRateDecreasePercent == nil {
+		return fmt.Errorf("Baseline requires at least one of P50IncreasePercent, P99IncreasePercent and SuccessRateDecreasePercent")
+	}
+	// the latency is 0 in the reports without the latency metric
+	if !enableLatencyMetric && (baseline.P50IncreasePercent != nil || baseline.P99IncreasePercent != nil) {
+		return fmt.Errorf("Baseline.P50IncreasePercent and Baseline.P99IncreasePercent require Target.EnableLatencyMetric")
+	}
+	if baseline.P50IncreasePercent != nil && *baseline.P50IncreasePercent < 0 {
+		return fmt.Errorf("Baseline.P50IncreasePercent %v must not be smaller than 0", *baseline.P50IncreasePercent)
+	}
+	if baseline.P99IncreasePercent != nil && *baseline.P99IncreasePercent < 0 {
+		return fmt.Errorf("Baseline.P99IncreasePercent %v must not be smaller than 0", *baseline.P99IncreasePercent)
+	}
+	if baseline.SuccessRateDecreasePercent != nil && (*baseline.SuccessRateDecreasePercent < 0 || *baseline.SuccessRateDecreasePercent > 100) {
+		return fmt.Errorf("Baseline.SuccessRateDecreasePercent %v must be between 0 and 100", *baseline.SuccessRateDecreasePercent)
+	}
+	return nil
+}
+
 func GetDefaultSchedule() (plan *crd.SchedulePlan) {
 	s := "0 1"
 	return &crd.SchedulePlan{
@@ -136,3 +254,24 @@ func GetDefaultNetSuccessCondition() (plan *crd.NetSuccessCondition) {
 		SuccessRate: &n,
 	}
 }
+
+func GetDefaultNetTcpSuccessCondition() (plan *crd.NetTcpSuccessCondition) {
+	n := float64(1)
+	return &crd.NetTcpSuccessCondition{
+		SuccessRate: &n,
+	}
+}
+
+func GetDefaultNetUdpSuccessCondition() (plan *crd.NetUdpSuccessCondition) {
+	n := float64(1)
+	return &crd.NetUdpSuccessCondition{
+		MaxLossPercentage: &n,
+	}
+}
+
+func GetDefaultNetDelaySuccessCondition() (plan *crd.NetDelaySuccessCondition) {
+	n := float64(1)
+	return &crd.NetDelaySuccessCondition{
+		SuccessRate: &n,
+	}
+}
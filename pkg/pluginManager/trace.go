@@ -0,0 +1,58 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginManager
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
+)
+
+// roundAttributes returns the attributes of the round, the taskName is in the format of "kind.name"
+func roundAttributes(taskName string, roundNumber int) []attribute.KeyValue {
+	kindName := strings.Split(taskName, ".")[0]
+	return tracing.RoundAttributes(kindName, strings.TrimPrefix(taskName, kindName+"."), roundNumber)
+}
+
+// roundContext returns the context of the trace shared by the controller and the agents for the task round
+func roundContext(ctx context.Context, obj client.Object, taskName string, roundNumber int) context.Context {
+	kindName := strings.Split(taskName, ".")[0]
+	return tracing.RoundContext(ctx, kindName, strings.TrimPrefix(taskName, kindName+"."), obj.GetUID(), roundNumber)
+}
+
+// traceRoundScheduled records the span from the scheduled start time of the round to the time the controller starts it
+func traceRoundScheduled(ctx context.Context, obj client.Object, taskName string, record *crd.StatusHistoryRecord) {
+	_, span := tracing.StartSpanAt(roundContext(ctx, obj, taskName, record.RoundNumber), "controller.round.schedule", record.StartTimeStamp.Time,
+		append(roundAttributes(taskName, record.RoundNumber),
+			attribute.String("kdoctor.round.deadline", record.DeadLineTimeStamp.String()),
+		)...)
+	span.End()
+}
+
+// startRoundSpan starts the root span of the round in the controller, which lasts from the scheduled start time of the round to the summary
+func startRoundSpan(ctx context.Context, obj client.Object, taskName string, record *crd.StatusHistoryRecord) (context.Context, trace.Span) {
+	kindName := strings.Split(taskName, ".")[0]
+	return tracing.StartRoundSpan(ctx, kindName, strings.TrimPrefix(taskName, kindName+"."), obj.GetUID(), record.RoundNumber, "controller.round",
+		record.StartTimeStamp.Time, roundAttributes(taskName, record.RoundNumber)...)
+}
+
+// endRoundSpan ends the span of the round with the result and the nodes of the agents
+func endRoundSpan(span trace.Span, record *crd.StatusHistoryRecord) {
+	span.SetAttributes(
+		tracing.AttrRoundResult.String(record.Status),
+		tracing.AttrSucceedNodes.StringSlice(record.SucceedAgentNodeList),
+		tracing.AttrFailedNodes.StringSlice(record.FailedAgentNodeList),
+	)
+	if record.Status == crd.StatusHistoryRecordStatusFail {
+		tracing.EndSpan(span, roundFailedMessage(record))
+		return
+	}
+	tracing.EndSpan(span, "")
+}
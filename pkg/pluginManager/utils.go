@@ -8,6 +8,7 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
 )
@@ -66,3 +67,136 @@ func RemoveDuplicates[T element](arr []T) []T {
 
 	return newArr
 }
+
+// TaskRequestQPS returns the qps of the requests sent by each agent in a round of the task
+func TaskRequestQPS(kind string, obj runtime.Object) int {
+	var qps int
+	switch kind {
+	case KindNameAppHttpHealthy:
+		app := obj.(*crd.AppHttpHealthy)
+		qps = app.Spec.Request.QPS
+		// the detect mode ramps the qps up to the maximum
+		if app.Spec.Detect != nil {
+			qps = app.Spec.Detect.MaxQPS
+		}
+	case KindNameNetReach:
+		app := obj.(*crd.NetReach)
+		caseNum := 0
+		// Multiple use cases of netreach are executed simultaneously, so qps needs to be multiplied by the number of use cases
+		if *app.Spec.Target.ClusterIP {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		if *app.Spec.Target.LoadBalancer {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		if *app.Spec.Target.Endpoint {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		if *app.Spec.Target.NodePort {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		if *app.Spec.Target.Ingress {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+		}
+		qps = app.Spec.Request.QPS * caseNum
+	case KindNameNetdns:
+		app := obj.(*crd.Netdns)
+		qps = app.Spec.Request.QPS
+		// the detect mode ramps the qps up to the maximum
+		if app.Spec.Detect != nil {
+			qps = app.Spec.Detect.MaxQPS
+		}
+	case KindNameNetTcp:
+		app := obj.(*crd.NetTcp)
+		caseNum := 0
+		// Multiple use cases of nettcp are executed simultaneously, so qps needs to be multiplied by the number of use cases
+		if *app.Spec.Target.ClusterIP {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		if *app.Spec.Target.Endpoint {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		if *app.Spec.Target.NodePort {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		qps = app.Spec.Request.QPS * caseNum
+	case KindNameNetUdp:
+		app := obj.(*crd.NetUdp)
+		caseNum := 0
+		// Multiple use cases of netudp are executed simultaneously, so the packet rate needs to be multiplied by the number of use cases
+		if *app.Spec.Target.ClusterIP {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		if *app.Spec.Target.Endpoint {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		if *app.Spec.Target.NodePort {
+			if *app.Spec.Target.IPv4 {
+				caseNum += 1
+			}
+			if *app.Spec.Target.IPv6 {
+				caseNum += 1
+			}
+		}
+		qps = app.Spec.Request.PacketRate * caseNum
+	case KindNameNetDelay:
+		app := obj.(*crd.NetDelay)
+		// probing ipv4 and ipv6 of the other agents are executed simultaneously, so the qps needs to be multiplied by the number of use cases
+		caseNum := 0
+		if *app.Spec.Target.IPv4 {
+			caseNum += 1
+		}
+		if *app.Spec.Target.IPv6 {
+			caseNum += 1
+		}
+		qps = app.Spec.Request.QPS * caseNum
+	}
+	return qps
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/fileManager"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+	"github.com/kdoctor-io/kdoctor/pkg/utils"
+)
+
+// readRoundReports reads the agent reports of a round under the local report directory,
+// and also returns the files of the report aggregated from them before, whose node name is aggregateNodeName
+func (s *reportManager) readRoundReports(logger *zap.Logger, kindName, taskName string, roundNumber int, aggregateNodeName string) (reports []v1beta1.Report, oldAggregateFiles []string, err error) {
+	localFileList, e := utils.GetFileList(s.reportDir)
+	if e != nil {
+		return nil, nil, fmt.Errorf("failed to get local report files underlay %v, error=%v", s.reportDir, e)
+	}
+
+	// file name format: fmt.Sprintf("%s_%s_round%d_%s_%s", kindName, taskName, roundNumber, nodeName, suffix)
+	prefix := fmt.Sprintf("%s_%s_round%d_", kindName, taskName, roundNumber)
+	for _, fileName := range localFileList {
+		if !strings.HasPrefix(fileName, prefix) {
+			continue
+		}
+		v := strings.Split(fileName, "_")
+		if len(v) != 5 {
+			logger.Sugar().Warnf("ignore unrecognized report file %v", fileName)
+			continue
+		}
+		switch v[3] {
+		case "summary":
+			continue
+		case aggregateNodeName:
+			oldAggregateFiles = append(oldAggregateFiles, fileName)
+			continue
+		case v1beta1.NetDelayMatrixNodeName, v1beta1.NetDnsDetectNodeName, v1beta1.BaselineNodeName:
+			// the other aggregated reports
+			continue
+		}
+
+		data, e := os.ReadFile(path.Join(s.reportDir, fileName))
+		if e != nil {
+			return nil, nil, fmt.Errorf("failed to read report file %v, error=%v", fileName, e)
+		}
+		report := v1beta1.Report{}
+		if e := json.Unmarshal(data, &report); e != nil {
+			return nil, nil, fmt.Errorf("failed to parse report file %v, error=%v", fileName, e)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, oldAggregateFiles, nil
+}
+
+// writeAggregateReport saves the report aggregated from the agent reports of a round, and replaces the old one
+func (s *reportManager) writeAggregateReport(logger *zap.Logger, kindName, taskName string, roundNumber int, aggregateNodeName string, data interface{}, oldAggregateFiles []string) error {
+	jsonByte, e := json.Marshal(data)
+	if e != nil {
+		return fmt.Errorf("failed to marshal the %v report of %v %v round %v, error=%v", aggregateNodeName, kindName, taskName, roundNumber, e)
+	}
+	var out bytes.Buffer
+	if e := json.Indent(&out, jsonByte, "", "\t"); e != nil {
+		return fmt.Errorf("failed to indent the %v report of %v %v round %v, error=%v", aggregateNodeName, kindName, taskName, roundNumber, e)
+	}
+
+	for _, fileName := range oldAggregateFiles {
+		if e := os.Remove(path.Join(s.reportDir, fileName)); e != nil {
+			logger.Sugar().Errorf("failed to remove old %v report %v, error=%v", aggregateNodeName, fileName, e)
+		}
+	}
+
+	t := time.Duration(types.ControllerConfig.ReportAgeInDay*24) * time.Hour
+	fileName := fileManager.GenerateTaskFileName(kindName, taskName, roundNumber, aggregateNodeName, time.Now().Add(t))
+	if e := os.WriteFile(path.Join(s.reportDir, fileName), out.Bytes(), 0644); e != nil {
+		return fmt.Errorf("failed to write %v report %v, error=%v", aggregateNodeName, fileName, e)
+	}
+	logger.Sugar().Infof("succeeded to generate %v report %v", aggregateNodeName, fileName)
+	if e := SaveReportToStore(kindName, taskName, roundNumber, aggregateNodeName, jsonByte); e != nil {
+		logger.Sugar().Errorf("failed to save %v report %v to store, error=%v", aggregateNodeName, fileName, e)
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/reportRender"
+)
+
+// BaselineComparedFunc is called with the comparison after the round is compared with the baseline
+type BaselineComparedFunc func(comparison *v1beta1.BaselineComparison)
+
+type roundBaseline struct {
+	baseline   crd.Baseline
+	onCompared BaselineComparedFunc
+}
+
+// RegisterRoundBaseline records the baseline of the task round, which is compared with the round
+// after the agent reports of the round are collected for the trigger
+func RegisterRoundBaseline(trigger string, baseline *crd.Baseline, onCompared BaselineComparedFunc) {
+	if globalReportManager == nil || baseline == nil {
+		return
+	}
+	updatePendingRound(trigger, func(p *pendingRound) {
+		p.baseline = &roundBaseline{baseline: *baseline.DeepCopy(), onCompared: onCompared}
+	})
+}
+
+func popRoundBaseline(trigger string) (roundBaseline, bool) {
+	var v *roundBaseline
+	updatePendingRound(trigger, func(p *pendingRound) {
+		v, p.baseline = p.baseline, nil
+	})
+	if v == nil {
+		return roundBaseline{}, false
+	}
+	return *v, true
+}
+
+// BaselineRounds returns the rounds as the baseline of the round, the pinned round or the previous rounds in the window
+func BaselineRounds(baseline crd.Baseline, roundNumber int64) []int64 {
+	if baseline.Round != nil {
+		if *baseline.Round >= roundNumber {
+			return nil
+		}
+		return []int64{*baseline.Round}
+	}
+	if baseline.Window == nil {
+		return nil
+	}
+	start := roundNumber - *baseline.Window
+	if start < 1 {
+		start = 1
+	}
+	rounds := []int64{}
+	for r := start; r < roundNumber; r++ {
+		rounds = append(rounds, r)
+	}
+	return rounds
+}
+
+type targetMetrics struct {
+	nodeName    string
+	targetName  string
+	p50         float64
+	p99         float64
+	successRate float64
+	// the number of the reports averaged
+	count int
+}
+
+// collectTargetMetrics sums the metrics of each target from each agent, whose key is "node/target"
+func collectTargetMetrics(reports []v1beta1.Report, metrics map[string]*targetMetrics) {
+	for i := range reports {
+		node := reportRender.NewNodeResult(&reports[i])
+		for _, t := range node.Targets {
+			key := node.NodeName + "/" + t.Name
+			m, ok := metrics[key]
+			if !ok {
+				m = &targetMetrics{nodeName: node.NodeName, targetName: t.Name}
+				metrics[key] = m
+			}
+			m.p50 += float64(t.Latencies.P50)
+			m.p99 += float64(t.Latencies.P99)
+			m.successRate += t.SucceedRate
+			m.count++
+		}
+	}
+}
+
+// changePercent returns the change of the value in the percentage of the baseline.
+// The increase from the baseline 0 is 100%, and it exceeds any threshold, see increasedBeyond
+func changePercent(value, baseline float64) float64 {
+	if baseline == 0 {
+		if value > 0 {
+			return 100
+		}
+		return 0
+	}
+	return (value - baseline) / baseline * 100
+}
+
+// increasedBeyond checks whether the value increases from the baseline by more than the threshold in percentage
+func increasedBeyond(value, baseline float64, threshold *float64) bool {
+	if threshold == nil {
+		return false
+	}
+	if baseline == 0 {
+		return value > 0
+	}
+	return changePercent(value, baseline) > *threshold
+}
+
+// CompareWithBaseline compares the metrics of each target in the round with the average of the baseline rounds
+func CompareWithBaseline(baseline crd.Baseline, roundNumber int64, reports []v1beta1.Report, baselineReports map[int64][]v1beta1.Report) *v1beta1.BaselineComparison {
+	comparison := &v1beta1.BaselineComparison{
+		RoundNumber:    roundNumber,
+		BaselineRounds: []int64{},
+		Targets:        []v1beta1.TargetBaselineDiff{},
+	}
+	base := map[string]*targetMetrics{}
+	for r, v := range baselineReports {
+		comparison.BaselineRounds = append(comparison.BaselineRounds, r)
+		collectTargetMetrics(v, base)
+	}
+	sort.Slice(comparison.BaselineRounds, func(i, j int) bool { return comparison.BaselineRounds[i] < comparison.BaselineRounds[j] })
+
+	current := map[string]*targetMetrics{}
+	collectTargetMetrics(reports, current)
+	keys := make([]string, 0, len(current))
+	for k := range current {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		cur := current[key]
+		b, ok := base[key]
+		if !ok {
+			// a new target without the baseline
+			continue
+		}
+		n := float64(b.count)
+		diff := v1beta1.TargetBaselineDiff{
+			NodeName:            cur.nodeName,
+			TargetName:          cur.targetName,
+			P50InMs:             float32(cur.p50 / float64(cur.count)),
+			BaselineP50InMs:     float32(b.p50 / n),
+			P99InMs:             float32(cur.p99 / float64(cur.count)),
+			BaselineP99InMs:     float32(b.p99 / n),
+			SuccessRate:         cur.successRate / float64(cur.count),
+			BaselineSuccessRate: b.successRate / n,
+		}
+		diff.P50ChangePercent = changePercent(float64(diff.P50InMs), float64(diff.BaselineP50InMs))
+		diff.P99ChangePercent = changePercent(float64(diff.P99InMs), float64(diff.BaselineP99InMs))
+		diff.SuccessRateChangePercent = changePercent(diff.SuccessRate, diff.BaselineSuccessRate)
+
+		regress := func(metric string, change float64) {
+			diff.RegressedMetrics = append(diff.RegressedMetrics, metric)
+			comparison.RegressionReasons = append(comparison.RegressionReasons, fmt.Sprintf("%s: %s %+.1f%%", key, metric, change))
+		}
+		if increasedBeyond(float64(diff.P50InMs), float64(diff.BaselineP50InMs), baseline.P50IncreasePercent) {
+			regress(v1beta1.BaselineMetricP50, diff.P50ChangePercent)
+		}
+		if increasedBeyond(float64(diff.P99InMs), float64(diff.BaselineP99InMs), baseline.P99IncreasePercent) {
+			regress(v1beta1.BaselineMetricP99, diff.P99ChangePercent)
+		}
+		if baseline.SuccessRateDecreasePercent != nil && -diff.SuccessRateChangePercent > *baseline.SuccessRateDecreasePercent {
+			regress(v1beta1.BaselineMetricSuccessRate, diff.SuccessRateChangePercent)
+		}
+		comparison.Targets = append(comparison.Targets, diff)
+	}
+	comparison.Regressed = len(comparison.RegressionReasons) > 0
+	return comparison
+}
+
+// compareRoundWithBaseline compares the round with the baseline registered for the trigger, and saves the comparison as an aggregated report
+func (s *reportManager) compareRoundWithBaseline(logger *zap.Logger, trigger, kindName, taskName string, roundNumber int) {
+	v, ok := popRoundBaseline(trigger)
+	if !ok {
+		return
+	}
+	rounds := BaselineRounds(v.baseline, int64(roundNumber))
+	if len(rounds) == 0 {
+		logger.Sugar().Debugf("no baseline round for %v %v round %v", kindName, taskName, roundNumber)
+		return
+	}
+
+	reports, oldComparisonFiles, e := s.readRoundReports(logger, kindName, taskName, roundNumber, v1beta1.BaselineNodeName)
+	if e != nil {
+		logger.Sugar().Errorf("failed to read reports of %v %v round %v for the baseline, error=%v", kindName, taskName, roundNumber, e)
+		return
+	}
+	baselineReports := map[int64][]v1beta1.Report{}
+	for _, r := range rounds {
+		roundReports, _, e := s.readRoundReports(logger, kindName, taskName, int(r), v1beta1.BaselineNodeName)
+		if e != nil {
+			logger.Sugar().Errorf("failed to read reports of %v %v round %v as the baseline, error=%v", kindName, taskName, r, e)
+			return
+		}
+		// the reports of the round may be removed for the age
+		if len(roundReports) > 0 {
+			baselineReports[r] = roundReports
+		}
+	}
+	if len(baselineReports) == 0 {
+		logger.Sugar().Warnf("no report of the baseline rounds %v for %v %v round %v", rounds, kindName, taskName, roundNumber)
+		return
+	}
+
+	comparison := CompareWithBaseline(v.baseline, int64(roundNumber), reports, baselineReports)
+	if e := s.writeAggregateReport(logger, kindName, taskName, roundNumber, v1beta1.BaselineNodeName, comparison, oldComparisonFiles); e != nil {
+		logger.Sugar().Errorf("failed to save the baseline comparison of %v %v round %v, error=%v", kindName, taskName, roundNumber, e)
+	}
+	if comparison.Regressed {
+		logger.Sugar().Warnf("%v %v round %v regressed from the baseline rounds %v: %v", kindName, taskName, roundNumber, comparison.BaselineRounds, comparison.RegressionReasons)
+	}
+	if v.onCompared != nil {
+		v.onCompared(comparison)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	"github.com/kdoctor-io/kdoctor/pkg/fileManager"
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+func netReachReport(roundNumber int64, nodeName string, p50, p99 float32, succeedRate float64) v1beta1.Report {
+	return v1beta1.Report{
+		RoundNumber: roundNumber,
+		NodeName:    nodeName,
+		TaskNetReach: &v1beta1.NetReachTask{
+			Detail: []v1beta1.NetReachTaskDetail{{
+				TargetName:  "HttpRequest_ClusterIP",
+				Succeed:     true,
+				SucceedRate: succeedRate,
+				Metrics: v1beta1.HttpMetrics{
+					Latencies: v1beta1.LatencyDistribution{P50: p50, P99: p99},
+				},
+			}},
+		},
+	}
+}
+
+var _ = Describe("test baseline", Label("reportManager baseline"), func() {
+
+	It("select the baseline rounds", func() {
+		Expect(BaselineRounds(crd.Baseline{Round: pointer.Int64(2)}, 5)).To(Equal([]int64{2}))
+		Expect(BaselineRounds(crd.Baseline{Round: pointer.Int64(2)}, 2)).To(BeEmpty())
+		Expect(BaselineRounds(crd.Baseline{Window: pointer.Int64(3)}, 5)).To(Equal([]int64{2, 3, 4}))
+		Expect(BaselineRounds(crd.Baseline{Window: pointer.Int64(3)}, 2)).To(Equal([]int64{1}))
+		Expect(BaselineRounds(crd.Baseline{Window: pointer.Int64(3)}, 1)).To(BeEmpty())
+	})
+
+	It("compare with the average of the window", func() {
+		baseline := crd.Baseline{
+			Window:                     pointer.Int64(2),
+			P50IncreasePercent:         pointer.Float64(50),
+			P99IncreasePercent:         pointer.Float64(50),
+			SuccessRateDecreasePercent: pointer.Float64(5),
+		}
+		baselineReports := map[int64][]v1beta1.Report{
+			2: {netReachReport(2, "node1", 10, 20, 1), netReachReport(2, "node2", 10, 20, 1)},
+			1: {netReachReport(1, "node1", 20, 40, 1), netReachReport(1, "node2", 10, 20, 1)},
+		}
+		reports := []v1beta1.Report{
+			netReachReport(3, "node1", 18, 80, 1),
+			netReachReport(3, "node2", 10, 20, 0.9),
+			netReachReport(3, "node3", 100, 200, 0),
+		}
+
+		comparison := CompareWithBaseline(baseline, 3, reports, baselineReports)
+		Expect(comparison.RoundNumber).To(Equal(int64(3)))
+		Expect(comparison.BaselineRounds).To(Equal([]int64{1, 2}))
+		// node3 has no baseline
+		Expect(comparison.Targets).To(HaveLen(2))
+
+		node1 := comparison.Targets[0]
+		Expect(node1.NodeName).To(Equal("node1"))
+		Expect(node1.BaselineP50InMs).To(Equal(float32(15)))
+		Expect(node1.P50ChangePercent).To(BeNumerically("~", 20, 0.01))
+		Expect(node1.BaselineP99InMs).To(Equal(float32(30)))
+		Expect(node1.P99ChangePercent).To(BeNumerically("~", 166.67, 0.01))
+		Expect(node1.RegressedMetrics).To(Equal([]string{v1beta1.BaselineMetricP99}))
+
+		node2 := comparison.Targets[1]
+		Expect(node2.SuccessRateChangePercent).To(BeNumerically("~", -10, 0.01))
+		Expect(node2.RegressedMetrics).To(Equal([]string{v1beta1.BaselineMetricSuccessRate}))
+
+		Expect(comparison.Regressed).To(BeTrue())
+		Expect(comparison.RegressionReasons).To(Equal([]string{
+			"node1/HttpRequest_ClusterIP: p99 +166.7%",
+			"node2/HttpRequest_ClusterIP: successRate -10.0%",
+		}))
+	})
+
+	It("not regress within the thresholds", func() {
+		baseline := crd.Baseline{Round: pointer.Int64(1), P99IncreasePercent: pointer.Float64(50)}
+		comparison := CompareWithBaseline(baseline, 2,
+			[]v1beta1.Report{netReachReport(2, "node1", 100, 29, 0.5)},
+			map[int64][]v1beta1.Report{1: {netReachReport(1, "node1", 10, 20, 1)}})
+		Expect(comparison.Regressed).To(BeFalse())
+		Expect(comparison.RegressionReasons).To(BeEmpty())
+		Expect(comparison.Targets).To(HaveLen(1))
+		Expect(comparison.Targets[0].RegressedMetrics).To(BeEmpty())
+	})
+
+	It("regress when the latency increases from the baseline 0", func() {
+		baseline := crd.Baseline{Round: pointer.Int64(1), P50IncreasePercent: pointer.Float64(500)}
+		comparison := CompareWithBaseline(baseline, 2,
+			[]v1beta1.Report{netReachReport(2, "node1", 10, 20, 1)},
+			map[int64][]v1beta1.Report{1: {netReachReport(1, "node1", 0, 0, 1)}})
+		Expect(comparison.Targets).To(HaveLen(1))
+		Expect(comparison.Targets[0].P50ChangePercent).To(Equal(float64(100)))
+		Expect(comparison.Targets[0].RegressedMetrics).To(Equal([]string{v1beta1.BaselineMetricP50}))
+		Expect(comparison.Regressed).To(BeTrue())
+	})
+
+	It("save the comparison of the round from local reports", func() {
+		reportDir := fmt.Sprintf("/tmp/_FM_%d", time.Now().Nanosecond())
+		Expect(os.MkdirAll(reportDir, os.ModePerm)).To(Succeed())
+		defer os.RemoveAll(reportDir)
+
+		endTime := time.Now().Add(time.Hour)
+		write := func(roundNumber int, nodeName string, data interface{}) {
+			b, err := json.Marshal(data)
+			Expect(err).NotTo(HaveOccurred())
+			name := fileManager.GenerateTaskFileName(types.KindNameNetReach, "test", roundNumber, nodeName, endTime)
+			Expect(os.WriteFile(path.Join(reportDir, name), b, 0644)).To(Succeed())
+		}
+		write(1, "node1", netReachReport(1, "node1", 10, 20, 1))
+		write(1, "summary", map[string]string{"TaskName": "netreach.test"})
+		write(1, v1beta1.BaselineNodeName, map[string]string{})
+		write(2, "node1", netReachReport(2, "node1", 10, 40, 1))
+		write(2, "summary", map[string]string{"TaskName": "netreach.test"})
+		write(2, v1beta1.BaselineNodeName, map[string]string{})
+
+		rm := &reportManager{
+			logger:    logger.NewStdoutLogger("debug", "reportManager Test"),
+			reportDir: reportDir,
+		}
+		trigger := types.KindNameNetReach + ".test.2"
+		var compared *v1beta1.BaselineComparison
+		updatePendingRound(trigger, func(p *pendingRound) {
+			p.baseline = &roundBaseline{
+				baseline:   crd.Baseline{Round: pointer.Int64(1), P99IncreasePercent: pointer.Float64(50)},
+				onCompared: func(comparison *v1beta1.BaselineComparison) { compared = comparison },
+			}
+		})
+		rm.compareRoundWithBaseline(rm.logger, trigger, types.KindNameNetReach, "test", 2)
+		Expect(pendingRounds.items).NotTo(HaveKey(trigger))
+
+		Expect(compared).NotTo(BeNil())
+		Expect(compared.Regressed).To(BeTrue())
+		Expect(compared.BaselineRounds).To(Equal([]int64{1}))
+		Expect(compared.Targets).To(HaveLen(1))
+		Expect(compared.Targets[0].P99ChangePercent).To(BeNumerically("~", 100, 0.01))
+
+		entries, err := os.ReadDir(reportDir)
+		Expect(err).NotTo(HaveOccurred())
+		var comparisonFiles []string
+		for _, item := range entries {
+			if strings.Contains(item.Name(), "_"+v1beta1.BaselineNodeName+"_") {
+				comparisonFiles = append(comparisonFiles, item.Name())
+			}
+		}
+		// the old comparison of round 2 is replaced, and the one of round 1 is kept
+		Expect(comparisonFiles).To(HaveLen(2))
+
+		// the round without the registered baseline is skipped
+		compared = nil
+		rm.compareRoundWithBaseline(rm.logger, trigger, types.KindNameNetReach, "test", 2)
+		Expect(compared).To(BeNil())
+	})
+})
@@ -0,0 +1,434 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/parquet-go/parquet-go"
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+	"github.com/kdoctor-io/kdoctor/pkg/utils"
+)
+
+const (
+	ReportExportFormatJSONLines = "jsonl"
+	ReportExportFormatParquet   = "parquet"
+
+	// the keys of the secret for the credential of the object storage
+	ReportExportAccessKeyIDKey     = "accessKeyID"
+	ReportExportSecretAccessKeyKey = "secretAccessKey"
+
+	// the report types of the exported records, besides the node name of the aggregated report
+	ReportExportTypeAgent   = "agent"
+	ReportExportTypeSummary = "summary"
+
+	// the directory under the report directory, which saves the objects failing to upload,
+	// and they are uploaded again when the next round is exported
+	reportExportPendingDir = "exportpending"
+	// the number of the pending objects kept by default, and the oldest ones are dropped beyond it
+	defaultReportExportMaxPendingObjects = 100
+
+	// the credential in the secret is read again after the time, to pick up the rotation
+	reportExportCredentialTTL = time.Minute
+)
+
+var (
+	// the timeout to upload an object
+	reportExportUploadTimeout = time.Minute
+	// the timeout to upload all pending objects, and the rest are uploaded when the next round is exported
+	reportExportPendingTimeout = 5 * time.Minute
+)
+
+// ExportRecord is a report file of the round, which is a line of JSON Lines or a row of Parquet
+type ExportRecord struct {
+	Kind         string          `json:"kind" parquet:"kind,dict"`
+	Task         string          `json:"task" parquet:"task,dict"`
+	RoundNumber  int64           `json:"roundNumber" parquet:"round_number"`
+	ReportType   string          `json:"reportType" parquet:"report_type,dict"`
+	NodeName     string          `json:"nodeName" parquet:"node_name,dict"`
+	PodName      string          `json:"podName" parquet:"pod_name"`
+	RoundResult  string          `json:"roundResult" parquet:"round_result,dict"`
+	FailedReason string          `json:"failedReason" parquet:"failed_reason"`
+	StartTime    time.Time       `json:"startTime" parquet:"start_time,timestamp(millisecond)"`
+	EndTime      time.Time       `json:"endTime" parquet:"end_time,timestamp(millisecond)"`
+	ExportTime   time.Time       `json:"exportTime" parquet:"export_time,timestamp(millisecond)"`
+	Report       json.RawMessage `json:"report" parquet:"report,json"`
+}
+
+// the summary report written by the controller
+type summaryReport struct {
+	RoundResult    string
+	PodName        string
+	FailedReason   string
+	StartTimeStamp time.Time
+	EndTimeStamp   time.Time
+}
+
+// objectUploader uploads the object to the bucket
+type objectUploader interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+type s3Uploader struct {
+	client *minio.Client
+	bucket string
+}
+
+func (s *s3Uploader) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	_, e := s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: contentType})
+	return e
+}
+
+// secretCredentials reads the credential of the object storage from the secret, and reads it again after it expires
+type secretCredentials struct {
+	credentials.Expiry
+	secretName string
+}
+
+func (c *secretCredentials) Retrieve() (credentials.Value, error) {
+	ctx := context.Background()
+	id, e := getSecretKey(ctx, c.secretName, ReportExportAccessKeyIDKey)
+	if e != nil {
+		return credentials.Value{}, e
+	}
+	secret, e := getSecretKey(ctx, c.secretName, ReportExportSecretAccessKeyKey)
+	if e != nil {
+		return credentials.Value{}, e
+	}
+	c.SetExpiration(time.Now().Add(reportExportCredentialTTL), 0)
+	return credentials.Value{
+		AccessKeyID:     string(id),
+		SecretAccessKey: string(secret),
+		SignerType:      credentials.SignatureV4,
+	}, nil
+}
+
+// newS3Uploader creates the client of the S3-compatible object storage, which is shared by all exports.
+// The credential in the secret is read when the client signs the request, so the rotation is picked up
+func newS3Uploader(cfg types.ReportExportConfig) (*s3Uploader, error) {
+	opts := &minio.Options{
+		Secure: !cfg.Insecure,
+		Region: cfg.Region,
+	}
+	if len(cfg.CredentialSecretName) > 0 {
+		opts.Creds = credentials.New(&secretCredentials{secretName: cfg.CredentialSecretName})
+	} else {
+		// the credential from the environment or the instance role
+		opts.Creds = credentials.NewChainCredentials([]credentials.Provider{&credentials.EnvAWS{}, &credentials.IAM{}})
+	}
+	client, e := minio.New(cfg.Endpoint, opts)
+	if e != nil {
+		return nil, e
+	}
+	return &s3Uploader{client: client, bucket: cfg.Bucket}, nil
+}
+
+// ValidateReportExportConfig checks the export config in the configmap
+func ValidateReportExportConfig(cfg types.ReportExportConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if len(cfg.Endpoint) == 0 {
+		return fmt.Errorf("the endpoint of the report export is required")
+	}
+	if strings.Contains(cfg.Endpoint, "://") || strings.Contains(cfg.Endpoint, "/") {
+		return fmt.Errorf("the endpoint %v of the report export should be the host and the optional port, without the scheme and the path", cfg.Endpoint)
+	}
+	if len(cfg.Bucket) == 0 {
+		return fmt.Errorf("the bucket of the report export is required")
+	}
+	if cfg.MaxPendingObjects < 0 {
+		return fmt.Errorf("the maxPendingObjects %v of the report export should not be negative", cfg.MaxPendingObjects)
+	}
+	switch cfg.Format {
+	case "", ReportExportFormatJSONLines, ReportExportFormatParquet:
+	default:
+		return fmt.Errorf("the format %v of the report export should be %v or %v", cfg.Format, ReportExportFormatJSONLines, ReportExportFormatParquet)
+	}
+	return nil
+}
+
+// ReportExportObjectKey returns the key of the exported round, which is partitioned by the kind, the task and the date of the round
+func ReportExportObjectKey(prefix, kindName, taskName string, roundNumber int, date time.Time, format string) string {
+	return path.Join(prefix,
+		"kind="+kindName,
+		"task="+taskName,
+		"date="+date.UTC().Format("2006-01-02"),
+		fmt.Sprintf("%s_%s_round%d.%s", kindName, taskName, roundNumber, format),
+	)
+}
+
+// readRoundExportRecords reads all report files of the round, including the summary and the aggregated report
+func (s *reportManager) readRoundExportRecords(kindName, taskName string, roundNumber int, exportTime time.Time) ([]ExportRecord, error) {
+	localFileList, e := utils.GetFileList(s.reportDir)
+	if e != nil {
+		return nil, fmt.Errorf("failed to get local report files underlay %v, error=%v", s.reportDir, e)
+	}
+
+	records := []ExportRecord{}
+	// file name format: fmt.Sprintf("%s_%s_round%d_%s_%s", kindName, taskName, roundNumber, nodeName, suffix)
+	prefix := fmt.Sprintf("%s_%s_round%d_", kindName, taskName, roundNumber)
+	for _, fileName := range localFileList {
+		if !strings.HasPrefix(fileName, prefix) {
+			continue
+		}
+		v := strings.Split(fileName, "_")
+		if len(v) != 5 {
+			continue
+		}
+		data, e := os.ReadFile(path.Join(s.reportDir, fileName))
+		if e != nil {
+			return nil, fmt.Errorf("failed to read report file %v, error=%v", fileName, e)
+		}
+		var compact bytes.Buffer
+		if e := json.Compact(&compact, data); e != nil {
+			return nil, fmt.Errorf("failed to parse report file %v, error=%v", fileName, e)
+		}
+
+		record := ExportRecord{
+			Kind:        kindName,
+			Task:        taskName,
+			RoundNumber: int64(roundNumber),
+			ExportTime:  exportTime,
+			Report:      compact.Bytes(),
+		}
+		switch v[3] {
+		case "summary":
+			summary := summaryReport{}
+			if e := json.Unmarshal(data, &summary); e != nil {
+				return nil, fmt.Errorf("failed to parse summary report %v, error=%v", fileName, e)
+			}
+			record.ReportType = ReportExportTypeSummary
+			record.PodName = summary.PodName
+			record.RoundResult = summary.RoundResult
+			record.FailedReason = summary.FailedReason
+			record.StartTime = summary.StartTimeStamp
+			record.EndTime = summary.EndTimeStamp
+		case v1beta1.NetDelayMatrixNodeName, v1beta1.NetDnsDetectNodeName, v1beta1.BaselineNodeName:
+			record.ReportType = v[3]
+		default:
+			report := v1beta1.Report{}
+			if e := json.Unmarshal(data, &report); e != nil {
+				return nil, fmt.Errorf("failed to parse report file %v, error=%v", fileName, e)
+			}
+			record.ReportType = ReportExportTypeAgent
+			record.NodeName = report.NodeName
+			record.PodName = report.PodName
+			record.RoundResult = report.RoundResult
+			if report.FailedReason != nil {
+				record.FailedReason = *report.FailedReason
+			}
+			record.StartTime = report.StartTimeStamp.Time
+			record.EndTime = report.EndTimeStamp.Time
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// EncodeExportRecords encodes the records in the format, and returns the content type
+func EncodeExportRecords(records []ExportRecord, format string) ([]byte, string, error) {
+	var out bytes.Buffer
+	switch format {
+	case ReportExportFormatParquet:
+		w := parquet.NewGenericWriter[ExportRecord](&out, parquet.Compression(&parquet.Snappy))
+		if _, e := w.Write(records); e != nil {
+			return nil, "", e
+		}
+		if e := w.Close(); e != nil {
+			return nil, "", e
+		}
+		return out.Bytes(), "application/vnd.apache.parquet", nil
+	default:
+		encoder := json.NewEncoder(&out)
+		for i := range records {
+			if e := encoder.Encode(&records[i]); e != nil {
+				return nil, "", e
+			}
+		}
+		return out.Bytes(), "application/x-ndjson", nil
+	}
+}
+
+// it makes sure that only one export uploads the pending objects at a time, and the others skip them
+var reportExportUploadingPending atomic.Bool
+
+// putObject uploads the object within the timeout
+func putObject(ctx context.Context, uploader objectUploader, key string, data []byte, contentType string) error {
+	ctx, cancel := context.WithTimeout(ctx, reportExportUploadTimeout)
+	defer cancel()
+	return uploader.PutObject(ctx, key, data, contentType)
+}
+
+// exportRound uploads all reports of the round to the object storage
+func (s *reportManager) exportRound(ctx context.Context, logger *zap.Logger, uploader objectUploader, cfg types.ReportExportConfig, kindName, taskName string, roundNumber int) {
+	if reportExportUploadingPending.CompareAndSwap(false, true) {
+		pendingCtx, cancel := context.WithTimeout(ctx, reportExportPendingTimeout)
+		s.uploadPendingExports(pendingCtx, logger, uploader, cfg)
+		cancel()
+		reportExportUploadingPending.Store(false)
+	}
+
+	now := time.Now()
+	records, e := s.readRoundExportRecords(kindName, taskName, roundNumber, now)
+	if e != nil {
+		logger.Sugar().Errorf("failed to read reports of %v %v round %v for export, error=%v", kindName, taskName, roundNumber, e)
+		return
+	}
+	if len(records) == 0 {
+		logger.Sugar().Debugf("no report of %v %v round %v to export", kindName, taskName, roundNumber)
+		return
+	}
+
+	format := cfg.Format
+	if len(format) == 0 {
+		format = ReportExportFormatJSONLines
+	}
+	data, contentType, e := EncodeExportRecords(records, format)
+	if e != nil {
+		logger.Sugar().Errorf("failed to encode reports of %v %v round %v to %v, error=%v", kindName, taskName, roundNumber, format, e)
+		return
+	}
+
+	// the round is partitioned by the date when it starts
+	date := now
+	for _, r := range records {
+		if !r.StartTime.IsZero() && r.StartTime.Before(date) {
+			date = r.StartTime
+		}
+	}
+	key := ReportExportObjectKey(cfg.Prefix, kindName, taskName, roundNumber, date, format)
+	if e := putObject(ctx, uploader, key, data, contentType); e != nil {
+		logger.Sugar().Errorf("failed to export reports of %v %v round %v to %v, error=%v", kindName, taskName, roundNumber, key, e)
+		s.savePendingExport(logger, cfg, key, data)
+		return
+	}
+	logger.Sugar().Infof("succeeded to export %v reports of %v %v round %v to %v", len(records), kindName, taskName, roundNumber, key)
+}
+
+// savePendingExport saves the object failing to upload, the file name is the escaped object key
+func (s *reportManager) savePendingExport(logger *zap.Logger, cfg types.ReportExportConfig, key string, data []byte) {
+	dir := path.Join(s.reportDir, reportExportPendingDir)
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		logger.Sugar().Errorf("failed to create directory %v, error=%v", dir, e)
+		return
+	}
+	if e := os.WriteFile(path.Join(dir, url.PathEscape(key)), data, 0644); e != nil {
+		logger.Sugar().Errorf("failed to save pending export %v, error=%v", key, e)
+	}
+	s.prunePendingExports(logger, cfg)
+}
+
+// prunePendingExports drops the pending objects older than the age of the reports,
+// and the oldest ones beyond the maximum number, so the directory does not grow when the object storage is down for long.
+// It returns the pending objects which are kept, the newest first
+func (s *reportManager) prunePendingExports(logger *zap.Logger, cfg types.ReportExportConfig) []os.DirEntry {
+	maxPending := cfg.MaxPendingObjects
+	if maxPending == 0 {
+		maxPending = defaultReportExportMaxPendingObjects
+	}
+	return pruneAgedFiles(logger, path.Join(s.reportDir, reportExportPendingDir), maxPending, "pending export")
+}
+
+// pruneAgedFiles drops the files in the directory older than the age of the reports, and the oldest ones beyond maxFiles.
+// It returns the files which are kept, the newest first
+func pruneAgedFiles(logger *zap.Logger, dir string, maxFiles int, fileKind string) []os.DirEntry {
+	entries, e := os.ReadDir(dir)
+	if e != nil {
+		// no file
+		return nil
+	}
+	expireTime := time.Now().Add(-time.Duration(types.ControllerConfig.ReportAgeInDay) * 24 * time.Hour)
+
+	type agedFile struct {
+		entry   os.DirEntry
+		modTime time.Time
+	}
+	files := []agedFile{}
+	for _, entry := range entries {
+		info, e := entry.Info()
+		if e != nil || entry.IsDir() {
+			continue
+		}
+		files = append(files, agedFile{entry: entry, modTime: info.ModTime()})
+	}
+	// the newest first
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	result := []os.DirEntry{}
+	for i, f := range files {
+		if i < maxFiles && (types.ControllerConfig.ReportAgeInDay <= 0 || f.modTime.After(expireTime)) {
+			result = append(result, f.entry)
+			continue
+		}
+		if e := os.Remove(path.Join(dir, f.entry.Name())); e != nil {
+			logger.Sugar().Errorf("failed to drop %v %v, error=%v", fileKind, f.entry.Name(), e)
+			continue
+		}
+		logger.Sugar().Warnf("drop %v %v, which is expired or beyond the maximum %v", fileKind, f.entry.Name(), maxFiles)
+	}
+	return result
+}
+
+// uploadPendingExports uploads the objects failing to upload before, from the oldest one
+func (s *reportManager) uploadPendingExports(ctx context.Context, logger *zap.Logger, uploader objectUploader, cfg types.ReportExportConfig) {
+	dir := path.Join(s.reportDir, reportExportPendingDir)
+	entries := s.prunePendingExports(logger, cfg)
+	for i := len(entries) - 1; i >= 0; i-- {
+		fileName := entries[i].Name()
+		key, e := url.PathUnescape(fileName)
+		if e != nil {
+			logger.Sugar().Warnf("ignore unrecognized pending export %v", fileName)
+			continue
+		}
+		data, e := os.ReadFile(path.Join(dir, fileName))
+		if e != nil {
+			logger.Sugar().Errorf("failed to read pending export %v, error=%v", fileName, e)
+			continue
+		}
+		contentType := "application/x-ndjson"
+		if strings.HasSuffix(key, "."+ReportExportFormatParquet) {
+			contentType = "application/vnd.apache.parquet"
+		}
+		if e := putObject(ctx, uploader, key, data, contentType); e != nil {
+			logger.Sugar().Errorf("failed to export pending %v, error=%v", key, e)
+			// the object storage is still not available
+			return
+		}
+		if e := os.Remove(path.Join(dir, fileName)); e != nil {
+			logger.Sugar().Errorf("failed to remove pending export %v, error=%v", fileName, e)
+		}
+		logger.Sugar().Infof("succeeded to export pending %v", key)
+	}
+}
+
+// exportRoundToObjectStorage exports the reports of the round in the background, when it is enabled in the configmap
+func (s *reportManager) exportRoundToObjectStorage(logger *zap.Logger, kindName, taskName string, roundNumber int) {
+	cfg := types.ControllerConfig.Configmap.ReportExport
+	if !cfg.Enabled {
+		return
+	}
+	if s.exportUploader == nil {
+		logger.Sugar().Errorf("no client of the object storage %v, ignore the export of %v %v round %v", cfg.Endpoint, kindName, taskName, roundNumber)
+		return
+	}
+	go s.exportRound(context.Background(), logger, s.exportUploader, cfg, kindName, taskName, roundNumber)
+}
@@ -0,0 +1,283 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/parquet-go/parquet-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	plugintypes "github.com/kdoctor-io/kdoctor/pkg/pluginManager/types"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// fakeObjectStorage is a minimal S3-compatible server which accepts PutObject
+type fakeObjectStorage struct {
+	lock    sync.Mutex
+	objects map[string][]byte
+	fail    bool
+}
+
+func (f *fakeObjectStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if r.Method != http.MethodPut || f.fail {
+		// the client does not retry on it
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	if r.Header.Get("X-Amz-Content-Sha256") == "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		body = decodeAwsChunked(body)
+	}
+	f.objects[r.URL.Path] = body
+	w.Header().Set("ETag", `"etag"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeAwsChunked decodes the signed body in the format of "size;chunk-signature=...\r\ndata\r\n"
+func decodeAwsChunked(body []byte) []byte {
+	var out []byte
+	for len(body) > 0 {
+		i := bytes.Index(body, []byte("\r\n"))
+		if i < 0 {
+			break
+		}
+		header := string(body[:i])
+		if j := strings.Index(header, ";"); j >= 0 {
+			header = header[:j]
+		}
+		size, err := strconv.ParseInt(header, 16, 64)
+		if err != nil || size == 0 {
+			break
+		}
+		body = body[i+2:]
+		out = append(out, body[:size]...)
+		body = body[size+2:]
+	}
+	return out
+}
+
+func (f *fakeObjectStorage) get(key string) ([]byte, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	v, ok := f.objects[key]
+	return v, ok
+}
+
+// hangingUploader does not respond until the context is done
+type hangingUploader struct{}
+
+func (hangingUploader) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+var _ = Describe("test report export", Label("report export"), func() {
+	var s *reportManager
+	var storage *fakeObjectStorage
+	var server *httptest.Server
+	var uploader objectUploader
+	log := logger.NewStdoutLogger("debug", "test")
+	start := time.Date(2023, 9, 1, 23, 59, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		s = &reportManager{logger: log, reportDir: GinkgoT().TempDir()}
+		storage = &fakeObjectStorage{objects: map[string][]byte{}}
+		server = httptest.NewServer(storage)
+		DeferCleanup(server.Close)
+
+		u, err := url.Parse(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		client, err := minio.New(u.Host, &minio.Options{
+			Creds:  credentials.NewStaticV4("id", "secret", ""),
+			Region: "us-east-1",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		uploader = &s3Uploader{client: client, bucket: "bucket"}
+
+		failedReason := "timeout"
+		agent := v1beta1.Report{
+			NodeName:       "worker1",
+			PodName:        "agent-1",
+			RoundResult:    "fail",
+			FailedReason:   &failedReason,
+			StartTimeStamp: metav1.NewTime(start),
+			EndTimeStamp:   metav1.NewTime(start.Add(time.Minute)),
+		}
+		summary := plugintypes.PluginReport{
+			RoundResult:    "fail",
+			PodName:        "controller-1",
+			FailedReason:   "failed agents",
+			StartTimeStamp: start,
+			EndTimeStamp:   start.Add(2 * time.Minute),
+		}
+		for name, v := range map[string]interface{}{
+			"NetReach_task_round2_worker1_1":  agent,
+			"NetReach_task_round2_summary_1":  summary,
+			"NetReach_task_round3_worker1_1":  agent,
+			"NetReach_other_round2_worker1_1": agent,
+		} {
+			data, err := json.Marshal(v)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(path.Join(s.reportDir, name), data, 0644)).To(Succeed())
+		}
+	})
+
+	It("validate the config", func() {
+		Expect(ValidateReportExportConfig(types.ReportExportConfig{})).To(Succeed())
+		cfg := types.ReportExportConfig{Enabled: true, Endpoint: "minio:9000", Bucket: "kdoctor"}
+		Expect(ValidateReportExportConfig(cfg)).To(Succeed())
+		cfg.Format = ReportExportFormatParquet
+		Expect(ValidateReportExportConfig(cfg)).To(Succeed())
+		cfg.Format = "csv"
+		Expect(ValidateReportExportConfig(cfg)).NotTo(Succeed())
+		cfg.Format = ""
+		cfg.Endpoint = "http://minio:9000"
+		Expect(ValidateReportExportConfig(cfg)).NotTo(Succeed())
+		cfg.Endpoint = "minio:9000"
+		cfg.Bucket = ""
+		Expect(ValidateReportExportConfig(cfg)).NotTo(Succeed())
+	})
+
+	It("partition the object key", func() {
+		key := ReportExportObjectKey("kdoctor", types.KindNameNetReach, "task", 2, start.In(time.FixedZone("UTC+8", 8*3600)), ReportExportFormatParquet)
+		Expect(key).To(Equal("kdoctor/kind=NetReach/task=task/date=2023-09-01/NetReach_task_round2.parquet"))
+		Expect(ReportExportObjectKey("", types.KindNameNetReach, "task", 2, start, ReportExportFormatJSONLines)).To(HavePrefix("kind=NetReach/"))
+	})
+
+	It("export the round as JSON Lines", func() {
+		cfg := types.ReportExportConfig{Prefix: "kdoctor"}
+		s.exportRound(context.Background(), log, uploader, cfg, types.KindNameNetReach, "task", 2)
+
+		data, ok := storage.get("/bucket/kdoctor/kind=NetReach/task=task/date=2023-09-01/NetReach_task_round2.jsonl")
+		Expect(ok).To(BeTrue())
+		records := map[string]ExportRecord{}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			r := ExportRecord{}
+			Expect(json.Unmarshal(scanner.Bytes(), &r)).To(Succeed())
+			records[r.ReportType] = r
+		}
+		Expect(records).To(HaveLen(2))
+		Expect(records[ReportExportTypeAgent].NodeName).To(Equal("worker1"))
+		Expect(records[ReportExportTypeAgent].FailedReason).To(Equal("timeout"))
+		Expect(records[ReportExportTypeAgent].RoundNumber).To(BeEquivalentTo(2))
+		Expect(records[ReportExportTypeSummary].PodName).To(Equal("controller-1"))
+		Expect(records[ReportExportTypeSummary].EndTime.Equal(start.Add(2 * time.Minute))).To(BeTrue())
+	})
+
+	It("export the round as Parquet", func() {
+		cfg := types.ReportExportConfig{Format: ReportExportFormatParquet}
+		s.exportRound(context.Background(), log, uploader, cfg, types.KindNameNetReach, "task", 2)
+
+		data, ok := storage.get("/bucket/kind=NetReach/task=task/date=2023-09-01/NetReach_task_round2.parquet")
+		Expect(ok).To(BeTrue())
+		reader := parquet.NewGenericReader[ExportRecord](bytes.NewReader(data))
+		defer reader.Close()
+		Expect(reader.NumRows()).To(BeEquivalentTo(2))
+		rows := make([]ExportRecord, 2)
+		n, err := reader.Read(rows)
+		if err != nil {
+			Expect(err).To(Equal(io.EOF))
+		}
+		Expect(n).To(Equal(2))
+		reportTypes := []string{rows[0].ReportType, rows[1].ReportType}
+		Expect(reportTypes).To(ConsistOf(ReportExportTypeAgent, ReportExportTypeSummary))
+		for _, r := range rows {
+			Expect(r.Task).To(Equal("task"))
+			Expect(json.Valid(r.Report)).To(BeTrue())
+		}
+	})
+
+	It("keep the failed object and upload it later", func() {
+		cfg := types.ReportExportConfig{Prefix: "kdoctor"}
+		storage.fail = true
+		s.exportRound(context.Background(), log, uploader, cfg, types.KindNameNetReach, "task", 2)
+		pending, err := os.ReadDir(path.Join(s.reportDir, reportExportPendingDir))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(HaveLen(1))
+
+		storage.lock.Lock()
+		storage.fail = false
+		storage.lock.Unlock()
+		s.exportRound(context.Background(), log, uploader, cfg, types.KindNameNetReach, "task", 3)
+		for _, round := range []int{2, 3} {
+			_, ok := storage.get(fmt.Sprintf("/bucket/kdoctor/kind=NetReach/task=task/date=2023-09-01/NetReach_task_round%d.jsonl", round))
+			Expect(ok).To(BeTrue())
+		}
+		pending, err = os.ReadDir(path.Join(s.reportDir, reportExportPendingDir))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(BeEmpty())
+	})
+
+	It("keep the object when the upload times out", func() {
+		timeout := reportExportUploadTimeout
+		DeferCleanup(func() { reportExportUploadTimeout = timeout })
+		reportExportUploadTimeout = 100 * time.Millisecond
+
+		s.exportRound(context.Background(), log, hangingUploader{}, types.ReportExportConfig{Prefix: "kdoctor"}, types.KindNameNetReach, "task", 2)
+		pending, err := os.ReadDir(path.Join(s.reportDir, reportExportPendingDir))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pending).To(HaveLen(1))
+	})
+
+	It("drop the expired pending objects and the oldest ones beyond the maximum", func() {
+		age := types.ControllerConfig.ReportAgeInDay
+		DeferCleanup(func() { types.ControllerConfig.ReportAgeInDay = age })
+		types.ControllerConfig.ReportAgeInDay = 1
+
+		dir := path.Join(s.reportDir, reportExportPendingDir)
+		Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+		now := time.Now()
+		for i, modTime := range []time.Time{now.Add(-48 * time.Hour), now.Add(-3 * time.Hour), now.Add(-2 * time.Hour), now.Add(-time.Hour)} {
+			name := path.Join(dir, url.PathEscape(fmt.Sprintf("kdoctor/old%d.jsonl", i)))
+			Expect(os.WriteFile(name, []byte("{}"), 0644)).To(Succeed())
+			Expect(os.Chtimes(name, modTime, modTime)).To(Succeed())
+		}
+
+		cfg := types.ReportExportConfig{Prefix: "kdoctor", MaxPendingObjects: 2}
+		storage.fail = true
+		s.exportRound(context.Background(), log, uploader, cfg, types.KindNameNetReach, "task", 2)
+		pending, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		names := []string{}
+		for _, p := range pending {
+			names = append(names, p.Name())
+		}
+		// the new object and the newest old one are kept
+		Expect(names).To(ConsistOf(
+			url.PathEscape("kdoctor/old3.jsonl"),
+			url.PathEscape("kdoctor/kind=NetReach/task=task/date=2023-09-01/NetReach_task_round2.jsonl"),
+		))
+	})
+
+	It("skip the round without reports", func() {
+		s.exportRound(context.Background(), log, uploader, types.ReportExportConfig{}, types.KindNameNetReach, "task", 9)
+		storage.lock.Lock()
+		defer storage.lock.Unlock()
+		Expect(storage.objects).To(BeEmpty())
+	})
+})
@@ -5,6 +5,8 @@ package reportManager
 
 import (
 	"context"
+	"fmt"
+	"github.com/kdoctor-io/kdoctor/pkg/grpcManager"
 	"github.com/kdoctor-io/kdoctor/pkg/scheduler"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 	"go.uber.org/zap"
@@ -15,6 +17,8 @@ import (
 
 const (
 	queueMaxRetries = 100
+	// the round is handled with the reports collected, when the reports of some agents still fail to be collected after the retries
+	roundCollectMaxRetries = 15
 )
 
 type reportManager struct {
@@ -25,21 +29,39 @@ type reportManager struct {
 	appHttpHealthyRuntimeDB scheduler.DB
 	netReachRuntimeDB       scheduler.DB
 	netDNSRuntimeDB         scheduler.DB
+	netTcpRuntimeDB         scheduler.DB
+	netUdpRuntimeDB         scheduler.DB
+	netDelayRuntimeDB       scheduler.DB
+	// the certificate of the controller and the ca of the agents for the grpc mTLS
+	certReloader *grpcManager.CertReloader
+	// the client of the object storage which the rounds are exported to, nil when the export is disabled
+	exportUploader objectUploader
 }
 
 var globalReportManager *reportManager
 
-func InitReportManager(logger *zap.Logger, reportDir string, collectInterval time.Duration, db map[string]scheduler.DB) {
+func InitReportManager(logger *zap.Logger, reportDir string, collectInterval time.Duration, db map[string]scheduler.DB, certReloader *grpcManager.CertReloader) {
 	if globalReportManager != nil {
 		return
 	}
+	if e := ValidateReportExportConfig(types.ControllerConfig.Configmap.ReportExport); e != nil {
+		logger.Sugar().Fatalf("invalid report export in the configmap, error=%v", e)
+	}
 
 	globalReportManager = &reportManager{
 		logger:          logger,
 		reportDir:       reportDir,
 		collectInterval: collectInterval,
+		certReloader:    certReloader,
 		queue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "reportManager"),
 	}
+	if cfg := types.ControllerConfig.Configmap.ReportExport; cfg.Enabled {
+		uploader, e := newS3Uploader(cfg)
+		if e != nil {
+			logger.Sugar().Fatalf("failed to create the client of the object storage %v, error=%v", cfg.Endpoint, e)
+		}
+		globalReportManager.exportUploader = uploader
+	}
 
 	for k, v := range db {
 		switch k {
@@ -49,6 +71,12 @@ func InitReportManager(logger *zap.Logger, reportDir string, collectInterval tim
 			globalReportManager.netReachRuntimeDB = v
 		case types.KindNameNetdns:
 			globalReportManager.netDNSRuntimeDB = v
+		case types.KindNameNetTcp:
+			globalReportManager.netTcpRuntimeDB = v
+		case types.KindNameNetUdp:
+			globalReportManager.netUdpRuntimeDB = v
+		case types.KindNameNetDelay:
+			globalReportManager.netDelayRuntimeDB = v
 		}
 	}
 
@@ -62,6 +90,9 @@ func (s *reportManager) runWorker() {
 
 	s.logger.Info("all agent grpc server ready, start worker")
 
+	s.importLocalReports()
+	s.loadPendingSinks(s.logger)
+
 	//
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -102,6 +133,12 @@ func (s *reportManager) processNextWorkItem(ctx context.Context) bool {
 		s.logger.Sugar().Warnf("worker failed , error=%v", err)
 		if s.queue.NumRequeues(key) < queueMaxRetries {
 			s.queue.AddRateLimited(key)
+		} else {
+			// nothing registered for the round is handled after giving up, except that the summary goes to the dead-letter directory
+			if p := dropPendingRound(key.(string)); p.sinks != nil {
+				s.deadLetterRoundSinks(s.logger, key.(string), *p.sinks, fmt.Errorf("failed to collect the reports of the round, error=%w", err))
+				s.removePendingSinks(s.logger, key.(string))
+			}
 		}
 	}
 	// handle nex item
@@ -52,7 +52,7 @@ var _ = Describe("unit test", Label("unit test"), func() {
 		dbMap[types.KindNameNetReach] = reachDB
 		dbMap[types.KindNameNetdns] = dnsDB
 
-		InitReportManager(log, reportDir, time.Second*5, dbMap)
+		InitReportManager(log, reportDir, time.Second*5, dbMap, nil)
 
 		_ = appDB.Apply(scheduler.BuildItem(v1beta1.TaskResource{
 			RuntimeName:   "test-app",
@@ -0,0 +1,129 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/utils/pointer"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// BuildNetDelayMatrix aggregates the NetDelay reports of all agents in a round into the node-to-node matrix.
+// When a node probes another node through both ipv4 and ipv6, the worse result is taken for the pair
+func BuildNetDelayMatrix(roundNumber int64, reports []v1beta1.Report) *v1beta1.NetDelayMatrix {
+	matrix := &v1beta1.NetDelayMatrix{
+		RoundNumber: roundNumber,
+		Matrix:      map[string]map[string]v1beta1.NetDelayPair{},
+	}
+
+	nodes := map[string]struct{}{}
+	sources := map[string]struct{}{}
+	for _, report := range reports {
+		if report.TaskNetDelay == nil {
+			continue
+		}
+		nodes[report.NodeName] = struct{}{}
+		sources[report.NodeName] = struct{}{}
+		if _, ok := matrix.Matrix[report.NodeName]; !ok {
+			matrix.Matrix[report.NodeName] = map[string]v1beta1.NetDelayPair{}
+		}
+
+		for _, detail := range report.TaskNetDelay.Detail {
+			nodes[detail.TargetNode] = struct{}{}
+			pair, ok := matrix.Matrix[report.NodeName][detail.TargetNode]
+			if !ok {
+				pair = v1beta1.NetDelayPair{
+					SourceNode:      report.NodeName,
+					DestinationNode: detail.TargetNode,
+					Succeed:         detail.Succeed,
+					SucceedRate:     detail.SucceedRate,
+					P50:             detail.P50,
+					P99:             detail.P99,
+					FailureReason:   detail.FailureReason,
+				}
+			} else {
+				if detail.P50 > pair.P50 {
+					pair.P50 = detail.P50
+				}
+				if detail.P99 > pair.P99 {
+					pair.P99 = detail.P99
+				}
+				if detail.SucceedRate < pair.SucceedRate {
+					pair.SucceedRate = detail.SucceedRate
+				}
+				if !detail.Succeed {
+					pair.Succeed = false
+					if pair.FailureReason == nil {
+						pair.FailureReason = detail.FailureReason
+					}
+				}
+			}
+			matrix.Matrix[report.NodeName][detail.TargetNode] = pair
+		}
+	}
+
+	for node := range nodes {
+		matrix.Nodes = append(matrix.Nodes, node)
+	}
+	sort.Strings(matrix.Nodes)
+
+	var failures []string
+	for _, src := range matrix.Nodes {
+		if _, ok := sources[src]; !ok {
+			failures = append(failures, fmt.Sprintf("no report from node %s", src))
+			continue
+		}
+		for _, dst := range matrix.Nodes {
+			if src == dst {
+				continue
+			}
+			pair, ok := matrix.Matrix[src][dst]
+			if !ok {
+				failures = append(failures, fmt.Sprintf("no probe from node %s to node %s", src, dst))
+				continue
+			}
+			if !pair.Succeed {
+				reason := "failed"
+				if pair.FailureReason != nil {
+					reason = *pair.FailureReason
+				}
+				failures = append(failures, fmt.Sprintf("%s -> %s: %s", src, dst, reason))
+			}
+			if matrix.SlowestPair == nil || pair.P99 > matrix.SlowestPair.P99 {
+				matrix.SlowestPair = pair.DeepCopy()
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		matrix.Succeed = false
+		matrix.FailureReason = pointer.String(strings.Join(failures, "; "))
+	} else {
+		matrix.Succeed = true
+	}
+
+	return matrix
+}
+
+// generateNetDelayMatrix builds the matrix from the agent reports of the round under the local report directory,
+// and replaces the matrix report generated before
+func (s *reportManager) generateNetDelayMatrix(logger *zap.Logger, taskName string, roundNumber int) error {
+	reports, oldMatrixFiles, e := s.readRoundReports(logger, types.KindNameNetDelay, taskName, roundNumber, v1beta1.NetDelayMatrixNodeName)
+	if e != nil {
+		return e
+	}
+	if len(reports) == 0 {
+		logger.Sugar().Debugf("no agent report of NetDelay %v round %v, skip generating the matrix", taskName, roundNumber)
+		return nil
+	}
+
+	matrix := BuildNetDelayMatrix(int64(roundNumber), reports)
+	return s.writeAggregateReport(logger, types.KindNameNetDelay, taskName, roundNumber, v1beta1.NetDelayMatrixNodeName, matrix, oldMatrixFiles)
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	"github.com/kdoctor-io/kdoctor/pkg/fileManager"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+func netDelayReport(roundNumber int64, source string, details ...v1beta1.NetDelayTaskDetail) v1beta1.Report {
+	for i := range details {
+		details[i].SourceNode = source
+	}
+	return v1beta1.Report{
+		RoundNumber: roundNumber,
+		NodeName:    source,
+		TaskNetDelay: &v1beta1.NetDelayTask{
+			Detail: details,
+		},
+	}
+}
+
+func netDelayDetail(target string, p50, p99 float32, succeed bool) v1beta1.NetDelayTaskDetail {
+	d := v1beta1.NetDelayTaskDetail{
+		TargetNode:  target,
+		Succeed:     succeed,
+		SucceedRate: 1,
+		P50:         p50,
+		P99:         p99,
+	}
+	if !succeed {
+		d.FailureReason = pointer.String(fmt.Sprintf("p99 delay %v ms is bigger than request", p99))
+	}
+	return d
+}
+
+var _ = Describe("unit test", Label("unit test "), func() {
+
+	It("build the full-mesh matrix", Label("reportManager netDelayMatrix"), func() {
+		reports := []v1beta1.Report{
+			netDelayReport(1, "node1", netDelayDetail("node2", 1, 2, true), netDelayDetail("node3", 1, 3, true)),
+			netDelayReport(1, "node2", netDelayDetail("node1", 1, 2, true), netDelayDetail("node3", 5, 20, true)),
+			netDelayReport(1, "node3", netDelayDetail("node1", 1, 3, true), netDelayDetail("node2", 4, 18, true)),
+		}
+
+		matrix := BuildNetDelayMatrix(1, reports)
+		Expect(matrix.RoundNumber).To(Equal(int64(1)))
+		Expect(matrix.Succeed).To(BeTrue())
+		Expect(matrix.FailureReason).To(BeNil())
+		Expect(matrix.Nodes).To(Equal([]string{"node1", "node2", "node3"}))
+		Expect(matrix.Matrix).To(HaveLen(3))
+		Expect(matrix.Matrix["node2"]).To(HaveLen(2))
+		Expect(matrix.Matrix["node2"]["node3"].P50).To(Equal(float32(5)))
+		Expect(matrix.Matrix["node2"]["node3"].P99).To(Equal(float32(20)))
+		Expect(matrix.SlowestPair).NotTo(BeNil())
+		Expect(matrix.SlowestPair.SourceNode).To(Equal("node2"))
+		Expect(matrix.SlowestPair.DestinationNode).To(Equal("node3"))
+	})
+
+	It("take the worse result of ipv4 and ipv6", Label("reportManager netDelayMatrix"), func() {
+		v6 := netDelayDetail("node2", 3, 4, false)
+		v6.SucceedRate = 0.5
+		reports := []v1beta1.Report{
+			netDelayReport(1, "node1", netDelayDetail("node2", 2, 9, true), v6),
+			netDelayReport(1, "node2", netDelayDetail("node1", 1, 2, true)),
+		}
+
+		matrix := BuildNetDelayMatrix(1, reports)
+		pair := matrix.Matrix["node1"]["node2"]
+		Expect(pair.P50).To(Equal(float32(3)))
+		Expect(pair.P99).To(Equal(float32(9)))
+		Expect(pair.SucceedRate).To(Equal(0.5))
+		Expect(pair.Succeed).To(BeFalse())
+		Expect(pair.FailureReason).NotTo(BeNil())
+		Expect(matrix.Succeed).To(BeFalse())
+		Expect(*matrix.FailureReason).To(ContainSubstring("node1 -> node2"))
+	})
+
+	It("fail the matrix with missing reports and pairs", Label("reportManager netDelayMatrix"), func() {
+		reports := []v1beta1.Report{
+			netDelayReport(1, "node1", netDelayDetail("node2", 1, 2, true), netDelayDetail("node3", 1, 2, true)),
+			netDelayReport(1, "node2", netDelayDetail("node1", 1, 2, true)),
+		}
+
+		matrix := BuildNetDelayMatrix(1, reports)
+		Expect(matrix.Nodes).To(Equal([]string{"node1", "node2", "node3"}))
+		Expect(matrix.Succeed).To(BeFalse())
+		Expect(*matrix.FailureReason).To(ContainSubstring("no probe from node node2 to node node3"))
+		Expect(*matrix.FailureReason).To(ContainSubstring("no report from node node3"))
+	})
+
+	It("generate the matrix report from local reports", Label("reportManager netDelayMatrix"), func() {
+		reportDir := fmt.Sprintf("/tmp/_FM_%d", time.Now().Nanosecond())
+		Expect(os.MkdirAll(reportDir, os.ModePerm)).To(Succeed())
+		defer os.RemoveAll(reportDir)
+
+		endTime := time.Now().Add(time.Hour)
+		write := func(roundNumber int, nodeName string, data interface{}) {
+			b, err := json.Marshal(data)
+			Expect(err).NotTo(HaveOccurred())
+			name := fileManager.GenerateTaskFileName(types.KindNameNetDelay, "test", roundNumber, nodeName, endTime)
+			Expect(os.WriteFile(path.Join(reportDir, name), b, 0644)).To(Succeed())
+		}
+		write(1, "node1", netDelayReport(1, "node1", netDelayDetail("node2", 1, 2, true)))
+		write(1, "node2", netDelayReport(1, "node2", netDelayDetail("node1", 1, 2, true)))
+		write(1, "summary", map[string]string{"TaskName": "netdelay.test"})
+		write(1, v1beta1.NetDelayMatrixNodeName, map[string]string{})
+		write(2, "node1", netDelayReport(2, "node1", netDelayDetail("node2", 1, 2, false)))
+
+		rm := &reportManager{
+			logger:    logger.NewStdoutLogger("debug", "reportManager Test"),
+			reportDir: reportDir,
+		}
+		Expect(rm.generateNetDelayMatrix(rm.logger, "test", 1)).To(Succeed())
+
+		entries, err := os.ReadDir(reportDir)
+		Expect(err).NotTo(HaveOccurred())
+		var matrixFiles []string
+		for _, item := range entries {
+			if strings.Contains(item.Name(), "_"+v1beta1.NetDelayMatrixNodeName+"_") {
+				matrixFiles = append(matrixFiles, item.Name())
+			}
+		}
+		Expect(matrixFiles).To(HaveLen(1))
+		Expect(matrixFiles[0]).To(HavePrefix("NetDelay_test_round1_matrix_"))
+
+		data, err := os.ReadFile(path.Join(reportDir, matrixFiles[0]))
+		Expect(err).NotTo(HaveOccurred())
+		matrix := v1beta1.NetDelayMatrix{}
+		Expect(json.Unmarshal(data, &matrix)).To(Succeed())
+		Expect(matrix.RoundNumber).To(Equal(int64(1)))
+		Expect(matrix.Succeed).To(BeTrue())
+		Expect(matrix.Nodes).To(Equal([]string{"node1", "node2"}))
+	})
+})
@@ -0,0 +1,151 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/utils/pointer"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+// BuildNetDnsDetectSummary combines the detect results of all agents in a round for each dns target.
+// All agents start the round at the same time and ramp the qps at the same pace,
+// so the steps with the same index of all agents are combined as the load of the whole cluster.
+// It returns nil when no agent runs in the detect mode
+func BuildNetDnsDetectSummary(roundNumber int64, reports []v1beta1.Report) *v1beta1.NetDnsDetectSummary {
+	summary := &v1beta1.NetDnsDetectSummary{
+		RoundNumber: roundNumber,
+		Targets:     []v1beta1.NetDnsDetectTarget{},
+	}
+
+	targets := map[string]*v1beta1.NetDnsDetectTarget{}
+	// the failure reasons of each step for each target
+	stepFailures := map[string]map[int][]string{}
+	var agents []string
+	for _, report := range reports {
+		if report.TaskNetDNS == nil {
+			continue
+		}
+		agents = append(agents, report.PodName)
+
+		for _, detail := range report.TaskNetDNS.Detail {
+			if detail.Detect == nil {
+				continue
+			}
+			target, ok := targets[detail.TargetName]
+			if !ok {
+				target = &v1beta1.NetDnsDetectTarget{
+					TargetName:   detail.TargetName,
+					TargetServer: detail.TargetServer,
+					AgentKneeQPS: map[string]int{},
+				}
+				targets[detail.TargetName] = target
+				stepFailures[detail.TargetName] = map[int][]string{}
+			}
+			target.AgentKneeQPS[report.PodName] = detail.Detect.KneeQPS
+
+			for i, step := range detail.Detect.Steps {
+				if len(target.Steps) <= i {
+					target.Steps = append(target.Steps, v1beta1.NetDnsDetectCombined{QPS: step.QPS, Succeed: true})
+				}
+				combined := &target.Steps[i]
+				combined.AgentNumber++
+				combined.ClusterQPS += step.QPS
+				combined.TPS += step.TPS
+				combined.RequestCounts += step.RequestCounts
+				combined.SuccessCounts += step.SuccessCounts
+				if step.Latencies.P99 > combined.MaxP99 {
+					combined.MaxP99 = step.Latencies.P99
+				}
+				if !step.Succeed {
+					combined.Succeed = false
+					reason := "failed"
+					if step.FailureReason != nil {
+						reason = *step.FailureReason
+					}
+					stepFailures[detail.TargetName][i] = append(stepFailures[detail.TargetName][i], fmt.Sprintf("%s: %s", report.PodName, reason))
+				}
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	summary.AgentNumber = len(agents)
+
+	var failures []string
+	for name, target := range targets {
+		for _, agent := range agents {
+			if _, ok := target.AgentKneeQPS[agent]; !ok {
+				failures = append(failures, fmt.Sprintf("%s: no detect result from agent %s", name, agent))
+			}
+		}
+
+		for i := range target.Steps {
+			combined := &target.Steps[i]
+			if combined.RequestCounts > 0 {
+				combined.SucceedRate = float64(combined.SuccessCounts) / float64(combined.RequestCounts)
+			}
+			// the agents which stop before the step could not contribute to the load of the cluster
+			if combined.AgentNumber < len(target.AgentKneeQPS) {
+				combined.Succeed = false
+				stepFailures[name][i] = append(stepFailures[name][i], fmt.Sprintf("only %d of %d agents run the step", combined.AgentNumber, len(target.AgentKneeQPS)))
+			}
+			if !combined.Succeed {
+				combined.FailureReason = pointer.String(strings.Join(stepFailures[name][i], "; "))
+				if target.BreakReason == nil {
+					target.BreakReason = pointer.String(fmt.Sprintf("qps %d of each agent: %s", combined.QPS, *combined.FailureReason))
+				}
+				continue
+			}
+			if target.BreakReason == nil {
+				target.ClusterKneeQPS = combined.ClusterQPS
+			}
+		}
+
+		if target.ClusterKneeQPS == 0 {
+			reason := "no step has been run"
+			if target.BreakReason != nil {
+				reason = *target.BreakReason
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s", name, reason))
+		}
+		summary.Targets = append(summary.Targets, *target)
+	}
+	sort.Slice(summary.Targets, func(i, j int) bool {
+		return summary.Targets[i].TargetName < summary.Targets[j].TargetName
+	})
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		summary.Succeed = false
+		summary.FailureReason = pointer.String(strings.Join(failures, "; "))
+	} else {
+		summary.Succeed = true
+	}
+
+	return summary
+}
+
+// generateNetDnsDetectSummary combines the detect results from the agent reports of the round under the local report directory,
+// and replaces the summary generated before
+func (s *reportManager) generateNetDnsDetectSummary(logger *zap.Logger, taskName string, roundNumber int) error {
+	reports, oldSummaryFiles, e := s.readRoundReports(logger, types.KindNameNetdns, taskName, roundNumber, v1beta1.NetDnsDetectNodeName)
+	if e != nil {
+		return e
+	}
+
+	summary := BuildNetDnsDetectSummary(int64(roundNumber), reports)
+	if summary == nil {
+		logger.Sugar().Debugf("no detect result of Netdns %v round %v, skip generating the detect summary", taskName, roundNumber)
+		return nil
+	}
+	return s.writeAggregateReport(logger, types.KindNameNetdns, taskName, roundNumber, v1beta1.NetDnsDetectNodeName, summary, oldSummaryFiles)
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+)
+
+const dnsDetectTarget = "typeA_10.96.0.10:53_kubernetes.default.svc.cluster.local"
+
+// dnsDetectReport generates the report of an agent whose steps succeed until the knee qps
+func dnsDetectReport(roundNumber int64, podName string, qpsList []int, kneeQPS int) v1beta1.Report {
+	result := &v1beta1.DnsDetectResult{KneeQPS: kneeQPS}
+	for _, qps := range qpsList {
+		step := v1beta1.DnsDetectStep{
+			QPS:           qps,
+			TPS:           float64(qps),
+			RequestCounts: int64(qps),
+			SuccessCounts: int64(qps),
+			Succeed:       qps <= kneeQPS,
+			Latencies:     v1beta1.LatencyDistribution{P99: float32(qps) / 10},
+		}
+		if !step.Succeed {
+			step.SuccessCounts = int64(qps) / 2
+			step.FailureReason = pointer.String("Success Rate 0.5 is lower than request 1")
+			result.BreakReason = step.FailureReason
+		}
+		result.Steps = append(result.Steps, step)
+	}
+
+	return v1beta1.Report{
+		RoundNumber: roundNumber,
+		NodeName:    podName,
+		PodName:     podName,
+		TaskNetDNS: &v1beta1.NetDNSTask{
+			Detail: []v1beta1.NetDNSTaskDetail{
+				{
+					TargetName:   dnsDetectTarget,
+					TargetServer: "10.96.0.10:53",
+					Detect:       result,
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("unit test", Label("unit test "), func() {
+
+	It("combine the steps of all agents", Label("reportManager netDnsDetect"), func() {
+		reports := []v1beta1.Report{
+			dnsDetectReport(1, "agent1", []int{100, 200, 300}, 200),
+			dnsDetectReport(1, "agent2", []int{100, 200, 300}, 300),
+		}
+
+		summary := BuildNetDnsDetectSummary(1, reports)
+		Expect(summary).NotTo(BeNil())
+		Expect(summary.AgentNumber).To(Equal(2))
+		Expect(summary.Succeed).To(BeTrue())
+		Expect(summary.Targets).To(HaveLen(1))
+
+		target := summary.Targets[0]
+		Expect(target.AgentKneeQPS).To(Equal(map[string]int{"agent1": 200, "agent2": 300}))
+		Expect(target.ClusterKneeQPS).To(Equal(400))
+		Expect(target.Steps).To(HaveLen(3))
+		Expect(target.Steps[1].ClusterQPS).To(Equal(400))
+		Expect(target.Steps[2].Succeed).To(BeFalse())
+		Expect(target.Steps[2].MaxP99).To(Equal(float32(30)))
+		Expect(target.Steps[2].SucceedRate).To(Equal(0.75))
+		Expect(*target.Steps[2].FailureReason).To(ContainSubstring("agent1"))
+		Expect(target.BreakReason).NotTo(BeNil())
+	})
+
+	It("break at the step which not all agents run", Label("reportManager netDnsDetect"), func() {
+		reports := []v1beta1.Report{
+			dnsDetectReport(1, "agent1", []int{100, 200}, 100),
+			dnsDetectReport(1, "agent2", []int{100, 200, 300}, 300),
+		}
+
+		summary := BuildNetDnsDetectSummary(1, reports)
+		target := summary.Targets[0]
+		Expect(target.ClusterKneeQPS).To(Equal(200))
+		Expect(target.Steps[2].AgentNumber).To(Equal(1))
+		Expect(target.Steps[2].Succeed).To(BeFalse())
+	})
+
+	It("fail the summary when the first step breaks", Label("reportManager netDnsDetect"), func() {
+		reports := []v1beta1.Report{
+			dnsDetectReport(1, "agent1", []int{100}, 0),
+			dnsDetectReport(1, "agent2", []int{100, 200}, 200),
+			{RoundNumber: 1, PodName: "agent3", TaskNetDNS: &v1beta1.NetDNSTask{}},
+		}
+
+		summary := BuildNetDnsDetectSummary(1, reports)
+		Expect(summary.AgentNumber).To(Equal(3))
+		Expect(summary.Succeed).To(BeFalse())
+		Expect(summary.Targets[0].ClusterKneeQPS).To(Equal(0))
+		Expect(*summary.FailureReason).To(ContainSubstring("no detect result from agent agent3"))
+	})
+
+	It("skip the reports without detect", Label("reportManager netDnsDetect"), func() {
+		reports := []v1beta1.Report{
+			{RoundNumber: 1, PodName: "agent1", TaskNetDNS: &v1beta1.NetDNSTask{Detail: []v1beta1.NetDNSTaskDetail{{TargetName: dnsDetectTarget}}}},
+		}
+		Expect(BuildNetDnsDetectSummary(1, reports)).To(BeNil())
+	})
+})
@@ -0,0 +1,120 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"time"
+
+	"github.com/kdoctor-io/kdoctor/pkg/lock"
+)
+
+const reportUpdateBuffer = 100
+
+// ReportUpdate tells the reports or the status of the task may be changed
+type ReportUpdate struct {
+	KindName string
+	TaskName string
+	// the revision increases with each update, and it is used as the resource version of the kdoctorreport
+	Revision uint64
+}
+
+type reportNotifier struct {
+	lock     lock.Mutex
+	revision uint64
+	// the revision of the tasks which have no update since the controller starts
+	startRevision uint64
+	// the latest update of each task
+	taskUpdate  map[string]ReportUpdate
+	subscribers map[int]chan ReportUpdate
+	nextID      int
+}
+
+// the revision starts from the time, so that it keeps increasing after the controller restarts
+var globalReportNotifier = newReportNotifier(uint64(time.Now().UnixNano()))
+
+func newReportNotifier(revision uint64) *reportNotifier {
+	return &reportNotifier{
+		revision:      revision,
+		startRevision: revision,
+		taskUpdate:    map[string]ReportUpdate{},
+		subscribers:   map[int]chan ReportUpdate{},
+	}
+}
+
+// NotifyReportUpdate is called when reports of a new round are collected, or when the status of the task changes
+func NotifyReportUpdate(kindName, taskName string) {
+	n := globalReportNotifier
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.revision++
+	update := ReportUpdate{KindName: kindName, TaskName: taskName, Revision: n.revision}
+	n.taskUpdate[kindName+"."+taskName] = update
+	for id, ch := range n.subscribers {
+		select {
+		case ch <- update:
+		default:
+			// the slow subscriber is closed, and it should subscribe again
+			close(ch)
+			delete(n.subscribers, id)
+		}
+	}
+}
+
+// SubscribeReportUpdate returns the channel of the updates, which is closed by the unsubscribe function,
+// or when the subscriber falls behind
+func SubscribeReportUpdate() (<-chan ReportUpdate, func()) {
+	n := globalReportNotifier
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	id := n.nextID
+	n.nextID++
+	ch := make(chan ReportUpdate, reportUpdateBuffer)
+	n.subscribers[id] = ch
+
+	unsubscribe := func() {
+		n.lock.Lock()
+		defer n.lock.Unlock()
+		if _, ok := n.subscribers[id]; ok {
+			close(ch)
+			delete(n.subscribers, id)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// CurrentRevision returns the revision of the latest update
+func CurrentRevision() uint64 {
+	n := globalReportNotifier
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.revision
+}
+
+// TaskRevision returns the revision of the latest update of the task, which does not change with the updates of the other tasks.
+// The task which has no update since the controller starts keeps the start revision
+func TaskRevision(kindName, taskName string) uint64 {
+	n := globalReportNotifier
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if update, ok := n.taskUpdate[kindName+"."+taskName]; ok {
+		return update.Revision
+	}
+	return n.startRevision
+}
+
+// UpdatesSince returns the latest update of the tasks which are updated after the revision
+func UpdatesSince(revision uint64) []ReportUpdate {
+	n := globalReportNotifier
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	result := []ReportUpdate{}
+	for _, update := range n.taskUpdate {
+		if update.Revision > revision {
+			result = append(result, update)
+		}
+	}
+	return result
+}
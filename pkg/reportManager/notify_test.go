@@ -0,0 +1,61 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+var _ = Describe("test report notifier", Label("reportManager notify"), func() {
+
+	It("notify the subscribers", func() {
+		updates, unsubscribe := SubscribeReportUpdate()
+		start := CurrentRevision()
+
+		NotifyReportUpdate(types.KindNameNetReach, "task1")
+		NotifyReportUpdate(types.KindNameNetdns, "task2")
+
+		var first, second ReportUpdate
+		Eventually(updates).Should(Receive(&first))
+		Eventually(updates).Should(Receive(&second))
+		Expect(first.KindName).To(Equal(types.KindNameNetReach))
+		Expect(first.TaskName).To(Equal("task1"))
+		Expect(second.Revision).To(BeNumerically(">", first.Revision))
+		Expect(first.Revision).To(BeNumerically(">", start))
+
+		Expect(TaskRevision(types.KindNameNetReach, "task1")).To(Equal(first.Revision))
+		Expect(TaskRevision(types.KindNameNetdns, "task2")).To(Equal(second.Revision))
+		// the revision of a task does not change with the updates of the other tasks
+		none := TaskRevision(types.KindNameNetReach, "none")
+		Expect(none).To(BeNumerically("<=", start))
+		NotifyReportUpdate(types.KindNameNetdns, "task2")
+		Eventually(updates).Should(Receive())
+		Expect(TaskRevision(types.KindNameNetReach, "task1")).To(Equal(first.Revision))
+		Expect(TaskRevision(types.KindNameNetReach, "none")).To(Equal(none))
+		Expect(UpdatesSince(first.Revision)).To(HaveLen(1))
+
+		unsubscribe()
+		Eventually(updates).Should(BeClosed())
+		// unsubscribe twice is fine
+		unsubscribe()
+	})
+
+	It("close the slow subscriber", func() {
+		updates, unsubscribe := SubscribeReportUpdate()
+		defer unsubscribe()
+
+		for i := 0; i <= reportUpdateBuffer; i++ {
+			NotifyReportUpdate(types.KindNameNetTcp, "task")
+		}
+		// the buffered updates are received before the channel is closed
+		received := 0
+		for range updates {
+			received++
+		}
+		Expect(received).To(Equal(reportUpdateBuffer))
+	})
+})
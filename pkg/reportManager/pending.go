@@ -0,0 +1,56 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kdoctor-io/kdoctor/pkg/lock"
+)
+
+// pendingRound holds what is registered for the trigger of a task round, which is handled
+// after the agent reports of the round are collected
+type pendingRound struct {
+	sinks    *roundSinks
+	baseline *roundBaseline
+	trace    trace.SpanContext
+}
+
+func (p *pendingRound) empty() bool {
+	return p.sinks == nil && p.baseline == nil && !p.trace.IsValid()
+}
+
+var pendingRounds = struct {
+	lock  lock.Mutex
+	items map[string]*pendingRound
+}{items: map[string]*pendingRound{}}
+
+// updatePendingRound calls fn with the pending round of the trigger under the lock,
+// and the pending round is removed when nothing is left in it
+func updatePendingRound(trigger string, fn func(p *pendingRound)) {
+	pendingRounds.lock.Lock()
+	defer pendingRounds.lock.Unlock()
+	p, ok := pendingRounds.items[trigger]
+	if !ok {
+		p = &pendingRound{}
+	}
+	fn(p)
+	if p.empty() {
+		delete(pendingRounds.items, trigger)
+		return
+	}
+	pendingRounds.items[trigger] = p
+}
+
+// dropPendingRound removes all registered for the trigger, when the collection of the reports of the round gives up
+func dropPendingRound(trigger string) pendingRound {
+	pendingRounds.lock.Lock()
+	defer pendingRounds.lock.Unlock()
+	p, ok := pendingRounds.items[trigger]
+	if !ok {
+		return pendingRound{}
+	}
+	delete(pendingRounds.items, trigger)
+	return *p
+}
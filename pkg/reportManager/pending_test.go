@@ -0,0 +1,55 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/trace"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+var _ = Describe("test pending round", Label("pending round"), func() {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	})
+	register := func(trigger string) {
+		updatePendingRound(trigger, func(p *pendingRound) {
+			p.sinks = &roundSinks{record: crd.StatusHistoryRecord{RoundNumber: 1}}
+			p.baseline = &roundBaseline{}
+			p.trace = spanContext
+		})
+	}
+
+	It("remove the pending round after all are handled", func() {
+		trigger := types.KindNameNetReach + ".handled.1"
+		register(trigger)
+
+		_, ok := popRoundBaseline(trigger)
+		Expect(ok).To(BeTrue())
+		v, ok := popRoundSinks(trigger)
+		Expect(ok).To(BeTrue())
+		Expect(v.record.RoundNumber).To(Equal(1))
+		Expect(pendingRounds.items).To(HaveKey(trigger))
+
+		forgetRoundTrace(trigger)
+		Expect(pendingRounds.items).NotTo(HaveKey(trigger))
+		_, ok = popRoundSinks(trigger)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("drop all of the pending round when the collection gives up", func() {
+		trigger := types.KindNameNetReach + ".dropped.1"
+		register(trigger)
+
+		p := dropPendingRound(trigger)
+		Expect(p.sinks).NotTo(BeNil())
+		Expect(p.baseline).NotTo(BeNil())
+		Expect(p.trace).To(Equal(spanContext))
+		Expect(pendingRounds.items).NotTo(HaveKey(trigger))
+	})
+})
@@ -0,0 +1,409 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+const (
+	// ReportSinkSignatureHeader carries "sha256=<hex>", the HMAC-SHA256 of the request body signed with the secret of the sink
+	ReportSinkSignatureHeader = "X-Kdoctor-Signature-256"
+
+	// the directory under the report directory, which saves the round summary failing to push
+	reportSinkDeadLetterDir = "deadletter"
+	// the directory under the report directory, which saves the summary and the sinks of the round not pushed yet,
+	// so that the round is still pushed after the controller restarts
+	reportSinkPendingDir = "pendingsinks"
+
+	reportSinkDefaultMaxRetries      = 3
+	reportSinkDefaultTimeoutInSecond = 10
+	reportSinkMaxRetryDelay          = 30 * time.Second
+)
+
+// the delay before the first retry, and it doubles for each retry
+var reportSinkRetryBaseDelay = time.Second
+
+// the oldest dead letters beyond it are dropped, so the directory does not grow when a sink is down for long
+var reportSinkMaxDeadLetters = 1000
+
+// ReportSinkPayload is the body posted to the report sink for each round
+type ReportSinkPayload struct {
+	KindName    string                  `json:"kindName"`
+	TaskName    string                  `json:"taskName"`
+	RoundNumber int                     `json:"roundNumber"`
+	Summary     crd.StatusHistoryRecord `json:"summary"`
+	Reports     []v1beta1.Report        `json:"reports"`
+	// the reports of some agents failed to be collected after the retries, so the reports are not all of the round
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+type reportSinkDeadLetter struct {
+	URL     string          `json:"url"`
+	Error   string          `json:"error"`
+	Time    time.Time       `json:"time"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// sinkTarget is the report sink with the resolved hmac key
+type sinkTarget struct {
+	url             string
+	key             []byte
+	failedRoundOnly bool
+	maxRetries      int
+	timeout         time.Duration
+	// the error to resolve the sink, and the summary goes to the dead-letter directory directly
+	err error
+}
+
+type roundSinks struct {
+	record crd.StatusHistoryRecord
+	sinks  []crd.ReportSink
+}
+
+// the file of the round sinks under the pending directory
+type pendingSinksFile struct {
+	Record crd.StatusHistoryRecord `json:"record"`
+	Sinks  []crd.ReportSink        `json:"sinks"`
+}
+
+// RegisterRoundSinks records the summary and the sinks of the task round, which is pushed
+// after the agent reports of the round are collected for the trigger
+func RegisterRoundSinks(trigger string, record crd.StatusHistoryRecord, sinks []crd.ReportSink) {
+	s := globalReportManager
+	if s == nil {
+		return
+	}
+	updatePendingRound(trigger, func(p *pendingRound) {
+		p.sinks = &roundSinks{record: *record.DeepCopy(), sinks: sinks}
+		s.savePendingSinks(s.logger, trigger, *p.sinks)
+	})
+}
+
+func pendingSinksFileName(trigger string) string {
+	return url.PathEscape(trigger) + ".json"
+}
+
+// savePendingSinks saves the round sinks of the trigger, until the round is pushed or goes to the dead-letter directory
+func (s *reportManager) savePendingSinks(logger *zap.Logger, trigger string, v roundSinks) {
+	dir := path.Join(s.reportDir, reportSinkPendingDir)
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		logger.Sugar().Errorf("failed to create directory %v, error=%v", dir, e)
+		return
+	}
+	data, e := json.Marshal(pendingSinksFile{Record: v.record, Sinks: v.sinks})
+	if e != nil {
+		logger.Sugar().Errorf("failed to marshal pending sinks of %v, error=%v", trigger, e)
+		return
+	}
+	if e := os.WriteFile(path.Join(dir, pendingSinksFileName(trigger)), data, 0644); e != nil {
+		logger.Sugar().Errorf("failed to save pending sinks of %v, error=%v", trigger, e)
+	}
+}
+
+func (s *reportManager) removePendingSinks(logger *zap.Logger, trigger string) {
+	if e := os.Remove(path.Join(s.reportDir, reportSinkPendingDir, pendingSinksFileName(trigger))); e != nil && !os.IsNotExist(e) {
+		logger.Sugar().Errorf("failed to remove pending sinks of %v, error=%v", trigger, e)
+	}
+}
+
+// loadPendingSinks registers the round sinks saved before the controller restarts,
+// and collects the reports of the rounds again to push them
+func (s *reportManager) loadPendingSinks(logger *zap.Logger) {
+	dir := path.Join(s.reportDir, reportSinkPendingDir)
+	entries, e := os.ReadDir(dir)
+	if e != nil {
+		// no pending sinks
+		return
+	}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		trigger, e := url.PathUnescape(strings.TrimSuffix(fileName, ".json"))
+		if e != nil || entry.IsDir() || !strings.HasSuffix(fileName, ".json") {
+			logger.Sugar().Warnf("ignore unrecognized pending sinks %v", fileName)
+			continue
+		}
+		data, e := os.ReadFile(path.Join(dir, fileName))
+		if e != nil {
+			logger.Sugar().Errorf("failed to read pending sinks %v, error=%v", fileName, e)
+			continue
+		}
+		v := pendingSinksFile{}
+		if e := json.Unmarshal(data, &v); e != nil {
+			logger.Sugar().Errorf("failed to parse pending sinks %v, error=%v", fileName, e)
+			continue
+		}
+		updatePendingRound(trigger, func(p *pendingRound) {
+			if p.sinks == nil {
+				p.sinks = &roundSinks{record: v.Record, sinks: v.Sinks}
+			}
+		})
+		logger.Sugar().Infof("restore the pending sinks of %v", trigger)
+		s.queue.Add(trigger)
+	}
+}
+
+func popRoundSinks(trigger string) (roundSinks, bool) {
+	var v *roundSinks
+	updatePendingRound(trigger, func(p *pendingRound) {
+		v, p.sinks = p.sinks, nil
+	})
+	if v == nil {
+		return roundSinks{}, false
+	}
+	return *v, true
+}
+
+func getSecretKey(ctx context.Context, name, key string) ([]byte, error) {
+	secret, e := k8sObjManager.GetK8sObjManager().GetSecret(ctx, name, types.ControllerConfig.PodNamespace)
+	if e != nil {
+		return nil, e
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("no key %v in secret %v/%v", key, types.ControllerConfig.PodNamespace, name)
+	}
+	return data, nil
+}
+
+// resolveReportSinks merges the cluster-wide sinks in the configmap with the sinks of the task
+func resolveReportSinks(ctx context.Context, sinks []crd.ReportSink) []sinkTarget {
+	result := []sinkTarget{}
+	newTarget := func(url string, failedRoundOnly bool, maxRetries, timeoutInSecond *int32) sinkTarget {
+		t := sinkTarget{
+			url:             url,
+			failedRoundOnly: failedRoundOnly,
+			maxRetries:      reportSinkDefaultMaxRetries,
+			timeout:         reportSinkDefaultTimeoutInSecond * time.Second,
+		}
+		if maxRetries != nil {
+			t.maxRetries = int(*maxRetries)
+		}
+		if timeoutInSecond != nil {
+			t.timeout = time.Duration(*timeoutInSecond) * time.Second
+		}
+		return t
+	}
+
+	for _, v := range types.ControllerConfig.Configmap.ReportSinks {
+		t := newTarget(v.URL, v.FailedRoundOnly, v.MaxRetries, v.TimeoutInSecond)
+		if len(v.HmacSecretName) != 0 {
+			t.key, t.err = getSecretKey(ctx, v.HmacSecretName, v.HmacSecretKey)
+		}
+		result = append(result, t)
+	}
+	for _, v := range sinks {
+		t := newTarget(v.URL, v.FailedRoundOnly != nil && *v.FailedRoundOnly, v.MaxRetries, v.TimeoutInSecond)
+		if v.HmacSecret != nil {
+			t.key, t.err = getSecretKey(ctx, v.HmacSecret.Name, v.HmacSecret.Key)
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// SignReportSinkBody returns the value of the signature header for the body
+func SignReportSinkBody(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postReportSink(ctx context.Context, sink sinkTarget, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, sink.timeout)
+	defer cancel()
+
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, sink.url, bytes.NewReader(body))
+	if e != nil {
+		return e
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(sink.key) != 0 {
+		req.Header.Set(ReportSinkSignatureHeader, SignReportSinkBody(sink.key, body))
+	}
+
+	resp, e := http.DefaultClient.Do(req)
+	if e != nil {
+		return e
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+	return nil
+}
+
+// pushToSink posts the body to the sink with exponential backoff, and returns the last error when all retries fail
+func pushToSink(ctx context.Context, logger *zap.Logger, sink sinkTarget, body []byte) error {
+	if sink.err != nil {
+		return sink.err
+	}
+	delay := reportSinkRetryBaseDelay
+	var e error
+	for i := 0; i <= sink.maxRetries; i++ {
+		if i > 0 {
+			logger.Sugar().Warnf("failed to push round summary to report sink %v, retry after %v, error=%v", sink.url, delay, e)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+			if delay > reportSinkMaxRetryDelay {
+				delay = reportSinkMaxRetryDelay
+			}
+		}
+		if e = postReportSink(ctx, sink, body); e == nil {
+			return nil
+		}
+	}
+	return e
+}
+
+func (s *reportManager) writeDeadLetter(logger *zap.Logger, payload *ReportSinkPayload, index int, sink sinkTarget, body []byte, err error) {
+	dir := path.Join(s.reportDir, reportSinkDeadLetterDir)
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		logger.Sugar().Errorf("failed to create dead-letter directory %v, error=%v", dir, e)
+		return
+	}
+	data, e := json.MarshalIndent(reportSinkDeadLetter{
+		URL:     sink.url,
+		Error:   err.Error(),
+		Time:    time.Now(),
+		Payload: body,
+	}, "", "\t")
+	if e != nil {
+		logger.Sugar().Errorf("failed to marshal dead letter for report sink %v, error=%v", sink.url, e)
+		return
+	}
+	fileName := fmt.Sprintf("%s_%s_round%d_sink%d_%s.json", payload.KindName, payload.TaskName, payload.RoundNumber, index, time.Now().Format("20060102150405"))
+	if e := os.WriteFile(path.Join(dir, fileName), data, 0644); e != nil {
+		logger.Sugar().Errorf("failed to write dead letter %v, error=%v", fileName, e)
+		return
+	}
+	logger.Sugar().Infof("saved the round summary failing to push to report sink %v as dead letter %v", sink.url, fileName)
+	pruneAgedFiles(logger, dir, reportSinkMaxDeadLetters, "dead letter")
+}
+
+// pushRoundSummary posts the round summary to all sinks, and saves it to the dead-letter directory when a sink fails
+func (s *reportManager) pushRoundSummary(ctx context.Context, logger *zap.Logger, payload *ReportSinkPayload, sinks []sinkTarget) {
+	body, e := json.Marshal(payload)
+	if e != nil {
+		logger.Sugar().Errorf("failed to marshal round summary of %v %v round %v, error=%v", payload.KindName, payload.TaskName, payload.RoundNumber, e)
+		return
+	}
+	failed := payload.Summary.Status != crd.StatusHistoryRecordStatusSucceed || payload.Incomplete
+
+	var wg sync.WaitGroup
+	for i, sink := range sinks {
+		if sink.failedRoundOnly && !failed {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, sink sinkTarget) {
+			defer wg.Done()
+			if e := pushToSink(ctx, logger, sink, body); e != nil {
+				logger.Sugar().Errorf("failed to push round summary of %v %v round %v to report sink %v, error=%v", payload.KindName, payload.TaskName, payload.RoundNumber, sink.url, e)
+				s.writeDeadLetter(logger, payload, i, sink, body, e)
+				return
+			}
+			logger.Sugar().Debugf("succeeded to push round summary of %v %v round %v to report sink %v", payload.KindName, payload.TaskName, payload.RoundNumber, sink.url)
+		}(i, sink)
+	}
+	wg.Wait()
+}
+
+// deadLetterRoundSinks saves the summary of the round registered for the trigger to the dead-letter directory,
+// when the collection of the agent reports of the round gives up
+func (s *reportManager) deadLetterRoundSinks(logger *zap.Logger, trigger string, v roundSinks, err error) {
+	sinks := resolveReportSinks(context.Background(), v.sinks)
+	if len(sinks) == 0 {
+		return
+	}
+
+	// trigger format: fmt.Sprintf("%s.%s.%d", kindName, taskName, roundNumber)
+	payload := &ReportSinkPayload{
+		RoundNumber: v.record.RoundNumber,
+		Summary:     v.record,
+		Reports:     []v1beta1.Report{},
+	}
+	if t := strings.Split(trigger, "."); len(t) > 1 {
+		payload.KindName = t[0]
+		payload.TaskName = t[1]
+	}
+	body, e := json.Marshal(payload)
+	if e != nil {
+		logger.Sugar().Errorf("failed to marshal round summary of %v, error=%v", trigger, e)
+		return
+	}
+	failed := payload.Summary.Status != crd.StatusHistoryRecordStatusSucceed
+	for i, sink := range sinks {
+		if sink.failedRoundOnly && !failed {
+			continue
+		}
+		s.writeDeadLetter(logger, payload, i, sink, body, err)
+	}
+}
+
+// pushRoundToSinks pushes the summary of the round registered for the trigger in the background
+func (s *reportManager) pushRoundToSinks(logger *zap.Logger, trigger, kindName, taskName string, roundNumber int, incomplete bool) {
+	v, ok := popRoundSinks(trigger)
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+	sinks := resolveReportSinks(ctx, v.sinks)
+	if len(sinks) == 0 {
+		s.removePendingSinks(logger, trigger)
+		return
+	}
+
+	// the aggregated report is not the report of an agent
+	aggregateNodeName := ""
+	switch kindName {
+	case types.KindNameNetDelay:
+		aggregateNodeName = v1beta1.NetDelayMatrixNodeName
+	case types.KindNameNetdns:
+		aggregateNodeName = v1beta1.NetDnsDetectNodeName
+	}
+	reports, _, e := s.readRoundReports(logger, kindName, taskName, roundNumber, aggregateNodeName)
+	if e != nil {
+		logger.Sugar().Errorf("failed to read reports of %v %v round %v for report sinks, error=%v", kindName, taskName, roundNumber, e)
+	}
+	payload := &ReportSinkPayload{
+		KindName:    kindName,
+		TaskName:    taskName,
+		RoundNumber: roundNumber,
+		Summary:     v.record,
+		Reports:     reports,
+		Incomplete:  incomplete,
+	}
+	go func() {
+		s.pushRoundSummary(ctx, logger, payload, sinks)
+		// the round is pushed again after the controller restarts during the push
+		s.removePendingSinks(logger, trigger)
+	}()
+}
@@ -0,0 +1,217 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+
+	crd "github.com/kdoctor-io/kdoctor/pkg/k8s/apis/kdoctor.io/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/types"
+)
+
+var _ = Describe("test report sink", Label("report sink"), func() {
+	var s *reportManager
+	var payload *ReportSinkPayload
+	log := logger.NewStdoutLogger("debug", "test")
+
+	BeforeEach(func() {
+		reportSinkRetryBaseDelay = 10 * time.Millisecond
+		s = &reportManager{logger: log, reportDir: GinkgoT().TempDir()}
+		payload = &ReportSinkPayload{
+			KindName:    types.KindNameNetReach,
+			TaskName:    "task",
+			RoundNumber: 2,
+			Summary:     crd.StatusHistoryRecord{Status: crd.StatusHistoryRecordStatusFail, RoundNumber: 2},
+			Reports:     []v1beta1.Report{{NodeName: "worker1", RoundResult: "fail"}},
+		}
+	})
+
+	target := func(url string) sinkTarget {
+		return sinkTarget{url: url, maxRetries: 2, timeout: time.Second}
+	}
+
+	It("push the signed round summary", func() {
+		key := []byte("secret")
+		var received ReportSinkPayload
+		var signature string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(body, &received)).To(Succeed())
+			signature = r.Header.Get(ReportSinkSignatureHeader)
+			Expect(signature).To(Equal(SignReportSinkBody(key, body)))
+		}))
+		defer server.Close()
+
+		sink := target(server.URL)
+		sink.key = key
+		s.pushRoundSummary(context.Background(), log, payload, []sinkTarget{sink})
+		Expect(received).To(Equal(*payload))
+		Expect(signature).To(HavePrefix("sha256="))
+	})
+
+	It("retry after failure", func() {
+		var count int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&count, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		s.pushRoundSummary(context.Background(), log, payload, []sinkTarget{target(server.URL)})
+		Expect(atomic.LoadInt32(&count)).To(BeEquivalentTo(3))
+		_, err := os.Stat(path.Join(s.reportDir, reportSinkDeadLetterDir))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("save the dead letter when all retries fail", func() {
+		var count int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&count, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		s.pushRoundSummary(context.Background(), log, payload, []sinkTarget{target(server.URL)})
+		Expect(atomic.LoadInt32(&count)).To(BeEquivalentTo(3))
+
+		dir := path.Join(s.reportDir, reportSinkDeadLetterDir)
+		files, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		data, err := os.ReadFile(path.Join(dir, files[0].Name()))
+		Expect(err).NotTo(HaveOccurred())
+		letter := reportSinkDeadLetter{}
+		Expect(json.Unmarshal(data, &letter)).To(Succeed())
+		Expect(letter.URL).To(Equal(server.URL))
+		Expect(letter.Error).To(ContainSubstring("500"))
+		received := ReportSinkPayload{}
+		Expect(json.Unmarshal(letter.Payload, &received)).To(Succeed())
+		Expect(received.RoundNumber).To(Equal(2))
+	})
+
+	It("drop the oldest dead letters beyond the maximum", func() {
+		reportSinkMaxDeadLetters = 2
+		defer func() { reportSinkMaxDeadLetters = 1000 }()
+		dir := path.Join(s.reportDir, reportSinkDeadLetterDir)
+		Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+		for i, name := range []string{"old1.json", "old2.json"} {
+			file := path.Join(dir, name)
+			Expect(os.WriteFile(file, []byte("{}"), 0644)).To(Succeed())
+			modTime := time.Now().Add(-time.Duration(10-i) * time.Minute)
+			Expect(os.Chtimes(file, modTime, modTime)).To(Succeed())
+		}
+
+		s.writeDeadLetter(log, payload, 0, target("http://sink"), []byte("{}"), errors.New("failed"))
+		files, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(2))
+		for _, f := range files {
+			Expect(f.Name()).NotTo(Equal("old1.json"))
+		}
+	})
+
+	It("save the dead letter when the collection of the round gives up", func() {
+		trigger := types.KindNameNetReach + ".task.2"
+		s.deadLetterRoundSinks(log, trigger, roundSinks{record: payload.Summary, sinks: []crd.ReportSink{{URL: "http://sink"}}}, errors.New("no agent"))
+
+		dir := path.Join(s.reportDir, reportSinkDeadLetterDir)
+		files, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		data, err := os.ReadFile(path.Join(dir, files[0].Name()))
+		Expect(err).NotTo(HaveOccurred())
+		letter := reportSinkDeadLetter{}
+		Expect(json.Unmarshal(data, &letter)).To(Succeed())
+		Expect(letter.URL).To(Equal("http://sink"))
+		Expect(letter.Error).To(Equal("no agent"))
+		received := ReportSinkPayload{}
+		Expect(json.Unmarshal(letter.Payload, &received)).To(Succeed())
+		Expect(received.KindName).To(Equal(types.KindNameNetReach))
+		Expect(received.TaskName).To(Equal("task"))
+		Expect(received.RoundNumber).To(Equal(2))
+		Expect(received.Summary.Status).To(Equal(crd.StatusHistoryRecordStatusFail))
+	})
+
+	It("restore the pending sinks after the controller restarts", func() {
+		trigger := types.KindNameNetReach + ".restored.2"
+		sinks := []crd.ReportSink{{URL: "http://sink"}}
+		s.savePendingSinks(log, trigger, roundSinks{record: payload.Summary, sinks: sinks})
+
+		s.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+		defer s.queue.ShutDown()
+		s.loadPendingSinks(log)
+		Expect(s.queue.Len()).To(Equal(1))
+		v, ok := popRoundSinks(trigger)
+		Expect(ok).To(BeTrue())
+		Expect(v.record.RoundNumber).To(Equal(2))
+		Expect(v.sinks).To(Equal(sinks))
+
+		s.removePendingSinks(log, trigger)
+		files, err := os.ReadDir(path.Join(s.reportDir, reportSinkPendingDir))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(BeEmpty())
+	})
+
+	It("skip the succeeded round for failedRoundOnly", func() {
+		var count int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&count, 1)
+		}))
+		defer server.Close()
+
+		sink := target(server.URL)
+		sink.failedRoundOnly = true
+		payload.Summary.Status = crd.StatusHistoryRecordStatusSucceed
+		s.pushRoundSummary(context.Background(), log, payload, []sinkTarget{sink})
+		Expect(atomic.LoadInt32(&count)).To(BeZero())
+	})
+
+	It("push the incomplete round for failedRoundOnly", func() {
+		var received ReportSinkPayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+		}))
+		defer server.Close()
+
+		sink := target(server.URL)
+		sink.failedRoundOnly = true
+		payload.Summary.Status = crd.StatusHistoryRecordStatusSucceed
+		payload.Incomplete = true
+		s.pushRoundSummary(context.Background(), log, payload, []sinkTarget{sink})
+		Expect(received.Incomplete).To(BeTrue())
+	})
+
+	It("merge the sinks of the configmap and the task", func() {
+		retries := int32(0)
+		failedOnly := true
+		types.ControllerConfig.Configmap.ReportSinks = []types.ReportSinkConfig{{URL: "http://a", FailedRoundOnly: true}}
+		defer func() { types.ControllerConfig.Configmap.ReportSinks = nil }()
+
+		sinks := resolveReportSinks(context.Background(), []crd.ReportSink{{URL: "http://b", FailedRoundOnly: &failedOnly, MaxRetries: &retries}})
+		Expect(sinks).To(HaveLen(2))
+		Expect(sinks[0].url).To(Equal("http://a"))
+		Expect(sinks[0].maxRetries).To(Equal(reportSinkDefaultMaxRetries))
+		Expect(sinks[0].timeout).To(Equal(reportSinkDefaultTimeoutInSecond * time.Second))
+		Expect(sinks[1].url).To(Equal("http://b"))
+		Expect(sinks[1].failedRoundOnly).To(BeTrue())
+		Expect(sinks[1].maxRetries).To(BeZero())
+	})
+})
@@ -0,0 +1,102 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
+	"github.com/kdoctor-io/kdoctor/pkg/utils"
+)
+
+// ParseReportFileName parses the file name format: fmt.Sprintf("%s_%s_round%d_%s_%s", kindName, taskName, roundNumber, nodeName, suffix)
+func ParseReportFileName(fileName string) (kindName, taskName string, roundNumber int, nodeName string, err error) {
+	v := strings.Split(fileName, "_")
+	if len(v) != 5 || !strings.HasPrefix(v[2], "round") {
+		return "", "", 0, "", fmt.Errorf("unrecognized report file %v", fileName)
+	}
+	roundNumber, err = strconv.Atoi(strings.TrimPrefix(v[2], "round"))
+	if err != nil {
+		return "", "", 0, "", fmt.Errorf("unrecognized round number of report file %v", fileName)
+	}
+	return v[0], v[1], roundNumber, v[3], nil
+}
+
+// reportEndTime returns the end time of the round in the agent report or the summary report, or else now
+func reportEndTime(data []byte) time.Time {
+	r := struct {
+		RoundEndTimeStamp *time.Time `json:"roundEndTimeStamp"`
+		EndTimeStamp      *time.Time `json:"EndTimeStamp"`
+	}{}
+	if e := json.Unmarshal(data, &r); e == nil {
+		if r.RoundEndTimeStamp != nil && !r.RoundEndTimeStamp.IsZero() {
+			return *r.RoundEndTimeStamp
+		}
+		if r.EndTimeStamp != nil && !r.EndTimeStamp.IsZero() {
+			return *r.EndTimeStamp
+		}
+	}
+	return time.Now()
+}
+
+// SaveReportToStore saves the report into the report store, and does nothing when the store is not initialized
+func SaveReportToStore(kindName, taskName string, roundNumber int, nodeName string, data []byte) error {
+	store := reportStore.GetReportStore()
+	if store == nil {
+		return nil
+	}
+	return store.Put(&reportStore.Record{
+		KindName:    kindName,
+		TaskName:    taskName,
+		RoundNumber: roundNumber,
+		NodeName:    nodeName,
+		Time:        reportEndTime(data),
+		Data:        data,
+	})
+}
+
+// saveReportFileToStore saves the local report file into the report store
+func (s *reportManager) saveReportFileToStore(logger *zap.Logger, fileName string) {
+	kindName, taskName, roundNumber, nodeName, e := ParseReportFileName(fileName)
+	if e != nil {
+		logger.Sugar().Debugf("ignore report file %v, error=%v", fileName, e)
+		return
+	}
+	data, e := os.ReadFile(path.Join(s.reportDir, fileName))
+	if e != nil {
+		logger.Sugar().Errorf("failed to read report file %v, error=%v", fileName, e)
+		return
+	}
+	if !json.Valid(data) {
+		logger.Sugar().Warnf("ignore report file %v which is not json", fileName)
+		return
+	}
+	if e := SaveReportToStore(kindName, taskName, roundNumber, nodeName, data); e != nil {
+		logger.Sugar().Errorf("failed to save report file %v to store, error=%v", fileName, e)
+	}
+}
+
+// importLocalReports saves the reports collected before the report store is enabled
+func (s *reportManager) importLocalReports() {
+	if reportStore.GetReportStore() == nil {
+		return
+	}
+	localFileList, e := utils.GetFileList(s.reportDir)
+	if e != nil {
+		s.logger.Sugar().Errorf("failed to get local report files underlay %v, error=%v ", s.reportDir, e)
+		return
+	}
+	for _, fileName := range localFileList {
+		s.saveReportFileToStore(s.logger, fileName)
+	}
+	s.logger.Sugar().Infof("imported %d local report files to report store", len(localFileList))
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportManager
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RegisterRoundTrace records the span of the task round in the controller, and the collection of the reports
+// for the trigger joins the trace of the round
+func RegisterRoundTrace(trigger string, spanContext trace.SpanContext) {
+	if globalReportManager == nil || !spanContext.IsValid() {
+		return
+	}
+	updatePendingRound(trigger, func(p *pendingRound) {
+		p.trace = spanContext
+	})
+}
+
+// roundTraceContext returns the context with the span of the task round registered for the trigger.
+// The span is kept for the retries of the trigger, until forgetRoundTrace is called
+func roundTraceContext(ctx context.Context, trigger string) context.Context {
+	var spanContext trace.SpanContext
+	updatePendingRound(trigger, func(p *pendingRound) {
+		spanContext = p.trace
+	})
+	if spanContext.IsValid() {
+		return trace.ContextWithSpanContext(ctx, spanContext)
+	}
+	return ctx
+}
+
+func forgetRoundTrace(trigger string) {
+	updatePendingRound(trigger, func(p *pendingRound) {
+		p.trace = trace.SpanContext{}
+	})
+}
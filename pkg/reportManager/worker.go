@@ -5,20 +5,28 @@ package reportManager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kdoctor-io/kdoctor/pkg/grpcManager"
 	k8sObjManager "github.com/kdoctor-io/kdoctor/pkg/k8ObjManager"
 	"github.com/kdoctor-io/kdoctor/pkg/scheduler"
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
 	"github.com/kdoctor-io/kdoctor/pkg/types"
 	"github.com/kdoctor-io/kdoctor/pkg/utils"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// errAgentReportsIncomplete is returned when the reports of some agents fail to be collected
+var errAgentReportsIncomplete = errors.New("failed to collect the reports")
+
 func GetMissRemoteReport(remoteFileList []string, localFileList []string) []string {
 	remoteMissFileList := []string{}
 
@@ -47,13 +55,18 @@ func GetMissRemoteReport(remoteFileList []string, localFileList []string) []stri
 	return remoteMissFileList
 }
 
-func (s *reportManager) syncReportFromOneAgent(ctx context.Context, logger *zap.Logger, client grpcManager.GrpcClientManager, localFileList []string, podName, address string) {
+// syncReportFromOneAgent copies the reports missing in the controller from the agent, and returns the last error
+func (s *reportManager) syncReportFromOneAgent(ctx context.Context, logger *zap.Logger, client grpcManager.GrpcClientManager, localFileList []string, podName, address string) (syncErr error) {
 	logger.Sugar().Debugf("sync report from agent %v with grpc address %v", podName, address)
 
+	_, span := tracing.Tracer().Start(ctx, "controller.report.sync", trace.WithAttributes(tracing.AttrPod.String(podName)))
+	defer func() { tracing.EndSpanWithError(span, syncErr) }()
+
 	remoteFilesList, e := client.GetFileList(ctx, address, types.ControllerConfig.DirPathAgentReport)
 	if e != nil {
+		syncErr = e
 		logger.Sugar().Errorf("%v", e)
-		return
+		return syncErr
 	}
 
 	logger.Sugar().Debugf("agent pod %v has reports: %v", podName, remoteFilesList)
@@ -75,12 +88,14 @@ func (s *reportManager) syncReportFromOneAgent(ctx context.Context, logger *zap.
 		localAbsPath := path.Join(types.ControllerConfig.DirPathControllerReport, localFileName)
 		// --
 		if e := client.SaveRemoteFileToLocal(ctx, address, remoteAbsPath, localAbsPath); e != nil {
+			syncErr = e
 			logger.Sugar().Errorf("failed to save remote file %v of pod %v to local file %v, error=%v", remoteAbsPath, podName, localAbsPath, e)
 		} else {
 			logger.Sugar().Infof("succeeded to save remote file %v of pod %v to local file %v", remoteAbsPath, podName, localAbsPath)
+			s.saveReportFileToStore(logger, localFileName)
 		}
 	}
-
+	return syncErr
 }
 
 func (s *reportManager) runControllerAggregateReportOnce(ctx context.Context, logger *zap.Logger, taskKind string, taskName string) error {
@@ -88,7 +103,7 @@ func (s *reportManager) runControllerAggregateReportOnce(ctx context.Context, lo
 	var err error
 	var podIP k8sObjManager.PodIps
 	// grpc client
-	grpcClient := grpcManager.NewGrpcClient(s.logger.Named("grpc"), true)
+	grpcClient := grpcManager.NewGrpcClient(s.logger.Named("grpc"), s.certReloader)
 
 	localFileList, e := utils.GetFileList(s.reportDir)
 	if e != nil {
@@ -105,6 +120,12 @@ func (s *reportManager) runControllerAggregateReportOnce(ctx context.Context, lo
 		task, err = s.netReachRuntimeDB.Get(taskName)
 	case types.KindNameNetdns:
 		task, err = s.netDNSRuntimeDB.Get(taskName)
+	case types.KindNameNetTcp:
+		task, err = s.netTcpRuntimeDB.Get(taskName)
+	case types.KindNameNetUdp:
+		task, err = s.netUdpRuntimeDB.Get(taskName)
+	case types.KindNameNetDelay:
+		task, err = s.netDelayRuntimeDB.Get(taskName)
 	}
 	if err != nil {
 		return err
@@ -124,10 +145,12 @@ func (s *reportManager) runControllerAggregateReportOnce(ctx context.Context, lo
 	}
 	logger.Sugar().Debugf("podIP : %v", podIP)
 
+	failedPods := []string{}
 	for podName, podIpInfo := range podIP {
 		// get pod ip
 		if len(podIpInfo) == 0 {
 			logger.Sugar().Errorf("failed to get agent %s ip ", podName)
+			failedPods = append(failedPods, podName)
 			continue
 		}
 		var podip string
@@ -138,6 +161,7 @@ func (s *reportManager) runControllerAggregateReportOnce(ctx context.Context, lo
 		}
 		if len(podip) == 0 {
 			logger.Sugar().Errorf("failed to get agent %s ip ", podName)
+			failedPods = append(failedPods, podName)
 			continue
 		}
 
@@ -148,16 +172,76 @@ func (s *reportManager) runControllerAggregateReportOnce(ctx context.Context, lo
 		} else {
 			address = fmt.Sprintf("%s:%d", podip, types.ControllerConfig.AgentGrpcListenPort)
 		}
-		s.syncReportFromOneAgent(ctx, logger, grpcClient, localFileList, podName, address)
+		if e := s.syncReportFromOneAgent(ctx, logger, grpcClient, localFileList, podName, address); e != nil {
+			failedPods = append(failedPods, podName)
+		}
+	}
+	if len(failedPods) > 0 {
+		sort.Strings(failedPods)
+		// retry
+		return fmt.Errorf("%w from agents %v", errAgentReportsIncomplete, failedPods)
 	}
 
 	return nil
 }
 
 // just one worker to sync all report and save to local disc of controller pod
-func (s *reportManager) syncHandler(ctx context.Context, trigger string) error {
+func (s *reportManager) syncHandler(ctx context.Context, trigger string) (err error) {
 	logger := s.logger.With(
 		zap.String("triggerSource", trigger),
 	)
-	return s.runControllerAggregateReportOnce(ctx, logger, strings.Split(trigger, ".")[0], strings.Split(trigger, ".")[1])
+	// trigger format: fmt.Sprintf("%s.%s.%d", kindName, taskName, roundNumber)
+	v := strings.Split(trigger, ".")
+
+	ctx, span := tracing.Tracer().Start(roundTraceContext(ctx, trigger), "controller.report.collect",
+		trace.WithAttributes(tracing.AttrKind.String(v[0]), tracing.AttrTask.String(v[1])))
+	defer func() {
+		tracing.EndSpanWithError(span, err)
+		if err == nil {
+			forgetRoundTrace(trigger)
+		}
+	}()
+
+	// the round is handled after the reports of all agents are collected, or with the reports collected
+	// when some agents still fail after the retries
+	incomplete := false
+	if err := s.runControllerAggregateReportOnce(ctx, logger, v[0], v[1]); err != nil {
+		if !errors.Is(err, errAgentReportsIncomplete) || s.queue.NumRequeues(trigger) < roundCollectMaxRetries {
+			return err
+		}
+		logger.Sugar().Warnf("handle the round with the incomplete reports after %v retries, error=%v", roundCollectMaxRetries, err)
+		span.SetAttributes(tracing.AttrRoundIncomplete.Bool(true))
+		incomplete = true
+	}
+	// notify the watchers of the kdoctorreport after the reports are collected
+	defer NotifyReportUpdate(v[0], v[1])
+
+	if len(v) <= 2 {
+		return nil
+	}
+	roundNumber, e := strconv.Atoi(v[2])
+	if e != nil {
+		logger.Sugar().Errorf("failed to parse round number from trigger %v, error=%v", trigger, e)
+		// ignore , no retry
+		return nil
+	}
+	span.SetAttributes(tracing.AttrRound.Int(roundNumber))
+
+	// the node-to-node matrix of NetDelay and the detect summary of Netdns are built from the reports of all agents
+	switch v[0] {
+	case types.KindNameNetDelay:
+		if err := s.generateNetDelayMatrix(logger, v[1], roundNumber); err != nil {
+			logger.Sugar().Errorf("failed to generate matrix for NetDelay %v round %v, error=%v", v[1], roundNumber, err)
+		}
+	case types.KindNameNetdns:
+		if err := s.generateNetDnsDetectSummary(logger, v[1], roundNumber); err != nil {
+			logger.Sugar().Errorf("failed to generate detect summary for Netdns %v round %v, error=%v", v[1], roundNumber, err)
+		}
+	}
+
+	// compare with the baseline before pushing, so the comparison is exported with the round
+	s.compareRoundWithBaseline(logger, trigger, v[0], v[1], roundNumber)
+	s.pushRoundToSinks(logger, trigger, v[0], v[1], roundNumber, incomplete)
+	s.exportRoundToObjectStorage(logger, v[0], v[1], roundNumber)
+	return nil
 }
@@ -0,0 +1,286 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportRender
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+)
+
+const roundResultSucceed = "succeed"
+const roundResultFail = "fail"
+
+// TaskReport is the rounds of a task to be rendered
+type TaskReport struct {
+	TaskType      string
+	TaskName      string
+	GeneratedTime time.Time
+	Rounds        []Round
+}
+
+// Round is the result of a round and the reports of the agents in it
+type Round struct {
+	RoundNumber  int64
+	RoundResult  string
+	FailedReason string
+	StartTime    time.Time
+	EndTime      time.Time
+	Nodes        []NodeResult
+}
+
+// NodeResult is the report of an agent in a round
+type NodeResult struct {
+	NodeName     string
+	PodName      string
+	RoundResult  string
+	FailedReason string
+	Targets      []TargetResult
+}
+
+// TargetResult is the result of the requests from an agent to a target
+type TargetResult struct {
+	Name          string
+	Address       string
+	Succeed       bool
+	SucceedRate   float64
+	RequestCounts int64
+	SuccessCounts int64
+	FailureReason string
+	Latencies     v1beta1.LatencyDistribution
+	Errors        map[string]int
+	// the reply codes of the dns requests
+	ReplyCodes map[string]int
+}
+
+// Count is the number of the occurrences of an error or a reply code
+type Count struct {
+	Name  string
+	Count int
+}
+
+// NewRound builds the round from its summary and the agent reports
+func NewRound(summary v1beta1.RoundSummary, reports []v1beta1.Report) Round {
+	round := Round{
+		RoundNumber: summary.RoundNumber,
+		RoundResult: summary.RoundResult,
+	}
+	if summary.FailedReason != nil {
+		round.FailedReason = *summary.FailedReason
+	}
+	if summary.StartTimeStamp != nil {
+		round.StartTime = summary.StartTimeStamp.Time
+	}
+	if summary.EndTimeStamp != nil {
+		round.EndTime = summary.EndTimeStamp.Time
+	}
+	for i := range reports {
+		round.Nodes = append(round.Nodes, NewNodeResult(&reports[i]))
+	}
+	sort.Slice(round.Nodes, func(i, j int) bool {
+		return round.Nodes[i].NodeName < round.Nodes[j].NodeName
+	})
+	return round
+}
+
+// NewNodeResult converts the agent report of any kind to the node result
+func NewNodeResult(report *v1beta1.Report) NodeResult {
+	node := NodeResult{
+		NodeName:    report.NodeName,
+		PodName:     report.PodName,
+		RoundResult: report.RoundResult,
+	}
+	if report.FailedReason != nil {
+		node.FailedReason = *report.FailedReason
+	}
+
+	switch {
+	case report.TaskNetReach != nil:
+		for _, d := range report.TaskNetReach.Detail {
+			node.Targets = append(node.Targets, httpTarget(d.TargetName, d.TargetUrl, d.Succeed, d.SucceedRate, d.FailureReason, d.Metrics))
+		}
+	case report.TaskAppHttpHealthy != nil:
+		for _, d := range report.TaskAppHttpHealthy.Detail {
+			node.Targets = append(node.Targets, httpTarget(d.TargetName, d.TargetUrl, d.Succeed, d.SucceedRate, d.FailureReason, d.Metrics))
+		}
+	case report.TaskNetDNS != nil:
+		for _, d := range report.TaskNetDNS.Detail {
+			node.Targets = append(node.Targets, TargetResult{
+				Name:          d.TargetName,
+				Address:       d.TargetServer,
+				Succeed:       d.Succeed,
+				SucceedRate:   d.SucceedRate,
+				RequestCounts: d.Metrics.RequestCounts,
+				SuccessCounts: d.Metrics.SuccessCounts,
+				FailureReason: stringValue(d.FailureReason),
+				Latencies:     d.Metrics.Latencies,
+				Errors:        d.Metrics.Errors,
+				ReplyCodes:    d.Metrics.ReplyCode,
+			})
+		}
+	case report.TaskNetTcp != nil:
+		for _, d := range report.TaskNetTcp.Detail {
+			node.Targets = append(node.Targets, tcpTarget(d.TargetName, d.TargetAddress, d.Succeed, d.SucceedRate, d.FailureReason, d.Metrics))
+		}
+	case report.TaskNetDelay != nil:
+		for _, d := range report.TaskNetDelay.Detail {
+			node.Targets = append(node.Targets, tcpTarget(d.TargetName, d.TargetAddress, d.Succeed, d.SucceedRate, d.FailureReason, d.Metrics))
+		}
+	case report.TaskNetUdp != nil:
+		for _, d := range report.TaskNetUdp.Detail {
+			node.Targets = append(node.Targets, TargetResult{
+				Name:          d.TargetName,
+				Address:       d.TargetAddress,
+				Succeed:       d.Succeed,
+				SucceedRate:   1 - d.LossPercentage/100,
+				RequestCounts: d.Metrics.SendCounts,
+				SuccessCounts: d.Metrics.ReceivedCounts,
+				FailureReason: stringValue(d.FailureReason),
+				Latencies:     d.Metrics.Latencies,
+				Errors:        d.Metrics.Errors,
+			})
+		}
+	}
+	return node
+}
+
+func httpTarget(name, address string, succeed bool, succeedRate float64, failureReason *string, m v1beta1.HttpMetrics) TargetResult {
+	return TargetResult{
+		Name:          name,
+		Address:       address,
+		Succeed:       succeed,
+		SucceedRate:   succeedRate,
+		RequestCounts: m.RequestCounts,
+		SuccessCounts: m.SuccessCounts,
+		FailureReason: stringValue(failureReason),
+		Latencies:     m.Latencies,
+		Errors:        m.Errors,
+	}
+}
+
+func tcpTarget(name, address string, succeed bool, succeedRate float64, failureReason *string, m v1beta1.TcpMetrics) TargetResult {
+	return TargetResult{
+		Name:          name,
+		Address:       address,
+		Succeed:       succeed,
+		SucceedRate:   succeedRate,
+		RequestCounts: m.RequestCounts,
+		SuccessCounts: m.SuccessCounts,
+		FailureReason: stringValue(failureReason),
+		Latencies:     m.ConnectLatencies,
+		Errors:        m.Errors,
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// SucceedRounds returns the number of the succeeded rounds
+func (t *TaskReport) SucceedRounds() int {
+	return t.countRounds(roundResultSucceed)
+}
+
+// FailedRounds returns the number of the failed rounds
+func (t *TaskReport) FailedRounds() int {
+	return t.countRounds(roundResultFail)
+}
+
+func (t *TaskReport) countRounds(result string) int {
+	n := 0
+	for _, r := range t.Rounds {
+		if r.RoundResult == result {
+			n++
+		}
+	}
+	return n
+}
+
+// Errors returns the errors of all targets in the rounds, in the descending order of the counts
+func (t *TaskReport) Errors() []Count {
+	return t.countBy(func(target *TargetResult) map[string]int { return target.Errors })
+}
+
+// ReplyCodes returns the reply codes of all dns targets in the rounds, in the descending order of the counts
+func (t *TaskReport) ReplyCodes() []Count {
+	return t.countBy(func(target *TargetResult) map[string]int { return target.ReplyCodes })
+}
+
+func (t *TaskReport) countBy(get func(target *TargetResult) map[string]int) []Count {
+	counts := map[string]int{}
+	for i := range t.Rounds {
+		for j := range t.Rounds[i].Nodes {
+			for k := range t.Rounds[i].Nodes[j].Targets {
+				for name, n := range get(&t.Rounds[i].Nodes[j].Targets[k]) {
+					counts[name] += n
+				}
+			}
+		}
+	}
+	return sortCounts(counts)
+}
+
+func sortCounts(counts map[string]int) []Count {
+	result := make([]Count, 0, len(counts))
+	for name, n := range counts {
+		result = append(result, Count{Name: name, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// WorstLatencies returns the largest percentiles of all targets in the round, which are drawn in the latency chart
+func (r *Round) WorstLatencies() v1beta1.LatencyDistribution {
+	worst := v1beta1.LatencyDistribution{}
+	for i := range r.Nodes {
+		for _, t := range r.Nodes[i].Targets {
+			worst.P50 = max32(worst.P50, t.Latencies.P50)
+			worst.P90 = max32(worst.P90, t.Latencies.P90)
+			worst.P95 = max32(worst.P95, t.Latencies.P95)
+			worst.P99 = max32(worst.P99, t.Latencies.P99)
+			worst.Max = max32(worst.Max, t.Latencies.Max)
+		}
+	}
+	return worst
+}
+
+// FailedNodes returns the nodes whose agents fail in the round
+func (r *Round) FailedNodes() []string {
+	nodes := []string{}
+	for i := range r.Nodes {
+		if r.Nodes[i].RoundResult != roundResultSucceed {
+			nodes = append(nodes, r.Nodes[i].NodeName)
+		}
+	}
+	return nodes
+}
+
+// Errors returns the errors of all targets in the round, in the descending order of the counts
+func (r *Round) Errors() []Count {
+	counts := map[string]int{}
+	for i := range r.Nodes {
+		for _, t := range r.Nodes[i].Targets {
+			for name, n := range t.Errors {
+				counts[name] += n
+			}
+		}
+	}
+	return sortCounts(counts)
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,252 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+// Package reportRender renders the rounds of a task as a human-readable HTML or Markdown document
+package reportRender
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "md"
+)
+
+// ContentType returns the content type of the format
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatHTML:
+		return "text/html; charset=utf-8", nil
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8", nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, it should be %s or %s", format, FormatHTML, FormatMarkdown)
+	}
+}
+
+// Render writes the report in the format
+func Render(w io.Writer, report *TaskReport, format string) error {
+	switch format {
+	case FormatHTML:
+		return htmlTemplate.Execute(w, report)
+	case FormatMarkdown:
+		return markdownTemplate.Execute(w, report)
+	default:
+		_, err := ContentType(format)
+		return err
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatDuration(start, end time.Time) string {
+	if start.IsZero() || end.IsZero() {
+		return "-"
+	}
+	return end.Sub(start).Round(time.Second).String()
+}
+
+func or(s, fallback string) string {
+	if len(s) == 0 {
+		return fallback
+	}
+	return s
+}
+
+// escapeCell makes the text safe in a cell of the markdown table
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r", "")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func resultMark(result string) string {
+	switch result {
+	case roundResultSucceed:
+		return "PASS"
+	case roundResultFail:
+		return "FAIL"
+	default:
+		return strings.ToUpper(or(result, "unknown"))
+	}
+}
+
+var commonFuncs = map[string]interface{}{
+	"time":     formatTime,
+	"duration": formatDuration,
+	"or":       or,
+	"mark":     resultMark,
+	"join":     func(s []string) string { return strings.Join(s, ", ") },
+}
+
+var markdownTemplate = texttemplate.Must(texttemplate.New("md").Funcs(commonFuncs).Funcs(texttemplate.FuncMap{
+	"cell": escapeCell,
+}).Parse(markdownText))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Funcs(commonFuncs).Funcs(htmltemplate.FuncMap{
+	// the svg only consists of the numbers and the escaped text
+	"timeline": func(rounds []Round) htmltemplate.HTML { return htmltemplate.HTML(TimelineSVG(rounds)) },
+	"latency":  func(rounds []Round) htmltemplate.HTML { return htmltemplate.HTML(LatencySVG(rounds)) },
+}).Parse(htmlText))
+
+const markdownText = `# {{ .TaskType }} {{ .TaskName }}
+
+Generated at {{ time .GeneratedTime }}
+
+## Summary
+
+| rounds | succeed | fail |
+|--------|---------|------|
+| {{ len .Rounds }} | {{ .SucceedRounds }} | {{ .FailedRounds }} |
+
+## Timeline
+
+| round | result | start | duration | agents | failed agents | failure reason |
+|-------|--------|-------|----------|--------|---------------|----------------|
+{{- range .Rounds }}
+| {{ .RoundNumber }} | {{ mark .RoundResult }} | {{ time .StartTime }} | {{ duration .StartTime .EndTime }} | {{ len .Nodes }} | {{ cell (or (join .FailedNodes) "-") }} | {{ cell (or .FailedReason "-") }} |
+{{- end }}
+
+## Latency
+
+The largest percentiles of all targets in each round, in milliseconds
+
+| round | P50 | P90 | P95 | P99 | max |
+|-------|-----|-----|-----|-----|-----|
+{{- range .Rounds }}{{ $l := .WorstLatencies }}
+| {{ .RoundNumber }} | {{ printf "%.2f" $l.P50 }} | {{ printf "%.2f" $l.P90 }} | {{ printf "%.2f" $l.P95 }} | {{ printf "%.2f" $l.P99 }} | {{ printf "%.2f" $l.Max }} |
+{{- end }}
+{{ with .Errors }}
+## Errors
+
+| error | count |
+|-------|-------|
+{{- range . }}
+| {{ cell .Name }} | {{ .Count }} |
+{{- end }}
+{{ end }}{{ with .ReplyCodes }}
+## DNS Reply Codes
+
+| reply code | count |
+|------------|-------|
+{{- range . }}
+| {{ cell .Name }} | {{ .Count }} |
+{{- end }}
+{{ end }}
+## Rounds
+{{ range .Rounds }}
+### Round {{ .RoundNumber }}: {{ mark .RoundResult }}
+
+{{ time .StartTime }} - {{ time .EndTime }}
+{{ if .FailedReason }}
+Failure reason: {{ .FailedReason }}
+{{ end }}
+| node | pod | result | target | address | success rate | requests | P50 | P90 | P99 | failure reason |
+|------|-----|--------|--------|---------|--------------|----------|-----|-----|-----|----------------|
+{{- range .Nodes }}{{ $node := . }}
+{{- if .Targets }}{{ range .Targets }}
+| {{ cell $node.NodeName }} | {{ cell $node.PodName }} | {{ mark $node.RoundResult }} | {{ cell .Name }} | {{ cell .Address }} | {{ printf "%.2f" .SucceedRate }} | {{ .SuccessCounts }}/{{ .RequestCounts }} | {{ printf "%.2f" .Latencies.P50 }} | {{ printf "%.2f" .Latencies.P90 }} | {{ printf "%.2f" .Latencies.P99 }} | {{ cell (or .FailureReason "-") }} |
+{{- end }}{{ else }}
+| {{ cell .NodeName }} | {{ cell .PodName }} | {{ mark .RoundResult }} | - | - | - | - | - | - | - | {{ cell (or .FailedReason "-") }} |
+{{- end }}
+{{- end }}
+{{ with .Errors }}
+| error | count |
+|-------|-------|
+{{- range . }}
+| {{ cell .Name }} | {{ .Count }} |
+{{- end }}
+{{ end }}{{ end }}`
+
+const htmlText = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .TaskType }} {{ .TaskName }}</title>
+<style>
+body { font-family: sans-serif; margin: 24px; color: #212121; }
+table { border-collapse: collapse; margin: 8px 0 16px; }
+th, td { border: 1px solid #bdbdbd; padding: 4px 8px; text-align: left; font-size: 13px; }
+th { background: #eeeeee; }
+.PASS { color: #2e7d32; font-weight: bold; }
+.FAIL { color: #c62828; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{ .TaskType }} {{ .TaskName }}</h1>
+<p>Generated at {{ time .GeneratedTime }}</p>
+<h2>Summary</h2>
+<table>
+<tr><th>rounds</th><th>succeed</th><th>fail</th></tr>
+<tr><td>{{ len .Rounds }}</td><td>{{ .SucceedRounds }}</td><td>{{ .FailedRounds }}</td></tr>
+</table>
+
+<h2>Timeline</h2>
+{{ timeline .Rounds }}
+<table>
+<tr><th>round</th><th>result</th><th>start</th><th>duration</th><th>agents</th><th>failed agents</th><th>failure reason</th></tr>
+{{- range .Rounds }}
+<tr><td><a href="#round-{{ .RoundNumber }}">{{ .RoundNumber }}</a></td><td class="{{ mark .RoundResult }}">{{ mark .RoundResult }}</td><td>{{ time .StartTime }}</td><td>{{ duration .StartTime .EndTime }}</td><td>{{ len .Nodes }}</td><td>{{ or (join .FailedNodes) "-" }}</td><td>{{ or .FailedReason "-" }}</td></tr>
+{{- end }}
+</table>
+
+<h2>Latency</h2>
+<p>The largest percentiles of all targets in each round, in milliseconds</p>
+{{ latency .Rounds }}
+{{ with .Errors }}
+<h2>Errors</h2>
+<table>
+<tr><th>error</th><th>count</th></tr>
+{{- range . }}
+<tr><td>{{ .Name }}</td><td>{{ .Count }}</td></tr>
+{{- end }}
+</table>
+{{ end }}{{ with .ReplyCodes }}
+<h2>DNS Reply Codes</h2>
+<table>
+<tr><th>reply code</th><th>count</th></tr>
+{{- range . }}
+<tr><td>{{ .Name }}</td><td>{{ .Count }}</td></tr>
+{{- end }}
+</table>
+{{ end }}
+<h2>Rounds</h2>
+{{ range .Rounds }}
+<h3 id="round-{{ .RoundNumber }}">Round {{ .RoundNumber }}: <span class="{{ mark .RoundResult }}">{{ mark .RoundResult }}</span></h3>
+<p>{{ time .StartTime }} - {{ time .EndTime }}</p>
+{{- if .FailedReason }}
+<p>Failure reason: {{ .FailedReason }}</p>
+{{- end }}
+<table>
+<tr><th>node</th><th>pod</th><th>result</th><th>target</th><th>address</th><th>success rate</th><th>requests</th><th>P50</th><th>P90</th><th>P99</th><th>failure reason</th></tr>
+{{- range .Nodes }}{{ $node := . }}
+{{- if .Targets }}{{ range .Targets }}
+<tr><td>{{ $node.NodeName }}</td><td>{{ $node.PodName }}</td><td class="{{ mark $node.RoundResult }}">{{ mark $node.RoundResult }}</td><td>{{ .Name }}</td><td>{{ .Address }}</td><td>{{ printf "%.2f" .SucceedRate }}</td><td>{{ .SuccessCounts }}/{{ .RequestCounts }}</td><td>{{ printf "%.2f" .Latencies.P50 }}</td><td>{{ printf "%.2f" .Latencies.P90 }}</td><td>{{ printf "%.2f" .Latencies.P99 }}</td><td>{{ or .FailureReason "-" }}</td></tr>
+{{- end }}{{ else }}
+<tr><td>{{ .NodeName }}</td><td>{{ .PodName }}</td><td class="{{ mark .RoundResult }}">{{ mark .RoundResult }}</td><td colspan="7">-</td><td>{{ or .FailedReason "-" }}</td></tr>
+{{- end }}
+{{- end }}
+</table>
+{{- with .Errors }}
+<table>
+<tr><th>error</th><th>count</th></tr>
+{{- range . }}
+<tr><td>{{ .Name }}</td><td>{{ .Count }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{ end }}
+</body>
+</html>
+`
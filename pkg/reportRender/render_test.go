@@ -0,0 +1,119 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+package reportRender_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kdoctor-io/kdoctor/pkg/k8s/apis/system/v1beta1"
+	"github.com/kdoctor-io/kdoctor/pkg/reportRender"
+)
+
+var _ = Describe("test report render", Label("report render"), func() {
+	start := time.Date(2023, 9, 1, 8, 0, 0, 0, time.UTC)
+	failedReason := "timeout | retry"
+
+	dnsReport := func(node, result string, p99 float32, errors map[string]int) v1beta1.Report {
+		return v1beta1.Report{
+			NodeName:    node,
+			PodName:     "agent-" + node,
+			RoundResult: result,
+			TaskNetDNS: &v1beta1.NetDNSTask{
+				Detail: []v1beta1.NetDNSTaskDetail{{
+					TargetName:   "typeA_kube-dns",
+					TargetServer: "10.96.0.10:53",
+					Succeed:      result == "succeed",
+					SucceedRate:  0.9,
+					Metrics: v1beta1.DNSMetrics{
+						RequestCounts: 10,
+						SuccessCounts: 9,
+						Errors:        errors,
+						ReplyCode:     map[string]int{"NOERROR": 9, "SERVFAIL": 1},
+						Latencies:     v1beta1.LatencyDistribution{P50: 1, P90: 2, P99: p99, Max: p99 + 1},
+					},
+				}},
+			},
+		}
+	}
+
+	newTaskReport := func() *reportRender.TaskReport {
+		end := metav1.NewTime(start.Add(time.Minute))
+		begin := metav1.NewTime(start)
+		return &reportRender.TaskReport{
+			TaskType:      v1beta1.NetDNSTaskName,
+			TaskName:      "nightly",
+			GeneratedTime: start,
+			Rounds: []reportRender.Round{
+				reportRender.NewRound(v1beta1.RoundSummary{RoundNumber: 1, RoundResult: "succeed", StartTimeStamp: &begin, EndTimeStamp: &end},
+					[]v1beta1.Report{dnsReport("worker2", "succeed", 3, nil), dnsReport("worker1", "succeed", 5, nil)}),
+				reportRender.NewRound(v1beta1.RoundSummary{RoundNumber: 2, RoundResult: "fail", FailedReason: &failedReason, StartTimeStamp: &begin, EndTimeStamp: &end},
+					[]v1beta1.Report{dnsReport("worker1", "fail", 8, map[string]int{"i/o timeout": 2, "<refused>": 1})}),
+			},
+		}
+	}
+
+	It("convert the reports", func() {
+		t := newTaskReport()
+		Expect(t.SucceedRounds()).To(Equal(1))
+		Expect(t.FailedRounds()).To(Equal(1))
+		Expect(t.Rounds[0].Nodes[0].NodeName).To(Equal("worker1"))
+		Expect(t.Rounds[0].WorstLatencies().P99).To(BeEquivalentTo(5))
+		Expect(t.Rounds[1].FailedNodes()).To(Equal([]string{"worker1"}))
+		Expect(t.Errors()).To(Equal([]reportRender.Count{{Name: "i/o timeout", Count: 2}, {Name: "<refused>", Count: 1}}))
+		Expect(t.ReplyCodes()).To(Equal([]reportRender.Count{{Name: "NOERROR", Count: 27}, {Name: "SERVFAIL", Count: 3}}))
+
+		node := reportRender.NewNodeResult(&v1beta1.Report{
+			NodeName: "worker1",
+			TaskNetTcp: &v1beta1.NetTcpTask{Detail: []v1beta1.NetTcpTaskDetail{{
+				TargetName: "svc",
+				Metrics:    v1beta1.TcpMetrics{ConnectLatencies: v1beta1.LatencyDistribution{P50: 4}},
+			}}},
+		})
+		Expect(node.Targets).To(HaveLen(1))
+		Expect(node.Targets[0].Latencies.P50).To(BeEquivalentTo(4))
+	})
+
+	It("render markdown", func() {
+		buf := &bytes.Buffer{}
+		Expect(reportRender.Render(buf, newTaskReport(), reportRender.FormatMarkdown)).To(Succeed())
+		md := buf.String()
+		Expect(md).To(HavePrefix("# " + v1beta1.NetDNSTaskName + " nightly"))
+		Expect(md).To(ContainSubstring("| 2 | FAIL | 2023-09-01T08:00:00Z | 1m0s | 1 | worker1 | timeout \\| retry |"))
+		Expect(md).To(ContainSubstring("| i/o timeout | 2 |"))
+		Expect(md).To(ContainSubstring("## DNS Reply Codes"))
+		Expect(md).To(ContainSubstring("| worker1 | agent-worker1 | FAIL | typeA_kube-dns | 10.96.0.10:53 | 0.90 | 9/10 | 1.00 | 2.00 | 8.00 | - |"))
+	})
+
+	It("render html", func() {
+		buf := &bytes.Buffer{}
+		Expect(reportRender.Render(buf, newTaskReport(), reportRender.FormatHTML)).To(Succeed())
+		html := buf.String()
+		Expect(html).To(ContainSubstring("<svg"))
+		Expect(html).To(ContainSubstring("<polyline"))
+		Expect(html).To(ContainSubstring("&lt;refused&gt;"))
+		Expect(html).NotTo(ContainSubstring("<refused>"))
+		Expect(strings.Count(html, `<h3 id="round-`)).To(Equal(2))
+	})
+
+	It("draw the svg", func() {
+		t := newTaskReport()
+		for _, svg := range []string{reportRender.TimelineSVG(t.Rounds), reportRender.LatencySVG(t.Rounds)} {
+			Expect(xml.Unmarshal([]byte(svg), new(interface{}))).To(Succeed())
+		}
+		Expect(reportRender.LatencySVG(nil)).To(BeEmpty())
+		Expect(reportRender.LatencySVG([]reportRender.Round{{RoundNumber: 1}})).To(BeEmpty())
+	})
+
+	It("reject unknown format", func() {
+		_, err := reportRender.ContentType("pdf")
+		Expect(err).To(HaveOccurred())
+		Expect(reportRender.Render(&bytes.Buffer{}, newTaskReport(), "pdf")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,120 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportRender
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	svgWidth         = 720
+	chartHeight      = 240
+	chartPadding     = 40
+	timelineHeight   = 48
+	timelineMaxBlock = 24
+)
+
+var resultColors = map[string]string{
+	roundResultSucceed: "#2e7d32",
+	roundResultFail:    "#c62828",
+}
+
+const unknownResultColor = "#9e9e9e"
+
+// latency lines drawn in the chart
+var latencySeries = []struct {
+	name  string
+	color string
+	value func(r *Round) float32
+}{
+	{"P50", "#1565c0", func(r *Round) float32 { return r.WorstLatencies().P50 }},
+	{"P90", "#ef6c00", func(r *Round) float32 { return r.WorstLatencies().P90 }},
+	{"P99", "#6a1b9a", func(r *Round) float32 { return r.WorstLatencies().P99 }},
+}
+
+// TimelineSVG draws a block for each round, colored by the round result
+func TimelineSVG(rounds []Round) string {
+	if len(rounds) == 0 {
+		return ""
+	}
+	block := float64(svgWidth) / float64(len(rounds))
+	if block > timelineMaxBlock {
+		block = timelineMaxBlock
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img" aria-label="round timeline">`, svgWidth, timelineHeight)
+	for i, r := range rounds {
+		color, ok := resultColors[r.RoundResult]
+		if !ok {
+			color = unknownResultColor
+		}
+		fmt.Fprintf(&b, `<rect x="%.1f" y="4" width="%.1f" height="24" fill="%s"><title>round %d: %s</title></rect>`,
+			float64(i)*block, block*0.9, color, r.RoundNumber, html.EscapeString(r.RoundResult))
+	}
+	fmt.Fprintf(&b, `<text x="0" y="44" font-size="11">round %d</text>`, rounds[0].RoundNumber)
+	if len(rounds) > 1 {
+		fmt.Fprintf(&b, `<text x="%.1f" y="44" font-size="11" text-anchor="end">round %d</text>`, float64(len(rounds))*block, rounds[len(rounds)-1].RoundNumber)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// LatencySVG draws the percentiles of the latency in each round, which are the largest ones of all targets
+func LatencySVG(rounds []Round) string {
+	if len(rounds) == 0 {
+		return ""
+	}
+	var top float32
+	for i := range rounds {
+		for _, s := range latencySeries {
+			top = max32(top, s.value(&rounds[i]))
+		}
+	}
+	if top == 0 {
+		return ""
+	}
+
+	plotWidth := float64(svgWidth - 2*chartPadding)
+	plotHeight := float64(chartHeight - 2*chartPadding)
+	x := func(i int) float64 {
+		if len(rounds) == 1 {
+			return chartPadding + plotWidth/2
+		}
+		return chartPadding + plotWidth*float64(i)/float64(len(rounds)-1)
+	}
+	y := func(v float32) float64 {
+		return chartPadding + plotHeight*(1-float64(v)/float64(top))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img" aria-label="latency percentiles">`, svgWidth, chartHeight)
+	// axes
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#616161"/>`, chartPadding, chartPadding, chartPadding, chartHeight-chartPadding)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#616161"/>`, chartPadding, chartHeight-chartPadding, svgWidth-chartPadding, chartHeight-chartPadding)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="11" text-anchor="end">%.1fms</text>`, chartPadding-4, chartPadding+4, top)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="11" text-anchor="end">0</text>`, chartPadding-4, chartHeight-chartPadding)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" font-size="11" text-anchor="middle">round %d</text>`, x(0), chartHeight-chartPadding+16, rounds[0].RoundNumber)
+	if len(rounds) > 1 {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" font-size="11" text-anchor="middle">round %d</text>`, x(len(rounds)-1), chartHeight-chartPadding+16, rounds[len(rounds)-1].RoundNumber)
+	}
+
+	for n, s := range latencySeries {
+		points := make([]string, 0, len(rounds))
+		for i := range rounds {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), y(s.value(&rounds[i]))))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`, s.color, strings.Join(points, " "))
+		for i := range rounds {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>round %d %s %.2fms</title></circle>`,
+				x(i), y(s.value(&rounds[i])), s.color, rounds[i].RoundNumber, s.name, s.value(&rounds[i]))
+		}
+		// legend
+		lx := chartPadding + n*80
+		fmt.Fprintf(&b, `<rect x="%d" y="12" width="12" height="12" fill="%s"/><text x="%d" y="22" font-size="11">%s</text>`, lx, s.color, lx+16, s.name)
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
@@ -0,0 +1,362 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportStore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+// Record is a report of a round, from an agent, or generated by the controller
+type Record struct {
+	KindName    string `json:"kindName"`
+	TaskName    string `json:"taskName"`
+	RoundNumber int    `json:"roundNumber"`
+	// the node name of the agent, or "summary" and the node name of the aggregated report for the controller
+	NodeName string `json:"nodeName"`
+	// the end time of the round, which is used by the query of the time range and the retention by age
+	Time time.Time `json:"time"`
+	// the report in json
+	Data json.RawMessage `json:"data"`
+}
+
+// Query selects the records of a kind, and the zero value of the other fields matches all
+type Query struct {
+	KindName string
+	TaskName string
+	// the inclusive range of the round number
+	StartRound int
+	EndRound   int
+	NodeName   string
+	// the inclusive range of the record time
+	StartTime time.Time
+	EndTime   time.Time
+	// the maximum number of the returned records
+	Limit int
+}
+
+type ReportStore interface {
+	// Put saves the record, and replaces the one with the same kind, task, round and node
+	Put(record *Record) error
+	// Query returns the records in the order of the task name, the round number and the node name
+	Query(q Query) ([]Record, error)
+	// LatestRound returns the biggest round number of the task, or 0 when the task has no record
+	LatestRound(kindName, taskName string) (int, error)
+	Close() error
+}
+
+type Config struct {
+	// the path of the database file
+	Path string
+	// the records older than MaxAge are removed, 0 for no limit
+	MaxAge time.Duration
+	// the oldest records are removed when the total size of the records exceeds it, 0 for no limit
+	MaxSizeInMB int
+	// the interval to remove the records by age
+	CleanInterval time.Duration
+}
+
+var (
+	bucketReports = []byte("reports")
+	bucketTime    = []byte("time")
+	bucketMeta    = []byte("meta")
+	keySize       = []byte("size")
+)
+
+type reportStore struct {
+	logger *zap.Logger
+	config Config
+	db     *bolt.DB
+	stopCh chan struct{}
+}
+
+var _ ReportStore = &reportStore{}
+
+func NewReportStore(logger *zap.Logger, config Config) (ReportStore, error) {
+	if logger == nil || len(config.Path) == 0 {
+		return nil, errors.New("bad request")
+	}
+
+	if e := os.MkdirAll(path.Dir(config.Path), os.ModePerm); e != nil {
+		return nil, fmt.Errorf("failed to create directory of %v, error=%v", config.Path, e)
+	}
+	db, e := bolt.Open(config.Path, 0600, &bolt.Options{Timeout: 10 * time.Second})
+	if e != nil {
+		return nil, fmt.Errorf("failed to open report store %v, error=%v", config.Path, e)
+	}
+	e = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketReports, bucketTime, bucketMeta} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if e != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to init report store %v, error=%v", config.Path, e)
+	}
+
+	s := &reportStore{
+		logger: logger,
+		config: config,
+		db:     db,
+		stopCh: make(chan struct{}),
+	}
+	if config.MaxAge > 0 && config.CleanInterval > 0 {
+		s.runCleanerByAge()
+	}
+	return s, nil
+}
+
+// the key of the record is "kind \x00 task \x00 round node", where the round is fixed in 8 bytes for the order
+func recordKeyPrefix(kindName, taskName string) []byte {
+	return []byte(kindName + "\x00" + taskName + "\x00")
+}
+
+func recordKey(kindName, taskName string, roundNumber int, nodeName string) []byte {
+	key := recordKeyPrefix(kindName, taskName)
+	key = binary.BigEndian.AppendUint64(key, uint64(roundNumber))
+	return append(key, nodeName...)
+}
+
+// the key of the time index is "unix nano" followed by the key of the record
+func timeKey(t time.Time, key []byte) []byte {
+	return append(binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano())), key...)
+}
+
+func getSize(tx *bolt.Tx) uint64 {
+	v := tx.Bucket(bucketMeta).Get(keySize)
+	if len(v) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(v)
+}
+
+func putSize(tx *bolt.Tx, size uint64) error {
+	return tx.Bucket(bucketMeta).Put(keySize, binary.BigEndian.AppendUint64(nil, size))
+}
+
+// deleteRecord removes the record and its time index, and returns its size
+func deleteRecord(tx *bolt.Tx, key []byte) (uint64, error) {
+	value := tx.Bucket(bucketReports).Get(key)
+	if value == nil {
+		return 0, nil
+	}
+	size := uint64(len(value))
+	old := Record{}
+	if e := json.Unmarshal(value, &old); e == nil {
+		if e := tx.Bucket(bucketTime).Delete(timeKey(old.Time, key)); e != nil {
+			return 0, e
+		}
+	}
+	return size, tx.Bucket(bucketReports).Delete(key)
+}
+
+func (s *reportStore) Put(record *Record) error {
+	if len(record.KindName) == 0 || len(record.TaskName) == 0 || record.RoundNumber < 0 {
+		return fmt.Errorf("invalid record of kind %q task %q round %d", record.KindName, record.TaskName, record.RoundNumber)
+	}
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	value, e := json.Marshal(record)
+	if e != nil {
+		return fmt.Errorf("failed to marshal record, error=%v", e)
+	}
+	key := recordKey(record.KindName, record.TaskName, record.RoundNumber, record.NodeName)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		size := getSize(tx)
+		oldSize, err := deleteRecord(tx, key)
+		if err != nil {
+			return err
+		}
+		size -= oldSize
+		if err := tx.Bucket(bucketReports).Put(key, value); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketTime).Put(timeKey(record.Time, key), nil); err != nil {
+			return err
+		}
+		size += uint64(len(value))
+
+		// remove the oldest records until the size is within the limit
+		if s.config.MaxSizeInMB > 0 {
+			maxSize := uint64(s.config.MaxSizeInMB) * 1024 * 1024
+			c := tx.Bucket(bucketTime).Cursor()
+			for k, _ := c.First(); k != nil && size > maxSize; k, _ = c.First() {
+				oldKey := append([]byte{}, k[8:]...)
+				if bytes.Equal(oldKey, key) {
+					break
+				}
+				n, err := deleteRecord(tx, oldKey)
+				if err != nil {
+					return err
+				}
+				// the index without the record
+				if n == 0 {
+					if err := c.Delete(); err != nil {
+						return err
+					}
+				}
+				size -= n
+				s.logger.Sugar().Infof("remove report %q which exceeds the size limit", oldKey)
+			}
+		}
+		return putSize(tx, size)
+	})
+}
+
+func (s *reportStore) Query(q Query) ([]Record, error) {
+	if len(q.KindName) == 0 {
+		return nil, errors.New("kind name is required")
+	}
+
+	var prefix, start []byte
+	if len(q.TaskName) > 0 {
+		prefix = recordKeyPrefix(q.KindName, q.TaskName)
+		start = binary.BigEndian.AppendUint64(append([]byte{}, prefix...), uint64(q.StartRound))
+	} else {
+		prefix = []byte(q.KindName + "\x00")
+		start = prefix
+	}
+
+	result := []Record{}
+	e := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketReports).Cursor()
+		for k, v := c.Seek(start); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			record := Record{}
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("failed to unmarshal record %q, error=%v", k, err)
+			}
+			if q.EndRound > 0 && record.RoundNumber > q.EndRound {
+				if len(q.TaskName) > 0 {
+					break
+				}
+				continue
+			}
+			if record.RoundNumber < q.StartRound {
+				continue
+			}
+			if len(q.NodeName) > 0 && record.NodeName != q.NodeName {
+				continue
+			}
+			if !q.StartTime.IsZero() && record.Time.Before(q.StartTime) {
+				continue
+			}
+			if !q.EndTime.IsZero() && record.Time.After(q.EndTime) {
+				continue
+			}
+			result = append(result, record)
+			if q.Limit > 0 && len(result) >= q.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	if e != nil {
+		return nil, e
+	}
+	return result, nil
+}
+
+func (s *reportStore) LatestRound(kindName, taskName string) (int, error) {
+	prefix := recordKeyPrefix(kindName, taskName)
+	roundNumber := 0
+	e := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketReports).Cursor()
+		// seek the first key after the prefix, and step back to the last key of the task
+		end := append(append([]byte{}, prefix[:len(prefix)-1]...), 0x01)
+		k, _ := c.Seek(end)
+		if k == nil {
+			k, _ = c.Last()
+		} else {
+			k, _ = c.Prev()
+		}
+		if k != nil && bytes.HasPrefix(k, prefix) && len(k) >= len(prefix)+8 {
+			roundNumber = int(binary.BigEndian.Uint64(k[len(prefix) : len(prefix)+8]))
+		}
+		return nil
+	})
+	return roundNumber, e
+}
+
+func (s *reportStore) Close() error {
+	close(s.stopCh)
+	return s.db.Close()
+}
+
+func (s *reportStore) cleanByAgeOnce() {
+	deadline := time.Now().Add(-s.config.MaxAge)
+	e := s.db.Update(func(tx *bolt.Tx) error {
+		size := getSize(tx)
+		c := tx.Bucket(bucketTime).Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.First() {
+			if int64(binary.BigEndian.Uint64(k[:8])) >= deadline.UnixNano() {
+				break
+			}
+			key := append([]byte{}, k[8:]...)
+			n, err := deleteRecord(tx, key)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+			size -= n
+			s.logger.Sugar().Infof("remove report %q who reach age", key)
+		}
+		return putSize(tx, size)
+	})
+	if e != nil {
+		s.logger.Sugar().Errorf("failed to remove aged reports, error=%v", e)
+	}
+}
+
+// remove records by age
+func (s *reportStore) runCleanerByAge() {
+	s.logger.Sugar().Infof("start report store cleaner at interval %v", s.config.CleanInterval.String())
+	go func() {
+		for {
+			s.cleanByAgeOnce()
+			select {
+			case <-s.stopCh:
+				return
+			case <-time.After(s.config.CleanInterval):
+			}
+		}
+	}()
+}
+
+var globalReportStore ReportStore
+
+// InitReportStore opens the report store shared by the reportManager and the apiserver in the controller
+func InitReportStore(logger *zap.Logger, config Config) error {
+	if globalReportStore != nil {
+		return nil
+	}
+	s, e := NewReportStore(logger, config)
+	if e != nil {
+		return e
+	}
+	globalReportStore = s
+	return nil
+}
+
+// GetReportStore returns nil when the report store is not initialized
+func GetReportStore() ReportStore {
+	return globalReportStore
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package reportStore_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/kdoctor-io/kdoctor/pkg/logger"
+	"github.com/kdoctor-io/kdoctor/pkg/reportStore"
+)
+
+var _ = Describe("test reportStore", Label("reportStore"), func() {
+
+	var dbPath string
+	log := logger.NewStdoutLogger("debug", "test")
+
+	BeforeEach(func() {
+		dbPath = path.Join(fmt.Sprintf("/tmp/_RS_%d", time.Now().Nanosecond()), "reports.db")
+	})
+
+	record := func(task string, round int, node string, t time.Time, data string) *reportStore.Record {
+		return &reportStore.Record{
+			KindName:    "NetReach",
+			TaskName:    task,
+			RoundNumber: round,
+			NodeName:    node,
+			Time:        t,
+			Data:        json.RawMessage(data),
+		}
+	}
+
+	It("put and query", func() {
+		s, e := reportStore.NewReportStore(log, reportStore.Config{Path: dbPath})
+		Expect(e).NotTo(HaveOccurred())
+		defer s.Close()
+
+		now := time.Now()
+		for round := 1; round <= 12; round++ {
+			for _, node := range []string{"node1", "node2"} {
+				t := now.Add(time.Duration(round) * time.Minute)
+				Expect(s.Put(record("task1", round, node, t, `{"round":1}`))).To(Succeed())
+			}
+		}
+		Expect(s.Put(record("task2", 3, "node1", now, `{}`))).To(Succeed())
+		// replace the record with the same key
+		Expect(s.Put(record("task1", 2, "node1", now.Add(2*time.Minute), `{"round":2}`))).To(Succeed())
+
+		records, e := s.Query(reportStore.Query{KindName: "NetReach"})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(25))
+
+		// the round 10 is after the round 9 in the order
+		records, e = s.Query(reportStore.Query{KindName: "NetReach", TaskName: "task1", StartRound: 9, EndRound: 10, NodeName: "node1"})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+		Expect(records[0].RoundNumber).To(Equal(9))
+		Expect(records[1].RoundNumber).To(Equal(10))
+
+		records, e = s.Query(reportStore.Query{KindName: "NetReach", TaskName: "task1", StartRound: 2, EndRound: 2})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+		Expect(string(records[0].Data)).To(Equal(`{"round":2}`))
+
+		records, e = s.Query(reportStore.Query{KindName: "NetReach", TaskName: "task1", StartTime: now.Add(11 * time.Minute)})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(4))
+
+		records, e = s.Query(reportStore.Query{KindName: "NetReach", TaskName: "task1", Limit: 3})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(3))
+
+		_, e = s.Query(reportStore.Query{})
+		Expect(e).To(HaveOccurred())
+
+		Expect(s.LatestRound("NetReach", "task1")).To(Equal(12))
+		Expect(s.LatestRound("NetReach", "task2")).To(Equal(3))
+		Expect(s.LatestRound("NetReach", "task")).To(Equal(0))
+		Expect(s.LatestRound("NetDns", "task1")).To(Equal(0))
+	})
+
+	It("keep the history after reopen", func() {
+		s, e := reportStore.NewReportStore(log, reportStore.Config{Path: dbPath})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(s.Put(record("task1", 1, "node1", time.Now(), `{}`))).To(Succeed())
+		Expect(s.Close()).To(Succeed())
+
+		s, e = reportStore.NewReportStore(log, reportStore.Config{Path: dbPath})
+		Expect(e).NotTo(HaveOccurred())
+		defer s.Close()
+		Expect(s.LatestRound("NetReach", "task1")).To(Equal(1))
+	})
+
+	It("remove the oldest records by size", func() {
+		s, e := reportStore.NewReportStore(log, reportStore.Config{Path: dbPath, MaxSizeInMB: 1})
+		Expect(e).NotTo(HaveOccurred())
+		defer s.Close()
+
+		data := `"` + strings.Repeat("a", 100*1024) + `"`
+		now := time.Now()
+		for round := 1; round <= 20; round++ {
+			Expect(s.Put(record("task1", round, "node1", now.Add(time.Duration(round)*time.Second), data))).To(Succeed())
+		}
+		records, e := s.Query(reportStore.Query{KindName: "NetReach", TaskName: "task1"})
+		Expect(e).NotTo(HaveOccurred())
+		Expect(len(records)).To(BeNumerically("<", 20))
+		Expect(len(records)).To(BeNumerically(">", 5))
+		Expect(records[len(records)-1].RoundNumber).To(Equal(20))
+		Expect(records[0].RoundNumber).To(Equal(20 - len(records) + 1))
+	})
+
+	It("remove the records by age", func() {
+		s, e := reportStore.NewReportStore(log, reportStore.Config{Path: dbPath, MaxAge: 5 * time.Second, CleanInterval: time.Second})
+		Expect(e).NotTo(HaveOccurred())
+		defer s.Close()
+
+		Expect(s.Put(record("task1", 1, "node1", time.Now().Add(-time.Minute), `{}`))).To(Succeed())
+		Expect(s.Put(record("task1", 2, "node1", time.Now(), `{}`))).To(Succeed())
+
+		Eventually(func() int {
+			records, _ := s.Query(reportStore.Query{KindName: "NetReach", TaskName: "task1"})
+			return len(records)
+		}).WithTimeout(4 * time.Second).Should(Equal(1))
+
+		Eventually(func() int {
+			records, _ := s.Query(reportStore.Query{KindName: "NetReach", TaskName: "task1"})
+			return len(records)
+		}).WithTimeout(10 * time.Second).Should(Equal(0))
+	})
+})
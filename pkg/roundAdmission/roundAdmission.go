@@ -0,0 +1,194 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package roundAdmission
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kdoctor-io/kdoctor/pkg/lock"
+)
+
+const (
+	PolicyAllow  = "Allow"
+	PolicyForbid = "Forbid"
+	PolicyQueue  = "Queue"
+)
+
+// Round is the round of a task which asks for the admission before it starts
+type Round struct {
+	// the kind and the name of the task, for example, NetReach.test
+	Task string
+	// Allow, Forbid or Queue, and the empty policy is Allow
+	Policy string
+	// the waiting round with bigger priority is admitted first
+	Priority int
+	// the qps sent by the agent on each node
+	QPS int
+	// the nodes of the agents of the task
+	Nodes []string
+	// the running round is released after the deadline, even if its end is missed
+	Deadline time.Time
+}
+
+// Decision is the result of the admission of the round
+type Decision struct {
+	Admitted bool
+	// the round of Forbid is skipped when it is not admitted
+	Skipped bool
+	// why the round is not admitted
+	Reason string
+}
+
+type entry struct {
+	Round
+	// when the round begins to wait
+	since time.Time
+	// when the round asks for the admission last time
+	lastSeen time.Time
+}
+
+// Queue admits the rounds of all tasks, the rounds which would overlap with the running rounds of Forbid or Queue,
+// or exceed the qps budget of any node, wait until the running rounds finish
+type Queue struct {
+	lock.Mutex
+	nodeQPSBudget int
+	// the waiting round is dropped when it does not ask for the admission in the duration, for example, the task is deleted
+	waitingTimeout time.Duration
+	running        map[string]*entry
+	waiting        map[string]*entry
+}
+
+// NewQueue returns the admission queue, and the nodeQPSBudget 0 means no limit
+func NewQueue(nodeQPSBudget int, waitingTimeout time.Duration) *Queue {
+	return &Queue{
+		nodeQPSBudget:  nodeQPSBudget,
+		waitingTimeout: waitingTimeout,
+		running:        map[string]*entry{},
+		waiting:        map[string]*entry{},
+	}
+}
+
+func exclusive(policy string) bool {
+	return policy == PolicyForbid || policy == PolicyQueue
+}
+
+func sortedKeys(m map[string]*entry) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Admit asks for the admission of the round, and the waiting round should ask again later until it is admitted
+func (q *Queue) Admit(r Round, now time.Time) Decision {
+	q.Lock()
+	defer q.Unlock()
+
+	q.expire(now)
+	// the previous round of the task is finished when the next round asks
+	delete(q.running, r.Task)
+
+	e, ok := q.waiting[r.Task]
+	if !ok {
+		e = &entry{since: now}
+		q.waiting[r.Task] = e
+	}
+	e.Round = r
+	e.lastSeen = now
+
+	reason := q.blockedReason(e)
+	if len(reason) == 0 {
+		delete(q.waiting, r.Task)
+		q.running[r.Task] = e
+		return Decision{Admitted: true}
+	}
+	if r.Policy == PolicyForbid {
+		delete(q.waiting, r.Task)
+		return Decision{Skipped: true, Reason: reason}
+	}
+	return Decision{Reason: reason}
+}
+
+func (q *Queue) blockedReason(e *entry) string {
+	others := sortedKeys(q.running)
+	for _, k := range others {
+		if exclusive(q.running[k].Policy) {
+			return fmt.Sprintf("the round of %s with policy %s is running", k, q.running[k].Policy)
+		}
+	}
+	if exclusive(e.Policy) && len(others) > 0 {
+		return fmt.Sprintf("the rounds of %s are running", strings.Join(others, ", "))
+	}
+
+	// the waiting round with bigger priority, or with the same priority but waiting longer, is admitted first
+	for _, k := range sortedKeys(q.waiting) {
+		w := q.waiting[k]
+		if k == e.Task {
+			continue
+		}
+		if w.Priority > e.Priority || (w.Priority == e.Priority && w.since.Before(e.since)) {
+			return fmt.Sprintf("the round of %s is waiting ahead", k)
+		}
+	}
+
+	if q.nodeQPSBudget > 0 {
+		load := map[string]int{}
+		for _, o := range q.running {
+			for _, node := range o.Nodes {
+				load[node] += o.QPS
+			}
+		}
+		nodes := append([]string{}, e.Nodes...)
+		sort.Strings(nodes)
+		for _, node := range nodes {
+			// the round exceeding the budget alone runs when no other round runs on the node
+			if load[node] > 0 && load[node]+e.QPS > q.nodeQPSBudget {
+				return fmt.Sprintf("the qps %d on node %s would exceed the budget %d", load[node]+e.QPS, node, q.nodeQPSBudget)
+			}
+		}
+	}
+	return ""
+}
+
+func (q *Queue) expire(now time.Time) {
+	for k, e := range q.running {
+		if now.After(e.Deadline) {
+			delete(q.running, k)
+		}
+	}
+	for k, e := range q.waiting {
+		if now.Sub(e.lastSeen) > q.waitingTimeout {
+			delete(q.waiting, k)
+		}
+	}
+}
+
+// Resume records the round which is running, for example, the ongoing round when the controller restarts
+func (q *Queue) Resume(r Round, now time.Time) {
+	q.Lock()
+	defer q.Unlock()
+	delete(q.waiting, r.Task)
+	q.running[r.Task] = &entry{Round: r, since: now, lastSeen: now}
+}
+
+// IsRunning returns whether the round of the task is running
+func (q *Queue) IsRunning(task string) bool {
+	q.Lock()
+	defer q.Unlock()
+	_, ok := q.running[task]
+	return ok
+}
+
+// Release removes the round of the task when the round finishes
+func (q *Queue) Release(task string) {
+	q.Lock()
+	defer q.Unlock()
+	delete(q.running, task)
+	delete(q.waiting, task)
+}
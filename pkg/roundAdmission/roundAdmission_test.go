@@ -0,0 +1,90 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package roundAdmission
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("roundAdmission unit test", Label("roundAdmission"), func() {
+	now := time.Now()
+	deadline := now.Add(time.Hour)
+
+	It("run the rounds of Allow together", func() {
+		q := NewQueue(0, time.Minute)
+		Expect(q.Admit(Round{Task: "NetReach.a", Deadline: deadline}, now).Admitted).To(BeTrue())
+		Expect(q.Admit(Round{Task: "Netdns.b", Policy: PolicyAllow, Deadline: deadline}, now).Admitted).To(BeTrue())
+		Expect(q.IsRunning("NetReach.a")).To(BeTrue())
+
+		q.Release("NetReach.a")
+		Expect(q.IsRunning("NetReach.a")).To(BeFalse())
+	})
+
+	It("run the round of Queue alone", func() {
+		q := NewQueue(0, time.Minute)
+		Expect(q.Admit(Round{Task: "NetReach.a", Deadline: deadline}, now).Admitted).To(BeTrue())
+
+		d := q.Admit(Round{Task: "Netdns.b", Policy: PolicyQueue, Deadline: deadline}, now)
+		Expect(d).To(Equal(Decision{Reason: "the rounds of NetReach.a are running"}))
+
+		// the later round waits behind the queued round
+		d = q.Admit(Round{Task: "NetReach.c", Deadline: deadline}, now.Add(time.Second))
+		Expect(d).To(Equal(Decision{Reason: "the round of Netdns.b is waiting ahead"}))
+
+		q.Release("NetReach.a")
+		Expect(q.Admit(Round{Task: "Netdns.b", Policy: PolicyQueue, Deadline: deadline}, now.Add(2*time.Second)).Admitted).To(BeTrue())
+		d = q.Admit(Round{Task: "NetReach.c", Deadline: deadline}, now.Add(2*time.Second))
+		Expect(d).To(Equal(Decision{Reason: "the round of Netdns.b with policy Queue is running"}))
+	})
+
+	It("skip the round of Forbid", func() {
+		q := NewQueue(0, time.Minute)
+		Expect(q.Admit(Round{Task: "NetReach.a", Deadline: deadline}, now).Admitted).To(BeTrue())
+		d := q.Admit(Round{Task: "Netdns.b", Policy: PolicyForbid, Deadline: deadline}, now)
+		Expect(d).To(Equal(Decision{Skipped: true, Reason: "the rounds of NetReach.a are running"}))
+		Expect(q.waiting).To(BeEmpty())
+	})
+
+	It("delay the round exceeding the qps budget", func() {
+		q := NewQueue(100, time.Minute)
+		Expect(q.Admit(Round{Task: "NetReach.a", QPS: 60, Nodes: []string{"node1", "node2"}, Deadline: deadline}, now).Admitted).To(BeTrue())
+		Expect(q.Admit(Round{Task: "Netdns.b", QPS: 40, Nodes: []string{"node1"}, Deadline: deadline}, now).Admitted).To(BeTrue())
+
+		d := q.Admit(Round{Task: "Netdns.c", QPS: 10, Nodes: []string{"node2", "node1"}, Deadline: deadline}, now)
+		Expect(d).To(Equal(Decision{Reason: "the qps 110 on node node1 would exceed the budget 100"}))
+
+		// the round exceeding the budget alone runs on the idle node
+		Expect(q.Admit(Round{Task: "AppHttpHealthy.d", QPS: 200, Nodes: []string{"node3"}, Deadline: deadline}, now.Add(-time.Second)).Admitted).To(BeTrue())
+
+		q.Release("Netdns.b")
+		Expect(q.Admit(Round{Task: "Netdns.c", QPS: 10, Nodes: []string{"node2", "node1"}, Deadline: deadline}, now).Admitted).To(BeTrue())
+	})
+
+	It("admit the waiting round with higher priority first", func() {
+		q := NewQueue(0, time.Minute)
+		Expect(q.Admit(Round{Task: "NetReach.a", Policy: PolicyQueue, Deadline: deadline}, now).Admitted).To(BeTrue())
+		Expect(q.Admit(Round{Task: "NetReach.low", Deadline: deadline}, now).Admitted).To(BeFalse())
+		Expect(q.Admit(Round{Task: "NetReach.high", Priority: 10, Deadline: deadline}, now.Add(time.Second)).Admitted).To(BeFalse())
+
+		q.Release("NetReach.a")
+		d := q.Admit(Round{Task: "NetReach.low", Deadline: deadline}, now.Add(2*time.Second))
+		Expect(d).To(Equal(Decision{Reason: "the round of NetReach.high is waiting ahead"}))
+		Expect(q.Admit(Round{Task: "NetReach.high", Priority: 10, Deadline: deadline}, now.Add(2*time.Second)).Admitted).To(BeTrue())
+		Expect(q.Admit(Round{Task: "NetReach.low", Deadline: deadline}, now.Add(3*time.Second)).Admitted).To(BeTrue())
+	})
+
+	It("drop the stale rounds", func() {
+		q := NewQueue(0, time.Minute)
+		q.Resume(Round{Task: "NetReach.a", Policy: PolicyQueue, Deadline: now.Add(time.Minute)}, now)
+		Expect(q.Admit(Round{Task: "NetReach.b", Deadline: deadline}, now).Admitted).To(BeFalse())
+
+		// the running round is released after its deadline, and the waiting round is dropped when it does not ask again
+		Expect(q.Admit(Round{Task: "NetReach.c", Deadline: deadline}, now.Add(2*time.Minute)).Admitted).To(BeTrue())
+		Expect(q.IsRunning("NetReach.a")).To(BeFalse())
+		Expect(q.waiting).To(BeEmpty())
+	})
+})
@@ -45,6 +45,9 @@ func (rt *RunningTask) QpsStats() v1beta1.TotalRunningLoad {
 	var appHealthQps int
 	var netDNSQps int
 	var netReachQps int
+	var netTcpQps int
+	var netUdpQps int
+	var netDelayQps int
 
 	for _, v := range rt.task {
 		switch v.Kind {
@@ -54,6 +57,12 @@ func (rt *RunningTask) QpsStats() v1beta1.TotalRunningLoad {
 			netReachQps += v.Qps
 		case types.KindNameNetdns:
 			netDNSQps += v.Qps
+		case types.KindNameNetTcp:
+			netTcpQps += v.Qps
+		case types.KindNameNetUdp:
+			netUdpQps += v.Qps
+		case types.KindNameNetDelay:
+			netDelayQps += v.Qps
 		}
 	}
 
@@ -61,5 +70,8 @@ func (rt *RunningTask) QpsStats() v1beta1.TotalRunningLoad {
 		AppHttpHealthyQPS: int64(appHealthQps),
 		NetDnsQPS:         int64(netDNSQps),
 		NetReachQPS:       int64(netReachQps),
+		NetTcpQPS:         int64(netTcpQps),
+		NetUdpQPS:         int64(netUdpQps),
+		NetDelayQPS:       int64(netDelayQps),
 	}
 }
@@ -223,6 +223,66 @@ func (t *Tracker) updateRuntimeStatus(ctx context.Context, item Item, status str
 				return err
 			}
 
+		case types.KindNameNetTcp:
+			instance := crd.NetTcp{}
+			err := t.apiReader.Get(ctx, k8types.NamespacedName{Name: taskName}, &instance)
+			if nil != err {
+				return err
+			}
+
+			// check the resource whether is already equal
+			if reflect.DeepEqual(instance.Status.Resource, resource) {
+				t.log.Sugar().Debugf("task %v resource already updatede, skip it", item.RuntimeKey)
+				return nil
+			}
+
+			t.log.Sugar().Debugf("task %v old resource is %v, the new resource is %v", item.RuntimeKey, *instance.Status.Resource, *resource)
+			instance.Status.Resource = resource
+			err = t.client.Status().Update(ctx, &instance)
+			if nil != err {
+				return err
+			}
+
+		case types.KindNameNetUdp:
+			instance := crd.NetUdp{}
+			err := t.apiReader.Get(ctx, k8types.NamespacedName{Name: taskName}, &instance)
+			if nil != err {
+				return err
+			}
+
+			// check the resource whether is already equal
+			if reflect.DeepEqual(instance.Status.Resource, resource) {
+				t.log.Sugar().Debugf("task %v resource already updatede, skip it", item.RuntimeKey)
+				return nil
+			}
+
+			t.log.Sugar().Debugf("task %v old resource is %v, the new resource is %v", item.RuntimeKey, *instance.Status.Resource, *resource)
+			instance.Status.Resource = resource
+			err = t.client.Status().Update(ctx, &instance)
+			if nil != err {
+				return err
+			}
+
+		case types.KindNameNetDelay:
+			instance := crd.NetDelay{}
+			err := t.apiReader.Get(ctx, k8types.NamespacedName{Name: taskName}, &instance)
+			if nil != err {
+				return err
+			}
+
+			// check the resource whether is already equal
+			if reflect.DeepEqual(instance.Status.Resource, resource) {
+				t.log.Sugar().Debugf("task %v resource already updatede, skip it", item.RuntimeKey)
+				return nil
+			}
+
+			t.log.Sugar().Debugf("task %v old resource is %v, the new resource is %v", item.RuntimeKey, *instance.Status.Resource, *resource)
+			instance.Status.Resource = resource
+			err = t.client.Status().Update(ctx, &instance)
+			if nil != err {
+				return err
+			}
+
 		default:
 			return fmt.Errorf("unsupported task '%s/%s'", taskKind, taskName)
 		}
@@ -0,0 +1,178 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+const TracerName = "github.com/kdoctor-io/kdoctor"
+
+// the attributes of the round spans
+const (
+	AttrKind          = attribute.Key("kdoctor.kind")
+	AttrTask          = attribute.Key("kdoctor.task")
+	AttrRound         = attribute.Key("kdoctor.round")
+	AttrNode          = attribute.Key("kdoctor.node")
+	AttrPod           = attribute.Key("kdoctor.pod")
+	AttrTarget        = attribute.Key("kdoctor.target")
+	AttrRoundResult   = attribute.Key("kdoctor.round.result")
+	AttrSucceedNodes  = attribute.Key("kdoctor.round.succeed_nodes")
+	AttrFailedNodes   = attribute.Key("kdoctor.round.failed_nodes")
+	AttrFailureReason = attribute.Key("kdoctor.failure_reason")
+	// the round is handled without the reports of some agents
+	AttrRoundIncomplete = attribute.Key("kdoctor.round.incomplete")
+)
+
+// Tracer returns the tracer of kdoctor, which does nothing when the tracing is disabled
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// RunTracing exports the spans to the OTLP collector with grpc.
+// The sampling only depends on the trace ID, and all spans of a round share the same trace ID,
+// so the controller and the agents make the same decision for the round
+func RunTracing(enabled bool, endpoint string, insecure bool, samplePercent int32, serviceName, podName string, logger *zap.Logger) {
+	if !enabled {
+		logger.Info("tracing is disabled")
+		return
+	}
+	if len(endpoint) == 0 {
+		logger.Sugar().Fatalf("the OTLP endpoint is required to enable tracing")
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	}
+	// the exporter connects in the background, so it does not block when the collector is not ready
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		logger.Sugar().Fatalf("failed to create the OTLP trace exporter, reason=%v", err)
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceInstanceID(podName),
+	)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(float64(samplePercent)/100)),
+		sdktrace.WithIDGenerator(NewIDGenerator()),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(e error) {
+		logger.Sugar().Warnf("tracing error: %v", e)
+	}))
+	logger.Sugar().Infof("export traces to %v, sample %v%% of the rounds", endpoint, samplePercent)
+}
+
+// RoundSpanContext returns the context of the root span of the task round, which the controller records by StartRoundSpan.
+// Its IDs are derived from the task and the round, so the controller and the agents join the same trace without passing the context to each other
+func RoundSpanContext(kind, task string, uid k8stypes.UID, roundNumber int) trace.SpanContext {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s/%d", kind, task, uid, roundNumber)))
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	copy(traceID[:], sum[:16])
+	copy(spanID[:], sum[16:24])
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+// RoundContext returns the context whose spans belong to the trace of the task round
+func RoundContext(ctx context.Context, kind, task string, uid k8stypes.UID, roundNumber int) context.Context {
+	return trace.ContextWithRemoteSpanContext(ctx, RoundSpanContext(kind, task, uid, roundNumber))
+}
+
+type roundRootKey struct{}
+
+// StartRoundSpan starts the root span of the task round with the IDs of RoundSpanContext,
+// so the spans started from RoundContext by the controller and the agents are its children
+func StartRoundSpan(ctx context.Context, kind, task string, uid k8stypes.UID, roundNumber int, name string, start time.Time, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx = context.WithValue(ctx, roundRootKey{}, RoundSpanContext(kind, task, uid, roundNumber))
+	return Tracer().Start(ctx, name, trace.WithNewRoot(), trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+}
+
+// idGenerator generates random IDs, except for the root span of the task round started by StartRoundSpan
+type idGenerator struct{}
+
+// NewIDGenerator returns the ID generator of the tracer provider, which StartRoundSpan requires
+func NewIDGenerator() sdktrace.IDGenerator {
+	return idGenerator{}
+}
+
+func (idGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if sc, ok := ctx.Value(roundRootKey{}).(trace.SpanContext); ok {
+		return sc.TraceID(), sc.SpanID()
+	}
+	var traceID trace.TraceID
+	_, _ = rand.Read(traceID[:])
+	return traceID, idGenerator{}.NewSpanID(ctx, traceID)
+}
+
+func (idGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	_, _ = rand.Read(spanID[:])
+	return spanID
+}
+
+// RoundAttributes returns the attributes which identify the task round
+func RoundAttributes(kind, task string, roundNumber int) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttrKind.String(kind),
+		AttrTask.String(task),
+		AttrRound.Int(roundNumber),
+	}
+}
+
+// StartTargetSpan starts the span of the load run to a target of the round
+func StartTargetSpan(ctx context.Context, target string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, "target.load", trace.WithAttributes(AttrTarget.String(target)))
+}
+
+// EndSpan ends the span, which is marked as an error with the failure reason when it is not empty
+func EndSpan(span trace.Span, failureReason string, options ...trace.SpanEndOption) {
+	if len(failureReason) > 0 {
+		span.SetAttributes(AttrFailureReason.String(failureReason))
+		span.SetStatus(codes.Error, failureReason)
+	}
+	span.End(options...)
+}
+
+// EndSpanWithError ends the span, which is marked as an error when err is not nil
+func EndSpanWithError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StartSpanAt starts a span with the start time, which is used to record the duration that has passed
+func StartSpanAt(ctx context.Context, name string, start time.Time, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+}
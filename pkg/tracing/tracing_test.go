@@ -0,0 +1,89 @@
+// Copyright 2023 Authors of kdoctor-io
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kdoctor-io/kdoctor/pkg/tracing"
+)
+
+var _ = Describe("test tracing", Label("tracing"), func() {
+	var recorder *tracetest.SpanRecorder
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithIDGenerator(tracing.NewIDGenerator()))
+		otel.SetTracerProvider(provider)
+		DeferCleanup(func() {
+			otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		})
+	})
+
+	It("derive the same trace from the task round", func() {
+		a := tracing.RoundSpanContext("NetReach", "task", "uid", 1)
+		Expect(a.IsValid()).To(BeTrue())
+		Expect(a.IsSampled()).To(BeTrue())
+		Expect(tracing.RoundSpanContext("NetReach", "task", "uid", 1)).To(Equal(a))
+		Expect(tracing.RoundSpanContext("NetReach", "task", "uid", 2).TraceID()).NotTo(Equal(a.TraceID()))
+		Expect(tracing.RoundSpanContext("NetReach", "task", "other", 1).TraceID()).NotTo(Equal(a.TraceID()))
+	})
+
+	It("join the spans of the controller and the agent to the trace of the round", func() {
+		parent := tracing.RoundSpanContext("NetReach", "task", "uid", 3)
+
+		start := time.Now().Add(-time.Minute)
+		_, scheduleSpan := tracing.StartSpanAt(tracing.RoundContext(context.Background(), "NetReach", "task", "uid", 3), "controller.round.schedule", start)
+		scheduleSpan.End()
+
+		ctx, agentSpan := tracing.Tracer().Start(tracing.RoundContext(context.Background(), "NetReach", "task", "uid", 3), "agent.round")
+		_, targetSpan := tracing.StartTargetSpan(ctx, "target1")
+		tracing.EndSpan(targetSpan, "timeout")
+		agentSpan.End()
+
+		// the root span is recorded by the controller when the round is done
+		_, controllerSpan := tracing.StartRoundSpan(context.Background(), "NetReach", "task", "uid", 3, "controller.round", start,
+			tracing.RoundAttributes("NetReach", "task", 3)...)
+		tracing.EndSpan(controllerSpan, "")
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(4))
+		for _, s := range spans {
+			Expect(s.SpanContext().TraceID()).To(Equal(parent.TraceID()))
+		}
+		Expect(spans[0].Name()).To(Equal("controller.round.schedule"))
+		Expect(spans[0].StartTime()).To(Equal(start))
+		Expect(spans[0].Parent().SpanID()).To(Equal(parent.SpanID()))
+		Expect(spans[2].Parent().SpanID()).To(Equal(parent.SpanID()))
+
+		root := spans[3]
+		Expect(root.Name()).To(Equal("controller.round"))
+		Expect(root.Parent().IsValid()).To(BeFalse())
+		Expect(root.SpanContext().SpanID()).To(Equal(parent.SpanID()))
+		Expect(root.StartTime()).To(Equal(start))
+		Expect(root.Status().Code).To(Equal(codes.Unset))
+
+		Expect(spans[1].Name()).To(Equal("target.load"))
+		Expect(spans[1].Parent().SpanID()).To(Equal(spans[2].SpanContext().SpanID()))
+		Expect(spans[1].Status().Code).To(Equal(codes.Error))
+		Expect(spans[1].Status().Description).To(Equal("timeout"))
+		Expect(spans[1].Attributes()).To(ContainElements(tracing.AttrTarget.String("target1"), tracing.AttrFailureReason.String("timeout")))
+	})
+
+	It("do nothing when the tracing is disabled", func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		_, span := tracing.StartTargetSpan(context.Background(), "target1")
+		Expect(span.IsRecording()).To(BeFalse())
+		tracing.EndSpanWithError(span, nil)
+	})
+})
@@ -6,6 +6,10 @@ package types
 var AgentEnvMapping = []EnvMapping{
 	{"ENV_ENABLED_METRIC", "false", &AgentConfig.EnableMetric},
 	{"ENV_METRIC_HTTP_PORT", "", &AgentConfig.MetricPort},
+	{"ENV_ENABLED_TRACE", "false", &AgentConfig.EnableTrace},
+	{"ENV_TRACE_OTLP_ENDPOINT", "", &AgentConfig.TraceOtlpEndpoint},
+	{"ENV_TRACE_OTLP_INSECURE", "true", &AgentConfig.TraceOtlpInsecure},
+	{"ENV_TRACE_SAMPLE_PERCENT", "100", &AgentConfig.TraceSamplePercent},
 	{"ENV_AGENT_HEALTH_HTTP_PORT", "5710", &AgentConfig.AgentHealthPort},
 	{"ENV_GOPS_LISTEN_PORT", "", &AgentConfig.GopsPort},
 	{"ENV_WEBHOOK_PORT", "", &AgentConfig.WebhookPort},
@@ -19,6 +23,10 @@ var AgentEnvMapping = []EnvMapping{
 	{"ENV_AGENT_APP_DNS_UDP_PORT", "53", &AgentConfig.AppDnsUdpPort},
 	{"ENV_AGENT_APP_DNS_TCP_PORT", "53", &AgentConfig.AppDnsTcpPort},
 	{"ENV_AGENT_APP_DNS_TCP_TLS_PORT", "853", &AgentConfig.AppDnsTcpTlsPort},
+	{"ENV_AGENT_APP_DNS_HTTPS_PORT", "8443", &AgentConfig.AppDnsHttpsPort},
+	{"ENV_AGENT_APP_DNS_QUIC_PORT", "853", &AgentConfig.AppDnsQuicPort},
+	{"ENV_AGENT_APP_TCP_PORT", "5720", &AgentConfig.AppTcpPort},
+	{"ENV_AGENT_APP_UDP_PORT", "5730", &AgentConfig.AppUdpPort},
 	{"ENV_AGENT_RESOURCE_COLLECT_INTERVAL_IN_SECOND", "1", &AgentConfig.CollectResourceInSecond},
 	{"ENV_ENABLE_AGGREGATE_AGENT_REPORT", "false", &AgentConfig.EnableAggregateAgentReport},
 	{"ENV_AGENT_REPORT_STORAGE_PATH", "", &AgentConfig.DirPathAgentReport},
@@ -34,6 +42,10 @@ type AgentConfigStruct struct {
 	// ------- from env
 	EnableMetric            bool
 	MetricPort              int32
+	EnableTrace             bool
+	TraceOtlpEndpoint       string
+	TraceOtlpInsecure       bool
+	TraceSamplePercent      int32
 	GopsPort                int32
 	WebhookPort             int32
 	AgentGrpcListenPort     int32
@@ -42,6 +54,10 @@ type AgentConfigStruct struct {
 	AppDnsUdpPort           int32
 	AppDnsTcpPort           int32
 	AppDnsTcpTlsPort        int32
+	AppDnsHttpsPort         int32
+	AppDnsQuicPort          int32
+	AppTcpPort              int32
+	AppUdpPort              int32
 	AgentHealthPort         int32
 	CollectResourceInSecond int32
 	PyroscopeServerAddress  string
@@ -68,6 +84,10 @@ type AgentConfigStruct struct {
 	TlsInsecure    bool
 	AppMode        bool
 	AppDnsUpstream bool
+	// the identity in the certificate of the controller, which calls the grpc server of the agent
+	TlsClientIdentity string
+	// the ca which signs the certificate of the controller, and its key is not mounted to the agent
+	TlsClientCaPath string
 
 	TaskKind      string
 	TaskName      string
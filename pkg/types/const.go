@@ -12,10 +12,14 @@ const (
 	KindNameAppHttpHealthy = "AppHttpHealthy"
 	KindNameNetReach       = "NetReach"
 	KindNameNetdns         = "Netdns"
+	KindNameNetTcp         = "NetTcp"
+	KindNameNetUdp         = "NetUdp"
+	KindNameNetDelay       = "NetDelay"
+	KindNameTaskSuite      = "TaskSuite"
 
 	KindDeployment = "Deployment"
 	KindDaemonSet  = "DaemonSet"
 )
 
-var TaskKinds = []string{KindNameAppHttpHealthy, KindNameNetReach, KindNameNetdns}
+var TaskKinds = []string{KindNameAppHttpHealthy, KindNameNetReach, KindNameNetdns, KindNameNetTcp, KindNameNetUdp, KindNameNetDelay}
 var TaskRuntimes = []string{KindDeployment, KindDaemonSet}
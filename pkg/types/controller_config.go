@@ -12,6 +12,10 @@ import (
 var ControllerEnvMapping = []EnvMapping{
 	{"ENV_ENABLED_METRIC", "false", &ControllerConfig.EnableMetric},
 	{"ENV_METRIC_HTTP_PORT", "", &ControllerConfig.MetricPort},
+	{"ENV_ENABLED_TRACE", "false", &ControllerConfig.EnableTrace},
+	{"ENV_TRACE_OTLP_ENDPOINT", "", &ControllerConfig.TraceOtlpEndpoint},
+	{"ENV_TRACE_OTLP_INSECURE", "true", &ControllerConfig.TraceOtlpInsecure},
+	{"ENV_TRACE_SAMPLE_PERCENT", "100", &ControllerConfig.TraceSamplePercent},
 	{"ENV_HTTP_PORT", "80", &ControllerConfig.HttpPort},
 	{"ENV_GOPS_LISTEN_PORT", "", &ControllerConfig.GopsPort},
 	{"ENV_WEBHOOK_PORT", "", &ControllerConfig.WebhookPort},
@@ -29,6 +33,7 @@ var ControllerEnvMapping = []EnvMapping{
 	{"ENV_AGENT_REPORT_STORAGE_PATH", "", &ControllerConfig.DirPathAgentReport},
 	{"ENV_CLEAN_AGED_REPORT_INTERVAL_IN_MINUTE", "10", &ControllerConfig.CleanAgedReportInMinute},
 	{"ENV_CONTROLLER_REPORT_AGE_IN_DAY", "30", &ControllerConfig.ReportAgeInDay},
+	{"ENV_CONTROLLER_REPORT_MAX_SIZE_IN_MB", "800", &ControllerConfig.ReportMaxSizeInMB},
 	{"ENV_COLLECT_AGENT_REPORT_INTERVAL_IN_SECOND", "600", &ControllerConfig.CollectAgentReportIntervalInSecond},
 	{"ENV_RESOURCE_TRACKER_CHANNEL_BUFFER", "500", &ControllerConfig.ResourceTrackerChannelBuffer},
 	{"ENV_RESOURCE_TRACKER_MAX_DATABASE_CAP", "5000", &ControllerConfig.ResourceTrackerMaxDatabaseCap},
@@ -41,6 +46,10 @@ type ControllerConfigStruct struct {
 	// ------- from env
 	EnableMetric           bool
 	MetricPort             int32
+	EnableTrace            bool
+	TraceOtlpEndpoint      string
+	TraceOtlpInsecure      bool
+	TraceSamplePercent     int32
 	HttpPort               int32
 	GopsPort               int32
 	WebhookPort            int32
@@ -60,6 +69,7 @@ type ControllerConfigStruct struct {
 	DirPathControllerReport            string
 	DirPathAgentReport                 string
 	ReportAgeInDay                     int32
+	ReportMaxSizeInMB                  int32
 	CollectAgentReportIntervalInSecond int32
 
 	ResourceTrackerChannelBuffer        int32
@@ -73,6 +83,10 @@ type ControllerConfigStruct struct {
 	TlsCaCertPath     string
 	TlsServerCertPath string
 	TlsServerKeyPath  string
+	// the certificate of the controller and the CA of the agents for the grpc mTLS
+	TlsClientCertPath string
+	TlsClientKeyPath  string
+	TlsAgentCaPath    string
 
 	ConfigMapDeploymentPath string
 	ConfigMapDaemonsetPath  string
@@ -18,6 +18,12 @@ type ConfigmapConfig struct {
 	AppHttpHealthyRequestMaxQPS int `yaml:"appHttpHealthyRequestMaxQPS"`
 	// netdns
 	NetDnsRequestMaxQPS int `yaml:"netDnsRequestMaxQPS"`
+	// nettcp
+	NetTcpRequestMaxQPS int `yaml:"netTcpRequestMaxQPS"`
+	// netudp
+	NetUdpRequestMaxQPS int `yaml:"netUdpRequestMaxQPS"`
+	// netdelay
+	NetDelayRequestMaxQPS int `yaml:"netDelayRequestMaxQPS"`
 
 	MultusPodAnnotationKey string `yaml:"multusPodAnnotationKey"`
 	CrdMaxHistory          int    `yaml:"crdMaxHistory"`
@@ -29,6 +35,55 @@ type ConfigmapConfig struct {
 
 	AgentDefaultTerminationGracePeriodMinutes int64              `yaml:"agentDefaultTerminationGracePeriodMinutes"`
 	KdoctorAgent                              KdoctorAgentConfig `yaml:"kdoctorAgent"`
+
+	// the report sinks of all tasks
+	ReportSinks []ReportSinkConfig `yaml:"reportSinks"`
+
+	// export the reports of all tasks to the object storage
+	ReportExport ReportExportConfig `yaml:"reportExport"`
+
+	// the admission of the rounds of all tasks
+	Admission AdmissionConfig `yaml:"admission"`
+}
+
+type ReportSinkConfig struct {
+	URL             string `yaml:"url"`
+	HmacSecretName  string `yaml:"hmacSecretName"`
+	HmacSecretKey   string `yaml:"hmacSecretKey"`
+	FailedRoundOnly bool   `yaml:"failedRoundOnly"`
+	MaxRetries      *int32 `yaml:"maxRetries"`
+	TimeoutInSecond *int32 `yaml:"timeoutInSecond"`
+}
+
+type ReportExportConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// the host and the optional port of the S3-compatible object storage
+	Endpoint string `yaml:"endpoint"`
+	Bucket   string `yaml:"bucket"`
+	Region   string `yaml:"region"`
+	// the prefix of the object keys
+	Prefix string `yaml:"prefix"`
+	// access the object storage with http instead of https
+	Insecure bool `yaml:"insecure"`
+	// jsonl or parquet
+	Format string `yaml:"format"`
+	// the secret in the namespace of the controller, which has the keys accessKeyID and secretAccessKey
+	CredentialSecretName string `yaml:"credentialSecretName"`
+	// the maximum number of the objects failing to upload, which are kept to upload again, 100 by default
+	MaxPendingObjects int `yaml:"maxPendingObjects"`
+}
+
+type AdmissionConfig struct {
+	// the maximum total qps of the running rounds sent by the agent on each node, 0 means no limit
+	NodeQPSBudget int `yaml:"nodeQPSBudget"`
+	// the priority classes referred by the priorityClassName of the schedule of the tasks
+	PriorityClasses []PriorityClassConfig `yaml:"priorityClasses"`
+}
+
+type PriorityClassConfig struct {
+	Name string `yaml:"name"`
+	// the bigger value is the higher priority
+	Value int `yaml:"value"`
 }
 
 type KdoctorAgentConfig struct {
@@ -264,7 +264,10 @@ func CompareResult(f *frame.Framework, name, taskKind string, podIPs []string, n
 				}
 			}
 			// startTime
-			schedule := pluginManager.NewSchedule(*rs.Spec.Schedule.Schedule)
+			schedule, err := pluginManager.NewSchedule(rs.Spec.Schedule)
+			if err != nil {
+				return GetResultFromReport(r), err
+			}
 			startTime := schedule.StartTime(rs.CreationTimestamp.Time)
 			if v.StartTimeStamp.Time.Compare(startTime) > 5 {
 				return GetResultFromReport(r), errors.New("the task start time error is greater than 5 seconds")
@@ -322,7 +325,10 @@ func CompareResult(f *frame.Framework, name, taskKind string, podIPs []string, n
 				}
 			}
 			// startTime
-			schedule := pluginManager.NewSchedule(*rs.Spec.Schedule.Schedule)
+			schedule, err := pluginManager.NewSchedule(rs.Spec.Schedule)
+			if err != nil {
+				return GetResultFromReport(r), err
+			}
 			startTime := schedule.StartTime(rs.CreationTimestamp.Time)
 			if v.StartTimeStamp.Time.Compare(startTime) > 5 {
 				return GetResultFromReport(r), errors.New("the task start time error is greater than 5 seconds")
@@ -393,7 +399,10 @@ func CompareResult(f *frame.Framework, name, taskKind string, podIPs []string, n
 				}
 			}
 			// startTime
-			schedule := pluginManager.NewSchedule(*rs.Spec.Schedule.Schedule)
+			schedule, err := pluginManager.NewSchedule(rs.Spec.Schedule)
+			if err != nil {
+				return GetResultFromReport(r), err
+			}
 			startTime := schedule.StartTime(rs.CreationTimestamp.Time)
 			if v.StartTimeStamp.Time.Compare(startTime) > 5 {
 				return GetResultFromReport(r), errors.New("the task start time error is greater than 5 seconds")
@@ -247,6 +247,118 @@ var _ = Describe("testing netDns ", Label("netDns"), func() {
 		Expect(success).To(BeTrue(), "compare report and task result")
 	})
 
+	It("Successfully testing User Define Dns server case use https protocol", Serial, Label("D00005"), func() {
+		var e error
+		crontab := "0 1"
+		netDnsName := "netdns-e2e-" + tools.RandomName()
+
+		netDns := new(v1beta1.Netdns)
+		netDns.Name = netDnsName
+
+		// successCondition
+		successCondition := new(v1beta1.NetSuccessCondition)
+		successCondition.SuccessRate = &successRate
+		successCondition.MeanAccessDelayInMs = &successMean
+		netDns.Spec.SuccessCondition = successCondition
+
+		// target
+		target := new(v1beta1.NetDnsTarget)
+		targetDnsUser := new(v1beta1.NetDnsTargetUserSpec)
+		targetDnsUser.Server = &testSvcIP
+		port := 8443
+		targetDnsUser.Port = &port
+		target.NetDnsTargetUser = targetDnsUser
+		target.EnableLatencyMetric = true
+		netDns.Spec.Target = target
+
+		// request
+		request := new(v1beta1.NetdnsRequest)
+		request.PerRequestTimeoutInMS = 2000
+		request.QPS = 10
+		request.DurationInSecond = 10
+		request.Domain = fmt.Sprintf(targetDomain, netDnsName)
+		protocol := "https"
+		request.Protocol = &protocol
+		dohMethod := "GET"
+		request.DohMethod = &dohMethod
+		netDns.Spec.Request = request
+
+		// Schedule
+		Schedule := new(v1beta1.SchedulePlan)
+		Schedule.Schedule = &crontab
+		Schedule.RoundNumber = 1
+		Schedule.RoundTimeoutMinute = 1
+		netDns.Spec.Schedule = Schedule
+
+		e = frame.CreateResource(netDns)
+		Expect(e).NotTo(HaveOccurred(), "create netDns resource")
+
+		e = common.CheckRuntime(frame, netDns, pluginManager.KindNameNetdns, 60)
+		Expect(e).NotTo(HaveOccurred(), "check task runtime spec")
+
+		e = common.WaitKdoctorTaskDone(frame, netDns, pluginManager.KindNameNetdns, 120)
+		Expect(e).NotTo(HaveOccurred(), "wait netDns task finish")
+
+		success, e := common.CompareResult(frame, netDnsName, pluginManager.KindNameNetdns, testPodIPs, reportNum, netDns)
+		Expect(e).NotTo(HaveOccurred(), "compare report and task")
+		Expect(success).To(BeTrue(), "compare report and task result")
+	})
+
+	It("Successfully testing User Define Dns server case use quic protocol", Serial, Label("D00006"), func() {
+		var e error
+		crontab := "0 1"
+		netDnsName := "netdns-e2e-" + tools.RandomName()
+
+		netDns := new(v1beta1.Netdns)
+		netDns.Name = netDnsName
+
+		// successCondition
+		successCondition := new(v1beta1.NetSuccessCondition)
+		successCondition.SuccessRate = &successRate
+		successCondition.MeanAccessDelayInMs = &successMean
+		netDns.Spec.SuccessCondition = successCondition
+
+		// target
+		target := new(v1beta1.NetDnsTarget)
+		targetDnsUser := new(v1beta1.NetDnsTargetUserSpec)
+		targetDnsUser.Server = &testSvcIP
+		port := 853
+		targetDnsUser.Port = &port
+		target.NetDnsTargetUser = targetDnsUser
+		target.EnableLatencyMetric = true
+		netDns.Spec.Target = target
+
+		// request
+		request := new(v1beta1.NetdnsRequest)
+		request.PerRequestTimeoutInMS = 2000
+		request.QPS = 10
+		request.DurationInSecond = 10
+		request.Domain = fmt.Sprintf(targetDomain, netDnsName)
+		protocol := "quic"
+		request.Protocol = &protocol
+		netDns.Spec.Request = request
+
+		// Schedule
+		Schedule := new(v1beta1.SchedulePlan)
+		Schedule.Schedule = &crontab
+		Schedule.RoundNumber = 1
+		Schedule.RoundTimeoutMinute = 1
+		netDns.Spec.Schedule = Schedule
+
+		e = frame.CreateResource(netDns)
+		Expect(e).NotTo(HaveOccurred(), "create netDns resource")
+
+		e = common.CheckRuntime(frame, netDns, pluginManager.KindNameNetdns, 60)
+		Expect(e).NotTo(HaveOccurred(), "check task runtime spec")
+
+		e = common.WaitKdoctorTaskDone(frame, netDns, pluginManager.KindNameNetdns, 120)
+		Expect(e).NotTo(HaveOccurred(), "wait netDns task finish")
+
+		success, e := common.CompareResult(frame, netDnsName, pluginManager.KindNameNetdns, testPodIPs, reportNum, netDns)
+		Expect(e).NotTo(HaveOccurred(), "compare report and task")
+		Expect(success).To(BeTrue(), "compare report and task result")
+	})
+
 	It("Successfully testing NetDns crontab case", Label("C00002"), Serial, func() {
 		var e error
 		crontab := "* * * * *"